@@ -0,0 +1,163 @@
+package snowflake
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/snowflakedb/gosnowflake"
+	"gorm.io/gorm"
+)
+
+// ErrArrowUnsupported is returned by RowsAsArrow when the result cannot be fetched as Arrow batches
+var ErrArrowUnsupported = errors.New("arrow batches are not supported")
+
+// RowsAsArrow runs the query of db and passes its result to fn as Arrow records, skipping the
+// row by row conversion of database/sql, for exports of large result sets:
+//
+//	err := snowflake.RowsAsArrow(db.Model(&Order{}).Where("created_at >= ?", since), func(record arrow.Record) error {
+//		return writer.Write(record)
+//	})
+//
+// The batches of the result are downloaded one at a time, each record is released once fn
+// returns, fn calls record.Retain to keep it. Timestamps are converted according to the
+// gosnowflake.WithArrowBatchesTimestampOption of the context of db.
+// The query runs on its own connection, so it is not supported in a transaction
+func RowsAsArrow(db *gorm.DB, fn func(record arrow.Record) error) error {
+	if _, inTransaction := unpreparedConnPool(db.Statement.ConnPool).(gorm.TxCommitter); inTransaction {
+		return fmt.Errorf("%w: in a transaction", ErrArrowUnsupported)
+	}
+
+	stmt := db.Session(&gorm.Session{DryRun: true}).Find(&[]map[string]interface{}{}).Statement
+	if stmt.Error != nil {
+		return stmt.Error
+	}
+
+	conn, release, err := arrowConn(db)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx := gosnowflake.WithArrowBatches(stmt.Context)
+	return conn.Raw(func(driverConn interface{}) error {
+		queryer, ok := driverConn.(driver.QueryerContext)
+		if !ok {
+			return fmt.Errorf("%w: the driver does not run queries", ErrArrowUnsupported)
+		}
+		args, err := arrowNamedValues(driverConn, stmt.Vars, isNamedBindVarStyle(db))
+		if err != nil {
+			return err
+		}
+
+		rows, err := queryer.QueryContext(ctx, stmt.SQL.String(), args)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		snowflakeRows, ok := rows.(gosnowflake.SnowflakeRows)
+		if !ok {
+			return fmt.Errorf("%w: the driver returned %T", ErrArrowUnsupported, rows)
+		}
+		batches, err := snowflakeRows.GetArrowBatches()
+		if err != nil {
+			return err
+		}
+
+		for _, batch := range batches {
+			if err := fetchArrowBatch(batch.WithContext(ctx), fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// fetchArrowBatch downloads batch and passes its records to fn, releasing every record
+// even when fn fails
+func fetchArrowBatch(batch *gosnowflake.ArrowBatch, fn func(record arrow.Record) error) error {
+	records, err := batch.Fetch()
+	if err != nil || records == nil {
+		return err
+	}
+	defer func() {
+		for _, record := range *records {
+			record.Release()
+		}
+	}()
+
+	for _, record := range *records {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// arrowConn returns the connection of db when it is pinned to one (db.Connection), or a
+// connection of its pool, released by the returned function
+func arrowConn(db *gorm.DB) (*sql.Conn, func(), error) {
+	pool := unpreparedConnPool(db.Statement.ConnPool)
+	if named, ok := pool.(*namedArgsConnPool); ok {
+		pool = named.ConnPool
+	}
+	if conn, pinned := pool.(*sql.Conn); pinned {
+		return conn, func() {}, nil
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, nil, err
+	}
+	conn, err := sqlDB.Conn(db.Statement.Context)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, func() { conn.Close() }, nil
+}
+
+// arrowNamedValues converts vars into the arguments of the driver connection conn the way
+// database/sql does: conn checks the values it handles itself (arrays...), the others are
+// converted by the default converter, which calls driver.Valuer
+func arrowNamedValues(conn interface{}, vars []interface{}, named bool) ([]driver.NamedValue, error) {
+	if named {
+		vars = namedArgs(vars)
+	}
+
+	checker, _ := conn.(driver.NamedValueChecker)
+	args := make([]driver.NamedValue, len(vars))
+	for idx, v := range vars {
+		arg := driver.NamedValue{Ordinal: idx + 1, Value: v}
+		if namedArg, ok := v.(sql.NamedArg); ok {
+			arg.Name, arg.Value = namedArg.Name, namedArg.Value
+		}
+
+		if checker != nil {
+			err := checker.CheckNamedValue(&arg)
+			if err == nil {
+				args[idx] = arg
+				continue
+			}
+			if !errors.Is(err, driver.ErrSkip) {
+				return nil, fmt.Errorf("argument %d: %w", idx+1, err)
+			}
+		}
+
+		value, err := driver.DefaultParameterConverter.ConvertValue(arg.Value)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", idx+1, err)
+		}
+		arg.Value = value
+		args[idx] = arg
+	}
+	return args, nil
+}
+
+// isNamedBindVarStyle reports whether the statements of db use `:pN` placeholders
+func isNamedBindVarStyle(db *gorm.DB) bool {
+	config := dialectorConfig(db.Dialector)
+	return config != nil && config.BindVarStyle == BindVarNamed
+}
@@ -0,0 +1,52 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"gorm.io/gorm"
+)
+
+func TestRowsAsArrow(t *testing.T) {
+	t.Run("Runs the query of db with its vars", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		err := RowsAsArrow(db.Model(&TestModel{}).Where("age > ?", 30), func(arrow.Record) error { return nil })
+		if !errors.Is(err, ErrArrowUnsupported) {
+			t.Fatalf("Expected ErrArrowUnsupported from the fake driver, got %v", err)
+		}
+
+		if len(fake.statements) != 1 {
+			t.Fatalf("Expected one statement, got %v", fake.queries())
+		}
+		statement := fake.statements[0]
+		if statement.query != "SELECT * FROM test_models WHERE age > ?" || len(statement.args) != 1 || statement.args[0] != 30 {
+			t.Errorf("Unexpected statement: %s %v", statement.query, statement.args)
+		}
+	})
+
+	t.Run("Passes named vars with BindVarNamed", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{BindVarStyle: BindVarNamed}, nil)
+
+		_ = RowsAsArrow(db.Model(&TestModel{}).Where("name = ?", "jinzhu"), func(arrow.Record) error { return nil })
+
+		if len(fake.statements) != 1 || fake.statements[0].query != "SELECT * FROM test_models WHERE name = :p1" {
+			t.Fatalf("Unexpected statements: %v", fake.queries())
+		}
+	})
+
+	t.Run("Is not supported in a transaction", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return RowsAsArrow(tx.Model(&TestModel{}), func(arrow.Record) error { return nil })
+		})
+		if !errors.Is(err, ErrArrowUnsupported) {
+			t.Errorf("Expected ErrArrowUnsupported, got %v", err)
+		}
+		if hasQuery(fake.queries(), "SELECT") {
+			t.Errorf("Expected no query, got %v", fake.queries())
+		}
+	})
+}
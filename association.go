@@ -0,0 +1,264 @@
+package snowflake
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ReplaceAssociation replaces the associated records name of model (a record or a slice of
+// records) with values, as db.Model(model).Association(name).Replace(values...) does, in a few
+// statements for many2many associations instead of a statement per association:
+//
+//	err := snowflake.ReplaceAssociation(db, &users, "Languages", &english, &french)
+//
+// The current rows of the join table of the records of model are read in one query, the rows
+// of the associations removed are deleted with one DELETE ... USING and the rows added are
+// inserted with one MERGE, in a transaction. Values without a primary key are created first,
+// the others are expected to exist. Other associations are replaced with Association.Replace
+func ReplaceAssociation(db *gorm.DB, model interface{}, name string, values ...interface{}) error {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	rel := stmt.Schema.Relationships.Relations[name]
+	if rel == nil {
+		return fmt.Errorf("%w: %s", gorm.ErrUnsupportedRelation, name)
+	}
+	if rel.Type != schema.Many2Many {
+		return db.Model(model).Association(name).Replace(values...)
+	}
+
+	owners := indirectRecords(reflect.ValueOf(model))
+	records, err := associationRecords(rel.FieldSchema, values)
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := createAssociationRecords(tx, rel.FieldSchema, records); err != nil {
+			return err
+		}
+
+		var ownColumns, joinColumns []string
+		for _, ref := range rel.References {
+			if ref.OwnPrimaryKey {
+				ownColumns = append(ownColumns, ref.ForeignKey.DBName)
+			}
+			joinColumns = append(joinColumns, ref.ForeignKey.DBName)
+		}
+
+		// the join rows of the records of model once replaced, by key in the order of records
+		var (
+			replaced = map[string]map[string]interface{}{}
+			keys     []string
+			ownKeys  [][]interface{}
+		)
+		for _, owner := range owners {
+			var ownKey []interface{}
+			for _, ref := range rel.References {
+				if ref.OwnPrimaryKey {
+					value, zero := ref.PrimaryKey.ValueOf(tx.Statement.Context, owner)
+					if zero {
+						return fmt.Errorf("%w: %s has no primary key", gorm.ErrPrimaryKeyRequired, stmt.Schema.Name)
+					}
+					ownKey = append(ownKey, value)
+				}
+			}
+			ownKeys = append(ownKeys, ownKey)
+
+			for _, record := range records {
+				row := make(map[string]interface{}, len(rel.References))
+				for _, ref := range rel.References {
+					source := record
+					if ref.OwnPrimaryKey {
+						source = owner
+					}
+					row[ref.ForeignKey.DBName], _ = ref.PrimaryKey.ValueOf(tx.Statement.Context, source)
+				}
+				if key := joinRowKey(row, joinColumns); replaced[key] == nil {
+					replaced[key] = row
+					keys = append(keys, key)
+				}
+			}
+		}
+		if len(ownKeys) == 0 {
+			return nil
+		}
+
+		var current []map[string]interface{}
+		column, queryValues := schema.ToQueryValues(rel.JoinTable.Table, ownColumns, ownKeys)
+		if err := tx.Table(rel.JoinTable.Table).Select(joinColumns).
+			Where(clause.IN{Column: column, Values: queryValues}).Find(&current).Error; err != nil {
+			return err
+		}
+
+		var removed []map[string]interface{}
+		for _, row := range current {
+			// unquoted columns are returned uppercase
+			for column, value := range row {
+				for _, joinColumn := range joinColumns {
+					if column != joinColumn && strings.EqualFold(column, joinColumn) {
+						row[joinColumn] = value
+					}
+				}
+			}
+
+			key := joinRowKey(row, joinColumns)
+			if _, ok := replaced[key]; ok {
+				delete(replaced, key)
+			} else {
+				removed = append(removed, row)
+			}
+		}
+
+		if err := deleteJoinRows(tx, rel.JoinTable.Table, joinColumns, removed); err != nil {
+			return err
+		}
+		if len(replaced) > 0 {
+			added := make([]map[string]interface{}, 0, len(replaced))
+			for _, key := range keys {
+				if row, ok := replaced[key]; ok {
+					added = append(added, row)
+				}
+			}
+			onConflict := clause.OnConflict{DoNothing: true}
+			for _, column := range joinColumns {
+				onConflict.Columns = append(onConflict.Columns, clause.Column{Name: column})
+			}
+			if err := tx.Table(rel.JoinTable.Table).Clauses(onConflict).Create(&added).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, owner := range owners {
+			if !owner.CanAddr() {
+				continue
+			}
+			if err := rel.Field.Set(tx.Statement.Context, owner, associationFieldValue(rel.Field, records).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// indirectRecords returns the addressable records of value, a pointer to a record or a slice
+func indirectRecords(value reflect.Value) []reflect.Value {
+	value = reflect.Indirect(value)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return []reflect.Value{value}
+	}
+
+	records := make([]reflect.Value, 0, value.Len())
+	for idx := 0; idx < value.Len(); idx++ {
+		records = append(records, reflect.Indirect(value.Index(idx)))
+	}
+	return records
+}
+
+// associationRecords returns the records of values, records or slices of records of sch, as
+// pointers: records passed by value are copied
+func associationRecords(sch *schema.Schema, values []interface{}) ([]reflect.Value, error) {
+	var records []reflect.Value
+	for _, value := range values {
+		for _, record := range indirectRecords(reflect.ValueOf(value)) {
+			if record.Type() != sch.ModelType {
+				return nil, fmt.Errorf("%w: %s is not a %s", gorm.ErrInvalidValue, record.Type(), sch.Name)
+			}
+			if !record.CanAddr() {
+				copied := reflect.New(sch.ModelType).Elem()
+				copied.Set(record)
+				record = copied
+			}
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// createAssociationRecords creates the records without primary key in one statement
+func createAssociationRecords(tx *gorm.DB, sch *schema.Schema, records []reflect.Value) error {
+	if sch.PrioritizedPrimaryField == nil {
+		return nil
+	}
+
+	created := reflect.MakeSlice(reflect.SliceOf(reflect.PtrTo(sch.ModelType)), 0, len(records))
+	for _, record := range records {
+		if _, zero := sch.PrioritizedPrimaryField.ValueOf(tx.Statement.Context, record); zero {
+			created = reflect.Append(created, record.Addr())
+		}
+	}
+	if created.Len() == 0 {
+		return nil
+	}
+
+	pointer := reflect.New(created.Type())
+	pointer.Elem().Set(created)
+	return tx.Omit(clause.Associations).Create(pointer.Interface()).Error
+}
+
+// deleteJoinRows deletes rows of the join table with one DELETE ... USING of their values
+func deleteJoinRows(tx *gorm.DB, table string, columns []string, rows []map[string]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var (
+		aliases    = make([]string, len(columns))
+		conditions = make([]string, len(columns))
+		values     = []interface{}{clause.Table{Name: table}}
+		tuples     = make([]string, len(rows))
+	)
+	for idx, column := range columns {
+		aliases[idx] = fmt.Sprintf("column%d AS ?", idx+1)
+		conditions[idx] = "? = ?"
+		values = append(values, clause.Column{Name: column})
+	}
+	for idx, row := range rows {
+		tuples[idx] = "(" + strings.TrimSuffix(strings.Repeat("?,", len(columns)), ",") + ")"
+		for _, column := range columns {
+			values = append(values, row[column])
+		}
+	}
+	for _, column := range columns {
+		values = append(values,
+			clause.Column{Table: table, Name: column},
+			clause.Column{Table: "removed", Name: column},
+		)
+	}
+
+	return tx.Exec(
+		"DELETE FROM ? USING (SELECT "+strings.Join(aliases, ", ")+" FROM VALUES "+strings.Join(tuples, ",")+") AS removed WHERE "+
+			strings.Join(conditions, " AND "),
+		values...,
+	).Error
+}
+
+// joinRowKey returns the key of a row of a join table, its values in the order of columns. The
+// values of records and of the database are compared as text, e.g. uint and int64 IDs
+func joinRowKey(row map[string]interface{}, columns []string) string {
+	key := make([]string, len(columns))
+	for idx, column := range columns {
+		key[idx] = fmt.Sprint(row[column])
+	}
+	return strings.Join(key, "\x00")
+}
+
+// associationFieldValue returns the value of the association field holding records, a slice of
+// records or of pointers
+func associationFieldValue(field *schema.Field, records []reflect.Value) reflect.Value {
+	value := reflect.MakeSlice(field.FieldType, 0, len(records))
+	for _, record := range records {
+		if field.FieldType.Elem().Kind() == reflect.Ptr {
+			value = reflect.Append(value, record.Addr())
+		} else {
+			value = reflect.Append(value, record)
+		}
+	}
+	return value
+}
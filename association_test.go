@@ -0,0 +1,83 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+func TestReplaceAssociation(t *testing.T) {
+	t.Run("Many2many delta", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			if strings.HasPrefix(query, "SELECT tag_model_id,post_model_id FROM post_tags") {
+				return fakeResult{
+					columns: []string{"TAG_MODEL_ID", "POST_MODEL_ID"},
+					rows:    [][]driver.Value{{int64(1), int64(10)}, {int64(1), int64(11)}, {int64(2), int64(10)}},
+				}
+			}
+			return fakeResult{}
+		})
+
+		tags := []TagModel{{ID: 1}, {ID: 2}}
+		if err := ReplaceAssociation(db, &tags, "Posts", &PostModel{ID: 10}, PostModel{ID: 12}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		var statements []fakeStatement
+		for _, stmt := range fake.statements {
+			if !strings.HasPrefix(stmt.query, "BEGIN") && !strings.HasPrefix(stmt.query, "COMMIT") {
+				statements = append(statements, stmt)
+			}
+		}
+		if len(statements) != 3 {
+			t.Fatalf("Expected a SELECT, a DELETE and a MERGE, got %v", fake.queries())
+		}
+
+		expectedSelect := "SELECT tag_model_id,post_model_id FROM post_tags WHERE post_tags.tag_model_id IN (?,?)"
+		if statements[0].query != expectedSelect {
+			t.Errorf("Expected %s, got %s", expectedSelect, statements[0].query)
+		}
+
+		expectedDelete := "DELETE FROM post_tags USING (SELECT column1 AS tag_model_id, column2 AS post_model_id FROM VALUES (?,?)) AS removed " +
+			"WHERE post_tags.tag_model_id = removed.tag_model_id AND post_tags.post_model_id = removed.post_model_id"
+		if statements[1].query != expectedDelete || len(statements[1].args) != 2 || statements[1].args[1] != int64(11) {
+			t.Errorf("Expected %s removing post 11, got %s %v", expectedDelete, statements[1].query, statements[1].args)
+		}
+
+		if !strings.HasPrefix(statements[2].query, "MERGE INTO post_tags") || !strings.Contains(statements[2].query, "WHEN NOT MATCHED THEN INSERT") {
+			t.Errorf("Expected a MERGE inserting the added rows, got %s", statements[2].query)
+		}
+		if len(statements[2].args) != 4 || statements[2].args[0] != uint(12) || statements[2].args[2] != uint(12) {
+			t.Errorf("Expected the rows of post 12 to be added, got %v", statements[2].args)
+		}
+
+		for _, tag := range tags {
+			if len(tag.Posts) != 2 || tag.Posts[0].ID != 10 || tag.Posts[1].ID != 12 {
+				t.Errorf("Expected the posts of the tags to be replaced, got %+v", tag.Posts)
+			}
+		}
+	})
+
+	t.Run("Unchanged associations", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			if strings.HasPrefix(query, "SELECT ") {
+				return fakeResult{columns: []string{"tag_model_id", "post_model_id"}, rows: [][]driver.Value{{int64(1), int64(10)}}}
+			}
+			return fakeResult{}
+		})
+
+		if err := ReplaceAssociation(db, &TagModel{ID: 1}, "Posts", &PostModel{ID: 10}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if hasQuery(fake.queries(), "DELETE") || hasQuery(fake.queries(), "MERGE") {
+			t.Errorf("Expected no change, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Unknown association", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, nil)
+		if err := ReplaceAssociation(db, &TagModel{ID: 1}, "Users"); err == nil {
+			t.Error("Expected an error for an unknown association")
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestBatch(t *testing.T) {
+	db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+	tx := BeginBatch(db)
+	if err := tx.Create(&TestModel{Name: "O'Brien", Age: 40}).Error; err != nil {
+		t.Fatalf("Expected create to be batched, got %v", err)
+	}
+	if err := tx.Model(&TestModel{ID: 7}).Update("age", gorm.Expr("age + ?", 1)).Error; err != nil {
+		t.Fatalf("Expected update to be batched, got %v", err)
+	}
+	if err := tx.Exec("DELETE FROM test_models WHERE name = ?", `a\b`).Error; err != nil {
+		t.Fatalf("Expected exec to be batched, got %v", err)
+	}
+
+	if queries := fake.queries(); len(queries) != 0 {
+		t.Fatalf("Expected no statement before the flush, got %v", queries)
+	}
+
+	if err := FlushBatch(tx); err != nil {
+		t.Fatalf("Expected flush to succeed, got %v", err)
+	}
+	if len(fake.statements) != 1 || len(fake.statements[0].args) != 0 {
+		t.Fatalf("Expected one request without binds, got %+v", fake.statements)
+	}
+
+	statements := strings.Split(fake.statements[0].query, ";\n")
+	expected := []string{
+		`INSERT INTO "test_models" ("name","age") VALUES ('O''Brien',40)`,
+		`UPDATE "test_models" SET "age"=age + 1 WHERE "id" = 7`,
+		`DELETE FROM test_models WHERE name = 'a\\b'`,
+	}
+	if len(statements) != len(expected) {
+		t.Fatalf("Expected %d statements, got %v", len(expected), statements)
+	}
+	for idx, statement := range statements {
+		if !strings.HasPrefix(statement, expected[idx]) {
+			t.Errorf("Expected statement %d to start with %s, got %s", idx, expected[idx], statement)
+		}
+	}
+
+	if err := FlushBatch(tx); err != nil || len(fake.statements) != 1 {
+		t.Errorf("Expected an empty batch after the flush, got %v %v", err, fake.queries())
+	}
+	if err := FlushBatch(db); !errors.Is(err, ErrNoBatch) {
+		t.Errorf("Expected ErrNoBatch, got %v", err)
+	}
+}
+
+func TestBatchBindVarStyles(t *testing.T) {
+	for _, style := range []BindVarStyle{BindVarQuestion, BindVarPositional, BindVarNamed} {
+		t.Run(string(style), func(t *testing.T) {
+			db, fake := setupFakeDB(t, Config{BindVarStyle: style}, nil)
+
+			tx := BeginBatch(db)
+			if err := tx.Exec("UPDATE t SET x = ? WHERE ts > '2024-01-01 12:01:00' AND \"a:1\" = ?", 42, "b").Error; err != nil {
+				t.Fatalf("Expected exec to be batched, got %v", err)
+			}
+			if err := FlushBatch(tx); err != nil {
+				t.Fatalf("Expected flush to succeed, got %v", err)
+			}
+
+			expected := `UPDATE t SET x = 42 WHERE ts > '2024-01-01 12:01:00' AND "a:1" = 'b'`
+			if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+				t.Errorf("Expected %s, got %v", expected, queries)
+			}
+		})
+	}
+}
+
+func TestBatchLiteral(t *testing.T) {
+	type status string
+	var nilTime *time.Time
+
+	for _, tt := range []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, "NULL"},
+		{nilTime, "NULL"},
+		{"it's", `'it''s'`},
+		{status("open"), `'open'`},
+		{true, "TRUE"},
+		{int8(-3), "-3"},
+		{uint64(math.MaxUint64), "18446744073709551615"},
+		{math.Inf(1), "'inf'::FLOAT"},
+		{[]byte{0xca, 0xfe}, "X'cafe'"},
+		{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "'2024-01-02 03:04:05 +00:00'"},
+	} {
+		literal, err := batchLiteral(tt.value)
+		if err != nil || literal != tt.expected {
+			t.Errorf("Expected %s for %v, got %s (%v)", tt.expected, tt.value, literal, err)
+		}
+	}
+
+	if _, err := batchLiteral(struct{}{}); !errors.Is(err, ErrBatchValue) {
+		t.Errorf("Expected ErrBatchValue, got %v", err)
+	}
+}
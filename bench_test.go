@@ -0,0 +1,149 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// benchmarkSizes are the row and column counts of the insert benchmarks
+var benchmarkSizes = []struct{ rows, columns int }{
+	{1, 3},
+	{10, 3},
+	{100, 10},
+	{1000, 10},
+	{1000, 50},
+}
+
+// benchmarkValues returns rows x columns values, the first column being the primary key "id"
+func benchmarkValues(rows, columns int) clause.Values {
+	values := clause.Values{Columns: make([]clause.Column, columns), Values: make([][]interface{}, rows)}
+	values.Columns[0] = clause.Column{Name: "id"}
+	for idx := 1; idx < columns; idx++ {
+		values.Columns[idx] = clause.Column{Name: fmt.Sprintf("column_%d", idx)}
+	}
+	for row := range values.Values {
+		values.Values[row] = make([]interface{}, columns)
+		values.Values[row][0] = uint(row + 1)
+		for idx := 1; idx < columns; idx++ {
+			values.Values[row][idx] = fmt.Sprintf("value_%d_%d", row, idx)
+		}
+	}
+	return values
+}
+
+// benchmarkStatement returns a dry run DB whose statement is parsed for TestModel
+func benchmarkStatement(b *testing.B, useUnionSelect bool) *gorm.DB {
+	db := setupMockDBWithConfig(b, useUnionSelect, true)
+	stmt := db.Session(&gorm.Session{DryRun: true}).Model(&TestModel{})
+	if err := stmt.Statement.Parse(&TestModel{}); err != nil {
+		b.Fatalf("Failed to parse model: %v", err)
+	}
+	return stmt
+}
+
+// runInsertBenchmark runs build over every benchmark size, resetting the statement between iterations
+func runInsertBenchmark(b *testing.B, useUnionSelect bool, build func(db *gorm.DB, values clause.Values)) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("rows=%d/columns=%d", size.rows, size.columns), func(b *testing.B) {
+			db := benchmarkStatement(b, useUnionSelect)
+			values := benchmarkValues(size.rows, size.columns)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				db.Statement.SQL.Reset()
+				db.Statement.Vars = nil
+				build(db, values)
+			}
+		})
+	}
+}
+
+func BenchmarkBuildUnionSelectInsert(b *testing.B) {
+	runInsertBenchmark(b, true, buildUnionSelectInsert)
+}
+
+func BenchmarkBuildValuesInsert(b *testing.B) {
+	runInsertBenchmark(b, false, buildValuesInsert)
+}
+
+func BenchmarkMergeCreate(b *testing.B) {
+	// the assignments are built once per column count, as Create does before calling MergeCreate
+	onConflicts := map[int]clause.OnConflict{}
+	runInsertBenchmark(b, false, func(db *gorm.DB, values clause.Values) {
+		onConflict, ok := onConflicts[len(values.Columns)]
+		if !ok {
+			updates := make([]string, 0, len(values.Columns)-1)
+			for _, column := range values.Columns[1:] {
+				updates = append(updates, column.Name)
+			}
+			onConflict = clause.OnConflict{DoUpdates: clause.AssignmentColumns(updates)}
+			onConflicts[len(values.Columns)] = onConflict
+		}
+		MergeCreate(db, onConflict, values)
+	})
+}
+
+func BenchmarkQuoteTo(b *testing.B) {
+	identifiers := map[string]string{
+		"column":    "name",
+		"qualified": "analytics.public.orders",
+		"aliased":   "orders AS o",
+		"reserved":  "public.order",
+		"long":      strings.Repeat("segment_", 16) + "name",
+	}
+
+	for _, quoteFields := range []bool{false, true} {
+		dialector := Dialector{Config: &Config{QuoteFields: quoteFields}}
+		for name, identifier := range identifiers {
+			b.Run(fmt.Sprintf("quoteFields=%v/%s", quoteFields, name), func(b *testing.B) {
+				var builder strings.Builder
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					builder.Reset()
+					dialector.QuoteTo(&builder, identifier)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkFetchDefaultValuesFromChanges(b *testing.B) {
+	for _, rows := range []int{10, 1000, 10000} {
+		b.Run(fmt.Sprintf("rows=%d", rows), func(b *testing.B) {
+			ids := make([][]driver.Value, rows)
+			for idx := range ids {
+				ids[idx] = []driver.Value{int64(idx + 1)}
+			}
+			db, _ := setupFakeDB(b, Config{}, func(query string, args []interface{}) fakeResult {
+				return fakeResult{columns: []string{"id"}, rows: ids}
+			})
+
+			models := make([]TestModel, rows)
+			tx := db.Model(&models)
+			if err := tx.Statement.Parse(&models); err != nil {
+				b.Fatalf("Failed to parse model: %v", err)
+			}
+			tx.Statement.ReflectValue = reflect.ValueOf(models)
+			fields := tx.Statement.Schema.FieldsWithDefaultDBValue
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for idx := range models {
+					models[idx].ID = 0
+				}
+				if err := fetchDefaultValuesFromChanges(tx, fields); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
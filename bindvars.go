@@ -0,0 +1,136 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BindVarStyle controls which placeholder syntax is written for bind variables
+type BindVarStyle string
+
+const (
+	// BindVarQuestion writes `?` placeholders (default)
+	BindVarQuestion BindVarStyle = "question"
+	// BindVarPositional writes numbered `:1`, `:2` placeholders
+	BindVarPositional BindVarStyle = "positional"
+	// BindVarNamed writes `:p1`, `:p2` placeholders and passes the vars as sql.NamedArg
+	BindVarNamed BindVarStyle = "named"
+
+	namedBindVarPrefix = "p"
+)
+
+// writeBindVar writes the placeholder for the n-th (1-based) bind variable of a statement
+func writeBindVar(writer clause.Writer, style BindVarStyle, n int) {
+	switch style {
+	case BindVarPositional:
+		writer.WriteByte(':')
+		writer.WriteString(strconv.Itoa(n))
+	case BindVarNamed:
+		writer.WriteByte(':')
+		writer.WriteString(namedBindVarPrefix)
+		writer.WriteString(strconv.Itoa(n))
+	default:
+		writer.WriteByte('?')
+	}
+}
+
+// bindVarPrefix returns the prefix of the numbered placeholders of the style, followed by
+// the 1-based number of the var, "" means plain `?` placeholders
+func bindVarPrefix(style BindVarStyle) string {
+	switch style {
+	case BindVarPositional:
+		return ":"
+	case BindVarNamed:
+		return ":" + namedBindVarPrefix
+	}
+	return ""
+}
+
+// namedArgs converts positional args into the sql.NamedArg matching the `:pN` placeholders
+func namedArgs(args []interface{}) []interface{} {
+	named := make([]interface{}, len(args))
+	for idx, arg := range args {
+		if namedArg, ok := arg.(sql.NamedArg); ok {
+			named[idx] = namedArg
+		} else {
+			named[idx] = sql.Named(namedBindVarPrefix+strconv.Itoa(idx+1), arg)
+		}
+	}
+	return named
+}
+
+// namedArgsConnPool wraps a connection pool so every statement built with BindVarNamed
+// receives its vars as sql.NamedArg, as required by the driver for `:name` placeholders
+type namedArgsConnPool struct {
+	gorm.ConnPool
+}
+
+func (p *namedArgsConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.ConnPool.ExecContext(ctx, query, namedArgs(args)...)
+}
+
+func (p *namedArgsConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.ConnPool.QueryContext(ctx, query, namedArgs(args)...)
+}
+
+func (p *namedArgsConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.ConnPool.QueryRowContext(ctx, query, namedArgs(args)...)
+}
+
+func (p *namedArgsConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	switch beginner := p.ConnPool.(type) {
+	case gorm.TxBeginner:
+		tx, err := beginner.BeginTx(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &namedArgsTx{namedArgsConnPool{tx}}, nil
+	case gorm.ConnPoolBeginner:
+		tx, err := beginner.BeginTx(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &namedArgsTx{namedArgsConnPool{tx}}, nil
+	}
+	return nil, gorm.ErrInvalidTransaction
+}
+
+func (p *namedArgsConnPool) GetDBConn() (*sql.DB, error) {
+	switch pool := p.ConnPool.(type) {
+	case *sql.DB:
+		return pool, nil
+	case gorm.GetDBConnector:
+		return pool.GetDBConn()
+	}
+	return nil, gorm.ErrInvalidDB
+}
+
+func (p *namedArgsConnPool) Ping() error {
+	if pinger, ok := p.ConnPool.(interface{ Ping() error }); ok {
+		return pinger.Ping()
+	}
+	return nil
+}
+
+// namedArgsTx is the transaction counterpart of namedArgsConnPool
+type namedArgsTx struct {
+	namedArgsConnPool
+}
+
+func (tx *namedArgsTx) Commit() error {
+	if committer, ok := tx.ConnPool.(gorm.TxCommitter); ok {
+		return committer.Commit()
+	}
+	return gorm.ErrInvalidTransaction
+}
+
+func (tx *namedArgsTx) Rollback() error {
+	if committer, ok := tx.ConnPool.(gorm.TxCommitter); ok {
+		return committer.Rollback()
+	}
+	return gorm.ErrInvalidTransaction
+}
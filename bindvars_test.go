@@ -0,0 +1,138 @@
+package snowflake
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+func TestBindVarStyles(t *testing.T) {
+	tests := []struct {
+		style    BindVarStyle
+		expected string
+	}{
+		{"", "?,?"},
+		{BindVarQuestion, "?,?"},
+		{BindVarPositional, ":1,:2"},
+		{BindVarNamed, ":p1,:p2"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.style), func(t *testing.T) {
+			dialector := New(Config{BindVarStyle: test.style})
+			builder := &strings.Builder{}
+			writer := &mockClauseWriter{builder: builder}
+
+			stmt := &gorm.Statement{}
+			for idx, v := range []interface{}{"a", "b"} {
+				if idx > 0 {
+					writer.WriteByte(',')
+				}
+				stmt.Vars = append(stmt.Vars, v)
+				dialector.BindVarTo(writer, stmt, v)
+			}
+
+			if builder.String() != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, builder.String())
+			}
+		})
+	}
+}
+
+func TestBindVarStyleInCreate(t *testing.T) {
+	t.Run("Positional VALUES insert", func(t *testing.T) {
+		db, err := gorm.Open(&Dialector{Config: &Config{
+			Conn:         &mockConnPool{},
+			BindVarStyle: BindVarPositional,
+		}}, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		if err != nil {
+			t.Fatalf("Failed to open db: %v", err)
+		}
+
+		models := []TestModel{{Name: "John", Age: 25}, {Name: "Jane", Age: 30}}
+		stmt := db.Session(&gorm.Session{DryRun: true}).Model(&TestModel{})
+		if err := stmt.Statement.Parse(&TestModel{}); err != nil {
+			t.Fatalf("Failed to parse model: %v", err)
+		}
+		stmt.Statement.Dest = models
+		stmt.Statement.ReflectValue = reflect.ValueOf(models)
+
+		Create(stmt)
+
+		sql := stmt.Statement.SQL.String()
+		if !strings.Contains(sql, "VALUES (:1,:2),(:3,:4)") {
+			t.Errorf("Expected positional placeholders, got: %s", sql)
+		}
+		if strings.Contains(sql, "?") {
+			t.Errorf("Expected no question mark placeholders, got: %s", sql)
+		}
+	})
+
+	t.Run("Positional MERGE", func(t *testing.T) {
+		db, err := gorm.Open(&Dialector{Config: &Config{
+			Conn:         &mockConnPool{},
+			BindVarStyle: BindVarPositional,
+			QuoteFields:  true,
+		}}, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		if err != nil {
+			t.Fatalf("Failed to open db: %v", err)
+		}
+
+		stmt := db.Session(&gorm.Session{DryRun: true}).Model(&TestModel{})
+		if err := stmt.Statement.Parse(&TestModel{}); err != nil {
+			t.Fatalf("Failed to parse model: %v", err)
+		}
+
+		values := clause.Values{
+			Columns: []clause.Column{{Name: "name"}, {Name: "age"}, {Name: "id"}},
+			Values:  [][]interface{}{{"John", 25, uint(1)}},
+		}
+		MergeCreate(stmt, clause.OnConflict{UpdateAll: true}, values)
+
+		sql := stmt.Statement.SQL.String()
+		if !strings.Contains(sql, "USING (VALUES(:1,:2,:3))") {
+			t.Errorf("Expected positional placeholders in MERGE, got: %s", sql)
+		}
+	})
+}
+
+func TestNamedArgs(t *testing.T) {
+	args := namedArgs([]interface{}{"a", sql.Named("custom", 1), 2})
+
+	expected := []interface{}{sql.Named("p1", "a"), sql.Named("custom", 1), sql.Named("p3", 2)}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("Expected %v, got %v", expected, args)
+	}
+}
+
+func TestNamedArgsConnPool(t *testing.T) {
+	db, err := gorm.Open(&Dialector{Config: &Config{
+		Conn:         &mockConnPool{},
+		BindVarStyle: BindVarNamed,
+	}}, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+
+	pool, ok := db.ConnPool.(*namedArgsConnPool)
+	if !ok {
+		t.Fatalf("Expected ConnPool to be wrapped for named binds, got %T", db.ConnPool)
+	}
+
+	tx, err := pool.BeginTx(db.Statement.Context, nil)
+	if err != nil {
+		t.Fatalf("Expected BeginTx to succeed, got %v", err)
+	}
+	if _, ok := tx.(*namedArgsTx); !ok {
+		t.Errorf("Expected transaction to keep named binds, got %T", tx)
+	}
+
+	if _, err := pool.GetDBConn(); err != gorm.ErrInvalidDB {
+		t.Errorf("Expected ErrInvalidDB for mock pool, got %v", err)
+	}
+}
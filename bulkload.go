@@ -0,0 +1,275 @@
+package snowflake
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// bulkLoadNull is the CSV field loaded as NULL, so empty strings stay empty strings
+	bulkLoadNull = `\N`
+	// bulkLoadTimeFormat keeps the offset of timestamps for TIMESTAMP_LTZ/TZ columns,
+	// TIMESTAMP_NTZ columns get the UTC wall clock as with bound times
+	bulkLoadTimeFormat = "2006-01-02T15:04:05.999999999Z07:00"
+	// bulkLoadFileFormat parses the files written by writeBulkLoadFile
+	bulkLoadFileFormat = `FILE_FORMAT = (TYPE = CSV FIELD_OPTIONALLY_ENCLOSED_BY = '"' ESCAPE_UNENCLOSED_FIELD = NONE NULL_IF = ('\\N') EMPTY_FIELD_AS_NULL = FALSE)`
+)
+
+// bulkLoadable reports whether the rows of a Create are loaded through the table stage,
+// i.e. there are more than Config.BulkLoadThreshold of them, or they are flushed by a
+// StreamWriter, and all are plain values
+func bulkLoadable(db *gorm.DB, values clause.Values) bool {
+	config := dialectorConfig(db.Dialector)
+	_, streamed := db.Get(streamWriterKey)
+	if config == nil || (!streamed && (config.BulkLoadThreshold <= 0 || len(values.Values) <= config.BulkLoadThreshold)) ||
+		len(values.Columns) == 0 || db.DryRun || db.Statement.Table == "" {
+		return false
+	}
+
+	// SQL expressions (e.g. gorm.Expr) cannot be written to a file
+	for _, row := range values.Values {
+		for _, value := range row {
+			if _, ok := value.(clause.Expression); ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// bulkLoad writes the rows of values to a temporary CSV file, PUTs it to the stage of the table
+// and loads it with COPY INTO, instead of binding every value of a giant INSERT.
+// The staged file is purged once loaded
+func bulkLoad(db *gorm.DB, values clause.Values) {
+	if fetcher, _ := defaultValueFetcherOf(db); fetcher != nil && !pinnedConnPool(db.Statement.ConnPool) {
+		// the default values are read back in the session of the COPY
+		db.AddError(withPinnedConnPool(db, func() error {
+			bulkLoad(db, values)
+			return nil
+		}))
+		return
+	}
+
+	types := semiStructuredColumns(db, values.Columns)
+
+	path, err := writeBulkLoadFile(values, types)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	defer os.Remove(path)
+
+	var (
+		ctx   = db.Statement.Context
+		pool  = unpreparedConnPool(db.Statement.ConnPool)
+		stage = "@%" + db.Statement.Quote(db.Statement.Table)
+		// PUT compresses the file with gzip and appends .gz to its name
+		stagedFile = filepath.Base(path) + ".gz"
+	)
+
+	putSQL := "PUT " + quoteLiteral("file://"+filepath.ToSlash(path)) + " " + stage + " AUTO_COMPRESS = TRUE OVERWRITE = TRUE"
+	if _, err := pool.ExecContext(ctx, putSQL); err != nil {
+		db.AddError(fmt.Errorf("failed to stage bulk load file: %w", err))
+		return
+	}
+
+	if snapshotID(db); db.Error != nil {
+		_, _ = pool.ExecContext(ctx, "REMOVE "+stage+"/"+stagedFile)
+		return
+	}
+
+	db.Statement.SQL.Reset()
+	db.Statement.Vars = nil
+	writeCopyInto(db, values.Columns, types, stage, stagedFile)
+
+	rows, err := pool.QueryContext(ctx, db.Statement.SQL.String())
+	if err != nil {
+		// COPY only purges the files it loaded
+		_, _ = pool.ExecContext(ctx, "REMOVE "+stage+"/"+stagedFile)
+		db.AddError(err)
+		return
+	}
+	loaded, err := scanRowsLoaded(rows)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	db.RowsAffected += loaded
+
+	db.Logger.Info(ctx, fmt.Sprintf("This is the result of bulk load %s, rows loaded %d", db.Statement.SQL.String(), loaded))
+
+	// the rows of a StreamWriter are copies, their default values are not read back
+	if _, streamed := db.Get(streamWriterKey); !streamed {
+		populateDefaultValues(db)
+	}
+}
+
+// writeCopyInto writes the COPY INTO statement loading stagedFile from stage, parsing the
+// semi-structured columns (types[idx] != "") with PARSE_JSON
+func writeCopyInto(db *gorm.DB, columns []clause.Column, types []string, stage, stagedFile string) {
+	db.Statement.WriteString("COPY INTO ")
+	db.Statement.WriteQuoted(db.Statement.Table)
+	db.Statement.WriteString(" (")
+	for idx, column := range columns {
+		if idx > 0 {
+			db.Statement.WriteByte(',')
+		}
+		db.Statement.WriteQuoted(column)
+	}
+	db.Statement.WriteString(") FROM (SELECT ")
+	for idx := range columns {
+		if idx > 0 {
+			db.Statement.WriteByte(',')
+		}
+		column := "$" + strconv.Itoa(idx+1)
+		if idx < len(types) && types[idx] != "" {
+			column = "PARSE_JSON(" + column + ")"
+			if types[idx] == "OBJECT" || types[idx] == "ARRAY" {
+				column += "::" + types[idx]
+			}
+		}
+		db.Statement.WriteString(column)
+	}
+	db.Statement.WriteString(" FROM " + stage + ") FILES = (" + quoteLiteral(stagedFile) + ") ")
+	db.Statement.WriteString(bulkLoadFileFormat)
+	db.Statement.WriteString(" PURGE = TRUE")
+}
+
+// writeBulkLoadFile writes the rows of values to a temporary CSV file and returns its path
+func writeBulkLoadFile(values clause.Values, types []string) (_ string, err error) {
+	file, err := os.CreateTemp("", "gorm-snowflake-*.csv")
+	if err != nil {
+		return "", fmt.Errorf("failed to create bulk load file: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(file.Name())
+		}
+	}()
+
+	writer := csv.NewWriter(file)
+	record := make([]string, len(values.Columns))
+	for _, row := range values.Values {
+		for idx, value := range row {
+			if idx < len(types) && types[idx] != "" {
+				if value, err = semiStructuredJSON(value); err != nil {
+					return "", err
+				}
+			}
+			if record[idx], err = bulkLoadValue(value); err != nil {
+				return "", err
+			}
+		}
+		if err = writer.Write(record); err != nil {
+			return "", fmt.Errorf("failed to write bulk load file: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		return "", fmt.Errorf("failed to write bulk load file: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// bulkLoadValue returns the CSV field of value
+func bulkLoadValue(value interface{}) (string, error) {
+	if valuer, ok := value.(driver.Valuer); ok {
+		var err error
+		if value, err = valuer.Value(); err != nil {
+			return "", err
+		}
+	}
+
+	if number, ok := numberBindValue(value); ok {
+		return number, nil
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return bulkLoadNull, nil
+	case string:
+		return v, nil
+	case []byte:
+		return hex.EncodeToString(v), nil
+	case time.Time:
+		return v.UTC().Format(bulkLoadTimeFormat), nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case float32:
+		return formatBulkLoadFloat(float64(v), 32), nil
+	case float64:
+		return formatBulkLoadFloat(v, 64), nil
+	}
+
+	if reflectValue := reflect.ValueOf(value); reflectValue.Kind() == reflect.Ptr {
+		if reflectValue.IsNil() {
+			return bulkLoadNull, nil
+		}
+		return bulkLoadValue(reflectValue.Elem().Interface())
+	}
+	return fmt.Sprint(value), nil
+}
+
+// formatBulkLoadFloat formats f with the spelling Snowflake parses for special values
+func formatBulkLoadFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
+
+// scanRowsLoaded sums the rows_loaded column of the result of COPY INTO
+func scanRowsLoaded(rows *sql.Rows) (int64, error) {
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		loaded int64
+		values = make([]interface{}, len(columns))
+		count  sql.NullInt64
+	)
+	for idx, column := range columns {
+		if strings.EqualFold(column, "rows_loaded") {
+			values[idx] = &count
+		} else {
+			values[idx] = new(sql.RawBytes)
+		}
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(values...); err != nil {
+			return 0, err
+		}
+		loaded += count.Int64
+	}
+	return loaded, rows.Err()
+}
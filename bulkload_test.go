@@ -0,0 +1,139 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"math"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestBulkLoad(t *testing.T) {
+	putFileRegex := regexp.MustCompile(`^PUT 'file://([^']+)'`)
+
+	newHandler := func(file *string) func(query string, args []interface{}) fakeResult {
+		return func(query string, args []interface{}) fakeResult {
+			switch {
+			case strings.HasPrefix(query, "PUT "):
+				// the file is removed once loaded, read it while it is staged
+				if match := putFileRegex.FindStringSubmatch(query); match != nil {
+					data, _ := os.ReadFile(match[1])
+					*file = string(data)
+				}
+			case strings.HasPrefix(query, "COPY INTO "):
+				return fakeResult{
+					columns: []string{"file", "status", "rows_parsed", "rows_loaded"},
+					rows:    [][]driver.Value{{"x.csv.gz", "LOADED", int64(3), int64(3)}},
+				}
+			case strings.Contains(query, "CHANGES("):
+				return fakeResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}, {int64(2)}, {int64(3)}}}
+			}
+			return fakeResult{}
+		}
+	}
+
+	t.Run("Loads rows above the threshold with PUT and COPY INTO", func(t *testing.T) {
+		var file string
+		db, fake := setupFakeDB(t, Config{BulkLoadThreshold: 2}, newHandler(&file))
+
+		models := []TestModel{{Name: "a"}, {Name: "b,c"}, {Name: ""}}
+		result := db.Create(&models)
+		if result.Error != nil {
+			t.Fatalf("Expected no error, got %v", result.Error)
+		}
+
+		queries := fake.queries()
+		if hasQuery(queries, "INSERT") {
+			t.Errorf("Expected no INSERT, got %v", queries)
+		}
+		if !hasQuery(queries, "AUTO_COMPRESS = TRUE") || !hasQuery(queries, "PURGE = TRUE") || !hasQuery(queries, "FROM (SELECT $1,$2 FROM @%") {
+			t.Errorf("Expected PUT and COPY INTO, got %v", queries)
+		}
+		// empty strings are loaded as empty strings, NULL is \N
+		if file != "a,0\n\"b,c\",0\n,0\n" {
+			t.Errorf("Unexpected file: %q", file)
+		}
+		if result.RowsAffected != 3 {
+			t.Errorf("Expected 3 rows affected, got %d", result.RowsAffected)
+		}
+		if models[0].ID != 1 || models[1].ID != 2 || models[2].ID != 3 {
+			t.Errorf("Expected IDs from CHANGES, got %+v", models)
+		}
+	})
+
+	t.Run("Inserts rows up to the threshold", func(t *testing.T) {
+		var file string
+		db, fake := setupFakeDB(t, Config{BulkLoadThreshold: 3}, newHandler(&file))
+
+		if err := db.Create(&[]TestModel{{Name: "a"}, {Name: "b"}, {Name: "c"}}).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if hasQuery(fake.queries(), "COPY INTO") || !hasQuery(fake.queries(), "INSERT") {
+			t.Errorf("Expected INSERT, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Inserts rows holding SQL expressions", func(t *testing.T) {
+		var file string
+		db, fake := setupFakeDB(t, Config{BulkLoadThreshold: 1}, newHandler(&file))
+
+		rows := []map[string]interface{}{{"name": "a", "age": gorm.Expr("1 + 1")}, {"name": "b", "age": 2}}
+		if err := db.Model(&TestModel{}).Create(rows).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if hasQuery(fake.queries(), "COPY INTO") {
+			t.Errorf("Expected no COPY INTO, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Removes the staged file when COPY fails", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{BulkLoadThreshold: 1}, func(query string, args []interface{}) fakeResult {
+			if strings.HasPrefix(query, "COPY INTO ") {
+				return fakeResult{err: driver.ErrBadConn}
+			}
+			return fakeResult{}
+		})
+
+		if err := db.Create(&[]TestModel{{Name: "a"}, {Name: "b"}}).Error; err == nil {
+			t.Fatal("Expected COPY error")
+		}
+		if !hasQuery(fake.queries(), "REMOVE @%") {
+			t.Errorf("Expected REMOVE of the staged file, got %v", fake.queries())
+		}
+	})
+}
+
+func TestBulkLoadValue(t *testing.T) {
+	var nilString *string
+	name := "x"
+
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, `\N`},
+		{nilString, `\N`},
+		{&name, "x"},
+		{[]byte{0xca, 0xfe}, "cafe"},
+		{true, "TRUE"},
+		{1.5, "1.5"},
+		{math.Inf(-1), "-inf"},
+		{math.NaN(), "NaN"},
+		{uint64(math.MaxUint64), "18446744073709551615"},
+		{time.Date(2024, 1, 2, 3, 4, 5, 6000, time.FixedZone("", 3600)), "2024-01-02T02:04:05.000006Z"},
+	}
+
+	for _, tt := range tests {
+		got, err := bulkLoadValue(tt.value)
+		if err != nil {
+			t.Fatalf("Expected no error for %v, got %v", tt.value, err)
+		}
+		if got != tt.expected {
+			t.Errorf("bulkLoadValue(%v) = %q, expected %q", tt.value, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	// clientSideIDEpoch is the origin of the timestamps of client side IDs, 2020-01-01 UTC in
+	// milliseconds, so they last until 2089
+	clientSideIDEpoch = 1577836800000
+
+	clientSideIDNodeBits     = 10
+	clientSideIDSequenceBits = 12
+	maxClientSideIDNode      = 1<<clientSideIDNodeBits - 1
+	maxClientSideIDSequence  = 1<<clientSideIDSequenceBits - 1
+)
+
+// ErrInvalidClientSideIDNode is returned by Initialize when Config.ClientSideIDNode is out of range
+var ErrInvalidClientSideIDNode = errors.New("ClientSideIDNode must be between 0 and 1023")
+
+// clientSideIDGenerators holds the generator of each node, shared by the DBs of the process so
+// they never hand out the same ID
+var clientSideIDGenerators sync.Map
+
+// clientSideIDGenerator generates 64-bit IDs ordered by time, the snowflake layout: 41 bits of
+// milliseconds since clientSideIDEpoch, 10 bits of node and 12 bits of sequence
+type clientSideIDGenerator struct {
+	mu       sync.Mutex
+	node     int64
+	last     int64
+	sequence int64
+	now      func() time.Time
+}
+
+// clientSideIDGeneratorOf returns the generator of node
+func clientSideIDGeneratorOf(node int64) *clientSideIDGenerator {
+	generator, _ := clientSideIDGenerators.LoadOrStore(node, &clientSideIDGenerator{node: node, now: time.Now})
+	return generator.(*clientSideIDGenerator)
+}
+
+// next returns a new ID. When the 4096 IDs of a millisecond are exhausted or the clock goes
+// back, IDs are taken from the following milliseconds, so they keep increasing
+func (generator *clientSideIDGenerator) next() int64 {
+	generator.mu.Lock()
+	defer generator.mu.Unlock()
+
+	now := generator.now().UnixMilli() - clientSideIDEpoch
+	if now > generator.last {
+		generator.last, generator.sequence = now, 0
+	} else if generator.sequence < maxClientSideIDSequence {
+		generator.sequence++
+	} else {
+		generator.last, generator.sequence = generator.last+1, 0
+	}
+	return generator.last<<(clientSideIDNodeBits+clientSideIDSequenceBits) | generator.node<<clientSideIDSequenceBits | generator.sequence
+}
+
+// clientSideIDField returns the auto increment field of sch whose values are generated by the
+// client with Config.ClientSideIDs, nil if there is none
+func clientSideIDField(config *Config, sch *schema.Schema) *schema.Field {
+	if config == nil || !config.ClientSideIDs || sch == nil {
+		return nil
+	}
+	if field := sch.PrioritizedPrimaryField; field != nil && field.AutoIncrement &&
+		(field.DataType == schema.Int || field.DataType == schema.Uint) {
+		return field
+	}
+	return nil
+}
+
+func registerClientSideIDCallbacks(db *gorm.DB, node int64) error {
+	if node < 0 || node > maxClientSideIDNode {
+		return ErrInvalidClientSideIDNode
+	}
+
+	generator := clientSideIDGeneratorOf(node)
+	return db.Callback().Create().Before("gorm:before_create").Register("snowflake:assign_client_side_ids", func(db *gorm.DB) {
+		assignClientSideIDs(db, generator)
+	})
+}
+
+// assignClientSideIDs sets the zero auto increment IDs of the rows to create with IDs of
+// generator, before the BeforeCreate hooks so they see them
+func assignClientSideIDs(db *gorm.DB, generator *clientSideIDGenerator) {
+	field := clientSideIDField(dialectorConfig(db.Dialector), db.Statement.Schema)
+	if field == nil || db.Error != nil {
+		return
+	}
+	if field.Size < 64 {
+		db.AddError(fmt.Errorf("client side IDs need a 64-bit field, %s is %s", field.Name, field.FieldType))
+		return
+	}
+
+	ctx := db.Statement.Context
+	for _, row := range modelRows(db.Statement, db.Statement.ReflectValue) {
+		if _, isZero := field.ValueOf(ctx, row); !isZero {
+			continue
+		}
+		if err := field.Set(ctx, row, generator.next()); err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+}
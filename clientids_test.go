@@ -0,0 +1,112 @@
+package snowflake
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type ClientSideIDHookModel struct {
+	ID       uint64 `gorm:"primaryKey;autoIncrement"`
+	Name     string
+	SeenByID uint64
+}
+
+func (model *ClientSideIDHookModel) BeforeCreate(*gorm.DB) error {
+	model.SeenByID = model.ID
+	return nil
+}
+
+func TestClientSideIDs(t *testing.T) {
+	t.Run("Binds generated IDs", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{ClientSideIDs: true, ClientSideIDNode: 7}, func(query string, args []interface{}) fakeResult {
+			return fakeResult{rowsAffected: 3}
+		})
+
+		models := []TestModel{{Name: "a"}, {ID: 5, Name: "b"}, {Name: "c"}}
+		if err := db.Create(&models).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if models[0].ID == 0 || models[1].ID != 5 || models[2].ID <= models[0].ID {
+			t.Errorf("Expected increasing generated IDs, got %+v", models)
+		}
+		if node := models[0].ID >> clientSideIDSequenceBits & maxClientSideIDNode; node != 7 {
+			t.Errorf("Expected node 7 in the ID, got %d", node)
+		}
+
+		queries := fake.queries()
+		if hasQuery(queries, "CHANGES(") {
+			t.Errorf("Expected no CHANGES query, got %v", queries)
+		}
+		if !hasQuery(queries, "INSERT INTO test_models (name,age,id)") && !hasQuery(queries, "INSERT INTO test_models (id,name,age)") {
+			t.Errorf("Expected IDs to be inserted, got %v", queries)
+		}
+	})
+
+	t.Run("IDs are assigned before BeforeCreate", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{ClientSideIDs: true}, nil)
+
+		model := ClientSideIDHookModel{Name: "a"}
+		if err := db.Create(&model).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if model.ID == 0 || model.SeenByID != model.ID {
+			t.Errorf("Expected the hook to see the ID, got %+v", model)
+		}
+	})
+
+	t.Run("Migrates auto increment columns without IDENTITY", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{ClientSideIDs: true}, nil)
+
+		if err := db.Migrator().CreateTable(&TestModel{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		queries := fake.queries()
+		if !hasQuery(queries, "id BIGINT") || hasQuery(queries, "IDENTITY") || hasQuery(queries, "CHANGE_TRACKING") {
+			t.Errorf("Expected a plain BIGINT column without change tracking, got %v", queries)
+		}
+	})
+
+	t.Run("Node out of range", func(t *testing.T) {
+		sqlDB := sql.OpenDB(&fakeDriver{})
+		defer sqlDB.Close()
+
+		_, err := gorm.Open(New(Config{Conn: sqlDB, ClientSideIDs: true, ClientSideIDNode: 1024}), &gorm.Config{
+			Logger: logger.Default.LogMode(logger.Silent),
+		})
+		if !errors.Is(err, ErrInvalidClientSideIDNode) {
+			t.Errorf("Expected ErrInvalidClientSideIDNode, got %v", err)
+		}
+	})
+}
+
+func TestClientSideIDGenerator(t *testing.T) {
+	now := time.UnixMilli(clientSideIDEpoch + 1000)
+	generator := &clientSideIDGenerator{node: 3, now: func() time.Time { return now }}
+
+	first := generator.next()
+	if first != 1000<<22|3<<12 {
+		t.Errorf("Unexpected first ID %d", first)
+	}
+	if second := generator.next(); second != first+1 {
+		t.Errorf("Expected the sequence to increase, got %d after %d", second, first)
+	}
+
+	// exhausted sequence
+	generator.sequence = maxClientSideIDSequence
+	if id := generator.next(); id != 1001<<22|3<<12 {
+		t.Errorf("Expected the next millisecond, got %d", id)
+	}
+
+	// the clock goes back
+	now = now.Add(-time.Second)
+	if id := generator.next(); id != 1001<<22|3<<12|1 {
+		t.Errorf("Expected the IDs to keep increasing, got %d", id)
+	}
+}
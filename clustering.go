@@ -0,0 +1,113 @@
+package snowflake
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ClusteringKeyer is implemented by models declaring the clustering key of their table, written
+// by CreateTable and kept in sync by AutoMigrate. Keys are field/column names or expressions
+// (e.g. TO_DATE(created_at)). An empty non-nil key drops the clustering key of the table
+type ClusteringKeyer interface {
+	ClusteringKeys() []string
+}
+
+// clusteringKeys returns the clustering key declared by the model of stmt, with ClusteringKeys
+// or clusterBy tags: `gorm:"clusterBy"` adds the column of the field in field order,
+// `gorm:"clusterBy:event_date,customer_id"` sets the whole key. declared is false when the model
+// declares none
+func clusteringKeys(stmt *gorm.Statement) (keys []string, declared bool) {
+	if stmt.Schema == nil {
+		return nil, false
+	}
+	if keyer, ok := reflect.New(stmt.Schema.ModelType).Interface().(ClusteringKeyer); ok {
+		if keys := keyer.ClusteringKeys(); keys != nil {
+			return keys, true
+		}
+	}
+
+	for _, field := range stmt.Schema.Fields {
+		value, ok := field.TagSettings["CLUSTERBY"]
+		if !ok {
+			continue
+		}
+		if value != "CLUSTERBY" {
+			return strings.Split(value, ","), true
+		}
+		if field.DBName != "" {
+			keys = append(keys, field.DBName)
+		}
+	}
+	return keys, keys != nil
+}
+
+// clusteringKeyExprs returns the expressions of keys, the names of fields are quoted as columns
+func clusteringKeyExprs(stmt *gorm.Statement, keys []string) (exprs []interface{}, names []string) {
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key == "" {
+			continue
+		}
+		if field := stmt.Schema.LookUpField(key); field != nil {
+			exprs = append(exprs, clause.Column{Name: field.DBName})
+			names = append(names, field.DBName)
+		} else {
+			exprs = append(exprs, clause.Expr{SQL: key})
+			names = append(names, key)
+		}
+	}
+	return exprs, names
+}
+
+// currentClusteringKey returns the clustering key of the table of stmt, e.g. LINEAR(NAME,AGE)
+func (m Migrator) currentClusteringKey(stmt *gorm.Statement) (string, error) {
+	var current []sql.NullString
+	err := m.informationSchemaTable(&InformationSchemaTable{}, stmt, "").
+		Pluck("CLUSTERING_KEY", &current).Error
+	if len(current) == 0 {
+		return "", err
+	}
+	return current[0].String, err
+}
+
+// migrateClusteringKey alters the clustering key of the existing table of stmt when it differs
+// from the one declared by the model
+func (m Migrator) migrateClusteringKey(stmt *gorm.Statement) error {
+	keys, declared := clusteringKeys(stmt)
+	if !declared {
+		return nil
+	}
+
+	current, err := m.currentClusteringKey(stmt)
+	if err != nil {
+		return err
+	}
+
+	exprs, names := clusteringKeyExprs(stmt, keys)
+	if len(exprs) == 0 {
+		if current == "" {
+			return nil
+		}
+		return m.DB.Exec("ALTER TABLE ? DROP CLUSTERING KEY", m.CurrentTable(stmt)).Error
+	}
+
+	if normalizeClusteringKey(current) == normalizeClusteringKey("LINEAR("+strings.Join(names, ",")+")") {
+		return nil
+	}
+	return m.DB.Exec("ALTER TABLE ? CLUSTER BY ?", m.CurrentTable(stmt), exprs).Error
+}
+
+// AlterClusteringKey sets the clustering key of the table of value to keys (field/column names or
+// expressions), without keys it drops the clustering key
+func (m Migrator) AlterClusteringKey(value interface{}, keys ...string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		exprs, _ := clusteringKeyExprs(stmt, keys)
+		if len(exprs) == 0 {
+			return m.DB.Exec("ALTER TABLE ? DROP CLUSTERING KEY", m.CurrentTable(stmt)).Error
+		}
+		return m.DB.Exec("ALTER TABLE ? CLUSTER BY ?", m.CurrentTable(stmt), exprs).Error
+	})
+}
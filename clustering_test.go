@@ -0,0 +1,128 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ClusteredEvent struct {
+	ID         uint
+	EventDate  time.Time `gorm:"clusterBy"`
+	CustomerID uint      `gorm:"clusterBy"`
+	Payload    string
+}
+
+type ClusteredByExpression struct {
+	ID         uint
+	CreatedAt  time.Time `gorm:"clusterBy:TO_DATE(created_at),customer_id"`
+	CustomerID uint
+}
+
+type ClusteredByMethod struct {
+	ID     uint
+	Region string
+	Day    time.Time
+}
+
+func (ClusteredByMethod) ClusteringKeys() []string {
+	return []string{"Region", "Day"}
+}
+
+type UnclusteredModel struct {
+	ID uint
+}
+
+func (UnclusteredModel) ClusteringKeys() []string {
+	return []string{}
+}
+
+func TestCreateTableClusteringKey(t *testing.T) {
+	for _, tt := range []struct {
+		model    interface{}
+		expected string
+	}{
+		{&ClusteredEvent{}, `CLUSTER BY ("event_date","customer_id")`},
+		{&ClusteredByExpression{}, `CLUSTER BY (TO_DATE(created_at),"customer_id")`},
+		{&ClusteredByMethod{}, `CLUSTER BY ("region","day")`},
+	} {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		if err := db.Migrator().CreateTable(tt.model); err != nil {
+			t.Fatalf("Expected CreateTable to succeed, got %v", err)
+		}
+		if !hasQuery(fake.queries(), tt.expected) {
+			t.Errorf("Expected %s in %v", tt.expected, fake.queries())
+		}
+	}
+
+	t.Run("Declared key wins over indexes", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true, IndexStrategy: IndexClusterByFirstIndex}, nil)
+
+		if err := db.Migrator().CreateTable(&UnclusteredModel{}); err != nil {
+			t.Fatalf("Expected CreateTable to succeed, got %v", err)
+		}
+		if hasQuery(fake.queries(), "CLUSTER BY") {
+			t.Errorf("Expected no clustering key, got %v", fake.queries())
+		}
+	})
+}
+
+func TestMigrateClusteringKey(t *testing.T) {
+	for _, tt := range []struct {
+		model    interface{}
+		current  interface{}
+		expected string
+	}{
+		{&ClusteredEvent{}, `LINEAR("event_date", "customer_id")`, ""},
+		{&ClusteredEvent{}, nil, `ALTER TABLE "clustered_events" CLUSTER BY ("event_date","customer_id")`},
+		{&ClusteredEvent{}, "LINEAR(event_date)", `ALTER TABLE "clustered_events" CLUSTER BY ("event_date","customer_id")`},
+		{&UnclusteredModel{}, "LINEAR(id)", `ALTER TABLE "unclustered_models" DROP CLUSTERING KEY`},
+		{&UnclusteredModel{}, nil, ""},
+	} {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, func(query string, args []interface{}) fakeResult {
+			if strings.Contains(query, "CLUSTERING_KEY") {
+				return fakeResult{columns: []string{"clustering_key"}, rows: [][]driver.Value{{tt.current}}}
+			}
+			return fakeResult{}
+		})
+
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(tt.model); err != nil {
+			t.Fatalf("Failed to parse model: %v", err)
+		}
+		if err := db.Migrator().(Migrator).migrateClusteringKey(stmt); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		altered := hasQuery(fake.queries(), "ALTER TABLE")
+		if tt.expected == "" && altered {
+			t.Errorf("With clustering key %v: expected no ALTER, got %v", tt.current, fake.queries())
+		} else if tt.expected != "" && !hasQuery(fake.queries(), tt.expected) {
+			t.Errorf("With clustering key %v: expected %s, got %v", tt.current, tt.expected, fake.queries())
+		}
+	}
+}
+
+func TestAlterClusteringKey(t *testing.T) {
+	db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+	if err := db.Migrator().(Migrator).AlterClusteringKey(&ClusteredByMethod{}, "Day"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := db.Migrator().(Migrator).AlterClusteringKey(&ClusteredByMethod{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, expected := range []string{
+		`ALTER TABLE "clustered_by_methods" CLUSTER BY ("day")`,
+		`ALTER TABLE "clustered_by_methods" DROP CLUSTERING KEY`,
+	} {
+		if !hasQuery(fake.queries(), expected) {
+			t.Errorf("Expected %s in %v", expected, fake.queries())
+		}
+	}
+}
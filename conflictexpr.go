@@ -0,0 +1,76 @@
+package snowflake
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// keyExprTokenRegex matches the string literals, quoted identifiers and unquoted identifiers of a
+// key expression
+var keyExprTokenRegex = regexp.MustCompile(`'(?:[^']|'')*'|"(?:[^"]|"")*"|[A-Za-z_][A-Za-z0-9_$]*`)
+
+// keyExprColumns calls fn with the position in expr of each reference to a column of the
+// statement schema and its name. Identifiers followed by a parenthesis (functions) or around a
+// dot (qualified names) are not references. Statements without a schema have no known columns
+func keyExprColumns(stmt *gorm.Statement, expr string, fn func(start, end int, column string)) {
+	if stmt.Schema == nil {
+		return
+	}
+	for _, match := range keyExprTokenRegex.FindAllStringIndex(expr, -1) {
+		start, end := match[0], match[1]
+		token := expr[start:end]
+		if token[0] == '\'' {
+			continue
+		}
+		if start > 0 && expr[start-1] == '.' {
+			continue
+		}
+		if next := strings.TrimLeftFunc(expr[end:], unicode.IsSpace); next != "" && (next[0] == '(' || next[0] == '.') {
+			continue
+		}
+
+		field := stmt.Schema.LookUpField(strings.ReplaceAll(strings.Trim(token, `"`), `""`, `"`))
+		if field == nil && token[0] != '"' {
+			field = lintLookUpField(stmt.Schema, token)
+		}
+		if field != nil && field.DBName != "" {
+			fn(start, end, field.DBName)
+		}
+	}
+}
+
+// qualifyKeyExpr returns the key expression expr with its column references prefixed with
+// qualifier, the quoted table of a MERGE or its EXCLUDED source
+func qualifyKeyExpr(stmt *gorm.Statement, expr, qualifier string) string {
+	var (
+		qualified strings.Builder
+		last      int
+	)
+	keyExprColumns(stmt, expr, func(start, end int, column string) {
+		qualified.WriteString(expr[last:start])
+		qualified.WriteString(qualifier)
+		qualified.WriteString(stmt.Quote(column))
+		last = end
+	})
+	qualified.WriteString(expr[last:])
+	return qualified.String()
+}
+
+// keyWritten reports whether the values of key are written by an upsert of columns: the column,
+// or every column referenced by a key expression
+func keyWritten(stmt *gorm.Statement, key clause.Column, columns map[string]bool) bool {
+	if !key.Raw {
+		return columns[key.Name]
+	}
+
+	written, referenced := true, false
+	keyExprColumns(stmt, key.Name, func(_, _ int, column string) {
+		referenced = true
+		written = written && columns[column]
+	})
+	return referenced && written
+}
@@ -0,0 +1,193 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInvalidCopy is returned by CopyIntoBuilder.Exec when the COPY INTO cannot be built
+	ErrInvalidCopy = errors.New("invalid COPY INTO")
+
+	// stageLocationRegex matches the stage locations of FromStage, e.g. @mystage/path or @%orders
+	stageLocationRegex = regexp.MustCompile(`^@[%~]?[^\s;']*$`)
+	// onErrorRegex matches the ON_ERROR actions of COPY INTO
+	onErrorRegex = regexp.MustCompile(`^(?i)(CONTINUE|ABORT_STATEMENT|SKIP_FILE|SKIP_FILE_\d+%?)$`)
+)
+
+// matchByColumnNames are the MATCH_BY_COLUMN_NAME modes of COPY INTO
+var matchByColumnNames = map[string]bool{"CASE_SENSITIVE": true, "CASE_INSENSITIVE": true, "NONE": true}
+
+// CopyIntoResult is a row of the output of COPY INTO, the load result of a file
+type CopyIntoResult struct {
+	File                 string
+	Status               string
+	RowsParsed           int64
+	RowsLoaded           int64
+	ErrorLimit           int64
+	ErrorsSeen           int64
+	FirstError           string
+	FirstErrorLine       int64
+	FirstErrorCharacter  int64
+	FirstErrorColumnName string
+}
+
+// CopyIntoBuilder builds a COPY INTO <table> statement loading staged files, see CopyInto
+type CopyIntoBuilder struct {
+	db                *gorm.DB
+	table             interface{}
+	stage             string
+	files             []string
+	pattern           string
+	fileFormat        string
+	onError           string
+	matchByColumnName string
+	purge             bool
+	force             bool
+	err               error
+}
+
+// CopyInto starts a COPY INTO <table> loading staged files into a table:
+//
+//	results, err := snowflake.CopyInto(db).Table(&Order{}).FromStage("@landing/orders/").
+//		FileFormat("TYPE = CSV SKIP_HEADER = 1").OnError("CONTINUE").Exec()
+//
+// Errors of the arguments are returned by Exec
+func CopyInto(db *gorm.DB) *CopyIntoBuilder {
+	return &CopyIntoBuilder{db: db}
+}
+
+// Table loads the table of model, or the table named by a string
+func (b *CopyIntoBuilder) Table(model interface{}) *CopyIntoBuilder {
+	if name, ok := model.(string); ok {
+		if err := validateIdentifier(name); err != nil {
+			return b.fail(err)
+		}
+		b.table = clause.Table{Name: name}
+		return b
+	}
+
+	stmt := &gorm.Statement{DB: b.db}
+	if err := stmt.Parse(model); err != nil {
+		return b.fail(err)
+	}
+	b.table = clause.Table{Name: stmt.Table}
+	return b
+}
+
+// FromStage loads the files of location, a stage with an optional path, e.g. @landing/orders/,
+// or the Location of a Stage
+func (b *CopyIntoBuilder) FromStage(location string) *CopyIntoBuilder {
+	if !stageLocationRegex.MatchString(location) {
+		return b.fail(fmt.Errorf("%w: unsupported stage location %q", ErrInvalidCopy, location))
+	}
+	b.stage = location
+	return b
+}
+
+// Files only loads the files named, relative to the stage location
+func (b *CopyIntoBuilder) Files(names ...string) *CopyIntoBuilder {
+	b.files = append(b.files, names...)
+	return b
+}
+
+// Pattern only loads the files whose path matches the regular expression pattern
+func (b *CopyIntoBuilder) Pattern(pattern string) *CopyIntoBuilder {
+	b.pattern = pattern
+	return b
+}
+
+// FileFormat sets the file format options of the files, e.g. TYPE = CSV SKIP_HEADER = 1 or
+// FORMAT_NAME = my_csv_format. The stage or table file format is used when not set
+func (b *CopyIntoBuilder) FileFormat(format string) *CopyIntoBuilder {
+	b.fileFormat = format
+	return b
+}
+
+// OnError sets the action on the errors of a file: CONTINUE, SKIP_FILE, SKIP_FILE_<n>,
+// SKIP_FILE_<n>% or ABORT_STATEMENT (the default of Snowflake)
+func (b *CopyIntoBuilder) OnError(action string) *CopyIntoBuilder {
+	if !onErrorRegex.MatchString(action) {
+		return b.fail(fmt.Errorf("%w: unsupported ON_ERROR %q", ErrInvalidCopy, action))
+	}
+	b.onError = strings.ToUpper(action)
+	return b
+}
+
+// MatchByColumnName loads semi-structured files into the columns of the same name:
+// CASE_SENSITIVE, CASE_INSENSITIVE or NONE
+func (b *CopyIntoBuilder) MatchByColumnName(mode string) *CopyIntoBuilder {
+	if !matchByColumnNames[strings.ToUpper(mode)] {
+		return b.fail(fmt.Errorf("%w: unsupported MATCH_BY_COLUMN_NAME %q", ErrInvalidCopy, mode))
+	}
+	b.matchByColumnName = strings.ToUpper(mode)
+	return b
+}
+
+// Purge removes the files from the stage once loaded
+func (b *CopyIntoBuilder) Purge(purge bool) *CopyIntoBuilder {
+	b.purge = purge
+	return b
+}
+
+// Force loads the files again even if they were already loaded in the last 64 days
+func (b *CopyIntoBuilder) Force(force bool) *CopyIntoBuilder {
+	b.force = force
+	return b
+}
+
+// Exec runs the COPY INTO and returns the load result of every file
+func (b *CopyIntoBuilder) Exec() ([]CopyIntoResult, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.table == nil {
+		return nil, fmt.Errorf("%w: no table", ErrInvalidCopy)
+	}
+	if b.stage == "" {
+		return nil, fmt.Errorf("%w: no stage", ErrInvalidCopy)
+	}
+
+	sql := "COPY INTO ? FROM " + b.stage
+	if len(b.files) > 0 {
+		files := make([]string, len(b.files))
+		for idx, file := range b.files {
+			files[idx] = quoteLiteral(file)
+		}
+		sql += " FILES = (" + strings.Join(files, ", ") + ")"
+	}
+	if b.pattern != "" {
+		sql += " PATTERN = " + quoteLiteral(b.pattern)
+	}
+	if b.fileFormat != "" {
+		sql += " FILE_FORMAT = (" + b.fileFormat + ")"
+	}
+	if b.onError != "" {
+		sql += " ON_ERROR = " + b.onError
+	}
+	if b.matchByColumnName != "" {
+		sql += " MATCH_BY_COLUMN_NAME = " + b.matchByColumnName
+	}
+	if b.purge {
+		sql += " PURGE = TRUE"
+	}
+	if b.force {
+		sql += " FORCE = TRUE"
+	}
+
+	var results []CopyIntoResult
+	return results, queryInto(b.db, sql, []interface{}{b.table}, &results)
+}
+
+// fail records the first error of the arguments, returned by Exec
+func (b *CopyIntoBuilder) fail(err error) *CopyIntoBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
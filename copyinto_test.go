@@ -0,0 +1,75 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestCopyInto(t *testing.T) {
+	t.Run("Builds and scans the results", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, func(query string, args []interface{}) fakeResult {
+			return fakeResult{
+				columns: []string{"file", "status", "rows_parsed", "rows_loaded", "error_limit", "errors_seen",
+					"first_error", "first_error_line", "first_error_character", "first_error_column_name"},
+				rows: [][]driver.Value{
+					{"landing/orders/a.csv", "LOADED", int64(10), int64(10), int64(10), int64(0), nil, nil, nil, nil},
+					{"landing/orders/b.csv", "PARTIALLY_LOADED", int64(5), int64(4), int64(5), int64(1),
+						"Numeric value 'x' is not recognized", int64(3), int64(7), `"TEST_MODELS"["AGE":3]`},
+				},
+			}
+		})
+
+		results, err := CopyInto(db).Table(&TestModel{}).FromStage("@landing/orders/").Pattern(".*[.]csv").
+			FileFormat("TYPE = CSV SKIP_HEADER = 1").OnError("continue").Purge(true).Exec()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := `COPY INTO "test_models" FROM @landing/orders/ PATTERN = '.*[.]csv' FILE_FORMAT = (TYPE = CSV SKIP_HEADER = 1) ON_ERROR = CONTINUE PURGE = TRUE`
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+		if len(results) != 2 || results[0].RowsLoaded != 10 || results[0].FirstError != "" ||
+			results[1].Status != "PARTIALLY_LOADED" || results[1].ErrorsSeen != 1 || results[1].FirstErrorLine != 3 {
+			t.Errorf("Unexpected results %+v", results)
+		}
+	})
+
+	t.Run("Files of a table stage", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		stage, _ := TableStage(db, &TestModel{})
+		_, err := CopyInto(db).Table("raw_orders").FromStage(stage.Location()).Files("a.csv.gz", "b.csv.gz").
+			MatchByColumnName("case_insensitive").OnError("SKIP_FILE_10%").Force(true).Exec()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := "COPY INTO raw_orders FROM @%test_models FILES = ('a.csv.gz', 'b.csv.gz') ON_ERROR = SKIP_FILE_10% " +
+			"MATCH_BY_COLUMN_NAME = CASE_INSENSITIVE FORCE = TRUE"
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		for _, builder := range []*CopyIntoBuilder{
+			CopyInto(db).Table(&TestModel{}).FromStage("@landing; DROP TABLE orders"),
+			CopyInto(db).Table(&TestModel{}).FromStage("landing"),
+			CopyInto(db).Table(&TestModel{}).FromStage("@landing").OnError("IGNORE"),
+			CopyInto(db).Table(&TestModel{}).FromStage("@landing").MatchByColumnName("ANY"),
+			CopyInto(db).Table(&TestModel{}),
+			CopyInto(db).FromStage("@landing"),
+		} {
+			if _, err := builder.Exec(); !errors.Is(err, ErrInvalidCopy) {
+				t.Errorf("Expected ErrInvalidCopy, got %v", err)
+			}
+		}
+		if len(fake.statements) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+}
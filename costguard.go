@@ -0,0 +1,92 @@
+package snowflake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+)
+
+// SkipCostGuardKey disables the cost guard for a single statement:
+// db.Set(SkipCostGuardKey, true).Find(&rows)
+const SkipCostGuardKey = "snowflake:skip_cost_guard"
+
+// ErrEstimatedBytesExceeded is returned when the plan of a query scans more than Config.MaxEstimatedBytesScanned
+var ErrEstimatedBytesExceeded = errors.New("estimated bytes scanned exceeds limit")
+
+// explainPlan is the part of EXPLAIN USING JSON output used by the cost guard
+type explainPlan struct {
+	GlobalStats struct {
+		PartitionsTotal    int64 `json:"partitionsTotal"`
+		PartitionsAssigned int64 `json:"partitionsAssigned"`
+		BytesAssigned      int64 `json:"bytesAssigned"`
+	} `json:"GlobalStats"`
+}
+
+// CostGuard is registered before gorm:query and gorm:row when Config.MaxEstimatedBytesScanned is set.
+// It builds the statement, EXPLAINs it and refuses to run it when the bytes assigned to
+// the plan (after partition pruning) exceed the limit. Only SELECT/WITH statements are checked.
+func CostGuard(db *gorm.DB) {
+	config := dialectorConfig(db.Dialector)
+	if db.Error != nil || config == nil || config.MaxEstimatedBytesScanned <= 0 {
+		return
+	}
+	if skip, ok := db.Get(SkipCostGuardKey); ok && skip == true {
+		return
+	}
+
+	callbacks.BuildQuerySQL(db)
+	if db.Error != nil || db.DryRun || !isSelectStatement(db.Statement.SQL.String()) {
+		return
+	}
+
+	estimated, err := estimateBytesScanned(db.Statement.Context, db.Statement.ConnPool, db.Statement.SQL.String(), db.Statement.Vars...)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	if estimated > config.MaxEstimatedBytesScanned {
+		db.AddError(fmt.Errorf("%w: %d bytes estimated, limit is %d", ErrEstimatedBytesExceeded, estimated, config.MaxEstimatedBytesScanned))
+	}
+}
+
+// estimateBytesScanned returns the bytes assigned to the plan of query as reported by EXPLAIN USING JSON
+func estimateBytesScanned(ctx context.Context, pool gorm.ConnPool, query string, vars ...interface{}) (int64, error) {
+	rows, err := pool.QueryContext(ctx, "EXPLAIN USING JSON "+query, vars...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to explain query: %w", err)
+	}
+	defer rows.Close()
+
+	var content string
+	if rows.Next() {
+		if err := rows.Scan(&content); err != nil {
+			return 0, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var plan explainPlan
+	if err := json.Unmarshal([]byte(content), &plan); err != nil {
+		return 0, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+	return plan.GlobalStats.BytesAssigned, nil
+}
+
+// isSelectStatement reports whether query is a SELECT, possibly parenthesized or introduced by WITH
+func isSelectStatement(query string) bool {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	for _, keyword := range []string{"SELECT", "WITH"} {
+		if len(query) >= len(keyword) && strings.EqualFold(query[:len(keyword)], keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// explainHandler answers EXPLAIN statements with a plan assigning bytes
+func explainHandler(bytes string) func(query string, args []interface{}) fakeResult {
+	return func(query string, args []interface{}) fakeResult {
+		if strings.HasPrefix(query, "EXPLAIN USING JSON ") {
+			return fakeResult{
+				columns: []string{"content"},
+				rows:    [][]driver.Value{{`{"GlobalStats":{"partitionsTotal":10,"partitionsAssigned":10,"bytesAssigned":` + bytes + `},"Operations":[]}`}},
+			}
+		}
+		return fakeResult{}
+	}
+}
+
+func TestCostGuard(t *testing.T) {
+	t.Run("Rejects query above limit", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{MaxEstimatedBytesScanned: 1 << 30}, explainHandler("5000000000000"))
+
+		var models []TestModel
+		err := db.Where("age > ?", 18).Find(&models).Error
+		if !errors.Is(err, ErrEstimatedBytesExceeded) {
+			t.Fatalf("Expected ErrEstimatedBytesExceeded, got %v", err)
+		}
+
+		queries := fake.queries()
+		if len(queries) != 1 || !strings.HasPrefix(queries[0], "EXPLAIN USING JSON SELECT") {
+			t.Errorf("Expected only the EXPLAIN to run, got %v", queries)
+		}
+		if len(fake.statements[0].args) != 1 {
+			t.Errorf("Expected EXPLAIN to receive the query vars, got %v", fake.statements[0].args)
+		}
+	})
+
+	t.Run("Runs query under limit", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{MaxEstimatedBytesScanned: 1 << 30}, explainHandler("1024"))
+
+		var models []TestModel
+		if err := db.Find(&models).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		queries := fake.queries()
+		if len(queries) != 2 || !strings.HasPrefix(queries[1], "SELECT") {
+			t.Errorf("Expected EXPLAIN then SELECT, got %v", queries)
+		}
+	})
+
+	t.Run("Skip key bypasses guard", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{MaxEstimatedBytesScanned: 1}, explainHandler("1024"))
+
+		var models []TestModel
+		if err := db.Set(SkipCostGuardKey, true).Find(&models).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if hasQuery(fake.queries(), "EXPLAIN") {
+			t.Errorf("Expected no EXPLAIN, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, explainHandler("1024"))
+
+		var models []TestModel
+		if err := db.Find(&models).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if hasQuery(fake.queries(), "EXPLAIN") {
+			t.Errorf("Expected no EXPLAIN, got %v", fake.queries())
+		}
+	})
+}
+
+func TestIsSelectStatement(t *testing.T) {
+	tests := map[string]bool{
+		"SELECT * FROM users":                  true,
+		"  select 1":                           true,
+		"(SELECT 1) UNION (SELECT 2)":          true,
+		"WITH t AS (SELECT 1) SELECT * FROM t": true,
+		"SHOW TABLES":                          false,
+		"SEL":                                  false,
+		"":                                     false,
+	}
+
+	for query, expected := range tests {
+		if got := isSelectStatement(query); got != expected {
+			t.Errorf("isSelectStatement(%q): expected %v, got %v", query, expected, got)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package snowflake
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/callbacks"
@@ -12,6 +15,10 @@ import (
 )
 
 func Create(db *gorm.DB) {
+	if _, unchanged := db.InstanceGet(unchangedSaveKey); unchanged {
+		return
+	}
+
 	if db.Statement.Schema != nil && !db.Statement.Unscoped {
 		for _, c := range db.Statement.Schema.CreateClauses {
 			db.Statement.AddClause(c)
@@ -19,23 +26,36 @@ func Create(db *gorm.DB) {
 	}
 
 	if db.Statement.SQL.String() == "" {
+		// IDs taken from a sequence and insert batches are bound like the other values
+		if assignSequenceValues(db); db.Error != nil {
+			return
+		}
+		if assignInsertBatch(db); db.Error != nil {
+			return
+		}
+
 		var (
 			values                  = callbacks.ConvertToCreateValues(db.Statement)
 			c                       = db.Statement.Clauses["ON CONFLICT"]
 			onConflict, hasConflict = c.Expression.(clause.OnConflict)
+			err                     error
 		)
 
+		if hasConflict && onConflict.DoNothing && len(onConflict.Columns) == 0 {
+			onConflict.Columns = doNothingKeyColumns(db.Statement.Schema, values.Columns)
+		}
+
 		if hasConflict {
-			if len(db.Statement.Schema.PrimaryFields) > 0 {
+			if keys, _ := mergeKeyColumns(db.Statement, onConflict); len(keys) > 0 {
 				// Pre-allocate map with exact capacity
 				columnsMap := make(map[string]bool, len(values.Columns))
 				for _, column := range values.Columns {
 					columnsMap[column.Name] = true
 				}
 
-				// Early exit on first missing field
-				for _, field := range db.Statement.Schema.PrimaryFields {
-					if !columnsMap[field.DBName] {
+				// Early exit on first missing key column
+				for _, key := range keys {
+					if !keyWritten(db.Statement, key, columnsMap) {
 						hasConflict = false
 						break
 					}
@@ -45,176 +65,507 @@ func Create(db *gorm.DB) {
 			}
 		}
 
-		if hasConflict {
-			MergeCreate(db, onConflict, values)
-		} else {
-			db.Statement.AddClauseIfNotExists(clause.Insert{})
-			db.Statement.Build("INSERT")
-			db.Statement.WriteByte(' ')
-			db.Statement.AddClause(values)
-
-			if values, ok := db.Statement.Clauses["VALUES"].Expression.(clause.Values); ok {
-				columnCount := len(values.Columns)
-				if columnCount > 0 {
-					// Determine insertion method based on configuration
-					useUnionSelect := shouldUseUnionSelect(db)
-
-					if useUnionSelect {
-						buildUnionSelectInsert(db, values)
-					} else {
-						buildValuesInsert(db, values)
-					}
-				} else {
-					// only one autoincrement column
-					db.Statement.WriteString("VALUES (DEFAULT);")
-				}
+		var overwrite bool
+		if overwrite, err = prepareInsertModifier(db); err != nil {
+			db.AddError(err)
+			return
+		} else if overwrite && hasConflict {
+			db.AddError(fmt.Errorf("%w: OVERWRITE with an upsert", ErrUnsupportedInsertModifier))
+			return
+		} else if overwrite && db.CreateBatchSize > 0 {
+			// gorm creates every batch of CreateBatchSize with its own statement, each would truncate the table
+			db.AddError(fmt.Errorf("%w: OVERWRITE with CreateBatchSize", ErrUnsupportedInsertModifier))
+			return
+		}
+
+		// tokenized values are SQL expressions, so they are neither bulk loaded nor merged via a temporary table
+		if values, err = bindTokenizedValues(db, values); err != nil {
+			db.AddError(err)
+			return
+		}
+
+		// COPY INTO cannot overwrite the table
+		if !hasConflict && !overwrite && bulkLoadable(db, values) {
+			if db.Error == nil {
+				db.RowsAffected = 0
+				bulkLoad(db, values)
 			}
+			return
+		}
+
+		if hasConflict && mergesViaTempTable(db, values) {
+			db.RowsAffected = 0
+			mergeViaTempTable(db, values, onConflict)
+			return
+		}
+
+		if values, err = bindSemiStructuredValues(db, values); err != nil {
+			db.AddError(err)
+			return
+		}
+
+		if batches := splitCreateValues(db, values); len(batches) > 1 {
+			createInBatches(db, batches, onConflict, hasConflict)
+			return
 		}
+
+		buildCreateSQL(db, values, onConflict, hasConflict)
 	}
 
 	if !db.DryRun && db.Error == nil {
 		db.RowsAffected = 0
+		execCreate(db)
+	}
+}
 
-		// exec the merge/insert first
-		if result, err := db.Statement.ConnPool.ExecContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...); err == nil {
-			db.RowsAffected, _ = result.RowsAffected()
-		} else {
-			_ = db.AddError(err)
+// buildCreateSQL builds the MERGE statement for upserts, or the INSERT statement otherwise
+func buildCreateSQL(db *gorm.DB, values clause.Values, onConflict clause.OnConflict, hasConflict bool) {
+	if hasConflict {
+		MergeCreate(db, onConflict, values)
+	} else {
+		db.Statement.AddClauseIfNotExists(clause.Insert{})
+		db.Statement.Build("INSERT")
+		db.Statement.WriteByte(' ')
+		db.Statement.AddClause(values)
+
+		if values, ok := db.Statement.Clauses["VALUES"].Expression.(clause.Values); ok {
+			columnCount := len(values.Columns)
+			if columnCount > 0 {
+				// Determine insertion method based on the model and configuration,
+				// PARSE_JSON of semi-structured values and tokenize functions are not allowed in a VALUES clause
+				mode, err := insertMode(db)
+				if err != nil {
+					db.AddError(err)
+					return
+				}
+				if hasSemiStructuredColumns(db, values.Columns) || hasTokenizedColumns(db, values.Columns) ||
+					(mode == InsertArrayBind && hasExpressionValues(values)) {
+					mode = InsertUnionSelect
+				}
+
+				switch mode {
+				case InsertUnionSelect:
+					buildUnionSelectInsert(db, values)
+				case InsertArrayBind:
+					buildArrayBindInsert(db, values)
+				default:
+					buildValuesInsert(db, values)
+				}
+			} else {
+				// only one autoincrement column
+				db.Statement.WriteString("VALUES (DEFAULT)")
+				writeStatementEnd(db)
+			}
+		}
+	}
+}
+
+// splitCreateValues splits values into batches of CreateBatchSize rows, also bounded by the
+// estimated size of Config.MaxStatementBytes.
+// It returns nil when no split is needed or when rows cannot be matched back to
+// db.Statement.ReflectValue, DryRun statements are never split so their SQL stays complete
+func splitCreateValues(db *gorm.DB, values clause.Values) []clause.Values {
+	batchSize := db.CreateBatchSize
+	maxBytes := 0
+	if config := dialectorConfig(db.Dialector); config != nil {
+		maxBytes = config.MaxStatementBytes
+	}
+	if ((batchSize <= 0 || len(values.Values) <= batchSize) && maxBytes <= 0) || len(values.Values) < 2 || db.DryRun {
+		return nil
+	}
+
+	if reflectValue := db.Statement.ReflectValue; reflectValue.Kind() != reflect.Slice || reflectValue.Len() != len(values.Values) {
+		return nil
+	}
+
+	var (
+		batches      []clause.Values
+		start, bytes int
+	)
+	for idx, row := range values.Values {
+		rowBytes := 0
+		if maxBytes > 0 {
+			rowBytes = estimateRowBytes(row)
 		}
 
-		db.Logger.Info(db.Statement.Context, fmt.Sprintf("This is the result of insert %s, values %v, rows affected %d", db.Statement.SQL.String(), db.Statement.Vars, db.RowsAffected))
+		// a batch holds at least one row, even if it exceeds MaxStatementBytes alone
+		if idx > start && ((batchSize > 0 && idx-start >= batchSize) || (maxBytes > 0 && bytes+rowBytes > maxBytes)) {
+			batches = append(batches, clause.Values{Columns: values.Columns, Values: values.Values[start:idx]})
+			start, bytes = idx, 0
+		}
+		bytes += rowBytes
+	}
 
-		// do another select on last inserted values to populate default values (e.g. ID)
-		// this relies on the result of SELECT * FROM CHANGES to align with the order of the VALUES in MERGE statement
-		if sch := db.Statement.Schema; sch != nil && len(sch.FieldsWithDefaultDBValue) > 0 {
-			fieldCount := len(sch.FieldsWithDefaultDBValue)
-			fields := make([]*schema.Field, fieldCount)
-			values := make([]interface{}, fieldCount)
+	if len(batches) == 0 {
+		return nil
+	}
+	return append(batches, clause.Values{Columns: values.Columns, Values: values.Values[start:]})
+}
 
-			db.Statement.SQL.Reset()
+// createInBatches builds and executes one statement per batch, default values are fetched
+// after each statement against the rows of that batch only
+func createInBatches(db *gorm.DB, batches []clause.Values, onConflict clause.OnConflict, hasConflict bool) {
+	reflectValue := db.Statement.ReflectValue
+	defer func() { db.Statement.ReflectValue = reflectValue }()
+
+	db.RowsAffected = 0
+	start := 0
+	for idx, batch := range batches {
+		db.Statement.SQL.Reset()
+		db.Statement.Vars = nil
+		if idx > 0 {
+			// only the first batch truncates the table of an INSERT OVERWRITE
+			withoutInsertOverwrite(db)
+		}
 
-			// Pre-allocate query builder capacity
-			estimatedQuerySize := 7 + (fieldCount * 25) + len(sch.Table) + 80
-			db.Statement.SQL.Grow(estimatedQuerySize)
+		buildCreateSQL(db, batch, onConflict, hasConflict)
+		if db.Error != nil {
+			return
+		}
 
-			// write select
-			db.Statement.WriteString("SELECT ")
-			// populate fields
-			for idx, field := range sch.FieldsWithDefaultDBValue {
-				if idx > 0 {
-					db.Statement.WriteByte(',')
-				}
+		end := start + len(batch.Values)
+		db.Statement.ReflectValue = reflectValue.Slice(start, end)
+		execCreate(db)
+		if db.Error != nil {
+			return
+		}
+		start = end
+	}
+}
 
-				fields[idx] = field
-				db.Statement.WriteQuoted(field.DBName)
-			}
-			db.Statement.WriteString(" FROM ")
-			db.Statement.WriteQuoted(sch.Table)
-			db.Statement.WriteString(" CHANGES(INFORMATION => APPEND_ONLY) BEFORE(statement=>LAST_QUERY_ID());")
+// execCreate executes the merge/insert built in db.Statement, then populates the default values
+// of the inserted rows on the same connection, CHANGES reads the statement of LAST_QUERY_ID().
+// RowsAffected is accumulated so a Create issuing several statements reports the total of all
+// of them rather than the last one
+func execCreate(db *gorm.DB) {
+	if fetcher, _ := defaultValueFetcherOf(db); fetcher != nil && !pinnedConnPool(db.Statement.ConnPool) {
+		db.AddError(withPinnedConnPool(db, func() error {
+			execCreate(db)
+			return nil
+		}))
+		return
+	}
 
-			rows, err := db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
-			if err != nil {
-				db.AddError(err)
-				return
-			}
-			defer rows.Close()
-
-			reflectValue := db.Statement.ReflectValue
-			reflectKind := reflectValue.Kind()
-
-			switch reflectKind {
-			case reflect.Slice, reflect.Array:
-				reflectIndex := 0
-				maxLen := reflectValue.Len()
-
-				// the strategy here is to match the returned rows with INSERT only values
-				for rows.Next() && reflectIndex < maxLen {
-					// Find next valid struct for insertion
-					for reflectIndex < maxLen {
-						currentValue := reflectValue.Index(reflectIndex)
-						if reflect.Indirect(currentValue).Kind() != reflect.Struct {
-							break
-						}
-
-						// Check if this row has zero defaults (indicates INSERT operation)
-						hasNonZeroDefaults := false
-						for _, field := range fields {
-							fieldValue := field.ReflectValueOf(db.Statement.Context, currentValue)
-							if !fieldValue.IsZero() {
-								hasNonZeroDefaults = true
-								break
-							}
-						}
-
-						if hasNonZeroDefaults {
-							// Skip this row, move to next record
-							reflectIndex++
-							if reflectIndex >= maxLen {
-								return
-							}
-							continue
-						}
-
-						// Found a valid INSERT row - populate interface slice for scanning
-						for idx, field := range fields {
-							fieldValue := field.ReflectValueOf(db.Statement.Context, currentValue)
-							values[idx] = fieldValue.Addr().Interface()
-						}
-
-						if err := rows.Scan(values...); err != nil {
-							db.AddError(err)
-						}
-						reflectIndex++
+	if snapshotID(db); db.Error != nil {
+		return
+	}
+
+	// exec the merge/insert first, bypassing the statement cache of PrepareStmt sessions
+	if result, err := unpreparedConnPool(db.Statement.ConnPool).ExecContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...); err == nil {
+		rowsAffected, _ := result.RowsAffected()
+		db.RowsAffected += rowsAffected
+	} else {
+		_ = db.AddError(err)
+	}
+
+	populateDefaultValues(db)
+}
+
+// populateDefaultValues populates the default values (e.g. ID) of the rows inserted by the
+// last statement, with Config.DefaultValueFetcher, fetchDefaultValuesFromSnapshot or
+// fetchDefaultValuesFromChanges
+func populateDefaultValues(db *gorm.DB) {
+	if fetcher, _ := defaultValueFetcherOf(db); fetcher != nil {
+		if err := fetcher(db, db.Statement.Schema.FieldsWithDefaultDBValue); err != nil {
+			db.AddError(err)
+		}
+	}
+}
+
+// defaultValueFetcherOf returns the fetcher of the default values of the rows created by db, nil
+// if they are not read back. changes reports whether it is fetchDefaultValuesFromChanges
+func defaultValueFetcherOf(db *gorm.DB) (fetcher func(db *gorm.DB, fields []*schema.Field) error, changes bool) {
+	if sch := db.Statement.Schema; sch == nil || len(sch.FieldsWithDefaultDBValue) == 0 {
+		return nil, false
+	}
+
+	if config := dialectorConfig(db.Dialector); config != nil && config.DefaultValueFetcher != nil {
+		return config.DefaultValueFetcher, false
+	} else if config != nil && (config.DefaultValueStrategy == DefaultValueSequence || config.ClientSideIDs) {
+		// the IDs were bound, CHANGES is not available without change tracking
+		return nil, false
+	} else if config != nil && config.DefaultValueStrategy == DefaultValueSnapshot {
+		return fetchDefaultValuesFromSnapshot, false
+	}
+	return fetchDefaultValuesFromChanges, true
+}
+
+// writeChangesQuery writes the SELECT of fields from the rows appended to the table of db by
+// the last statement
+func writeChangesQuery(db *gorm.DB, fields []*schema.Field) {
+	sch := db.Statement.Schema
+
+	// Pre-allocate query builder capacity
+	estimatedQuerySize := 7 + (len(fields) * 25) + len(sch.Table) + 80
+	db.Statement.SQL.Grow(estimatedQuerySize)
+
+	// write select
+	db.Statement.WriteString("SELECT ")
+	// populate fields
+	for idx, field := range fields {
+		if idx > 0 {
+			db.Statement.WriteByte(',')
+		}
+		db.Statement.WriteQuoted(field.DBName)
+	}
+	db.Statement.WriteString(" FROM ")
+	db.Statement.WriteQuoted(sch.Table)
+	db.Statement.WriteString(" CHANGES(INFORMATION => APPEND_ONLY) BEFORE(statement=>LAST_QUERY_ID())")
+	writeStatementEnd(db)
+}
+
+// fetchDefaultValuesFromChanges is the default DefaultValueFetcher, it does another select on last
+// inserted values to populate default values (e.g. ID).
+// This relies on the result of SELECT * FROM CHANGES to align with the order of the VALUES in MERGE statement
+func fetchDefaultValuesFromChanges(db *gorm.DB, fields []*schema.Field) error {
+	db.Statement.SQL.Reset()
+	writeChangesQuery(db, fields)
+
+	// the CHANGES query has no placeholders, the vars of the insert must not be re-bound
+	rows, err := db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	scanDefaultValues(db, rows, fields)
+	return nil
+}
+
+// scanDefaultValues scans the default values of fields read back after an insert into the rows
+// of db.Statement.ReflectValue, in insert order. Rows with non-zero defaults were provided by the
+// caller and are skipped
+func scanDefaultValues(db *gorm.DB, rows *sql.Rows, fields []*schema.Field) {
+	var (
+		fieldCount   = len(fields)
+		values       = make([]interface{}, fieldCount)
+		ctx          = db.Statement.Context
+		reflectValue = db.Statement.ReflectValue
+		accessors    = make([]fieldAccessor, fieldCount)
+	)
+	for idx, field := range fields {
+		accessors[idx] = fieldAccessorOf(field)
+	}
+
+	switch reflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		elemType := reflectValue.Type().Elem()
+		for elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() != reflect.Struct {
+			return
+		}
+
+		var (
+			reflectIndex = 0
+			maxLen       = reflectValue.Len()
+			fieldValues  = make([]reflect.Value, fieldCount)
+		)
+
+		// the strategy here is to match the returned rows with INSERT only values,
+		// rows with non-zero defaults were provided by the caller and are skipped
+		for rows.Next() && reflectIndex < maxLen {
+			for ; reflectIndex < maxLen; reflectIndex++ {
+				currentValue := reflectValue.Index(reflectIndex)
+
+				hasNonZeroDefaults := false
+				for idx, accessor := range accessors {
+					fieldValues[idx] = accessor(ctx, currentValue)
+					if !fieldValues[idx].IsZero() {
+						hasNonZeroDefaults = true
 						break
 					}
 				}
-			case reflect.Struct:
-				for idx, field := range fields {
-					values[idx] = field.ReflectValueOf(db.Statement.Context, reflectValue).Addr().Interface()
+				if !hasNonZeroDefaults {
+					break
 				}
+			}
+			if reflectIndex >= maxLen {
+				return
+			}
 
-				if rows.Next() {
-					if err := rows.Scan(values...); err != nil {
-						db.AddError(err)
-					}
-				}
+			// Found a valid INSERT row - populate interface slice for scanning
+			for idx, fieldValue := range fieldValues {
+				values[idx] = fieldValue.Addr().Interface()
+			}
+
+			if err := rows.Scan(values...); err != nil {
+				db.AddError(err)
+			}
+			reflectIndex++
+		}
+	case reflect.Struct:
+		for idx, accessor := range accessors {
+			values[idx] = accessor(ctx, reflectValue).Addr().Interface()
+		}
+
+		if rows.Next() {
+			if err := rows.Scan(values...); err != nil {
+				db.AddError(err)
 			}
 		}
 	}
 }
 
+// fieldAccessor returns the addressable value of a field in a struct (or pointer to struct) value
+type fieldAccessor func(ctx context.Context, value reflect.Value) reflect.Value
+
+// fieldAccessors caches the fieldAccessor of schema fields, schemas being cached by gorm
+var fieldAccessors sync.Map
+
+// fieldAccessorOf returns the cached fieldAccessor of field. Fields declared directly on the
+// model are read through their index, without going through the generic accessor of gorm,
+// embedded fields keep it as it allocates nil embedded pointers
+func fieldAccessorOf(field *schema.Field) fieldAccessor {
+	if accessor, ok := fieldAccessors.Load(field); ok {
+		return accessor.(fieldAccessor)
+	}
+
+	var accessor fieldAccessor = field.ReflectValueOf
+	if index := field.StructField.Index; len(index) == 1 {
+		fieldIndex := index[0]
+		accessor = func(_ context.Context, value reflect.Value) reflect.Value {
+			for value.Kind() == reflect.Ptr {
+				value = value.Elem()
+			}
+			return value.Field(fieldIndex)
+		}
+	}
+
+	fieldAccessors.Store(field, accessor)
+	return accessor
+}
+
 func MergeCreate(db *gorm.DB, onConflict clause.OnConflict, values clause.Values) {
 	// Transform any column references in DoUpdates to EXCLUDED.column format upfront
 	// This prevents GORM from incorrectly quoting "excluded" as a table reference
 	onConflict = prepareOnConflictForMerge(db, onConflict)
 
-	valueCount := len(values.Values)
 	columnCount := len(values.Columns)
-	primaryFieldCount := len(db.Statement.Schema.PrimaryFields)
+	primaryFieldCount := len(onConflict.Columns)
+	if db.Statement.Schema != nil {
+		primaryFieldCount = len(db.Statement.Schema.PrimaryFields)
+	}
 
-	// Pre-allocate statement capacity for better performance
+	// Pre-allocate statement capacity for everything but the rows, written at once
 	estimatedSize := 100 + len(db.Statement.Table)*2 +
-		(valueCount * columnCount * 3) + // VALUES content
 		(columnCount * 25) + // column names
 		(primaryFieldCount * 50) // WHERE conditions
-	db.Statement.SQL.Grow(estimatedSize)
 
-	db.Statement.WriteString("MERGE INTO ")
-	db.Statement.WriteQuoted(db.Statement.Table)
-	db.Statement.WriteString(" USING (VALUES")
+	// SQL expressions (e.g. CURRENT_TIMESTAMP()), PARSE_JSON of semi-structured values and tokenize
+	// functions are not allowed in a VALUES clause, rows are selected instead
+	source, prefix, separator, suffix := " USING (VALUES", "(", ",", ")"
+	if hasExpressionValues(values) || hasSemiStructuredColumns(db, values.Columns) || hasTokenizedColumns(db, values.Columns) {
+		source, prefix, separator, suffix = " USING (SELECT ", "", " UNION ALL SELECT ", ""
+	}
 
-	for idx, value := range values.Values {
-		if idx > 0 {
-			db.Statement.WriteByte(',')
-		}
+	// the rows of DoNothing upserts are deduplicated, a MERGE inserts every unmatched source row
+	if onConflict.DoNothing {
+		source = " USING (SELECT * FROM (" + strings.TrimPrefix(source, " USING (")
+	}
 
-		db.Statement.WriteByte('(')
-		db.Statement.AddVar(db.Statement, value...)
+	writeRowsTo(db, values.Values, prefix, separator, suffix, estimatedSize, func() {
+		db.Statement.WriteString("MERGE INTO ")
+		db.Statement.WriteQuoted(db.Statement.Table)
+		db.Statement.WriteString(source)
+	})
+
+	db.Statement.WriteByte(')')
+	if onConflict.DoNothing {
+		db.Statement.WriteString(" AS SOURCE (")
+		for idx, column := range values.Columns {
+			if idx > 0 {
+				db.Statement.WriteByte(',')
+			}
+			db.Statement.WriteQuoted(column.Name)
+		}
 		db.Statement.WriteByte(')')
+		writeDistinctKeysQualify(db, onConflict)
+		db.Statement.WriteByte(')')
+	}
+	writeMergeClauses(db, onConflict, values.Columns)
+}
+
+// writeDistinctKeysQualify writes the QUALIFY clause keeping a single source row per key of
+// the upsert
+func writeDistinctKeysQualify(db *gorm.DB, onConflict clause.OnConflict) {
+	keys, _ := mergeKeyColumns(db.Statement, onConflict)
+	writeKeys := func() {
+		for idx, key := range keys {
+			if idx > 0 {
+				db.Statement.WriteByte(',')
+			}
+			// key expressions read the single source, they need no qualifier
+			db.Statement.WriteQuoted(key)
+		}
+	}
+
+	db.Statement.WriteString(" QUALIFY ROW_NUMBER() OVER (PARTITION BY ")
+	writeKeys()
+	db.Statement.WriteString(" ORDER BY ")
+	writeKeys()
+	db.Statement.WriteString(") = 1")
+}
+
+// doNothingKeyColumns returns the columns of the first unique key of sch whose columns are all
+// among columns, for DoNothing upserts without conflict columns that do not write the primary
+// key (e.g. auto increment IDs), nil when the primary key is written, there is no such key or no
+// schema, e.g. for creates of maps into a table
+func doNothingKeyColumns(sch *schema.Schema, columns []clause.Column) []clause.Column {
+	if sch == nil {
+		return nil
+	}
+
+	written := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		written[column.Name] = true
+	}
+
+	covers := func(fields []*schema.Field) bool {
+		for _, field := range fields {
+			if field == nil || !written[field.DBName] {
+				return false
+			}
+		}
+		return len(fields) > 0
+	}
+	keyColumns := func(fields []*schema.Field) []clause.Column {
+		keys := make([]clause.Column, len(fields))
+		for idx, field := range fields {
+			keys[idx] = clause.Column{Name: field.DBName}
+		}
+		return keys
+	}
+
+	if covers(sch.PrimaryFields) {
+		return nil
 	}
+	for _, field := range sch.Fields {
+		if field.Unique && covers([]*schema.Field{field}) {
+			return keyColumns([]*schema.Field{field})
+		}
+	}
+	for _, index := range sch.ParseIndexes() {
+		if index.Class != "UNIQUE" {
+			continue
+		}
+		fields := make([]*schema.Field, len(index.Fields))
+		for idx, option := range index.Fields {
+			fields[idx] = option.Field
+		}
+		if covers(fields) {
+			return keyColumns(fields)
+		}
+	}
+	return nil
+}
 
-	db.Statement.WriteString(") AS EXCLUDED (")
-	for idx, column := range values.Columns {
+// writeMergeClauses writes the end of a MERGE statement after its source: the EXCLUDED alias of
+// the source columns, the match on the conflict columns and the WHEN MATCHED/NOT MATCHED actions
+func writeMergeClauses(db *gorm.DB, onConflict clause.OnConflict, columns []clause.Column) {
+	db.Statement.WriteString(" AS EXCLUDED (")
+	for idx, column := range columns {
 		if idx > 0 {
 			db.Statement.WriteByte(',')
 		}
@@ -222,29 +573,75 @@ func MergeCreate(db *gorm.DB, onConflict clause.OnConflict, values clause.Values
 	}
 	db.Statement.WriteString(") ON ")
 
-	// Build ON clause with proper quoting based on QuoteFields setting
-	for i, field := range db.Statement.Schema.PrimaryFields {
+	// Build ON clause with proper quoting based on QuoteFields setting, EQUAL_NULL matches
+	// NULL keys with NullSafeMergeJoin, and always for pointer keys which may be nil
+	config := dialectorConfig(db.Dialector)
+	keys, keyFields := mergeKeyColumns(db.Statement, onConflict)
+	for i, key := range keys {
 		if i > 0 {
 			db.Statement.WriteString(" AND ")
 		}
-		db.Statement.WriteQuoted(db.Statement.Table)
-		db.Statement.WriteByte('.')
-		db.Statement.WriteQuoted(field.DBName)
-		db.Statement.WriteString(" = EXCLUDED.")
-		db.Statement.WriteQuoted(field.DBName)
+		field := keyFields[i]
+		nullSafe := (config != nil && config.NullSafeMergeJoin) || (field != nil && field.FieldType.Kind() == reflect.Ptr && !field.NotNull)
+		if nullSafe {
+			db.Statement.WriteString("EQUAL_NULL(")
+		}
+		if key.Raw {
+			db.Statement.WriteString(qualifyKeyExpr(db.Statement, key.Name, db.Statement.Quote(db.Statement.Table)+"."))
+		} else {
+			db.Statement.WriteQuoted(db.Statement.Table)
+			db.Statement.WriteByte('.')
+			db.Statement.WriteQuoted(key)
+		}
+		if nullSafe {
+			db.Statement.WriteByte(',')
+		} else {
+			db.Statement.WriteString(" = ")
+		}
+		if key.Raw {
+			db.Statement.WriteString(qualifyKeyExpr(db.Statement, key.Name, "EXCLUDED."))
+		} else {
+			db.Statement.WriteString("EXCLUDED.")
+			db.Statement.WriteQuoted(key)
+		}
+		if nullSafe {
+			db.Statement.WriteByte(')')
+		}
+	}
+
+	deletes, _ := db.Statement.Clauses[upsertDeleteClauseName].Expression.(upsertDelete)
+	if len(deletes.Exprs) > 0 {
+		db.Statement.WriteString(" WHEN MATCHED AND (")
+		clause.Where{Exprs: deletes.Exprs}.Build(db.Statement)
+		db.Statement.WriteString(") THEN DELETE")
 	}
 
 	if len(onConflict.DoUpdates) > 0 {
-		db.Statement.WriteString(" WHEN MATCHED THEN UPDATE SET ")
+		db.Statement.WriteString(" WHEN MATCHED")
+		writeMatchedCondition(db, onConflict)
+		if config != nil && config.SkipNoopUpdates {
+			writeDistinctCondition(db, onConflict.DoUpdates)
+		}
+		db.Statement.WriteString(" THEN UPDATE SET ")
 		onConflict.DoUpdates.Build(db.Statement)
 	}
 
-	db.Statement.WriteString(" WHEN NOT MATCHED THEN INSERT (")
+	db.Statement.WriteString(" WHEN NOT MATCHED")
+	if len(deletes.Exprs) > 0 {
+		// tombstones of rows that do not exist are not inserted, a NULL condition does not hold
+		db.Statement.WriteString(" AND NOT COALESCE((")
+		clause.Where{Exprs: deletes.Exprs}.Build(db.Statement)
+		db.Statement.WriteString("),FALSE)")
+	}
+	db.Statement.WriteString(" THEN INSERT (")
 
 	// Cache auto-increment field check
-	autoIncrementField := db.Statement.Schema.PrioritizedPrimaryField
+	var autoIncrementField *schema.Field
+	if db.Statement.Schema != nil {
+		autoIncrementField = db.Statement.Schema.PrioritizedPrimaryField
+	}
 	written := false
-	for _, column := range values.Columns {
+	for _, column := range columns {
 		if autoIncrementField == nil || !autoIncrementField.AutoIncrement || autoIncrementField.DBName != column.Name {
 			if written {
 				db.Statement.WriteByte(',')
@@ -257,7 +654,7 @@ func MergeCreate(db *gorm.DB, onConflict clause.OnConflict, values clause.Values
 	db.Statement.WriteString(") VALUES (")
 
 	written = false
-	for _, column := range values.Columns {
+	for _, column := range columns {
 		if autoIncrementField == nil || !autoIncrementField.AutoIncrement || autoIncrementField.DBName != column.Name {
 			if written {
 				db.Statement.WriteByte(',')
@@ -270,21 +667,101 @@ func MergeCreate(db *gorm.DB, onConflict clause.OnConflict, values clause.Values
 	}
 
 	db.Statement.WriteString(")")
-	db.Statement.WriteString(";")
+	writeStatementEnd(db)
+}
+
+// mergeKeyColumns returns the columns matching the rows of an upsert with the existing rows, with
+// their field (nil for columns without one): the columns of the OnConflict clause, e.g. a unique
+// key, or the primary key. Raw columns are SQL expressions of the columns of the table, e.g.
+// clause.Column{Name: "LOWER(email)", Raw: true} for case-insensitive keys. Statements without a
+// schema, e.g. creates of maps into a table, only match on the OnConflict columns
+func mergeKeyColumns(stmt *gorm.Statement, onConflict clause.OnConflict) ([]clause.Column, []*schema.Field) {
+	if len(onConflict.Columns) == 0 {
+		if stmt.Schema == nil {
+			return nil, nil
+		}
+		keys := make([]clause.Column, len(stmt.Schema.PrimaryFields))
+		for idx, field := range stmt.Schema.PrimaryFields {
+			keys[idx] = clause.Column{Name: field.DBName}
+		}
+		return keys, stmt.Schema.PrimaryFields
+	}
+
+	keys := make([]clause.Column, len(onConflict.Columns))
+	fields := make([]*schema.Field, len(onConflict.Columns))
+	for idx, column := range onConflict.Columns {
+		keys[idx] = clause.Column{Name: column.Name, Raw: column.Raw}
+		if column.Raw || stmt.Schema == nil {
+			continue
+		}
+		if field := stmt.Schema.LookUpField(column.Name); field != nil {
+			keys[idx].Name, fields[idx] = field.DBName, field
+		}
+	}
+	return keys, fields
+}
+
+// writeMatchedCondition writes the WHEN MATCHED predicate of the conditions of an upsert,
+// OnConflict.TargetWhere and OnConflict.Where, so matched rows are only updated when they hold.
+// The incoming row is referenced as EXCLUDED:
+//
+//	db.Clauses(clause.OnConflict{
+//		Columns:   []clause.Column{{Name: "id"}},
+//		DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+//		Where: clause.Where{Exprs: []clause.Expression{
+//			clause.Expr{SQL: `EXCLUDED."updated_at" > "events"."updated_at"`},
+//		}},
+//	}).Create(&events)
+func writeMatchedCondition(db *gorm.DB, onConflict clause.OnConflict) {
+	exprs := make([]clause.Expression, 0, len(onConflict.TargetWhere.Exprs)+len(onConflict.Where.Exprs))
+	exprs = append(exprs, onConflict.TargetWhere.Exprs...)
+	exprs = append(exprs, onConflict.Where.Exprs...)
+	if len(exprs) == 0 {
+		return
+	}
+
+	db.Statement.WriteString(" AND (")
+	clause.Where{Exprs: exprs}.Build(db.Statement)
+	db.Statement.WriteByte(')')
+}
+
+// writeDistinctCondition writes the WHEN MATCHED predicate that skips rows where
+// none of the updated columns would change
+func writeDistinctCondition(db *gorm.DB, updates clause.Set) {
+	db.Statement.WriteString(" AND (")
+	for idx, assignment := range updates {
+		if idx > 0 {
+			db.Statement.WriteString(" OR ")
+		}
+		db.Statement.WriteQuoted(db.Statement.Table)
+		db.Statement.WriteByte('.')
+		db.Statement.WriteQuoted(assignment.Column.Name)
+		db.Statement.WriteString(" IS DISTINCT FROM ")
+		db.Statement.AddVar(db.Statement, assignment.Value)
+	}
+	db.Statement.WriteByte(')')
 }
 
 // prepareOnConflictForMerge prepares the OnConflict clause for use in MERGE statements
 // It converts column references to raw SQL expressions to prevent incorrect quoting
 // GORM doesn't support unquoted table-qualified columns, so we use clause.Expr
 func prepareOnConflictForMerge(db *gorm.DB, onConflict clause.OnConflict) clause.OnConflict {
+	onConflict.DoUpdates = omitUpsertColumns(db, onConflict.DoUpdates)
 	if len(onConflict.DoUpdates) == 0 {
 		return onConflict
 	}
 
 	// Check if we should quote fields
-	shouldQuote := false
+	shouldQuote, ignoreCase := false, false
 	if dialector, ok := db.Dialector.(*Dialector); ok && dialector.Config != nil {
 		shouldQuote = dialector.Config.QuoteFields
+		ignoreCase = dialector.Config.QuotedIdentifiersIgnoreCase
+	}
+	quote := func(name string) string {
+		if ignoreCase {
+			name = strings.ToUpper(name)
+		}
+		return quoteIdentifier(name)
 	}
 
 	// Create a new Set with converted assignments
@@ -308,11 +785,11 @@ func prepareOnConflictForMerge(db *gorm.DB, onConflict clause.OnConflict) clause
 
 				if shouldQuote {
 					transformed[i].Value = clause.Expr{
-						SQL: fmt.Sprintf(`EXCLUDED."%s"`, columnPart),
+						SQL: `EXCLUDED.` + quote(columnPart),
 					}
 				} else {
 					transformed[i].Value = clause.Expr{
-						SQL: fmt.Sprintf(`EXCLUDED.%s`, columnPart),
+						SQL: `EXCLUDED.` + cleanIdentifier(columnPart),
 					}
 				}
 				continue
@@ -321,11 +798,11 @@ func prepareOnConflictForMerge(db *gorm.DB, onConflict clause.OnConflict) clause
 			// Normal case: simple column name, wrap with EXCLUDED prefix
 			if shouldQuote {
 				transformed[i].Value = clause.Expr{
-					SQL: fmt.Sprintf(`EXCLUDED."%s"`, colName),
+					SQL: `EXCLUDED.` + quote(colName),
 				}
 			} else {
 				transformed[i].Value = clause.Expr{
-					SQL: fmt.Sprintf(`EXCLUDED.%s`, colName),
+					SQL: `EXCLUDED.` + cleanIdentifier(colName),
 				}
 			}
 		}
@@ -336,6 +813,117 @@ func prepareOnConflictForMerge(db *gorm.DB, onConflict clause.OnConflict) clause
 	return onConflict
 }
 
+// upsertOmitClauseName is the statement clause holding the columns excluded from MERGE updates
+const upsertOmitClauseName = "SNOWFLAKE_UPSERT_OMIT"
+
+// upsertOmit lists columns that must never be updated by an upsert, it is never rendered
+type upsertOmit struct {
+	Columns []string
+}
+
+// UpsertOmit excludes columns (e.g. immutable ones like external_id) from the
+// WHEN MATCHED THEN UPDATE SET list of upserts, whatever the OnConflict clause updates:
+//
+//	db.Clauses(clause.OnConflict{UpdateAll: true}, snowflake.UpsertOmit("external_id")).Create(&rows)
+func UpsertOmit(columns ...string) clause.Interface {
+	return upsertOmit{Columns: columns}
+}
+
+func (upsertOmit) Name() string {
+	return upsertOmitClauseName
+}
+
+func (upsertOmit) Build(clause.Builder) {}
+
+func (omit upsertOmit) MergeClause(c *clause.Clause) {
+	if existing, ok := c.Expression.(upsertOmit); ok {
+		omit.Columns = append(append([]string{}, existing.Columns...), omit.Columns...)
+	}
+	c.Expression = omit
+}
+
+// upsertDeleteClauseName is the statement clause holding the conditions of the rows deleted by
+// an upsert
+const upsertDeleteClauseName = "SNOWFLAKE_UPSERT_DELETE"
+
+// upsertDelete holds the conditions of the incoming rows that delete the row they match, it is
+// rendered by MERGE statements only
+type upsertDelete struct {
+	Exprs []clause.Expression
+}
+
+// UpsertDelete makes upserts delete the existing rows matched by incoming rows satisfying the
+// conditions (e.g. tombstones of CDC streams) with a WHEN MATCHED AND <conditions> THEN DELETE
+// clause, ahead of the update. Such rows are not inserted when nothing matches them, so the
+// conditions can only reference the incoming row, as EXCLUDED:
+//
+//	db.Clauses(
+//		clause.OnConflict{UpdateAll: true},
+//		snowflake.UpsertDelete(clause.Expr{SQL: `EXCLUDED."deleted" = TRUE`}),
+//	).Create(&changes)
+func UpsertDelete(conditions ...clause.Expression) clause.Interface {
+	return upsertDelete{Exprs: conditions}
+}
+
+func (upsertDelete) Name() string {
+	return upsertDeleteClauseName
+}
+
+func (upsertDelete) Build(clause.Builder) {}
+
+func (del upsertDelete) MergeClause(c *clause.Clause) {
+	if existing, ok := c.Expression.(upsertDelete); ok {
+		del.Exprs = append(append([]clause.Expression{}, existing.Exprs...), del.Exprs...)
+	}
+	c.Expression = del
+}
+
+// omitUpsertColumns removes the assignments of columns registered with UpsertOmit
+func omitUpsertColumns(db *gorm.DB, updates clause.Set) clause.Set {
+	omit, ok := db.Statement.Clauses[upsertOmitClauseName].Expression.(upsertOmit)
+	if !ok || len(omit.Columns) == 0 || len(updates) == 0 {
+		return updates
+	}
+
+	omitted := make(map[string]bool, len(omit.Columns))
+	for _, column := range omit.Columns {
+		if db.Statement.Schema != nil {
+			if field := db.Statement.Schema.LookUpField(column); field != nil {
+				column = field.DBName
+			}
+		}
+		omitted[strings.ToLower(column)] = true
+	}
+
+	filtered := make(clause.Set, 0, len(updates))
+	for _, assignment := range updates {
+		if !omitted[strings.ToLower(assignment.Column.Name)] {
+			filtered = append(filtered, assignment)
+		}
+	}
+	return filtered
+}
+
+// hasExpressionValues reports whether a row of values holds a SQL expression (e.g. gorm.Expr),
+// which cannot be bound
+func hasExpressionValues(values clause.Values) bool {
+	for _, row := range values.Values {
+		for _, value := range row {
+			if _, ok := value.(clause.Expression); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeStatementEnd terminates the statement with a semicolon, unless Config.AppendSemicolon is false
+func writeStatementEnd(db *gorm.DB) {
+	if config := dialectorConfig(db.Dialector); config == nil || config.AppendSemicolon == nil || *config.AppendSemicolon {
+		db.Statement.WriteByte(';')
+	}
+}
+
 // shouldUseUnionSelect determines whether to use UNION SELECT or VALUES syntax
 func shouldUseUnionSelect(db *gorm.DB) bool {
 	// Try to get the config from the dialector
@@ -363,40 +951,23 @@ func buildUnionSelectInsert(db *gorm.DB, values clause.Values) {
 		db.Statement.Vars = newVars
 	}
 
-	// Pre-allocate statement builder capacity for better performance
+	// Pre-allocate statement builder capacity for everything but the rows, written at once
 	estimatedSize := (columnCount * 20) + // column names with quotes
-		(valueCount * 15) + // " UNION SELECT " strings
-		(valueCount * columnCount * 2) + // placeholders
 		50 // base structure
-	db.Statement.SQL.Grow(estimatedSize)
 
-	db.Statement.WriteByte('(')
-	for idx, column := range values.Columns {
-		if idx > 0 {
-			db.Statement.WriteByte(',')
-		}
-		db.Statement.WriteQuoted(column)
-	}
-
-	db.Statement.WriteString(") SELECT ")
-
-	// Cache the union string to avoid repeated allocations
-	const unionSelect = " UNION SELECT "
-	for idx, value := range values.Values {
-		if idx > 0 {
-			db.Statement.WriteString(unionSelect)
-		}
-
-		valueLen := len(value)
-		for i := 0; i < valueLen; i++ {
-			if i > 0 {
+	writeRowsTo(db, values.Values, "", " UNION SELECT ", "", estimatedSize, func() {
+		db.Statement.WriteByte('(')
+		for idx, column := range values.Columns {
+			if idx > 0 {
 				db.Statement.WriteByte(',')
 			}
-			db.Statement.AddVar(db.Statement, value[i])
+			db.Statement.WriteQuoted(column)
 		}
-	}
 
-	db.Statement.WriteString(";")
+		db.Statement.WriteString(") SELECT ")
+	})
+
+	writeStatementEnd(db)
 }
 
 // buildValuesInsert builds INSERT statement using traditional VALUES syntax
@@ -414,33 +985,22 @@ func buildValuesInsert(db *gorm.DB, values clause.Values) {
 		db.Statement.Vars = newVars
 	}
 
-	// Pre-allocate statement builder capacity for better performance
+	// Pre-allocate statement builder capacity for everything but the rows, written at once
 	estimatedSize := (columnCount * 15) + // column names with quotes
-		(valueCount * 10) + // "(),(),()," patterns
-		(valueCount * columnCount * 2) + // placeholders
 		50 // base structure
-	db.Statement.SQL.Grow(estimatedSize)
-
-	db.Statement.WriteByte('(')
-	for idx, column := range values.Columns {
-		if idx > 0 {
-			db.Statement.WriteByte(',')
-		}
-		db.Statement.WriteQuoted(column)
-	}
-	db.Statement.WriteByte(')')
-
-	db.Statement.WriteString(" VALUES ")
-
-	for idx, value := range values.Values {
-		if idx > 0 {
-			db.Statement.WriteByte(',')
-		}
 
+	writeRowsTo(db, values.Values, "(", ",", ")", estimatedSize, func() {
 		db.Statement.WriteByte('(')
-		db.Statement.AddVar(db.Statement, value...)
+		for idx, column := range values.Columns {
+			if idx > 0 {
+				db.Statement.WriteByte(',')
+			}
+			db.Statement.WriteQuoted(column)
+		}
 		db.Statement.WriteByte(')')
-	}
 
-	db.Statement.WriteString(";")
+		db.Statement.WriteString(" VALUES ")
+	})
+
+	writeStatementEnd(db)
 }
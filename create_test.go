@@ -1,6 +1,7 @@
 package snowflake
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"reflect"
 	"strings"
@@ -9,6 +10,7 @@ import (
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
 )
 
@@ -1057,3 +1059,652 @@ func (w *clauseWriter) WriteByte(b byte) error {
 func (w *clauseWriter) WriteString(s string) (int, error) {
 	return w.Builder.WriteString(s)
 }
+
+// newCreateStatement returns a DryRun statement parsed for TestModel on a dialector using config
+func newCreateStatement(t *testing.T, config Config) *gorm.DB {
+	t.Helper()
+
+	config.Conn = &mockConnPool{}
+	db, err := gorm.Open(New(config), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+
+	stmt := db.Session(&gorm.Session{DryRun: true}).Model(&TestModel{})
+	if err := stmt.Statement.Parse(&TestModel{}); err != nil {
+		t.Fatalf("Failed to parse model: %v", err)
+	}
+	return stmt
+}
+
+// testMergeValues are the values of a two rows upsert of TestModel
+var testMergeValues = clause.Values{
+	Columns: []clause.Column{{Name: "name"}, {Name: "age"}, {Name: "id"}},
+	Values:  [][]interface{}{{"John", 25, uint(1)}, {"Jane", 30, uint(2)}},
+}
+
+func TestMergeCreateSkipNoopUpdates(t *testing.T) {
+	onConflict := clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"name", "age"}),
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true, SkipNoopUpdates: true})
+		MergeCreate(stmt, onConflict, testMergeValues)
+
+		sql := stmt.Statement.SQL.String()
+		expected := ` WHEN MATCHED AND ("test_models"."name" IS DISTINCT FROM EXCLUDED."name" OR "test_models"."age" IS DISTINCT FROM EXCLUDED."age") THEN UPDATE SET "name"=EXCLUDED."name","age"=EXCLUDED."age"`
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expected distinct condition %s, got: %s", expected, sql)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		MergeCreate(stmt, onConflict, testMergeValues)
+
+		sql := stmt.Statement.SQL.String()
+		if strings.Contains(sql, "IS DISTINCT FROM") || !strings.Contains(sql, " WHEN MATCHED THEN UPDATE SET ") {
+			t.Errorf("Expected unconditional update, got: %s", sql)
+		}
+	})
+}
+
+func TestMergeCreateNullSafeMergeJoin(t *testing.T) {
+	onConflict := clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"name"})}
+
+	t.Run("Enabled", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true, NullSafeMergeJoin: true})
+		MergeCreate(stmt, onConflict, testMergeValues)
+
+		sql := stmt.Statement.SQL.String()
+		if !strings.Contains(sql, `) ON EQUAL_NULL("test_models"."id",EXCLUDED."id") WHEN MATCHED`) {
+			t.Errorf("Expected null-safe join, got: %s", sql)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		MergeCreate(stmt, onConflict, testMergeValues)
+
+		sql := stmt.Statement.SQL.String()
+		if strings.Contains(sql, "EQUAL_NULL") || !strings.Contains(sql, `) ON "test_models"."id" = EXCLUDED."id" WHEN MATCHED`) {
+			t.Errorf("Expected equality join, got: %s", sql)
+		}
+	})
+}
+
+func TestMergeCreateConflictColumns(t *testing.T) {
+	t.Run("Composite key", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		onConflict := clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}, {Name: "Age"}},
+			DoUpdates: clause.AssignmentColumns([]string{"id"}),
+		}
+		MergeCreate(stmt, onConflict, testMergeValues)
+
+		sql := stmt.Statement.SQL.String()
+		if !strings.Contains(sql, `) ON "test_models"."name" = EXCLUDED."name" AND "test_models"."age" = EXCLUDED."age" WHEN MATCHED`) {
+			t.Errorf("Expected a join on the conflict columns, got: %s", sql)
+		}
+	})
+
+	t.Run("Unique key without the primary key", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true})
+
+		result := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"age"}),
+		}).Create(&TestModel{Name: "John", Age: 25})
+		if result.Error != nil {
+			t.Fatalf("Expected no error, got %v", result.Error)
+		}
+
+		sql := result.Statement.SQL.String()
+		if !strings.HasPrefix(sql, "MERGE INTO") || !strings.Contains(sql, `ON "test_models"."name" = EXCLUDED."name"`) {
+			t.Errorf("Expected a MERGE on name, got: %s", sql)
+		}
+	})
+
+	t.Run("Key expression", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		onConflict := clause.OnConflict{
+			Columns:   []clause.Column{{Name: `LOWER(TRIM(Name)) || 'x.y'`, Raw: true}},
+			DoUpdates: clause.AssignmentColumns([]string{"age"}),
+		}
+		MergeCreate(stmt, onConflict, testMergeValues)
+
+		sql := stmt.Statement.SQL.String()
+		expected := `) ON LOWER(TRIM("test_models"."name")) || 'x.y' = LOWER(TRIM(EXCLUDED."name")) || 'x.y' WHEN MATCHED`
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expected %s, got: %s", expected, sql)
+		}
+	})
+
+	t.Run("Key expression deduplicates DoNothing rows", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		onConflict := clause.OnConflict{Columns: []clause.Column{{Name: `LOWER("name")`, Raw: true}}, DoNothing: true}
+		MergeCreate(stmt, onConflict, testMergeValues)
+
+		sql := stmt.Statement.SQL.String()
+		if !strings.Contains(sql, `QUALIFY ROW_NUMBER() OVER (PARTITION BY LOWER("name") ORDER BY LOWER("name")) = 1`) ||
+			!strings.Contains(sql, `ON LOWER("test_models"."name") = LOWER(EXCLUDED."name")`) {
+			t.Errorf("Expected the key expression, got: %s", sql)
+		}
+	})
+
+	t.Run("Key expression of unwritten columns", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true})
+
+		result := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "LOWER(name)", Raw: true}},
+			DoUpdates: clause.AssignmentColumns([]string{"age"}),
+		}).Select("age").Create(&TestModel{Name: "John", Age: 25})
+		if result.Error != nil {
+			t.Fatalf("Expected no error, got %v", result.Error)
+		}
+
+		if sql := result.Statement.SQL.String(); strings.HasPrefix(sql, "MERGE INTO") {
+			t.Errorf("Expected a plain INSERT, got: %s", sql)
+		}
+	})
+}
+
+func TestMergeCreateConditions(t *testing.T) {
+	t.Run("Where", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		onConflict := clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"name"}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: `EXCLUDED."age" > "test_models"."age"`},
+			}},
+		}
+		MergeCreate(stmt, onConflict, testMergeValues)
+
+		sql := stmt.Statement.SQL.String()
+		if !strings.Contains(sql, ` WHEN MATCHED AND (EXCLUDED."age" > "test_models"."age") THEN UPDATE SET "name"=EXCLUDED."name"`) {
+			t.Errorf("Expected the condition on WHEN MATCHED, got: %s", sql)
+		}
+	})
+
+	t.Run("TargetWhere and Where", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		onConflict := clause.OnConflict{
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "name"}, Value: "John"}}},
+			DoUpdates:   clause.AssignmentColumns([]string{"name"}),
+			Where:       clause.Where{Exprs: []clause.Expression{clause.Gt{Column: clause.Column{Table: clause.CurrentTable, Name: "age"}, Value: 18}}},
+		}
+		MergeCreate(stmt, onConflict, testMergeValues)
+
+		sql := stmt.Statement.SQL.String()
+		if !strings.Contains(sql, ` WHEN MATCHED AND ("test_models"."name" = ? AND "test_models"."age" > ?) THEN UPDATE`) {
+			t.Errorf("Expected both conditions on WHEN MATCHED, got: %s", sql)
+		}
+		if vars := stmt.Statement.Vars; len(vars) != 8 || vars[6] != "John" || vars[7] != 18 {
+			t.Errorf("Expected the condition vars after the rows, got %v", vars)
+		}
+	})
+
+	t.Run("With SkipNoopUpdates", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true, SkipNoopUpdates: true})
+		onConflict := clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"name"}),
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "EXCLUDED.\"age\" > 0"}}},
+		}
+		MergeCreate(stmt, onConflict, testMergeValues)
+
+		sql := stmt.Statement.SQL.String()
+		if !strings.Contains(sql, ` WHEN MATCHED AND (EXCLUDED."age" > 0) AND ("test_models"."name" IS DISTINCT FROM`) {
+			t.Errorf("Expected both predicates, got: %s", sql)
+		}
+	})
+}
+
+func TestMergeCreateExpressions(t *testing.T) {
+	values := clause.Values{
+		Columns: []clause.Column{{Name: "name"}, {Name: "age"}, {Name: "id"}},
+		Values: [][]interface{}{
+			{"John", gorm.Expr("UNIFORM(1, 99, RANDOM())"), 1},
+			{"Jane", 30, 2},
+		},
+	}
+
+	t.Run("Rows are selected", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		MergeCreate(stmt, clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"age"})}, values)
+
+		sql := stmt.Statement.SQL.String()
+		expected := `MERGE INTO "test_models" USING (SELECT ?,UNIFORM(1, 99, RANDOM()),? UNION ALL SELECT ?,?,?) AS EXCLUDED ("name","age","id")`
+		if !strings.HasPrefix(sql, expected) {
+			t.Errorf("Expected %s, got: %s", expected, sql)
+		}
+		if len(stmt.Statement.Vars) != 5 {
+			t.Errorf("Expected 5 vars, got %v", stmt.Statement.Vars)
+		}
+	})
+
+	t.Run("DoNothing", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		MergeCreate(stmt, clause.OnConflict{DoNothing: true}, values)
+
+		sql := stmt.Statement.SQL.String()
+		if !strings.HasPrefix(sql, `MERGE INTO "test_models" USING (SELECT * FROM (SELECT ?,UNIFORM(1, 99, RANDOM()),? UNION ALL SELECT ?,?,?) AS SOURCE`) {
+			t.Errorf("Expected selected rows, got: %s", sql)
+		}
+	})
+
+	t.Run("Plain values", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		MergeCreate(stmt, clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"age"})}, testMergeValues)
+
+		if sql := stmt.Statement.SQL.String(); !strings.HasPrefix(sql, `MERGE INTO "test_models" USING (VALUES(?,?,?),(?,?,?))`) {
+			t.Errorf("Expected VALUES, got: %s", sql)
+		}
+	})
+}
+
+func TestMergeCreateDoNothing(t *testing.T) {
+	t.Run("Deduplicates the source rows", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		MergeCreate(stmt, clause.OnConflict{DoNothing: true}, testMergeValues)
+
+		expected := `MERGE INTO "test_models" USING (SELECT * FROM (VALUES(?,?,?),(?,?,?)) AS SOURCE ("name","age","id")` +
+			` QUALIFY ROW_NUMBER() OVER (PARTITION BY "id" ORDER BY "id") = 1) AS EXCLUDED ("name","age","id")` +
+			` ON "test_models"."id" = EXCLUDED."id" WHEN NOT MATCHED THEN INSERT`
+		if sql := stmt.Statement.SQL.String(); !strings.HasPrefix(sql, expected) || strings.Contains(sql, "WHEN MATCHED") {
+			t.Errorf("Expected %s, got: %s", expected, sql)
+		}
+	})
+
+	t.Run("Matches on a unique key without the primary key", func(t *testing.T) {
+		type DoNothingModel struct {
+			ID    uint   `gorm:"primaryKey;autoIncrement"`
+			Email string `gorm:"unique"`
+			Name  string
+		}
+
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true})
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&DoNothingModel{Email: "a@b.c", Name: "A"})
+		if result.Error != nil {
+			t.Fatalf("Expected no error, got %v", result.Error)
+		}
+
+		sql := result.Statement.SQL.String()
+		if !strings.HasPrefix(sql, "MERGE INTO") || !strings.Contains(sql, `ON "do_nothing_models"."email" = EXCLUDED."email" WHEN NOT MATCHED`) {
+			t.Errorf("Expected a MERGE on email, got: %s", sql)
+		}
+	})
+
+	t.Run("Inserts without key", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true})
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&TestModel{Name: "John", Age: 25})
+		if result.Error != nil {
+			t.Fatalf("Expected no error, got %v", result.Error)
+		}
+
+		if sql := result.Statement.SQL.String(); !strings.HasPrefix(sql, "INSERT INTO") {
+			t.Errorf("Expected an INSERT, got: %s", sql)
+		}
+	})
+}
+
+func TestAppendSemicolon(t *testing.T) {
+	appendSemicolon := false
+	config := Config{QuoteFields: true, AppendSemicolon: &appendSemicolon}
+
+	t.Run("Insert", func(t *testing.T) {
+		stmt := newCreateStatement(t, config)
+		buildValuesInsert(stmt, testMergeValues)
+
+		if sql := stmt.Statement.SQL.String(); strings.HasSuffix(sql, ";") || !strings.HasSuffix(sql, "(?,?,?)") {
+			t.Errorf("Expected no terminator, got: %s", sql)
+		}
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		stmt := newCreateStatement(t, config)
+		MergeCreate(stmt, clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"name"})}, testMergeValues)
+
+		if sql := stmt.Statement.SQL.String(); strings.HasSuffix(sql, ";") || !strings.HasSuffix(sql, `EXCLUDED."age")`) {
+			t.Errorf("Expected no terminator, got: %s", sql)
+		}
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		MergeCreate(stmt, clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"name"})}, testMergeValues)
+
+		if sql := stmt.Statement.SQL.String(); !strings.HasSuffix(sql, ";") {
+			t.Errorf("Expected a terminator, got: %s", sql)
+		}
+	})
+}
+
+func TestUpsertOmit(t *testing.T) {
+	t.Run("Omitted columns are not updated", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		stmt = stmt.Clauses(UpsertOmit("Age"))
+
+		MergeCreate(stmt, clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"name", "age"})}, testMergeValues)
+
+		sql := stmt.Statement.SQL.String()
+		if !strings.Contains(sql, `UPDATE SET "name"=EXCLUDED."name" WHEN NOT MATCHED`) {
+			t.Errorf("Expected only name to be updated, got: %s", sql)
+		}
+	})
+
+	t.Run("Multiple UpsertOmit clauses are merged", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		stmt = stmt.Clauses(UpsertOmit("name")).Clauses(UpsertOmit("age"))
+
+		MergeCreate(stmt, clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"name", "age"})}, testMergeValues)
+
+		sql := stmt.Statement.SQL.String()
+		if strings.Contains(sql, "WHEN MATCHED") {
+			t.Errorf("Expected no update branch when every column is omitted, got: %s", sql)
+		}
+	})
+
+	t.Run("Clause is never rendered", func(t *testing.T) {
+		db := setupMockDB(t)
+		sql := db.Session(&gorm.Session{DryRun: true}).Clauses(UpsertOmit("name")).Find(&[]TestModel{}).Statement.SQL.String()
+		if strings.Contains(strings.ToUpper(sql), "OMIT") {
+			t.Errorf("Expected UpsertOmit not to be rendered, got: %s", sql)
+		}
+	})
+}
+
+func TestUpsertDelete(t *testing.T) {
+	t.Run("Matched tombstones are deleted", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		stmt = stmt.Clauses(UpsertDelete(clause.Expr{SQL: `EXCLUDED."age" = ?`, Vars: []interface{}{0}}))
+
+		MergeCreate(stmt, clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"name"})}, testMergeValues)
+
+		sql := stmt.Statement.SQL.String()
+		expected := ` WHEN MATCHED AND (EXCLUDED."age" = ?) THEN DELETE WHEN MATCHED THEN UPDATE SET "name"=EXCLUDED."name"` +
+			` WHEN NOT MATCHED AND NOT COALESCE((EXCLUDED."age" = ?),FALSE) THEN INSERT`
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expected %s, got: %s", expected, sql)
+		}
+		if vars := stmt.Statement.Vars; len(vars) != 8 || vars[6] != 0 || vars[7] != 0 {
+			t.Errorf("Expected the condition vars after the rows, got %v", vars)
+		}
+	})
+
+	t.Run("Multiple UpsertDelete clauses are merged", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		stmt = stmt.Clauses(UpsertDelete(clause.Expr{SQL: "EXCLUDED.a"})).Clauses(UpsertDelete(clause.Expr{SQL: "EXCLUDED.b"}))
+
+		MergeCreate(stmt, clause.OnConflict{DoNothing: true}, testMergeValues)
+
+		sql := stmt.Statement.SQL.String()
+		if !strings.Contains(sql, " WHEN MATCHED AND (EXCLUDED.a AND EXCLUDED.b) THEN DELETE WHEN NOT MATCHED AND NOT") {
+			t.Errorf("Expected both conditions, got: %s", sql)
+		}
+	})
+
+	t.Run("Upserts without it", func(t *testing.T) {
+		stmt := newCreateStatement(t, Config{QuoteFields: true})
+		MergeCreate(stmt, clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"name"})}, testMergeValues)
+
+		if sql := stmt.Statement.SQL.String(); strings.Contains(sql, "DELETE") || !strings.Contains(sql, " WHEN NOT MATCHED THEN INSERT") {
+			t.Errorf("Expected no delete branch, got: %s", sql)
+		}
+	})
+}
+
+func TestDefaultValueFetcher(t *testing.T) {
+	t.Run("Custom fetcher replaces CHANGES query", func(t *testing.T) {
+		var fetchedFields []string
+		db, fake := setupFakeDB(t, Config{
+			DefaultValueFetcher: func(db *gorm.DB, fields []*schema.Field) error {
+				for _, field := range fields {
+					fetchedFields = append(fetchedFields, field.DBName)
+					if field.DBName == "id" {
+						if err := field.Set(db.Statement.Context, db.Statement.ReflectValue, uint(42)); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			},
+		}, nil)
+
+		model := TestModel{Name: "John"}
+		if err := db.Create(&model).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if model.ID != 42 {
+			t.Errorf("Expected ID to be populated by the fetcher, got %d", model.ID)
+		}
+		if len(fetchedFields) != 1 || fetchedFields[0] != "id" {
+			t.Errorf("Expected fetcher to receive the id field, got %v", fetchedFields)
+		}
+		if hasQuery(fake.queries(), "CHANGES(") {
+			t.Errorf("Expected no CHANGES query, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Fetcher error is reported", func(t *testing.T) {
+		fetchErr := fmt.Errorf("fetch failed")
+		db, _ := setupFakeDB(t, Config{
+			DefaultValueFetcher: func(db *gorm.DB, fields []*schema.Field) error { return fetchErr },
+		}, nil)
+
+		if err := db.Create(&TestModel{Name: "John"}).Error; err != fetchErr {
+			t.Errorf("Expected fetcher error, got %v", err)
+		}
+	})
+
+	t.Run("Default fetcher queries CHANGES", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		if err := db.Create(&TestModel{Name: "John"}).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !hasQuery(fake.queries(), "CHANGES(INFORMATION => APPEND_ONLY)") {
+			t.Errorf("Expected CHANGES query, got %v", fake.queries())
+		}
+	})
+}
+
+func TestFetchDefaultValuesFromChanges(t *testing.T) {
+	db, _ := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+		return fakeResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}, {int64(2)}}}
+	})
+
+	fetch := func(models interface{}) {
+		t.Helper()
+		tx := db.Model(models)
+		if err := tx.Statement.Parse(models); err != nil {
+			t.Fatalf("Failed to parse model: %v", err)
+		}
+		tx.Statement.ReflectValue = reflect.ValueOf(models).Elem()
+		if err := fetchDefaultValuesFromChanges(tx, tx.Statement.Schema.FieldsWithDefaultDBValue); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	// rows with a provided ID were not inserted with a default, they are skipped
+	models := []TestModel{{Name: "a"}, {ID: 7, Name: "b"}, {Name: "c"}}
+	fetch(&models)
+	if models[0].ID != 1 || models[1].ID != 7 || models[2].ID != 2 {
+		t.Errorf("Unexpected IDs: %+v", models)
+	}
+
+	pointers := []*TestModel{{ID: 7}, {Name: "a"}, {Name: "b"}}
+	fetch(&pointers)
+	if pointers[0].ID != 7 || pointers[1].ID != 1 || pointers[2].ID != 2 {
+		t.Errorf("Unexpected IDs: %+v %+v %+v", *pointers[0], *pointers[1], *pointers[2])
+	}
+}
+
+func TestExecCreateAccumulatesRowsAffected(t *testing.T) {
+	db := setupMockDB(t)
+	tx := db.Session(&gorm.Session{NewDB: true})
+	tx.Statement.SQL.WriteString("INSERT INTO t (a,b,c) VALUES (?,?,?),(?,?,?)")
+	tx.Statement.Vars = []interface{}{1, 2, 3, 4, 5, 6}
+
+	// mockConnPool reports one affected row per 3 vars
+	execCreate(tx)
+	execCreate(tx)
+
+	if tx.RowsAffected != 4 {
+		t.Errorf("Expected RowsAffected to be accumulated to 4, got %d", tx.RowsAffected)
+	}
+}
+
+func TestCreateBatchSize(t *testing.T) {
+	newModels := func(withIDs bool) []TestModel {
+		models := make([]TestModel, 5)
+		for i := range models {
+			models[i] = TestModel{Name: fmt.Sprintf("user%d", i), Age: i}
+			if withIDs {
+				models[i].ID = uint(i + 1)
+			}
+		}
+		return models
+	}
+
+	tests := []struct {
+		name           string
+		useUnionSelect bool
+		withIDs        bool
+		prefix         string
+	}{
+		{"UNION SELECT", true, false, "INSERT INTO"},
+		{"VALUES", false, false, "INSERT INTO"},
+		{"MERGE", false, true, "MERGE INTO"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, fake := setupFakeDB(t, Config{UseUnionSelect: test.useUnionSelect}, func(query string, args []interface{}) fakeResult {
+				return fakeResult{rowsAffected: int64(len(args) / 2)}
+			})
+
+			// Save bypasses gorm's CreateInBatches, so batching is done by the Create callback
+			models := newModels(test.withIDs)
+			tx := db.Session(&gorm.Session{CreateBatchSize: 2}).Save(&models)
+			if tx.Error != nil {
+				t.Fatalf("Expected no error, got %v", tx.Error)
+			}
+
+			if count := countQueries(fake.queries(), test.prefix); count != 3 {
+				t.Errorf("Expected 3 statements, got %d: %v", count, fake.queries())
+			}
+			if count := countQueries(fake.queries(), "SELECT"); count != 3 {
+				t.Errorf("Expected default values to be fetched after each batch, got %d", count)
+			}
+			if test.withIDs {
+				// MERGE binds 3 vars per row
+				if tx.RowsAffected != 7 {
+					t.Errorf("Expected RowsAffected to be accumulated, got %d", tx.RowsAffected)
+				}
+			} else if tx.RowsAffected != 5 {
+				t.Errorf("Expected RowsAffected to be accumulated to 5, got %d", tx.RowsAffected)
+			}
+		})
+	}
+
+	t.Run("DryRun is not split", func(t *testing.T) {
+		db := setupMockDB(t)
+		models := newModels(false)
+
+		sql := db.Session(&gorm.Session{DryRun: true, CreateBatchSize: 2}).Save(&models).Statement.SQL.String()
+		if strings.Count(sql, "UNION SELECT") != 4 {
+			t.Errorf("Expected all 5 rows in DryRun SQL, got: %s", sql)
+		}
+	})
+}
+
+func TestMaxStatementBytes(t *testing.T) {
+	models := make([]TestModel, 5)
+	for i := range models {
+		models[i] = TestModel{Name: fmt.Sprintf("user%d", i), Age: i}
+	}
+	models[2].Name = strings.Repeat("x", 100)
+
+	db, fake := setupFakeDB(t, Config{UseUnionSelect: false, MaxStatementBytes: 60}, nil)
+	if err := db.Create(&models).Error; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// rows are estimated at 27 bytes, the third one alone exceeds the limit
+	var rows []int
+	for _, statement := range fake.statements {
+		if strings.HasPrefix(statement.query, "INSERT INTO") {
+			rows = append(rows, len(statement.args)/2)
+		}
+	}
+	if fmt.Sprint(rows) != "[2 1 2]" {
+		t.Errorf("Expected batches of [2 1 2] rows, got %v", rows)
+	}
+}
+
+func TestCreateWithoutSchema(t *testing.T) {
+	rows := []map[string]interface{}{{"name": "a", "age": 1}, {"name": "b", "age": 2}}
+
+	tests := []struct {
+		name       string
+		onConflict *clause.OnConflict
+		expected   string
+	}{
+		{
+			"Insert",
+			nil,
+			`INSERT INTO "people" ("age","name") VALUES (?,?),(?,?);`,
+		},
+		{
+			"Upsert on conflict columns",
+			&clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoUpdates: clause.AssignmentColumns([]string{"age"})},
+			`MERGE INTO "people" USING (VALUES(?,?),(?,?)) AS EXCLUDED ("age","name") ON "people"."name" = EXCLUDED."name" WHEN MATCHED THEN UPDATE SET "age"=EXCLUDED."age" WHEN NOT MATCHED THEN INSERT ("age","name") VALUES (EXCLUDED."age",EXCLUDED."name");`,
+		},
+		{
+			"DoNothing on conflict columns",
+			&clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true},
+			`MERGE INTO "people" USING (SELECT * FROM (VALUES(?,?),(?,?)) AS SOURCE ("age","name") QUALIFY ROW_NUMBER() OVER (PARTITION BY "name" ORDER BY "name") = 1) AS EXCLUDED ("age","name") ON "people"."name" = EXCLUDED."name" WHEN NOT MATCHED THEN INSERT ("age","name") VALUES (EXCLUDED."age",EXCLUDED."name");`,
+		},
+		{
+			// there is no primary key to match on
+			"UpdateAll without conflict columns",
+			&clause.OnConflict{UpdateAll: true},
+			`INSERT INTO "people" ("age","name") VALUES (?,?),(?,?);`,
+		},
+		{
+			"DoNothing without conflict columns",
+			&clause.OnConflict{DoNothing: true},
+			`INSERT INTO "people" ("age","name") VALUES (?,?),(?,?);`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+			tx := db.Table("people")
+			if tt.onConflict != nil {
+				tx = tx.Clauses(*tt.onConflict)
+			}
+			if err := tx.Create(rows).Error; err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if queries := fake.queries(); len(queries) != 1 || queries[0] != tt.expected {
+				t.Errorf("Expected %s, got %v", tt.expected, queries)
+			}
+		})
+	}
+
+	t.Run("Single map", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true})
+		stmt := db.Table("people").Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, UpdateAll: true}).
+			Create(map[string]interface{}{"name": "a", "age": 1}).Statement
+		if stmt.Error != nil {
+			t.Fatalf("Expected no error, got %v", stmt.Error)
+		}
+		if sql := stmt.SQL.String(); !strings.HasPrefix(sql, `MERGE INTO "people"`) || !strings.Contains(sql, `ON "people"."name" = EXCLUDED."name"`) {
+			t.Errorf("Expected a MERGE on name, got %s", sql)
+		}
+	})
+}
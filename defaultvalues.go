@@ -0,0 +1,269 @@
+package snowflake
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// DefaultValueStrategy selects how Create populates the auto increment IDs of inserted rows
+type DefaultValueStrategy string
+
+const (
+	// DefaultValueChanges reads the inserted rows back with CHANGES(INFORMATION => APPEND_ONLY)
+	// after the insert, which requires change tracking on the table (default)
+	DefaultValueChanges DefaultValueStrategy = ""
+	// DefaultValueSequence takes the IDs from the sequence of the column before the insert and
+	// binds them, so tables do not need change tracking. The migrator creates the sequence
+	// (<table>_<column>_seq) and defaults the column to its NEXTVAL instead of IDENTITY.
+	// Other database defaults are not read back, unless Config.DefaultValueFetcher is set
+	DefaultValueSequence DefaultValueStrategy = "sequence"
+	// DefaultValueSnapshot records the MAX of the auto increment column before each insert and
+	// reads back the rows above it whose insert batch is the one of the Create, a UUID bound to
+	// the string field tagged insertbatch, so tables do not need change tracking:
+	//
+	//	type Event struct {
+	//		ID    uint
+	//		Batch string `gorm:"size:36;insertbatch"`
+	//	}
+	//
+	// The column must be numbered in insert order (IDENTITY ... ORDER), creates of models with an
+	// auto increment column but no insertbatch field fail with ErrNoInsertBatchField
+	DefaultValueSnapshot DefaultValueStrategy = "snapshot"
+)
+
+// ErrNoInsertBatchField is returned by the creates of models without an insertbatch field under
+// the DefaultValueSnapshot strategy
+var ErrNoInsertBatchField = errors.New("no insertbatch field")
+
+const (
+	// insertBatchKey is the instance key of the insert batch of a Create under DefaultValueSnapshot
+	insertBatchKey = "snowflake:insert_batch"
+	// idSnapshotKey is the instance key of the MAX of the auto increment column before the last
+	// statement of a Create under DefaultValueSnapshot
+	idSnapshotKey = "snowflake:id_snapshot"
+)
+
+// sequenceName returns the name of the sequence of an auto increment column
+func sequenceName(table, column string) string {
+	return table + "_" + column + "_seq"
+}
+
+// sequenceField returns the auto increment field of sch whose values are taken from a sequence
+// under the DefaultValueSequence strategy, nil if there is none
+func sequenceField(config *Config, sch *schema.Schema) *schema.Field {
+	if config == nil || config.DefaultValueStrategy != DefaultValueSequence || sch == nil {
+		return nil
+	}
+	if field := sch.PrioritizedPrimaryField; field != nil && field.AutoIncrement {
+		return field
+	}
+	return nil
+}
+
+// assignSequenceValues sets the zero auto increment IDs of the rows to create with values of
+// their sequence, fetched with a single query
+func assignSequenceValues(db *gorm.DB) {
+	field := sequenceField(dialectorConfig(db.Dialector), db.Statement.Schema)
+	if field == nil || db.DryRun {
+		return
+	}
+
+	var (
+		ctx          = db.Statement.Context
+		reflectValue = db.Statement.ReflectValue
+		rows         []reflect.Value
+	)
+	switch reflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for idx := 0; idx < reflectValue.Len(); idx++ {
+			row := reflect.Indirect(reflectValue.Index(idx))
+			if row.Kind() != reflect.Struct {
+				return
+			}
+			if _, isZero := field.ValueOf(ctx, row); isZero {
+				rows = append(rows, row)
+			}
+		}
+	case reflect.Struct:
+		if _, isZero := field.ValueOf(ctx, reflectValue); isZero {
+			rows = append(rows, reflectValue)
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	sql := "SELECT " + db.Statement.Quote(clause.Table{Name: sequenceName(db.Statement.Table, field.DBName)}) +
+		".NEXTVAL FROM TABLE(GENERATOR(ROWCOUNT => " + strconv.Itoa(len(rows)) + "))"
+	values, err := db.Statement.ConnPool.QueryContext(ctx, sql)
+	if err != nil {
+		db.AddError(fmt.Errorf("failed to fetch sequence values: %w", err))
+		return
+	}
+	defer values.Close()
+
+	idx := 0
+	for ; values.Next() && idx < len(rows); idx++ {
+		var value int64
+		if err := values.Scan(&value); err != nil {
+			db.AddError(err)
+			return
+		}
+		if err := field.Set(ctx, rows[idx], value); err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+	if err := values.Err(); err != nil {
+		db.AddError(err)
+	} else if idx < len(rows) {
+		db.AddError(fmt.Errorf("failed to fetch sequence values: got %d of %d", idx, len(rows)))
+	}
+}
+
+// sequenceDefault returns the DEFAULT clause of an auto increment column under the
+// DefaultValueSequence strategy
+func (dialector Dialector) sequenceDefault(field *schema.Field) string {
+	var name strings.Builder
+	dialector.QuoteTo(&name, sequenceName(field.Schema.Table, field.DBName))
+	return "DEFAULT " + name.String() + ".NEXTVAL"
+}
+
+// createSequences creates the sequences of the auto increment columns of stmt under the
+// DefaultValueSequence strategy
+func (m Migrator) createSequences(tx *gorm.DB, stmt *gorm.Statement) error {
+	if field := sequenceField(dialectorConfig(m.Dialector), stmt.Schema); field != nil {
+		return tx.Exec("CREATE SEQUENCE IF NOT EXISTS ?", clause.Table{Name: sequenceName(stmt.Table, field.DBName)}).Error
+	}
+	return nil
+}
+
+// snapshotFields returns the auto increment field of sch and its insertbatch field under the
+// DefaultValueSnapshot strategy, nil when the default values of sch are not read back this way
+func snapshotFields(config *Config, sch *schema.Schema) (id, batch *schema.Field, err error) {
+	if config == nil || config.DefaultValueStrategy != DefaultValueSnapshot || config.DefaultValueFetcher != nil || config.ClientSideIDs || sch == nil {
+		return nil, nil, nil
+	}
+	if id = sch.PrioritizedPrimaryField; id == nil || !id.AutoIncrement {
+		return nil, nil, nil
+	}
+
+	for _, field := range sch.Fields {
+		if _, ok := field.TagSettings["INSERTBATCH"]; ok && field.DBName != "" {
+			if field.DataType != schema.String {
+				return nil, nil, fmt.Errorf("%w: %s.%s is %s, not a string", ErrNoInsertBatchField, sch.Name, field.Name, field.FieldType)
+			}
+			return id, field, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%w: %s has an auto increment field", ErrNoInsertBatchField, sch.Name)
+}
+
+// assignInsertBatch sets the insertbatch field of the rows to create to a new UUID under the
+// DefaultValueSnapshot strategy
+func assignInsertBatch(db *gorm.DB) {
+	_, field, err := snapshotFields(dialectorConfig(db.Dialector), db.Statement.Schema)
+	if err != nil {
+		db.AddError(err)
+		return
+	} else if field == nil {
+		return
+	}
+
+	batch, err := newInsertBatch()
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	ctx := db.Statement.Context
+	for _, row := range modelRows(db.Statement, db.Statement.ReflectValue) {
+		if err := field.Set(ctx, row, batch); err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+	db.InstanceSet(insertBatchKey, batch)
+}
+
+// newInsertBatch returns a random (version 4) UUID
+func newInsertBatch() (string, error) {
+	uuid := make([]byte, 16)
+	if _, err := rand.Read(uuid); err != nil {
+		return "", err
+	}
+	uuid[6] = uuid[6]&0x0f | 0x40
+	uuid[8] = uuid[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:]), nil
+}
+
+// snapshotID records the MAX of the auto increment column before a statement inserting the rows
+// of a Create under the DefaultValueSnapshot strategy
+func snapshotID(db *gorm.DB) {
+	if _, ok := db.InstanceGet(insertBatchKey); !ok || db.DryRun || db.Error != nil {
+		return
+	}
+	id, _, _ := snapshotFields(dialectorConfig(db.Dialector), db.Statement.Schema)
+
+	var max sql.NullInt64
+	query := "SELECT MAX(" + db.Statement.Quote(id.DBName) + ") FROM " + db.Statement.Quote(db.Statement.Table)
+	if err := db.Statement.ConnPool.QueryRowContext(db.Statement.Context, query).Scan(&max); err != nil {
+		db.AddError(fmt.Errorf("failed to snapshot %s: %w", id.DBName, err))
+		return
+	}
+	db.InstanceSet(idSnapshotKey, max.Int64)
+}
+
+// fetchDefaultValuesFromSnapshot is the DefaultValueFetcher of the DefaultValueSnapshot strategy,
+// it selects the rows above the snapshot of the last statement in the insert batch of the Create
+func fetchDefaultValuesFromSnapshot(db *gorm.DB, fields []*schema.Field) error {
+	batch, ok := db.InstanceGet(insertBatchKey)
+	snapshot, snapshotted := db.InstanceGet(idSnapshotKey)
+	if !ok || !snapshotted {
+		return nil
+	}
+	id, batchField, err := snapshotFields(dialectorConfig(db.Dialector), db.Statement.Schema)
+	if err != nil || id == nil {
+		return err
+	}
+
+	db.Statement.SQL.Reset()
+	db.Statement.Vars = nil
+	db.Statement.WriteString("SELECT ")
+	for idx, field := range fields {
+		if idx > 0 {
+			db.Statement.WriteByte(',')
+		}
+		db.Statement.WriteQuoted(field.DBName)
+	}
+	db.Statement.WriteString(" FROM ")
+	db.Statement.WriteQuoted(db.Statement.Table)
+	db.Statement.WriteString(" WHERE ")
+	db.Statement.WriteQuoted(id.DBName)
+	db.Statement.WriteString(" > ")
+	db.Statement.AddVar(db.Statement, snapshot)
+	db.Statement.WriteString(" AND ")
+	db.Statement.WriteQuoted(batchField.DBName)
+	db.Statement.WriteString(" = ")
+	db.Statement.AddVar(db.Statement, batch)
+	db.Statement.WriteString(" ORDER BY ")
+	db.Statement.WriteQuoted(id.DBName)
+
+	rows, err := db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	scanDefaultValues(db, rows, fields)
+	return rows.Err()
+}
@@ -0,0 +1,141 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func TestDefaultValueSequence(t *testing.T) {
+	t.Run("Binds IDs taken from the sequence", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{DefaultValueStrategy: DefaultValueSequence}, func(query string, args []interface{}) fakeResult {
+			if strings.Contains(query, ".NEXTVAL") {
+				return fakeResult{columns: []string{"NEXTVAL"}, rows: [][]driver.Value{{int64(11)}, {int64(12)}}}
+			}
+			return fakeResult{rowsAffected: 3}
+		})
+
+		models := []TestModel{{Name: "a"}, {ID: 5, Name: "b"}, {Name: "c"}}
+		if err := db.Create(&models).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if models[0].ID != 11 || models[1].ID != 5 || models[2].ID != 12 {
+			t.Errorf("Unexpected IDs: %+v", models)
+		}
+
+		queries := fake.queries()
+		if !hasQuery(queries, "SELECT test_models_id_seq.NEXTVAL FROM TABLE(GENERATOR(ROWCOUNT => 2))") {
+			t.Errorf("Expected sequence query, got %v", queries)
+		}
+		if hasQuery(queries, "CHANGES(") {
+			t.Errorf("Expected no CHANGES query, got %v", queries)
+		}
+		if !hasQuery(queries, "INSERT INTO test_models (name,age,id)") && !hasQuery(queries, "INSERT INTO test_models (id,name,age)") {
+			t.Errorf("Expected IDs to be inserted, got %v", queries)
+		}
+	})
+
+	t.Run("Reports missing sequence values", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{DefaultValueStrategy: DefaultValueSequence}, func(query string, args []interface{}) fakeResult {
+			if strings.Contains(query, ".NEXTVAL") {
+				return fakeResult{columns: []string{"NEXTVAL"}, rows: [][]driver.Value{{int64(11)}}}
+			}
+			return fakeResult{}
+		})
+
+		if err := db.Create(&[]TestModel{{Name: "a"}, {Name: "b"}}).Error; err == nil {
+			t.Error("Expected an error for missing sequence values")
+		}
+	})
+
+	t.Run("Migrates auto increment columns to the sequence", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{DefaultValueStrategy: DefaultValueSequence}, nil)
+
+		if err := db.Migrator().CreateTable(&TestModel{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		queries := fake.queries()
+		if !hasQuery(queries, "CREATE SEQUENCE IF NOT EXISTS test_models_id_seq") {
+			t.Errorf("Expected sequence to be created, got %v", queries)
+		}
+		if !hasQuery(queries, "id BIGINT DEFAULT test_models_id_seq.NEXTVAL") || hasQuery(queries, "IDENTITY") {
+			t.Errorf("Expected column to default to the sequence, got %v", queries)
+		}
+	})
+}
+
+type snapshotEvent struct {
+	ID    uint
+	Batch string `gorm:"size:36;insertbatch"`
+	Name  string
+}
+
+func TestDefaultValueSnapshot(t *testing.T) {
+	t.Run("Reads back IDs above the snapshot in the insert batch", func(t *testing.T) {
+		var batch interface{}
+		db, fake := setupFakeDB(t, Config{DefaultValueStrategy: DefaultValueSnapshot}, func(query string, args []interface{}) fakeResult {
+			switch {
+			case strings.HasPrefix(query, "SELECT MAX(id)"):
+				return fakeResult{columns: []string{"MAX"}, rows: [][]driver.Value{{int64(10)}}}
+			case strings.HasPrefix(query, "INSERT"):
+				batch = args[0]
+				return fakeResult{rowsAffected: 2}
+			case strings.HasPrefix(query, "SELECT id FROM"):
+				return fakeResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(11)}, {int64(12)}}}
+			}
+			return fakeResult{}
+		})
+
+		events := []snapshotEvent{{Name: "a"}, {Name: "b"}}
+		if err := db.Create(&events).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if events[0].ID != 11 || events[1].ID != 12 {
+			t.Errorf("Unexpected IDs: %+v", events)
+		}
+		if len(events[0].Batch) != 36 || events[0].Batch != events[1].Batch || batch != events[0].Batch {
+			t.Errorf("Expected the rows to share the inserted batch, got %+v and %v", events, batch)
+		}
+
+		queries := fake.queries()
+		if len(queries) != 3 || queries[0] != "SELECT MAX(id) FROM snapshot_events" || hasQuery(queries, "CHANGES(") {
+			t.Errorf("Unexpected queries: %v", queries)
+		}
+		readBack := fake.statements[2]
+		if readBack.query != "SELECT id FROM snapshot_events WHERE id > ? AND batch = ? ORDER BY id" ||
+			len(readBack.args) != 2 || readBack.args[0] != int64(10) || readBack.args[1] != events[0].Batch {
+			t.Errorf("Unexpected read back: %s %v", readBack.query, readBack.args)
+		}
+	})
+
+	t.Run("Requires an insertbatch field", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{DefaultValueStrategy: DefaultValueSnapshot}, nil)
+
+		if err := db.Create(&TestModel{Name: "a"}).Error; !errors.Is(err, ErrNoInsertBatchField) {
+			t.Errorf("Expected ErrNoInsertBatchField, got %v", err)
+		}
+		if len(fake.statements) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Defers to the DefaultValueFetcher", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{DefaultValueStrategy: DefaultValueSnapshot, DefaultValueFetcher: func(*gorm.DB, []*schema.Field) error {
+			return nil
+		}}, nil)
+
+		if err := db.Create(&TestModel{Name: "a"}).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if queries := fake.queries(); len(queries) != 1 || !strings.HasPrefix(queries[0], "INSERT") {
+			t.Errorf("Expected only the INSERT, got %v", queries)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package snowflake
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// deleteClauses are the clauses of DELETE statements, USING joins other tables
+var deleteClauses = []string{"DELETE", "FROM", "USING", "WHERE"}
+
+// Using joins other tables to a delete, DELETE FROM <table> USING <tables> WHERE ...:
+//
+//	db.Clauses(snowflake.Using{Tables: []clause.Table{{Name: "customers"}}}).
+//		Where("orders.customer_id = customers.id AND customers.closed").Delete(&Order{})
+//
+// Soft deletes join the tables with UPDATE ... FROM <tables> instead
+type Using struct {
+	Tables []clause.Table
+}
+
+func (Using) Name() string {
+	return "USING"
+}
+
+func (using Using) Build(builder clause.Builder) {
+	for idx, table := range using.Tables {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteQuoted(table)
+	}
+}
+
+func (using Using) MergeClause(c *clause.Clause) {
+	c.Expression = using
+}
+
+// registerDeleteCallbacks registers softDeleteUsing before gorm:delete
+func registerDeleteCallbacks(db *gorm.DB) {
+	_ = db.Callback().Delete().Before("gorm:delete").Register("snowflake:soft_delete_using", softDeleteUsing)
+}
+
+// softDeleteUsing moves the tables of the USING clause of soft deletes, built as an UPDATE, to
+// its FROM clause
+func softDeleteUsing(db *gorm.DB) {
+	if db.Error == nil && db.Statement.Schema != nil && !db.Statement.Unscoped && isSoftDelete(db.Statement.Schema) {
+		usingToUpdateFrom(db.Statement)
+	}
+}
+
+// isSoftDelete reports whether deletes of sch are soft deletes (gorm.DeletedAt)
+func isSoftDelete(sch *schema.Schema) bool {
+	for _, c := range sch.DeleteClauses {
+		if _, ok := c.(gorm.SoftDeleteDeleteClause); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// usingToUpdateFrom moves the tables of the USING clause to the FROM clause of the UPDATE
+// built by soft deletes
+func usingToUpdateFrom(stmt *gorm.Statement) {
+	c, ok := stmt.Clauses["USING"]
+	if !ok {
+		return
+	}
+	delete(stmt.Clauses, "USING")
+	if using, ok := c.Expression.(Using); ok && len(using.Tables) > 0 {
+		stmt.AddClause(clause.From{Tables: using.Tables})
+	}
+}
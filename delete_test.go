@@ -0,0 +1,87 @@
+package snowflake
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type softDeleteModel struct {
+	ID        uint
+	Name      string
+	DeletedAt gorm.DeletedAt
+}
+
+func TestDelete(t *testing.T) {
+	db := setupMockDB(t)
+	using := Using{Tables: []clause.Table{{Name: "people"}}}
+
+	tests := []struct {
+		name     string
+		delete   func(tx *gorm.DB) *gorm.DB
+		expected []string
+	}{
+		{
+			name: "Deletes with the primary key",
+			delete: func(tx *gorm.DB) *gorm.DB {
+				return tx.Delete(&TestModel{ID: 1})
+			},
+			expected: []string{`DELETE FROM "test_models" WHERE "test_models"."id" = ?`},
+		},
+		{
+			name: "Joins other tables with USING",
+			delete: func(tx *gorm.DB) *gorm.DB {
+				return tx.Clauses(using).Where("test_models.name = people.name").Delete(&TestModel{})
+			},
+			expected: []string{`DELETE FROM "test_models" USING "people" WHERE test_models.name = people.name`},
+		},
+		{
+			name: "Soft deletes join other tables with FROM",
+			delete: func(tx *gorm.DB) *gorm.DB {
+				return tx.Clauses(using).Where("soft_delete_models.name = people.name").Delete(&softDeleteModel{})
+			},
+			expected: []string{`UPDATE "soft_delete_models" SET "deleted_at"=? FROM "people" WHERE soft_delete_models.name = people.name AND "soft_delete_models"."deleted_at" IS NULL`},
+		},
+		{
+			name: "Unscoped deletes remove soft deleted rows",
+			delete: func(tx *gorm.DB) *gorm.DB {
+				return tx.Unscoped().Clauses(using).Where("soft_delete_models.name = people.name").Delete(&softDeleteModel{})
+			},
+			expected: []string{`DELETE FROM "soft_delete_models" USING "people" WHERE soft_delete_models.name = people.name`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := tt.delete(db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true}))
+			if tx.Error != nil {
+				t.Fatalf("Expected no error, got %v", tx.Error)
+			}
+			for _, expected := range tt.expected {
+				if sql := tx.Statement.SQL.String(); !strings.Contains(sql, expected) {
+					t.Errorf("Expected SQL:\n%s\ngot:\n%s", expected, sql)
+				}
+			}
+		})
+	}
+
+	t.Run("Refuses deletes without conditions", func(t *testing.T) {
+		err := db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true}).Delete(&TestModel{}).Error
+		if !errors.Is(err, gorm.ErrMissingWhereClause) {
+			t.Errorf("Expected ErrMissingWhereClause, got %v", err)
+		}
+	})
+
+	t.Run("Reports rows affected", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			return fakeResult{rowsAffected: 4}
+		})
+
+		if result := db.Where("age > ?", 10).Delete(&TestModel{}); result.Error != nil || result.RowsAffected != 4 {
+			t.Errorf("Expected 4 rows affected, got %d (%v)", result.RowsAffected, result.Error)
+		}
+	})
+}
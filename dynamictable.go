@@ -0,0 +1,97 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TargetLagDownstream refreshes a dynamic table only when the dynamic tables reading it refresh
+const TargetLagDownstream = "DOWNSTREAM"
+
+var (
+	// ErrInvalidDynamicTable is returned when a dynamic table cannot be created with the given arguments
+	ErrInvalidDynamicTable = errors.New("invalid dynamic table")
+
+	// targetLagRegex matches the TARGET_LAG durations of CREATE DYNAMIC TABLE
+	targetLagRegex = regexp.MustCompile(`^(?i)\d+ (seconds?|minutes?|hours?|days?)$`)
+)
+
+// CreateDynamicTable creates the table of model as a dynamic table materializing query, refreshed
+// by warehouse so it lags its sources by at most targetLag ("<n> seconds|minutes|hours|days", at
+// least 1 minute, or TargetLagDownstream):
+//
+//	err := db.Migrator().(snowflake.Migrator).CreateDynamicTable(&DailyRevenue{}, "10 minutes", "transform_wh",
+//		db.Model(&Order{}).Select("TO_DATE(created_at) AS day, SUM(amount) AS revenue").Group("1"))
+//
+// The vars of query are inlined. The table is transient for TableTransient models, created
+// with Config.Migration.CreateMode and has the clustering key and comment of the model
+func (m Migrator) CreateDynamicTable(model interface{}, targetLag, warehouse string, query *gorm.DB) error {
+	if m.needsMigrationSession() {
+		return m.withMigrationSession(func(m Migrator) error { return m.CreateDynamicTable(model, targetLag, warehouse, query) })
+	}
+
+	if query == nil {
+		return gorm.ErrSubQueryRequired
+	}
+	lag := strings.TrimSpace(targetLag)
+	if strings.EqualFold(lag, TargetLagDownstream) {
+		lag = TargetLagDownstream
+	} else if targetLagRegex.MatchString(lag) {
+		lag = quoteLiteral(lag)
+	} else {
+		return fmt.Errorf("%w: unsupported target lag %q", ErrInvalidDynamicTable, targetLag)
+	}
+	if warehouse == "" {
+		return fmt.Errorf("%w: no warehouse", ErrInvalidDynamicTable)
+	}
+	if err := validateIdentifier(warehouse); err != nil {
+		return err
+	}
+
+	definition, err := m.inlineQuery(query)
+	if err != nil {
+		return err
+	}
+
+	return m.RunWithValue(model, func(stmt *gorm.Statement) error {
+		tableType, err := m.tableType(stmt)
+		if err != nil {
+			return err
+		}
+		if tableType != TablePermanent && tableType != TableTransient {
+			return fmt.Errorf("%w: %s tables cannot be dynamic", ErrInvalidDynamicTable, strings.ToLower(string(tableType)))
+		}
+
+		sql := m.createTableKeywords(tableType, "DYNAMIC TABLE") + " ? TARGET_LAG = " + lag + " WAREHOUSE = ?"
+		values := []interface{}{m.CurrentTable(stmt), clause.Table{Name: warehouse}}
+
+		if columns := m.clusterByColumns(stmt); len(columns) > 0 {
+			sql += " CLUSTER BY ?"
+			values = append(values, columns)
+		}
+		if comment, ok := tableComment(stmt); ok && comment != "" {
+			sql += " COMMENT = ?"
+			values = append(values, clause.Expr{SQL: quoteLiteral(comment)})
+		}
+
+		sql += " AS ?"
+		values = append(values, clause.Expr{SQL: definition})
+		return m.DB.Exec(sql, values...).Error
+	})
+}
+
+// DropDynamicTable drops the dynamic table of model, DropTable only drops tables
+func (m Migrator) DropDynamicTable(model interface{}) error {
+	if m.needsMigrationSession() {
+		return m.withMigrationSession(func(m Migrator) error { return m.DropDynamicTable(model) })
+	}
+
+	return m.RunWithValue(model, func(stmt *gorm.Statement) error {
+		return m.DB.Exec("DROP DYNAMIC TABLE IF EXISTS ?", m.CurrentTable(stmt)).Error
+	})
+}
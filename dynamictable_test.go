@@ -0,0 +1,95 @@
+package snowflake
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type revenueByDay struct {
+	Day     string `gorm:"clusterBy"`
+	Revenue float64
+}
+
+func (revenueByDay) TableComment() string {
+	return "revenue by day"
+}
+
+func (revenueByDay) TableType() TableType {
+	return TableTransient
+}
+
+func TestCreateDynamicTable(t *testing.T) {
+	t.Run("Dynamic table of a model", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+		migrator := db.Migrator().(Migrator)
+
+		query := db.Model(&TestModel{}).Select("name AS day, SUM(age) AS revenue").Where("age > ?", 18).Group("name")
+		if err := migrator.CreateDynamicTable(&revenueByDay{}, "10 minutes", "transform_wh", query); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := migrator.DropDynamicTable(&revenueByDay{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := []string{
+			"CREATE TRANSIENT DYNAMIC TABLE revenue_by_days TARGET_LAG = '10 minutes' WAREHOUSE = transform_wh CLUSTER BY (day) " +
+				"COMMENT = 'revenue by day' AS SELECT name AS day, SUM(age) AS revenue FROM test_models WHERE age > 18 GROUP BY name",
+			"DROP DYNAMIC TABLE IF EXISTS revenue_by_days",
+		}
+		queries := fake.queries()
+		if len(queries) != len(expected) || queries[0] != expected[0] || queries[1] != expected[1] {
+			t.Errorf("Expected %v, got %v", expected, queries)
+		}
+	})
+
+	t.Run("Dynamic table with an escaped literal", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		query := db.Table("raw_events").Where("name = ?", `a\' OR 1=1 --`)
+		if err := db.Migrator().(Migrator).CreateDynamicTable("events", "1 hour", "transform_wh", query); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if queries := fake.queries(); len(queries) != 1 || !strings.HasSuffix(queries[0], `AS SELECT * FROM raw_events WHERE name = 'a\\'' OR 1=1 --'`) {
+			t.Errorf("Expected the name escaped, got %v", queries)
+		}
+
+		err := db.Migrator().(Migrator).CreateDynamicTable("events", "1 hour", "transform_wh", db.Table("raw_events").Where("name = ?", struct{}{}))
+		if !errors.Is(err, ErrInlineValue) {
+			t.Errorf("Expected ErrInlineValue, got %v", err)
+		}
+	})
+
+	t.Run("Downstream lag and create mode", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true, Migration: MigratorConfig{CreateMode: CreateTableOrReplace}}, nil)
+
+		err := db.Migrator().(Migrator).CreateDynamicTable(&TestModel{}, "downstream", "transform_wh", db.Table("raw_events"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := `CREATE OR REPLACE DYNAMIC TABLE "test_models" TARGET_LAG = DOWNSTREAM WAREHOUSE = "transform_wh" AS SELECT * FROM "raw_events"`
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+		migrator := db.Migrator().(Migrator)
+
+		query := db.Model(&TestModel{})
+		if err := migrator.CreateDynamicTable(&TestModel{}, "10; DROP TABLE x", "wh", query); !errors.Is(err, ErrInvalidDynamicTable) {
+			t.Errorf("Expected ErrInvalidDynamicTable, got %v", err)
+		}
+		if err := migrator.CreateDynamicTable(&TestModel{}, "1 hour", "", query); !errors.Is(err, ErrInvalidDynamicTable) {
+			t.Errorf("Expected ErrInvalidDynamicTable, got %v", err)
+		}
+		if err := WithTableType(db, TableHybrid).Migrator().(Migrator).CreateDynamicTable(&TestModel{}, "1 hour", "wh", query); !errors.Is(err, ErrInvalidDynamicTable) {
+			t.Errorf("Expected ErrInvalidDynamicTable, got %v", err)
+		}
+		if len(fake.statements) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+}
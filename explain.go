@@ -0,0 +1,219 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+const (
+	// explainTimeFormat keeps the full fractional precision and the UTC offset so
+	// logged TIMESTAMP_TZ/LTZ literals are not silently reinterpreted by the session timezone
+	explainTimeFormat = "2006-01-02 15:04:05.999999999 -07:00"
+)
+
+// explainSQL renders sql with its vars inlined for logging purposes.
+// Values Snowflake cannot parse from GORM's generic rendering (NaN/Inf floats,
+// exponent notation, tz-less timestamps) are formatted by explainVar, everything
+// else is delegated to logger.ExplainSQL one value at a time.
+// style is the placeholder syntax of sql, see BindVarStyle.
+func explainSQL(sql string, style BindVarStyle, vars ...interface{}) string {
+	return replacePlaceholders(sql, style, vars, explainVar)
+}
+
+// replacePlaceholders replaces the placeholders of sql, written in style, with the vars
+// formatted by format. Placeholders inside string literals ('...' and $$...$$), quoted
+// identifiers and comments are kept, e.g. the `:01` of '12:01:00'
+func replacePlaceholders(sql string, style BindVarStyle, vars []interface{}, format func(interface{}) string) string {
+	if len(vars) == 0 {
+		return sql
+	}
+
+	var (
+		idx    int
+		prefix = bindVarPrefix(style)
+		newSQL strings.Builder
+	)
+	newSQL.Grow(len(sql) + len(vars)*8)
+
+	for i := 0; i < len(sql); {
+		if end := skipQuoted(sql, i); end > i {
+			newSQL.WriteString(sql[i:end])
+			i = end
+			continue
+		}
+
+		if prefix == "" {
+			if sql[i] == '?' && idx < len(vars) {
+				newSQL.WriteString(format(vars[idx]))
+				idx++
+				i++
+				continue
+			}
+		} else if strings.HasPrefix(sql[i:], prefix) && (i == 0 || sql[i-1] != ':') {
+			end := i + len(prefix)
+			for end < len(sql) && sql[end] >= '0' && sql[end] <= '9' {
+				end++
+			}
+			// position var start from 1 (:1, :2)
+			if n, err := strconv.Atoi(sql[i+len(prefix) : end]); err == nil && n >= 1 && n <= len(vars) {
+				newSQL.WriteString(format(vars[n-1]))
+				i = end
+				continue
+			}
+		}
+
+		newSQL.WriteByte(sql[i])
+		i++
+	}
+
+	return newSQL.String()
+}
+
+// skipQuoted returns the end of the string literal, quoted identifier or comment starting at
+// i of sql, i when none starts there. An unterminated one ends with sql
+func skipQuoted(sql string, i int) int {
+	switch {
+	case sql[i] == '\'':
+		for j := i + 1; j < len(sql); j++ {
+			switch sql[j] {
+			case '\\':
+				j++
+			case '\'':
+				return j + 1
+			}
+		}
+	case sql[i] == '"':
+		if j := strings.IndexByte(sql[i+1:], '"'); j >= 0 {
+			return i + 1 + j + 1
+		}
+	case strings.HasPrefix(sql[i:], "$$"):
+		if j := strings.Index(sql[i+2:], "$$"); j >= 0 {
+			return i + 2 + j + 2
+		}
+	case strings.HasPrefix(sql[i:], "--"), strings.HasPrefix(sql[i:], "//"):
+		if j := strings.IndexByte(sql[i:], '\n'); j >= 0 {
+			return i + j
+		}
+	case strings.HasPrefix(sql[i:], "/*"):
+		if j := strings.Index(sql[i+2:], "*/"); j >= 0 {
+			return i + 2 + j + 2
+		}
+	default:
+		return i
+	}
+	return len(sql)
+}
+
+// explainVar formats a single bind value as a Snowflake literal
+func explainVar(v interface{}) string {
+	switch v := v.(type) {
+	case float64:
+		return formatFloat(v, 64)
+	case float32:
+		return formatFloat(float64(v), 32)
+	case *float64:
+		if v != nil {
+			return formatFloat(*v, 64)
+		}
+	case *float32:
+		if v != nil {
+			return formatFloat(float64(*v), 32)
+		}
+	case *big.Float:
+		if v != nil {
+			if v.IsInf() {
+				return formatFloat(math.Inf(v.Sign()), 64)
+			}
+			return v.Text('f', -1)
+		}
+	case *big.Int:
+		if v != nil {
+			return v.String()
+		}
+	case time.Time:
+		if !v.IsZero() {
+			return "'" + v.Format(explainTimeFormat) + "'"
+		}
+	case *time.Time:
+		if v != nil && !v.IsZero() {
+			return "'" + v.Format(explainTimeFormat) + "'"
+		}
+	}
+
+	return logger.ExplainSQL("?", nil, `'`, v)
+}
+
+// sqlLiteral returns value as a Snowflake literal, for the statements that take no bind
+// variables, strings are escaped by quoteLiteral. A value of another type than nil, a number, a
+// bool, a string, []byte, time.Time or a driver.Valuer returning one is rejected with unsupported
+func sqlLiteral(value interface{}, unsupported error) (string, error) {
+	for {
+		reflectValue := reflect.ValueOf(value)
+		if value == nil || (reflectValue.Kind() == reflect.Ptr && reflectValue.IsNil()) {
+			return "NULL", nil
+		}
+		if number, ok := numberBindValue(value); ok {
+			return number, nil
+		}
+		if valuer, ok := value.(driver.Valuer); ok {
+			var err error
+			if value, err = valuer.Value(); err != nil {
+				return "", err
+			}
+			continue
+		}
+		if reflectValue.Kind() != reflect.Ptr {
+			break
+		}
+		value = reflectValue.Elem().Interface()
+	}
+
+	switch v := value.(type) {
+	case []byte:
+		return "X'" + hex.EncodeToString(v) + "'", nil
+	case time.Time:
+		return quoteLiteral(v.UTC().Format(explainTimeFormat)), nil
+	}
+
+	switch reflectValue := reflect.ValueOf(value); reflectValue.Kind() {
+	case reflect.String:
+		return quoteLiteral(reflectValue.String()), nil
+	case reflect.Bool:
+		if reflectValue.Bool() {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(reflectValue.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(reflectValue.Uint(), 10), nil
+	case reflect.Float32:
+		return formatFloat(reflectValue.Float(), 32), nil
+	case reflect.Float64:
+		return formatFloat(reflectValue.Float(), 64), nil
+	}
+	return "", fmt.Errorf("%w: %T", unsupported, value)
+}
+
+// formatFloat renders a float without scientific notation, using Snowflake's
+// string-cast syntax for the special values NaN, inf and -inf
+func formatFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return "'NaN'::FLOAT"
+	case math.IsInf(f, 1):
+		return "'inf'::FLOAT"
+	case math.IsInf(f, -1):
+		return "'-inf'::FLOAT"
+	}
+	return strconv.FormatFloat(f, 'f', -1, bitSize)
+}
@@ -0,0 +1,94 @@
+package snowflake
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestExplainVar(t *testing.T) {
+	bigFloat, _ := new(big.Float).SetPrec(128).SetString("12345678901234567890123.5")
+	bigInt, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	nan := math.NaN()
+	tz := time.FixedZone("CEST", 2*60*60)
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"NaN", math.NaN(), "'NaN'::FLOAT"},
+		{"Positive Inf", math.Inf(1), "'inf'::FLOAT"},
+		{"Negative Inf", math.Inf(-1), "'-inf'::FLOAT"},
+		{"float32 NaN", float32(math.NaN()), "'NaN'::FLOAT"},
+		{"Pointer NaN", &nan, "'NaN'::FLOAT"},
+		{"Large float", 1e21, "1000000000000000000000"},
+		{"Small float", 1.5e-7, "0.00000015"},
+		{"big.Float", bigFloat, "12345678901234567890123.5"},
+		{"big.Float Inf", new(big.Float).SetInf(true), "'-inf'::FLOAT"},
+		{"big.Int", bigInt, "123456789012345678901234567890"},
+		{"Time with zone", time.Date(2024, 3, 1, 10, 30, 0, 123000000, tz), "'2024-03-01 10:30:00.123 +02:00'"},
+		{"Time UTC", time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC), "'2024-03-01 10:30:00 +00:00'"},
+		{"Zero time", time.Time{}, "'0000-00-00 00:00:00'"},
+		{"Int", 42, "42"},
+		{"String", "O'Brien", "'O''Brien'"},
+		{"Nil", nil, "NULL"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := explainVar(test.value); result != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestExplainSQL(t *testing.T) {
+	t.Run("Replaces placeholders in order", func(t *testing.T) {
+		result := explainSQL("INSERT INTO t (a,b,c) VALUES (?,?,?)", BindVarQuestion, math.Inf(1), 1e25, "x")
+		expected := "INSERT INTO t (a,b,c) VALUES ('inf'::FLOAT,10000000000000000000000000,'x')"
+		if result != expected {
+			t.Errorf("Expected %s, got %s", expected, result)
+		}
+	})
+
+	t.Run("Extra placeholders are kept", func(t *testing.T) {
+		result := explainSQL("SELECT ?, ?", BindVarQuestion, 1)
+		if result != "SELECT 1, ?" {
+			t.Errorf("Expected extra placeholder to be kept, got %s", result)
+		}
+	})
+
+	t.Run("Positional placeholders", func(t *testing.T) {
+		result := explainSQL("SELECT :2, :1, :3", BindVarPositional, "a", math.NaN())
+		if result != "SELECT 'NaN'::FLOAT, 'a', :3" {
+			t.Errorf("Expected positional placeholders to be replaced, got %s", result)
+		}
+	})
+
+	t.Run("Named placeholders", func(t *testing.T) {
+		result := explainSQL("SELECT :p1 WHERE x = :p2", BindVarNamed, 1, "b")
+		if result != "SELECT 1 WHERE x = 'b'" {
+			t.Errorf("Expected named placeholders to be replaced, got %s", result)
+		}
+	})
+
+	t.Run("Placeholders in literals, identifiers and comments are kept", func(t *testing.T) {
+		sql := "SELECT :1, '12:01:00', 'it\\'s :2', $$:2$$, \":2\" -- :2\n/* :2 */ FROM t WHERE x = :2"
+		expected := "SELECT 'a', '12:01:00', 'it\\'s :2', $$:2$$, \":2\" -- :2\n/* :2 */ FROM t WHERE x = 1"
+		if result := explainSQL(sql, BindVarPositional, "a", 1); result != expected {
+			t.Errorf("Expected %s, got %s", expected, result)
+		}
+		if result := explainSQL("SELECT '?', ?", BindVarQuestion, 1); result != "SELECT '?', 1" {
+			t.Errorf("Expected the quoted placeholder to be kept, got %s", result)
+		}
+	})
+
+	t.Run("No vars", func(t *testing.T) {
+		if result := explainSQL("SELECT 1", BindVarQuestion); result != "SELECT 1" {
+			t.Errorf("Expected SQL unchanged, got %s", result)
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeResult is the programmable answer of the fake driver to a statement
+type fakeResult struct {
+	columns      []string
+	types        []string
+	rows         [][]driver.Value
+	rowsAffected int64
+	err          error
+}
+
+// fakeStatement records a statement received by the fake driver
+type fakeStatement struct {
+	query string
+	args  []interface{}
+	// names are the names of the args, empty for positional args
+	names []string
+	// conn is the connection the statement ran on
+	conn *fakeConn
+}
+
+// fakeDriver is a minimal database/sql driver returning canned results,
+// used to test code paths that need real *sql.Rows
+type fakeDriver struct {
+	mu         sync.Mutex
+	statements []fakeStatement
+	prepared   []string
+	handler    func(query string, args []interface{}) fakeResult
+}
+
+func (d *fakeDriver) Connect(ctx context.Context) (driver.Conn, error) { return &fakeConn{d}, nil }
+func (d *fakeDriver) Driver() driver.Driver                            { return d }
+func (d *fakeDriver) Open(name string) (driver.Conn, error)            { return &fakeConn{d}, nil }
+
+func (d *fakeDriver) run(conn *fakeConn, query string, named []driver.NamedValue) fakeResult {
+	args := make([]interface{}, len(named))
+	names := make([]string, len(named))
+	for idx, arg := range named {
+		args[idx], names[idx] = arg.Value, arg.Name
+	}
+
+	d.mu.Lock()
+	d.statements = append(d.statements, fakeStatement{query: query, args: args, names: names, conn: conn})
+	d.mu.Unlock()
+
+	if d.handler == nil {
+		return fakeResult{}
+	}
+	return d.handler(query, args)
+}
+
+// queries returns the SQL of every statement received so far
+func (d *fakeDriver) queries() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	queries := make([]string, len(d.statements))
+	for idx, stmt := range d.statements {
+		queries[idx] = stmt.query
+	}
+	return queries
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	c.driver.mu.Lock()
+	c.driver.prepared = append(c.driver.prepared, query)
+	c.driver.mu.Unlock()
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return c, nil }
+func (c *fakeConn) Commit() error             { return nil }
+func (c *fakeConn) Rollback() error           { return nil }
+
+func (c *fakeConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result := c.driver.run(c, query, args)
+	if result.err != nil {
+		return nil, result.err
+	}
+	return driver.RowsAffected(result.rowsAffected), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result := c.driver.run(c, query, args)
+	if result.err != nil {
+		return nil, result.err
+	}
+	return &fakeRows{columns: result.columns, types: result.types, rows: result.rows}, nil
+}
+
+// fakeStmt is a prepared statement of the fake driver, run like a direct statement
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, driver.ErrSkip
+}
+
+func (s *fakeStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *fakeStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+type fakeRows struct {
+	columns []string
+	types   []string
+	rows    [][]driver.Value
+	pos     int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+// ColumnTypeDatabaseTypeName returns the Snowflake type of the column, if the result sets types
+func (r *fakeRows) ColumnTypeDatabaseTypeName(index int) string {
+	if index < len(r.types) {
+		return r.types[index]
+	}
+	return ""
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+// setupFakeDB opens a gorm DB backed by the fake driver
+func setupFakeDB(t testing.TB, config Config, handler func(query string, args []interface{}) fakeResult) (*gorm.DB, *fakeDriver) {
+	t.Helper()
+
+	fake := &fakeDriver{handler: handler}
+	sqlDB := sql.OpenDB(fake)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	config.Conn = sqlDB
+	db, err := gorm.Open(New(config), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("Failed to setup fake DB: %v", err)
+	}
+	return db, fake
+}
+
+// hasQuery reports whether one of the queries contains substr
+func hasQuery(queries []string, substr string) bool {
+	for _, query := range queries {
+		if strings.Contains(query, substr) {
+			return true
+		}
+	}
+	return false
+}
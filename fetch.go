@@ -0,0 +1,103 @@
+package snowflake
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/snowflakedb/gosnowflake"
+	"gorm.io/gorm"
+)
+
+// fetchOptionsKey is the context key of the FetchOptions set by WithFetchOptions
+type fetchOptionsKey struct{}
+
+// FetchOptions controls how the result of a query is downloaded, see WithFetchOptions
+type FetchOptions struct {
+	// Stream downloads the chunks of the result one at a time as the rows are read, instead of
+	// prefetching them with gosnowflake.MaxChunkDownloadWorkers goroutines, so reading a large
+	// result holds a single chunk in memory. The stream downloader of gosnowflake only handles
+	// results in the JSON format, ForEachRow sets GO_QUERY_RESULT_FORMAT to JSON for its query
+	Stream bool
+	// ChunkSize is the maximum size in MB (48 to 160) of the chunks the result is split into,
+	// the CLIENT_RESULT_CHUNK_SIZE session parameter. It is only set by ForEachRow
+	// Default: 0 (the value of the session)
+	ChunkSize int
+}
+
+// WithFetchOptions returns a context whose queries download their result according to opts:
+//
+//	ctx := snowflake.WithFetchOptions(ctx, snowflake.FetchOptions{Stream: true})
+//	rows, err := db.WithContext(ctx).Model(&Event{}).Rows()
+//
+// Rows read with db.Rows only stream when the session returns JSON results, ForEachRow
+// also sets the session parameters of opts
+func WithFetchOptions(ctx context.Context, opts FetchOptions) context.Context {
+	ctx = context.WithValue(ctx, fetchOptionsKey{}, opts)
+	if opts.Stream {
+		ctx = gosnowflake.WithStreamDownloader(ctx)
+	}
+	return ctx
+}
+
+// fetchOptionsFrom returns the FetchOptions of ctx set by WithFetchOptions
+func fetchOptionsFrom(ctx context.Context) FetchOptions {
+	if ctx == nil {
+		return FetchOptions{}
+	}
+	opts, _ := ctx.Value(fetchOptionsKey{}).(FetchOptions)
+	return opts
+}
+
+// sessionParams returns the session parameters the query of ForEachRow runs with
+func (opts FetchOptions) sessionParams() map[string]string {
+	params := map[string]string{}
+	if opts.Stream {
+		params["GO_QUERY_RESULT_FORMAT"] = "JSON"
+	}
+	if opts.ChunkSize > 0 {
+		params["CLIENT_RESULT_CHUNK_SIZE"] = strconv.Itoa(opts.ChunkSize)
+	}
+	return params
+}
+
+// ForEachRow runs the query of db, a model query or db.Raw, and calls fn with each row of its
+// result scanned into a T, reading the rows as they are downloaded instead of loading the
+// whole result like Find. The FetchOptions of the context of db bound the memory it takes:
+//
+//	ctx := snowflake.WithFetchOptions(ctx, snowflake.FetchOptions{Stream: true, ChunkSize: 48})
+//	err := snowflake.ForEachRow(db.WithContext(ctx).Where("day = ?", day), func(event *Event) error {
+//		return encoder.Encode(event)
+//	})
+//
+// The row passed to fn is reused for the next row. Session parameters of the options are set
+// with WithSessionParams, so the query then runs in a transaction.
+// Hooks (AfterFind) and Preload are not applied
+func ForEachRow[T any](db *gorm.DB, fn func(row *T) error) error {
+	run := func(tx *gorm.DB) error {
+		if tx.Statement.Model == nil {
+			tx = tx.Model(new(T))
+		}
+		rows, err := tx.Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		row := new(T)
+		for rows.Next() {
+			*row = *new(T)
+			if err := tx.ScanRows(rows, row); err != nil {
+				return err
+			}
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	}
+
+	if params := fetchOptionsFrom(db.Statement.Context).sessionParams(); len(params) > 0 {
+		return WithSessionParams(db, params, run)
+	}
+	return run(db)
+}
@@ -0,0 +1,98 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func fetchHandler(query string, args []interface{}) fakeResult {
+	switch {
+	case strings.HasPrefix(query, "SHOW PARAMETERS"):
+		return fakeResult{
+			columns: []string{"key", "value"},
+			rows: [][]driver.Value{
+				{"GO_QUERY_RESULT_FORMAT", "ARROW"},
+				{"CLIENT_RESULT_CHUNK_SIZE", "160"},
+			},
+		}
+	case strings.HasPrefix(query, "SELECT"):
+		return fakeResult{
+			columns: []string{"id", "name", "age"},
+			rows:    [][]driver.Value{{int64(1), "jinzhu", int64(18)}, {int64(2), "gorm", nil}},
+		}
+	}
+	return fakeResult{}
+}
+
+func TestForEachRow(t *testing.T) {
+	t.Run("Scans every row", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, fetchHandler)
+
+		var names []string
+		var ages []int
+		err := ForEachRow(db.Where("age > ?", 10), func(model *TestModel) error {
+			names = append(names, model.Name)
+			ages = append(ages, model.Age)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if strings.Join(names, ",") != "jinzhu,gorm" || ages[1] != 0 {
+			t.Errorf("Unexpected rows: %v %v", names, ages)
+		}
+		if hasQuery(fake.queries(), "ALTER SESSION") || !hasQuery(fake.queries(), `FROM test_models WHERE age > ?`) {
+			t.Errorf("Unexpected statements: %v", fake.queries())
+		}
+	})
+
+	t.Run("Sets the session parameters of the fetch options", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, fetchHandler)
+
+		ctx := WithFetchOptions(context.Background(), FetchOptions{Stream: true, ChunkSize: 48})
+		count := 0
+		if err := ForEachRow(db.WithContext(ctx), func(*TestModel) error { count++; return nil }); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := []string{
+			"SHOW PARAMETERS IN SESSION",
+			"ALTER SESSION SET CLIENT_RESULT_CHUNK_SIZE = 48",
+			"ALTER SESSION SET GO_QUERY_RESULT_FORMAT = 'JSON'",
+			"SELECT * FROM test_models",
+			"ALTER SESSION SET CLIENT_RESULT_CHUNK_SIZE = 160",
+			"ALTER SESSION SET GO_QUERY_RESULT_FORMAT = 'ARROW'",
+		}
+		if strings.Join(fake.queries(), ";") != strings.Join(expected, ";") {
+			t.Errorf("Expected %v, got %v", expected, fake.queries())
+		}
+		if count != 2 {
+			t.Errorf("Expected 2 rows, got %d", count)
+		}
+	})
+
+	t.Run("Stops on the error of fn", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, fetchHandler)
+		fnErr := errors.New("stop")
+
+		count := 0
+		err := ForEachRow(db.Raw("SELECT id, name FROM test_models"), func(*TestModel) error { count++; return fnErr })
+		if !errors.Is(err, fnErr) || count != 1 {
+			t.Errorf("Expected fn error after one row, got %v after %d", err, count)
+		}
+	})
+}
+
+func TestWithFetchOptions(t *testing.T) {
+	ctx := WithFetchOptions(context.Background(), FetchOptions{ChunkSize: 64})
+	if opts := fetchOptionsFrom(ctx); opts.ChunkSize != 64 || opts.Stream {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+	if opts := fetchOptionsFrom(context.Background()); opts != (FetchOptions{}) {
+		t.Errorf("Expected no options, got %+v", opts)
+	}
+}
@@ -0,0 +1,114 @@
+package snowflake
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// flattenClauseName is the statement clause holding the LATERAL FLATTEN views of a query
+const flattenClauseName = "SNOWFLAKE_FLATTEN"
+
+// LateralFlatten expands a semi-structured (VARIANT, ARRAY, OBJECT) column into one row per
+// element with a LATERAL FLATTEN view of the FROM clause of a SELECT statement. Build it with
+// Flatten and reference its output columns with Value, Key, Index and PathColumn:
+//
+//	tags := snowflake.Flatten("tags", "tag")
+//	db.Model(&Event{}).Clauses(tags).
+//		Select(`"events"."id", ? AS tag`, tags.Value()).
+//		Where(clause.Eq{Column: tags.Value(), Value: "urgent"}).
+//		Find(&rows)
+//
+// renders FROM "events", LATERAL FLATTEN(input => "events"."tags") "tag"
+type LateralFlatten struct {
+	// Column is the flattened column, of the table of the statement unless qualified, e.g. by the
+	// alias of another LateralFlatten: "tag.value"
+	Column string
+	// Alias names the view in the statement
+	Alias string
+	// Path is the path of the element to flatten within the column, e.g. "items" or "a.b[0]"
+	// Default: "" (the column itself)
+	Path string
+	// Outer keeps the rows whose element is empty or missing, with NULL output columns
+	Outer bool
+}
+
+// lateralFlattens are the LateralFlatten views of a statement, in the order they were added
+type lateralFlattens []LateralFlatten
+
+// Flatten returns the LateralFlatten of column aliased alias
+func Flatten(column, alias string) LateralFlatten {
+	return LateralFlatten{Column: column, Alias: alias}
+}
+
+// Value is the VALUE output column of the view, the element
+func (flatten LateralFlatten) Value() clause.Column {
+	return flatten.output("VALUE")
+}
+
+// Key is the KEY output column of the view, the key of the element in an object
+func (flatten LateralFlatten) Key() clause.Column {
+	return flatten.output("KEY")
+}
+
+// Index is the INDEX output column of the view, the index of the element in an array
+func (flatten LateralFlatten) Index() clause.Column {
+	return flatten.output("INDEX")
+}
+
+// PathColumn is the PATH output column of the view, the path to the element
+func (flatten LateralFlatten) PathColumn() clause.Column {
+	return flatten.output("PATH")
+}
+
+// output returns an output column of the view. Output columns are uppercase, they are referenced
+// as such whether identifiers are quoted or not
+func (flatten LateralFlatten) output(name string) clause.Column {
+	return clause.Column{Table: flatten.Alias, Name: name}
+}
+
+func (LateralFlatten) Name() string {
+	return flattenClauseName
+}
+
+func (LateralFlatten) Build(clause.Builder) {}
+
+func (lateralFlattens) Build(clause.Builder) {}
+
+func (flatten LateralFlatten) MergeClause(c *clause.Clause) {
+	existing, _ := c.Expression.(lateralFlattens)
+	c.Expression = append(append(lateralFlattens{}, existing...), flatten)
+}
+
+// build writes the LATERAL FLATTEN view of the FROM clause
+func (flatten LateralFlatten) build(stmt *gorm.Statement) {
+	if err := validateIdentifier(flatten.Alias); err != nil {
+		stmt.AddError(err)
+		return
+	}
+	if err := validateIdentifier(flatten.Column); err != nil {
+		stmt.AddError(err)
+		return
+	}
+
+	input := clause.Column{Table: clause.CurrentTable, Name: flatten.Column}
+	if table, name, qualified := strings.Cut(flatten.Column, "."); qualified {
+		input = clause.Column{Table: table, Name: name}
+		if strings.EqualFold(name, "value") || strings.EqualFold(name, "this") {
+			input.Name = strings.ToUpper(name)
+		}
+	}
+
+	stmt.WriteString("LATERAL FLATTEN(input => ")
+	stmt.WriteQuoted(input)
+	if flatten.Path != "" {
+		stmt.WriteString(", path => ")
+		stmt.WriteString(quoteLiteral(flatten.Path))
+	}
+	if flatten.Outer {
+		stmt.WriteString(", outer => TRUE")
+	}
+	stmt.WriteString(") ")
+	stmt.WriteQuoted(flatten.Alias)
+}
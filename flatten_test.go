@@ -0,0 +1,92 @@
+package snowflake
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name     string
+		flattens []clause.Expression
+		expected string
+	}{
+		{
+			"Column",
+			[]clause.Expression{Flatten("tags", "tag")},
+			`SELECT * FROM "test_models", LATERAL FLATTEN(input => "test_models"."tags") "tag"`,
+		},
+		{
+			"Path and outer",
+			[]clause.Expression{LateralFlatten{Column: "payload", Alias: "item", Path: "order.items", Outer: true}},
+			`SELECT * FROM "test_models", LATERAL FLATTEN(input => "test_models"."payload", path => 'order.items', outer => TRUE) "item"`,
+		},
+		{
+			"Nested",
+			[]clause.Expression{Flatten("payload", "item"), Flatten("item.value", "tag")},
+			`SELECT * FROM "test_models", LATERAL FLATTEN(input => "test_models"."payload") "item", LATERAL FLATTEN(input => "item"."VALUE") "tag"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+			stmt := db.Clauses(tt.flattens...).Find(&[]TestModel{}).Statement
+			if stmt.Error != nil {
+				t.Fatalf("Expected no error, got %v", stmt.Error)
+			}
+			if sql := stmt.SQL.String(); sql != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, sql)
+			}
+		})
+	}
+
+	t.Run("Output columns in Select and Where", func(t *testing.T) {
+		tags := Flatten("tags", "tag")
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		stmt := db.Model(&TestModel{}).Clauses(tags).
+			Select("id, ? AS tag", tags.Value()).
+			Where(clause.Eq{Column: tags.Value(), Value: "urgent"}).
+			Find(&[]map[string]interface{}{}).Statement
+		if stmt.Error != nil {
+			t.Fatalf("Expected no error, got %v", stmt.Error)
+		}
+
+		expected := `SELECT id, "tag"."VALUE" AS tag FROM "test_models", LATERAL FLATTEN(input => "test_models"."tags") "tag" WHERE "tag"."VALUE" = ?`
+		if sql := stmt.SQL.String(); sql != expected {
+			t.Errorf("Expected %s, got %s", expected, sql)
+		}
+	})
+
+	t.Run("With joins and time travel", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		stmt := db.Model(&TestModel{}).
+			Joins(`JOIN "owners" ON "owners"."id" = "test_models"."id"`).
+			Clauses(Flatten("tags", "tag"), Before("01b2c3d4")).
+			Find(&[]TestModel{}).Statement
+
+		expected := `FROM "test_models" BEFORE(STATEMENT => ?) JOIN "owners" ON "owners"."id" = "test_models"."id", LATERAL FLATTEN(input => "test_models"."tags") "tag"`
+		if sql := stmt.SQL.String(); !strings.Contains(sql, expected) {
+			t.Errorf("Expected %s, got %s", expected, sql)
+		}
+	})
+
+	t.Run("Only in SELECT statements", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		stmt := db.Clauses(Flatten("tags", "tag")).Where("id = ?", 1).Delete(&TestModel{}).Statement
+		if sql := stmt.SQL.String(); strings.Contains(sql, "FLATTEN") {
+			t.Errorf("Expected no FLATTEN, got %s", sql)
+		}
+	})
+
+	t.Run("Invalid alias", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		if err := db.Clauses(Flatten("tags", "")).Find(&[]TestModel{}).Error; !errors.Is(err, ErrInvalidIdentifier) {
+			t.Errorf("Expected ErrInvalidIdentifier, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package snowflake
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// buildSelectFrom builds the FROM clause of a SELECT statement reading its table at the point
+// in time of AsOf or Before, sampling it with a TableSample and/or flattening its semi-structured
+// columns with Flatten, it returns false when the statement has none of them
+func buildSelectFrom(c clause.Clause, builder clause.Builder) bool {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok || !buildsSelect(stmt) {
+		return false
+	}
+	travel, traveling := stmt.Clauses[timeTravelClauseName].Expression.(timeTravel)
+	sample, sampling := stmt.Clauses[tableSampleClauseName].Expression.(TableSample)
+	flattens, _ := stmt.Clauses[flattenClauseName].Expression.(lateralFlattens)
+	if !traveling && !sampling && len(flattens) == 0 {
+		return false
+	}
+	from, ok := c.Expression.(clause.From)
+	if !ok {
+		return false
+	}
+
+	tables := from.Tables
+	if len(tables) == 0 {
+		tables = []clause.Table{{Name: clause.CurrentTable}}
+	}
+
+	stmt.WriteString("FROM ")
+	for idx, table := range tables {
+		if idx > 0 {
+			stmt.WriteByte(',')
+			stmt.WriteQuoted(table)
+			continue
+		}
+
+		// the point in time follows the table name, the sample its alias
+		stmt.WriteQuoted(clause.Table{Name: table.Name, Raw: table.Raw})
+		if traveling {
+			stmt.WriteByte(' ')
+			clause.Expr{SQL: travel.SQL, Vars: []interface{}{travel.Value}}.Build(stmt)
+		}
+		if table.Alias != "" {
+			stmt.WriteString(" AS ")
+			stmt.WriteQuoted(table.Alias)
+		}
+		if sampling {
+			stmt.WriteByte(' ')
+			sample.build(stmt)
+		}
+	}
+
+	for _, join := range from.Joins {
+		stmt.WriteByte(' ')
+		join.Build(stmt)
+	}
+
+	// lateral views follow the joins, which bind tighter than the comma
+	for _, flatten := range flattens {
+		stmt.WriteString(", ")
+		flatten.build(stmt)
+	}
+	return true
+}
+
+// buildsSelect reports whether stmt builds a SELECT statement
+func buildsSelect(stmt *gorm.Statement) bool {
+	for _, name := range stmt.BuildClauses {
+		if name == "SELECT" {
+			return true
+		}
+	}
+	return false
+}
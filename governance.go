@@ -0,0 +1,203 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// columnTag is an object tag set on a column and its value
+type columnTag struct {
+	Name  string
+	Value string
+}
+
+// columnGovernance returns the masking policy and the object tags of a column:
+//
+//	SSN   string `gorm:"maskingPolicy:pii_mask;tag:classification=PII"`
+//	Email string `gorm:"maskingPolicy:governance.policies.email_mask;tag:classification=PII,owner=crm"`
+//
+// CreateTable and AddColumn attach them to the column, AlterColumn and AutoMigrate set them on
+// existing columns. The policies and tags must exist, policies and tags removed from a model are
+// left on the column
+func columnGovernance(field *schema.Field) (policy string, tags []columnTag, err error) {
+	if field == nil {
+		return "", nil, nil
+	}
+
+	policy = strings.TrimSpace(field.TagSettings["MASKINGPOLICY"])
+	if policy != "" && !objectNameRegex.MatchString(policy) {
+		return "", nil, fmt.Errorf("%w: %q is not a masking policy name (field %s)", ErrInvalidIdentifier, policy, field.Name)
+	}
+
+	if setting := strings.TrimSpace(field.TagSettings["TAG"]); setting != "" {
+		for _, pair := range strings.Split(setting, ",") {
+			name, value, _ := strings.Cut(pair, "=")
+			if name = strings.TrimSpace(name); !objectNameRegex.MatchString(name) {
+				return "", nil, fmt.Errorf("%w: %q is not a tag name (field %s)", ErrInvalidIdentifier, name, field.Name)
+			}
+			tags = append(tags, columnTag{Name: name, Value: strings.TrimSpace(value)})
+		}
+	}
+	return policy, tags, nil
+}
+
+// governanceSQL returns the WITH MASKING POLICY and WITH TAG clauses of a column definition
+func governanceSQL(policy string, tags []columnTag) (sql string) {
+	if policy != "" {
+		sql += " WITH MASKING POLICY " + policy
+	}
+	if len(tags) > 0 {
+		sql += " WITH TAG (" + tagsSQL(tags) + ")"
+	}
+	return sql
+}
+
+// tagsSQL returns the name = 'value' list of tags
+func tagsSQL(tags []columnTag) string {
+	pairs := make([]string, len(tags))
+	for idx, tag := range tags {
+		pairs[idx] = tag.Name + " = " + quoteLiteral(tag.Value)
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// unqualifiedName returns the last part of a (database and schema) qualified object name
+func unqualifiedName(name string) string {
+	return name[strings.LastIndexByte(name, '.')+1:]
+}
+
+// validateGovernance returns the error of the first column of stmt with an invalid masking
+// policy or tag name
+func validateGovernance(stmt *gorm.Statement) error {
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || field.IgnoreMigration {
+			continue
+		}
+		if _, _, err := columnGovernance(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setColumnGovernance sets the masking policy, replacing the one of the column if any, and the
+// tags of an existing column
+func (m Migrator) setColumnGovernance(stmt *gorm.Statement, field *schema.Field, policy string, tags []columnTag) error {
+	if policy != "" {
+		if err := m.DB.Exec(
+			"ALTER TABLE ? ALTER COLUMN ? SET MASKING POLICY "+policy+" FORCE",
+			m.CurrentTable(stmt), clause.Column{Name: field.DBName},
+		).Error; err != nil {
+			return err
+		}
+	}
+	if len(tags) > 0 {
+		return m.DB.Exec(
+			"ALTER TABLE ? ALTER COLUMN ? SET TAG "+tagsSQL(tags),
+			m.CurrentTable(stmt), clause.Column{Name: field.DBName},
+		).Error
+	}
+	return nil
+}
+
+// migrateGovernance sets the masking policies and the tags of the columns of stmt that drifted
+// from the model, the references of the table are only read when one of its columns has some
+func (m Migrator) migrateGovernance(stmt *gorm.Statement) error {
+	var fields []*schema.Field
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || field.IgnoreMigration {
+			continue
+		}
+		if policy, tags, err := columnGovernance(field); err != nil {
+			return err
+		} else if policy != "" || len(tags) > 0 {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	policies, err := m.columnPolicies(stmt)
+	if err != nil {
+		return err
+	}
+	tagValues, err := m.columnTags(stmt)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		policy, tags, _ := columnGovernance(field)
+		column := m.lookupName(field.DBName)
+
+		if policy != "" && strings.EqualFold(unqualifiedName(policy), policies[column]) {
+			policy = ""
+		}
+		var drifted []columnTag
+		for _, tag := range tags {
+			if value, ok := tagValues[column][strings.ToUpper(unqualifiedName(tag.Name))]; !ok || value != tag.Value {
+				drifted = append(drifted, tag)
+			}
+		}
+
+		if err := m.setColumnGovernance(stmt, field, policy, drifted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnPolicies returns the masking policy of the columns of the table of stmt, by column name
+// as stored in INFORMATION_SCHEMA
+func (m Migrator) columnPolicies(stmt *gorm.Statement) (map[string]string, error) {
+	rows, err := m.DB.Raw(
+		"SELECT ref_column_name, policy_name FROM TABLE(INFORMATION_SCHEMA.POLICY_REFERENCES(ref_entity_name => ?, ref_entity_domain => 'table')) "+
+			"WHERE policy_kind = 'MASKING_POLICY'",
+		stmt.Quote(stmt.Table),
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := map[string]string{}
+	for rows.Next() {
+		var column, policy string
+		if err := rows.Scan(&column, &policy); err != nil {
+			return nil, err
+		}
+		policies[column] = policy
+	}
+	return policies, rows.Err()
+}
+
+// columnTags returns the values of the tags set on the columns of the table of stmt, by column
+// name as stored in INFORMATION_SCHEMA and uppercase tag name
+func (m Migrator) columnTags(stmt *gorm.Statement) (map[string]map[string]string, error) {
+	rows, err := m.DB.Raw(
+		"SELECT column_name, tag_name, tag_value FROM TABLE(INFORMATION_SCHEMA.TAG_REFERENCES_ALL_COLUMNS(?, 'table')) WHERE level = 'COLUMN'",
+		stmt.Quote(stmt.Table),
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := map[string]map[string]string{}
+	for rows.Next() {
+		var column, tag, value string
+		if err := rows.Scan(&column, &tag, &value); err != nil {
+			return nil, err
+		}
+		if tags[column] == nil {
+			tags[column] = map[string]string{}
+		}
+		tags[column][strings.ToUpper(tag)] = value
+	}
+	return tags, rows.Err()
+}
@@ -0,0 +1,134 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type governedCustomer struct {
+	ID    uint
+	SSN   string `gorm:"maskingPolicy:pii_mask;tag:classification=PII"`
+	Email string `gorm:"maskingPolicy:governance.policies.email_mask;tag:classification=PII,owner=crm's team"`
+	Name  string
+}
+
+type invalidGovernedCustomer struct {
+	ID  uint
+	SSN string `gorm:"maskingPolicy:pii mask"`
+}
+
+func TestGovernance(t *testing.T) {
+	t.Run("CreateTable attaches policies and tags", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		if err := db.Migrator().CreateTable(&governedCustomer{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		queries := fake.queries()
+		if !hasQuery(queries, "ssn VARCHAR WITH MASKING POLICY pii_mask WITH TAG (classification = 'PII'),") ||
+			!hasQuery(queries, "email VARCHAR WITH MASKING POLICY governance.policies.email_mask WITH TAG (classification = 'PII', owner = 'crm''s team'),") ||
+			!hasQuery(queries, "name VARCHAR,") {
+			t.Errorf("Unexpected CREATE TABLE: %v", queries)
+		}
+	})
+
+	t.Run("Invalid names", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		if err := db.Migrator().CreateTable(&invalidGovernedCustomer{}); !errors.Is(err, ErrInvalidIdentifier) {
+			t.Errorf("Expected ErrInvalidIdentifier, got %v", err)
+		}
+		if err := db.Migrator().AddColumn(&invalidGovernedCustomer{}, "ssn"); !errors.Is(err, ErrInvalidIdentifier) {
+			t.Errorf("Expected ErrInvalidIdentifier, got %v", err)
+		}
+		if len(fake.statements) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+
+	t.Run("AlterColumn sets the policy and tags", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		if err := db.Migrator().AlterColumn(&governedCustomer{}, "SSN"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := []string{
+			`ALTER TABLE "governed_customers" ALTER COLUMN "ssn" VARCHAR`,
+			`ALTER TABLE "governed_customers" ALTER COLUMN "ssn" SET MASKING POLICY pii_mask FORCE`,
+			`ALTER TABLE "governed_customers" ALTER COLUMN "ssn" SET TAG classification = 'PII'`,
+		}
+		if queries := fake.queries(); strings.Join(queries, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected %v, got %v", expected, queries)
+		}
+	})
+
+	t.Run("AutoMigrate sets drifted policies and tags", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			switch {
+			case strings.Contains(query, "count(*)"):
+				return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(1)}}}
+			case queriesInformationSchema(query, "COLUMNS"):
+				return informationSchemaColumns(
+					[]driver.Value{"ID", "NUMBER", "NO", nil, nil, int64(38), int64(0), "YES", nil},
+					[]driver.Value{"SSN", "TEXT", "YES", nil, int64(16777216), nil, nil, "NO", nil},
+					[]driver.Value{"EMAIL", "TEXT", "YES", nil, int64(16777216), nil, nil, "NO", nil},
+					[]driver.Value{"NAME", "TEXT", "YES", nil, int64(16777216), nil, nil, "NO", nil},
+				)
+			case strings.Contains(query, "POLICY_REFERENCES"):
+				return fakeResult{columns: []string{"ref_column_name", "policy_name"}, rows: [][]driver.Value{{"SSN", "PII_MASK"}}}
+			case strings.Contains(query, "TAG_REFERENCES_ALL_COLUMNS"):
+				return fakeResult{
+					columns: []string{"column_name", "tag_name", "tag_value"},
+					rows: [][]driver.Value{
+						{"SSN", "CLASSIFICATION", "PII"},
+						{"EMAIL", "CLASSIFICATION", "PII"},
+						{"EMAIL", "OWNER", "sales"},
+					},
+				}
+			}
+			return fakeResult{}
+		})
+
+		if err := db.AutoMigrate(&governedCustomer{}); err != nil {
+			t.Fatalf("Expected AutoMigrate to succeed, got %v", err)
+		}
+
+		queries := fake.queries()
+		var altered []string
+		for _, query := range queries {
+			if strings.HasPrefix(query, "ALTER TABLE") {
+				altered = append(altered, query)
+			}
+		}
+		expected := []string{
+			"ALTER TABLE governed_customers ALTER COLUMN email SET MASKING POLICY governance.policies.email_mask FORCE",
+			"ALTER TABLE governed_customers ALTER COLUMN email SET TAG owner = 'crm''s team'",
+		}
+		if strings.Join(altered, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected %v, got %v", expected, queries)
+		}
+		if !hasQuery(queries, "POLICY_REFERENCES(ref_entity_name => ?, ref_entity_domain => 'table')") {
+			t.Errorf("Expected the policy references to be read, got %v", queries)
+		}
+	})
+
+	t.Run("AutoMigrate skips ungoverned tables", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			if strings.Contains(query, "count(*)") {
+				return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(1)}}}
+			}
+			return fakeResult{}
+		})
+
+		if err := db.AutoMigrate(&TestModel{}); err != nil {
+			t.Fatalf("Expected AutoMigrate to succeed, got %v", err)
+		}
+		if queries := fake.queries(); hasQuery(queries, "POLICY_REFERENCES") || hasQuery(queries, "TAG_REFERENCES") {
+			t.Errorf("Expected no reference query, got %v", queries)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/snowflakedb/gosnowflake"
+	"gorm.io/gorm"
+)
+
+// HeavyStatementKey flags the statements of a DB as heavy: db.Set(HeavyStatementKey, true).Exec(...)
+const HeavyStatementKey = "snowflake:heavy_statement"
+
+var (
+	// ErrBudgetExceeded is returned when the remaining credits of the resource monitor
+	// are below HeavyStatementConfig.MinRemainingCredits
+	ErrBudgetExceeded = errors.New("resource monitor budget exceeded")
+
+	// heavyStatementRegex matches MERGE and CREATE TABLE ... AS SELECT statements
+	heavyStatementRegex = regexp.MustCompile(`(?is)^[\s(]*(MERGE\s+INTO\s|CREATE\s+(OR\s+REPLACE\s+)?(\w+\s+)*TABLE\s+.+?\s+AS\s+[(\s]*(SELECT|WITH)\s)`)
+)
+
+// HeavyStatementConfig annotates heavy statements (MERGE upserts and updates, CTAS and statements
+// flagged with HeavyStatementKey) with a QUERY_TAG and checks the credits left before running them
+type HeavyStatementConfig struct {
+	// WarehouseSizeHint is written in the query tag, e.g. for a proxy routing heavy statements
+	// or to find them in QUERY_HISTORY
+	WarehouseSizeHint WarehouseSize
+	// QueryTag is an application defined label written in the query tag
+	QueryTag string
+	// ResourceMonitor is checked before heavy statements when MinRemainingCredits is set
+	ResourceMonitor string
+	// MinRemainingCredits aborts heavy statements with ErrBudgetExceeded when the resource
+	// monitor has less credits left
+	MinRemainingCredits float64
+}
+
+// enabled reports whether heavy statements are annotated or checked
+func (config HeavyStatementConfig) enabled() bool {
+	return config.WarehouseSizeHint != "" || config.QueryTag != "" || config.ResourceMonitor != ""
+}
+
+// queryTag returns the JSON query tag of heavy statements
+func (config HeavyStatementConfig) queryTag() (string, error) {
+	tag, err := json.Marshal(struct {
+		Heavy             bool          `json:"heavy"`
+		WarehouseSizeHint WarehouseSize `json:"warehouse_size_hint,omitempty"`
+		Tag               string        `json:"tag,omitempty"`
+	}{true, config.WarehouseSizeHint, config.QueryTag})
+	return string(tag), err
+}
+
+// ShowResourceMonitor is a row of SHOW RESOURCE MONITORS
+type ShowResourceMonitor struct {
+	Name             string
+	CreditQuota      float64
+	UsedCredits      float64
+	RemainingCredits float64
+	Level            string
+	Frequency        string
+	Owner            string
+	Comment          string
+}
+
+// registerHeavyStatementCallbacks registers HeavyStatementGuard for the statements that can be heavy
+func registerHeavyStatementCallbacks(db *gorm.DB) {
+	_ = db.Callback().Create().Before("gorm:create").Register("snowflake:heavy_statement", HeavyStatementGuard)
+	_ = db.Callback().Update().Before("gorm:update").Register("snowflake:heavy_statement", HeavyStatementGuard)
+	_ = db.Callback().Raw().Before("gorm:raw").Register("snowflake:heavy_statement", HeavyStatementGuard)
+}
+
+// HeavyStatementGuard tags heavy statements and checks the resource monitor budget,
+// according to Config.HeavyStatements
+func HeavyStatementGuard(db *gorm.DB) {
+	config := dialectorConfig(db.Dialector)
+	if db.Error != nil || db.DryRun || config == nil || !config.HeavyStatements.enabled() || !isHeavyStatement(db) {
+		return
+	}
+	heavy := config.HeavyStatements
+
+	if heavy.ResourceMonitor != "" && heavy.MinRemainingCredits > 0 {
+		if err := checkRemainingCredits(db.Session(&gorm.Session{NewDB: true}), heavy.ResourceMonitor, heavy.MinRemainingCredits); err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+
+	if heavy.WarehouseSizeHint != "" || heavy.QueryTag != "" {
+		tag, err := heavy.queryTag()
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		db.Statement.Context = gosnowflake.WithQueryTag(db.Statement.Context, tag)
+	}
+}
+
+// isHeavyStatement reports whether the statement of db is flagged with HeavyStatementKey,
+// is an upsert or an update with MergeUsing (MERGE) or a raw MERGE/CTAS statement
+func isHeavyStatement(db *gorm.DB) bool {
+	if heavy, ok := db.Get(HeavyStatementKey); ok {
+		return heavy == true
+	}
+	if _, ok := db.Statement.Clauses["ON CONFLICT"]; ok {
+		return true
+	}
+	if _, ok := db.Statement.Clauses["MERGE"]; ok {
+		return true
+	}
+	return heavyStatementRegex.MatchString(db.Statement.SQL.String())
+}
+
+// checkRemainingCredits returns ErrBudgetExceeded when monitor has less than minCredits left
+func checkRemainingCredits(db *gorm.DB, monitor string, minCredits float64) error {
+	if err := validateIdentifier(monitor); err != nil {
+		return err
+	}
+
+	var monitors []ShowResourceMonitor
+	if err := Show(db, "RESOURCE MONITORS LIKE ?", monitor, &monitors); err != nil {
+		return fmt.Errorf("failed to check resource monitor %s: %w", monitor, err)
+	}
+	if len(monitors) == 0 {
+		return fmt.Errorf("resource monitor %s not found", monitor)
+	}
+
+	if remaining := monitors[0].RemainingCredits; remaining < minCredits {
+		return fmt.Errorf("%w: %s has %g credits left, %g required", ErrBudgetExceeded, monitor, remaining, minCredits)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func resourceMonitorHandler(remaining string) func(query string, args []interface{}) fakeResult {
+	return func(query string, args []interface{}) fakeResult {
+		if !strings.HasPrefix(query, "SHOW RESOURCE MONITORS") {
+			return fakeResult{}
+		}
+		return fakeResult{
+			columns: []string{"name", "credit_quota", "used_credits", "remaining_credits", "level", "frequency"},
+			rows:    [][]driver.Value{{"ETL_MONITOR", "100.00", "95.50", remaining, "ACCOUNT", "MONTHLY"}},
+		}
+	}
+}
+
+func TestHeavyStatements(t *testing.T) {
+	config := Config{HeavyStatements: HeavyStatementConfig{
+		WarehouseSizeHint:   WarehouseLarge,
+		ResourceMonitor:     "ETL_MONITOR",
+		MinRemainingCredits: 10,
+	}}
+
+	t.Run("Aborts below budget", func(t *testing.T) {
+		db, fake := setupFakeDB(t, config, resourceMonitorHandler("4.50"))
+
+		err := db.Exec("CREATE OR REPLACE TABLE daily_totals AS SELECT day, SUM(amount) FROM orders GROUP BY day").Error
+		if !errors.Is(err, ErrBudgetExceeded) {
+			t.Fatalf("Expected ErrBudgetExceeded, got %v", err)
+		}
+
+		err = db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&TestModel{ID: 1, Name: "John"}).Error
+		if !errors.Is(err, ErrBudgetExceeded) {
+			t.Fatalf("Expected ErrBudgetExceeded for upsert, got %v", err)
+		}
+
+		err = db.Model(&TestModel{}).Clauses(MergeUsing{Source: clause.Table{Name: "staged"}}).
+			Where("test_models.id = staged.id").Update("name", gorm.Expr("staged.name")).Error
+		if !errors.Is(err, ErrBudgetExceeded) {
+			t.Fatalf("Expected ErrBudgetExceeded for MERGE update, got %v", err)
+		}
+
+		if hasQuery(fake.queries(), "CREATE OR REPLACE TABLE") || hasQuery(fake.queries(), "MERGE") {
+			t.Errorf("Expected heavy statements not to run, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Runs within budget", func(t *testing.T) {
+		db, fake := setupFakeDB(t, config, resourceMonitorHandler("42.00"))
+
+		if err := db.Exec("MERGE INTO orders USING staged_orders ON orders.id = staged_orders.id WHEN MATCHED THEN DELETE").Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !hasQuery(fake.queries(), "SHOW RESOURCE MONITORS") || !hasQuery(fake.queries(), "MERGE INTO orders") {
+			t.Errorf("Expected budget check then MERGE, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Skips light statements", func(t *testing.T) {
+		db, fake := setupFakeDB(t, config, resourceMonitorHandler("4.50"))
+
+		if err := db.Exec("UPDATE orders SET status = 'shipped' WHERE id = 1").Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := db.Model(&TestModel{ID: 1}).Update("name", "shipped").Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := db.Set(HeavyStatementKey, true).Exec("INSERT INTO archive SELECT * FROM orders").Error; !errors.Is(err, ErrBudgetExceeded) {
+			t.Errorf("Expected flagged statement to be checked, got %v", err)
+		}
+		if hasQuery(fake.queries(), "INSERT INTO archive") {
+			t.Errorf("Expected flagged statement not to run, got %v", fake.queries())
+		}
+	})
+}
+
+func TestHeavyStatementQueryTag(t *testing.T) {
+	tag, err := HeavyStatementConfig{WarehouseSizeHint: WarehouseXLarge, QueryTag: "nightly"}.queryTag()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expected := `{"heavy":true,"warehouse_size_hint":"XLARGE","tag":"nightly"}`; tag != expected {
+		t.Errorf("Expected %s, got %s", expected, tag)
+	}
+}
+
+func TestHeavyStatementRegex(t *testing.T) {
+	tests := map[string]bool{
+		"MERGE INTO t USING s ON t.id = s.id":                                 true,
+		"create transient table t as select * from s":                         true,
+		"CREATE OR REPLACE TABLE t AS (WITH x AS (SELECT 1) SELECT * FROM x)": true,
+		"CREATE TABLE t (id INT)":                                             false,
+		"SELECT * FROM merge_log":                                             false,
+		"INSERT INTO t VALUES (1)":                                            false,
+	}
+	for sql, expected := range tests {
+		if heavyStatementRegex.MatchString(sql) != expected {
+			t.Errorf("Expected %q heavy=%v", sql, expected)
+		}
+	}
+}
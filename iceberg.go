@@ -0,0 +1,111 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// icebergSnowflakeCatalog is the catalog of Snowflake-managed Iceberg tables
+const icebergSnowflakeCatalog = "SNOWFLAKE"
+
+// ErrIcebergUnsupported is returned by CreateIcebergTable for model features Iceberg tables do not support
+var ErrIcebergUnsupported = errors.New("unsupported by iceberg tables")
+
+// IcebergOptions are the storage properties of an Iceberg table
+type IcebergOptions struct {
+	// ExternalVolume stores the table data and metadata, required unless set on the schema/database
+	ExternalVolume string
+	// Catalog is the catalog integration (default: SNOWFLAKE, i.e. Snowflake-managed)
+	Catalog string
+	// BaseLocation is the path of the table relative to the external volume
+	BaseLocation string
+}
+
+// CreateIcebergTable creates the Iceberg table of value, using types Iceberg can store
+// (STRING, NUMBER(10,0)/NUMBER(19,0), TIMESTAMP_NTZ(6)...).
+// Iceberg tables have neither IDENTITY nor DEFAULT columns, models must generate their keys,
+// integer primary keys need `gorm:"autoIncrement:false"` so Create binds them instead of
+// expecting them from the database.
+// Change tracking, used by Create to read back inserted rows, is only enabled for
+// Snowflake-managed tables
+func (m Migrator) CreateIcebergTable(value interface{}, opts IcebergOptions) error {
+	if m.needsMigrationSession() {
+		return m.withMigrationSession(func(m Migrator) error { return m.CreateIcebergTable(value, opts) })
+	}
+
+	catalog := opts.Catalog
+	if catalog == "" {
+		catalog = icebergSnowflakeCatalog
+	}
+
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		var (
+			createTableSQL = "CREATE ICEBERG TABLE ? ("
+			values         = []interface{}{m.CurrentTable(stmt)}
+		)
+
+		for _, dbName := range stmt.Schema.DBNames {
+			field := stmt.Schema.FieldsByDBName[dbName]
+			if field.AutoIncrement {
+				return fmt.Errorf("%w: %s.%s is auto increment, declare it with autoIncrement:false", ErrIcebergUnsupported, stmt.Table, dbName)
+			}
+			if field.HasDefaultValue && (field.DefaultValueInterface != nil || field.DefaultValue != "") {
+				return fmt.Errorf("%w: %s.%s has a default value", ErrIcebergUnsupported, stmt.Table, dbName)
+			}
+
+			dataType := icebergDataTypeOf(field)
+			if field.NotNull {
+				dataType += " NOT NULL"
+			}
+			createTableSQL += "? ?,"
+			values = append(values, clause.Column{Name: dbName}, clause.Expr{SQL: dataType})
+		}
+
+		createTableSQL = strings.TrimSuffix(createTableSQL, ",") + ")"
+
+		for _, property := range []struct{ name, value string }{
+			{"EXTERNAL_VOLUME", opts.ExternalVolume},
+			{"CATALOG", catalog},
+			{"BASE_LOCATION", opts.BaseLocation},
+		} {
+			if property.value != "" {
+				createTableSQL += " " + property.name + " = ?"
+				values = append(values, clause.Expr{SQL: quoteLiteral(property.value)})
+			}
+		}
+
+		if strings.EqualFold(catalog, icebergSnowflakeCatalog) {
+			createTableSQL += " CHANGE_TRACKING = TRUE"
+		}
+
+		return m.DB.Exec(createTableSQL, values...).Error
+	})
+}
+
+// icebergDataTypeOf returns the Snowflake type of field that maps to an Iceberg type
+func icebergDataTypeOf(field *schema.Field) string {
+	switch field.DataType {
+	case schema.Bool:
+		return "BOOLEAN"
+	case schema.Int, schema.Uint:
+		if field.Size > 0 && field.Size <= 32 {
+			return "NUMBER(10,0)"
+		}
+		return "NUMBER(19,0)"
+	case schema.Float:
+		return "FLOAT"
+	case schema.String:
+		return "STRING"
+	case schema.Time:
+		return "TIMESTAMP_NTZ(6)"
+	case schema.Bytes:
+		return "BINARY"
+	}
+
+	return string(field.DataType)
+}
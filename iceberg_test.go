@@ -0,0 +1,69 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type IcebergEvent struct {
+	ID        uint64 `gorm:"primaryKey;autoIncrement:false"`
+	Kind      string `gorm:"size:32;not null"`
+	Count     int32
+	Score     float64
+	Active    bool
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+func TestMigratorCreateIcebergTable(t *testing.T) {
+	t.Run("Snowflake-managed", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		err := db.Migrator().(Migrator).CreateIcebergTable(&IcebergEvent{}, IcebergOptions{
+			ExternalVolume: "lake_vol",
+			BaseLocation:   "events/",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := `CREATE ICEBERG TABLE "iceberg_events" ("id" NUMBER(19,0),"kind" STRING NOT NULL,"count" NUMBER(10,0),` +
+			`"score" FLOAT,"active" BOOLEAN,"payload" BINARY,"created_at" TIMESTAMP_NTZ(6)) ` +
+			`EXTERNAL_VOLUME = 'lake_vol' CATALOG = 'SNOWFLAKE' BASE_LOCATION = 'events/' CHANGE_TRACKING = TRUE`
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("External catalog", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		if err := db.Migrator().(Migrator).CreateIcebergTable(&IcebergEvent{}, IcebergOptions{Catalog: "glue_int"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !hasQuery(fake.queries(), ") CATALOG = 'glue_int'") || hasQuery(fake.queries(), "CHANGE_TRACKING") {
+			t.Errorf("Expected external catalog without change tracking, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Unsupported fields", func(t *testing.T) {
+		type WithDefault struct {
+			ID     uint64 `gorm:"primaryKey;autoIncrement:false"`
+			Status string `gorm:"default:'new'"`
+		}
+
+		db, fake := setupFakeDB(t, Config{}, nil)
+		migrator := db.Migrator().(Migrator)
+
+		if err := migrator.CreateIcebergTable(&TestModel{}, IcebergOptions{}); !errors.Is(err, ErrIcebergUnsupported) {
+			t.Errorf("Expected ErrIcebergUnsupported for auto increment, got %v", err)
+		}
+		if err := migrator.CreateIcebergTable(&WithDefault{}, IcebergOptions{}); !errors.Is(err, ErrIcebergUnsupported) {
+			t.Errorf("Expected ErrIcebergUnsupported for default value, got %v", err)
+		}
+		if len(fake.queries()) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxIdentifierLength is the maximum number of characters of a Snowflake identifier
+const MaxIdentifierLength = 255
+
+// ErrInvalidIdentifier is returned when a table, column or constraint name cannot be safely used in SQL
+var ErrInvalidIdentifier = errors.New("invalid identifier")
+
+// reservedWords are the keywords Snowflake reserves, they can only be used as quoted identifiers
+// (https://docs.snowflake.com/en/sql-reference/reserved-keywords)
+var reservedWords = map[string]bool{
+	"ACCOUNT": true, "ALL": true, "ALTER": true, "AND": true, "ANY": true, "AS": true,
+	"BETWEEN": true, "BY": true, "CASE": true, "CAST": true, "CHECK": true, "COLUMN": true,
+	"CONNECT": true, "CONNECTION": true, "CONSTRAINT": true, "CREATE": true, "CROSS": true,
+	"CURRENT": true, "CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true,
+	"CURRENT_USER": true, "DATABASE": true, "DELETE": true, "DISTINCT": true, "DROP": true,
+	"ELSE": true, "EXISTS": true, "FALSE": true, "FOLLOWING": true, "FOR": true, "FROM": true,
+	"FULL": true, "GRANT": true, "GROUP": true, "GSCLUSTER": true, "HAVING": true, "ILIKE": true,
+	"IN": true, "INCREMENT": true, "INNER": true, "INSERT": true, "INTERSECT": true, "INTO": true,
+	"IS": true, "ISSUE": true, "JOIN": true, "LATERAL": true, "LEFT": true, "LIKE": true,
+	"LOCALTIME": true, "LOCALTIMESTAMP": true, "MINUS": true, "NATURAL": true, "NOT": true,
+	"NULL": true, "OF": true, "ON": true, "OR": true, "ORDER": true, "ORGANIZATION": true,
+	"QUALIFY": true, "REGEXP": true, "REVOKE": true, "RIGHT": true, "RLIKE": true, "ROW": true,
+	"ROWS": true, "SAMPLE": true, "SCHEMA": true, "SELECT": true, "SET": true, "SOME": true,
+	"START": true, "TABLE": true, "TABLESAMPLE": true, "THEN": true, "TO": true, "TRIGGER": true,
+	"TRUE": true, "TRY_CAST": true, "UNION": true, "UNIQUE": true, "UPDATE": true, "USING": true,
+	"VALUES": true, "VIEW": true, "WHEN": true, "WHENEVER": true, "WHERE": true, "WINDOW": true,
+	"WITH": true,
+}
+
+// ReservedWords returns the sorted list of Snowflake reserved keywords
+func ReservedWords() []string {
+	words := make([]string, 0, len(reservedWords))
+	for word := range reservedWords {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}
+
+// IsReservedWord reports whether word is a Snowflake reserved keyword (case-insensitive)
+func IsReservedWord(word string) bool {
+	return reservedWords[strings.ToUpper(word)]
+}
+
+// IsValidIdentifier reports whether name can be used as a single identifier once quoted:
+// it is not empty, has no control characters and is at most MaxIdentifierLength characters long
+func IsValidIdentifier(name string) bool {
+	return validateIdentifier(name) == nil && utf8.RuneCountInString(name) <= MaxIdentifierLength
+}
+
+// RequiresQuoting reports whether name cannot be written as an unquoted identifier, i.e. it does
+// not start with a letter or an underscore, contains other characters than letters, digits,
+// underscores and dollar signs, or is a reserved keyword.
+// Note that unquoted identifiers are resolved uppercase, quoting is also required to keep the case
+func RequiresQuoting(name string) bool {
+	if name == "" || IsReservedWord(name) {
+		return true
+	}
+
+	for idx, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case idx > 0 && (r == '$' || r >= '0' && r <= '9'):
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// validateIdentifier rejects names that are empty or contain control characters,
+// which can never be part of a legitimate Snowflake identifier
+func validateIdentifier(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidIdentifier)
+	}
+
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("%w: %q contains control characters", ErrInvalidIdentifier, name)
+		}
+	}
+	return nil
+}
+
+// cleanIdentifier removes control characters from name.
+// It is used where an error cannot be surfaced (e.g. QuoteTo)
+func cleanIdentifier(name string) string {
+	if strings.IndexFunc(name, unicode.IsControl) < 0 {
+		return name
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+}
+
+// escapeIdentifier makes name safe to be written between double quotes:
+// control characters are dropped and embedded double quotes are doubled
+func escapeIdentifier(name string) string {
+	return strings.ReplaceAll(cleanIdentifier(name), `"`, `""`)
+}
+
+// quoteIdentifier returns name as an escaped, double quoted identifier
+func quoteIdentifier(name string) string {
+	return `"` + escapeIdentifier(name) + `"`
+}
+
+// quoteLiteral returns s as a single quoted string literal, for the DDL
+// statements (e.g. COMMENT) where Snowflake does not accept bind variables
+func quoteLiteral(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `''`).Replace(s) + "'"
+}
@@ -0,0 +1,217 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateIdentifier(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"users", true},
+		{"MY_SCHEMA.USERS", true},
+		{`weird "name"`, true},
+		{"", false},
+		{"users\n; DROP TABLE x", false},
+		{"tab\tle", false},
+		{"nul\x00", false},
+	}
+
+	for _, test := range tests {
+		err := validateIdentifier(test.name)
+		if test.valid && err != nil {
+			t.Errorf("Expected %q to be valid, got %v", test.name, err)
+		}
+		if !test.valid && !errors.Is(err, ErrInvalidIdentifier) {
+			t.Errorf("Expected ErrInvalidIdentifier for %q, got %v", test.name, err)
+		}
+	}
+}
+
+func TestEscapeIdentifier(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"users", "users"},
+		{`a"b`, `a""b`},
+		{"line\nbreak", "linebreak"},
+		{`x"; DROP TABLE users; --`, `x""; DROP TABLE users; --`},
+	}
+
+	for _, test := range tests {
+		if result := escapeIdentifier(test.input); result != test.expected {
+			t.Errorf("escapeIdentifier(%q): expected %q, got %q", test.input, test.expected, result)
+		}
+	}
+
+	if result := quoteIdentifier(`a"b`); result != `"a""b"` {
+		t.Errorf(`Expected "a""b", got %s`, result)
+	}
+}
+
+func TestQuoteToEscapesIdentifiers(t *testing.T) {
+	t.Run("Quotes Enabled", func(t *testing.T) {
+		builder := &strings.Builder{}
+		dialector := New(Config{QuoteFields: true})
+
+		dialector.QuoteTo(&mockClauseWriter{builder: builder}, `users"; DROP TABLE x; --`)
+
+		const expected = `"users""; DROP TABLE x; --"`
+		if builder.String() != expected {
+			t.Errorf("Expected %s got %s", expected, builder.String())
+		}
+	})
+
+	t.Run("Quotes Enabled with schema", func(t *testing.T) {
+		builder := &strings.Builder{}
+		dialector := New(Config{QuoteFields: true})
+
+		dialector.QuoteTo(&mockClauseWriter{builder: builder}, "sch\n.tab\"le")
+
+		const expected = `"sch"."tab""le"`
+		if builder.String() != expected {
+			t.Errorf("Expected %s got %s", expected, builder.String())
+		}
+	})
+
+	t.Run("Quotes Disabled", func(t *testing.T) {
+		builder := &strings.Builder{}
+		dialector := New(Config{})
+
+		dialector.QuoteTo(&mockClauseWriter{builder: builder}, "Users\r\n")
+
+		if builder.String() != "users" {
+			t.Errorf("Expected control characters to be dropped, got %q", builder.String())
+		}
+	})
+}
+
+func TestMigratorRejectsInvalidIdentifiers(t *testing.T) {
+	db := setupMockDB(t)
+	migrator := db.Migrator().(Migrator)
+
+	if err := migrator.RenameTable("old_table", "new\ntable"); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected RenameTable to reject control characters, got %v", err)
+	}
+
+	if err := migrator.DropConstraint(&MigratorTestModel{}, "chk\x00"); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected DropConstraint to reject control characters, got %v", err)
+	}
+
+	if err := migrator.CreateConstraint(&MigratorTestModel{}, ""); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected CreateConstraint to reject empty name, got %v", err)
+	}
+
+	dialector := New(Config{}).(*Dialector)
+	if err := dialector.RollbackTo(db, "sp; DROP\n"); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected RollbackTo to reject control characters, got %v", err)
+	}
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	tests := map[string]string{
+		"":         "''",
+		"plain":    "'plain'",
+		"it's":     "'it''s'",
+		`C:\temp`:  `'C:\\temp'`,
+		"what? ok": "'what? ok'",
+	}
+
+	for input, expected := range tests {
+		if got := quoteLiteral(input); got != expected {
+			t.Errorf("quoteLiteral(%q): expected %s, got %s", input, expected, got)
+		}
+	}
+}
+
+func TestIdentifierRules(t *testing.T) {
+	t.Run("RequiresQuoting", func(t *testing.T) {
+		tests := map[string]bool{
+			"users":      false,
+			"_private":   false,
+			"Col$1":      false,
+			"order":      true,
+			"Group":      true,
+			"1st":        true,
+			"$price":     true,
+			"first name": true,
+			"naïve":      true,
+			"":           true,
+		}
+		for name, expected := range tests {
+			if got := RequiresQuoting(name); got != expected {
+				t.Errorf("RequiresQuoting(%q): expected %v, got %v", name, expected, got)
+			}
+		}
+	})
+
+	t.Run("IsValidIdentifier", func(t *testing.T) {
+		if !IsValidIdentifier("order") || !IsValidIdentifier(`weird "name"`) {
+			t.Error("Expected quotable names to be valid")
+		}
+		if IsValidIdentifier("") || IsValidIdentifier("a\nb") || IsValidIdentifier(strings.Repeat("a", MaxIdentifierLength+1)) {
+			t.Error("Expected empty, control characters and too long names to be invalid")
+		}
+	})
+
+	t.Run("ReservedWords", func(t *testing.T) {
+		words := ReservedWords()
+		if len(words) == 0 || !IsReservedWord("select") || IsReservedWord("users") {
+			t.Errorf("Unexpected reserved words: %v", words)
+		}
+		for idx := 1; idx < len(words); idx++ {
+			if words[idx-1] >= words[idx] {
+				t.Fatalf("Expected sorted reserved words, got %v", words)
+			}
+		}
+	})
+
+	t.Run("QuoteTo without QuoteFields", func(t *testing.T) {
+		dialector := New(Config{}).(*Dialector)
+		tests := map[string]string{
+			"users.Order": `users."ORDER"`,
+			"first name":  `"first name"`,
+			"users.*":     "users.*",
+			"Users":       "users",
+		}
+		for input, expected := range tests {
+			builder := &strings.Builder{}
+			dialector.QuoteTo(&mockClauseWriter{builder: builder}, input)
+			if builder.String() != expected {
+				t.Errorf("QuoteTo(%q): expected %s, got %s", input, expected, builder.String())
+			}
+		}
+	})
+
+	t.Run("Reserved column created unquoted", func(t *testing.T) {
+		type reservedModel struct {
+			ID    uint
+			Order int
+		}
+
+		// a column created unquoted, e.g. by ALTER TABLE ... ADD COLUMN order, is stored as ORDER
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			if strings.Contains(query, "count(*)") {
+				return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(1)}}}
+			}
+			return fakeResult{columns: []string{"ID", "ORDER"}, rows: [][]driver.Value{{int64(1), int64(3)}}}
+		})
+
+		if !db.Migrator().HasColumn(&reservedModel{}, "Order") {
+			t.Error("Expected the column to exist")
+		}
+		if last := fake.statements[len(fake.statements)-1]; last.args[0] != "ORDER" {
+			t.Errorf("Expected the column looked up as ORDER, got %v", last.args)
+		}
+
+		db.Where(&reservedModel{Order: 3}).Find(&[]reservedModel{})
+		if !hasQuery(fake.queries(), `WHERE reserved_models."ORDER" = ?`) {
+			t.Errorf("Expected the column quoted as stored, got %v", fake.queries())
+		}
+	})
+}
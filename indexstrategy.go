@@ -0,0 +1,156 @@
+package snowflake
+
+import (
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// IndexStrategy selects how index declarations (`gorm:"index"`, `gorm:"uniqueIndex"`) are
+// migrated, as Snowflake has no indexes
+type IndexStrategy string
+
+const (
+	// IndexIgnore ignores index declarations (default)
+	IndexIgnore IndexStrategy = ""
+	// IndexClusterByFirstIndex uses the columns of the first index (in field order) as clustering key
+	IndexClusterByFirstIndex IndexStrategy = "cluster_by_first_index"
+	// IndexSearchOptimization adds search optimization ON EQUALITY for the columns of every index,
+	// it requires Enterprise Edition and is billed separately
+	IndexSearchOptimization IndexStrategy = "search_optimization"
+)
+
+// indexColumns returns the columns of the indexes declared on the schema of stmt,
+// ordered by the position of their first column in the model then by index name
+func indexColumns(stmt *gorm.Statement) [][]string {
+	if stmt.Schema == nil {
+		return nil
+	}
+
+	position := make(map[*schema.Field]int, len(stmt.Schema.Fields))
+	for idx, field := range stmt.Schema.Fields {
+		position[field] = idx
+	}
+
+	type declaredIndex struct {
+		name    string
+		first   int
+		columns []string
+	}
+
+	var indexes []declaredIndex
+	for _, index := range stmt.Schema.ParseIndexes() {
+		if len(index.Fields) == 0 {
+			continue
+		}
+
+		declared := declaredIndex{name: index.Name, first: position[index.Fields[0].Field]}
+		for _, option := range index.Fields {
+			declared.columns = append(declared.columns, option.DBName)
+		}
+		indexes = append(indexes, declared)
+	}
+
+	sort.Slice(indexes, func(i, j int) bool {
+		if indexes[i].first != indexes[j].first {
+			return indexes[i].first < indexes[j].first
+		}
+		return indexes[i].name < indexes[j].name
+	})
+
+	columns := make([][]string, len(indexes))
+	for idx, index := range indexes {
+		columns[idx] = index.columns
+	}
+	return columns
+}
+
+// indexStrategy returns the IndexStrategy configured on the dialector of m
+func (m Migrator) indexStrategy() IndexStrategy {
+	if config := dialectorConfig(m.Dialector); config != nil {
+		return config.IndexStrategy
+	}
+	return IndexIgnore
+}
+
+// clusterByColumns returns the clustering key declared by the model of stmt (see ClusteringKeyer),
+// else the one derived from its indexes, nil when IndexClusterByFirstIndex is not used or no
+// index is declared
+func (m Migrator) clusterByColumns(stmt *gorm.Statement) []interface{} {
+	if keys, declared := clusteringKeys(stmt); declared {
+		exprs, _ := clusteringKeyExprs(stmt, keys)
+		return exprs
+	}
+	if m.indexStrategy() != IndexClusterByFirstIndex {
+		return nil
+	}
+
+	indexes := indexColumns(stmt)
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	columns := make([]interface{}, len(indexes[0]))
+	for idx, column := range indexes[0] {
+		columns[idx] = clause.Column{Name: column}
+	}
+	return columns
+}
+
+// migrateIndexStrategy applies the IndexStrategy to the existing table of stmt: the clustering key
+// is altered when it differs from the first index, search optimization is added for every index
+func (m Migrator) migrateIndexStrategy(stmt *gorm.Statement) error {
+	switch m.indexStrategy() {
+	case IndexClusterByFirstIndex:
+		// a clustering key declared by the model is migrated by migrateClusteringKey
+		if _, declared := clusteringKeys(stmt); declared {
+			return nil
+		}
+		columns := m.clusterByColumns(stmt)
+		if len(columns) == 0 {
+			return nil
+		}
+
+		current, err := m.currentClusteringKey(stmt)
+		if err != nil {
+			return err
+		}
+
+		if normalizeClusteringKey(current) == normalizeClusteringKey("LINEAR("+strings.Join(indexColumns(stmt)[0], ",")+")") {
+			return nil
+		}
+		return m.DB.Exec("ALTER TABLE ? CLUSTER BY ?", m.CurrentTable(stmt), columns).Error
+	case IndexSearchOptimization:
+		return m.addSearchOptimization(stmt)
+	}
+	return nil
+}
+
+// addSearchOptimization adds search optimization ON EQUALITY for the columns of every index of stmt,
+// adding an already enabled method is a no-op for Snowflake
+func (m Migrator) addSearchOptimization(stmt *gorm.Statement) error {
+	if m.indexStrategy() != IndexSearchOptimization {
+		return nil
+	}
+
+	for _, index := range indexColumns(stmt) {
+		columns := make([]interface{}, len(index))
+		for idx, column := range index {
+			columns[idx] = clause.Column{Name: column}
+		}
+
+		if err := m.DB.Exec("ALTER TABLE ? ADD SEARCH OPTIMIZATION ON EQUALITY?", m.CurrentTable(stmt), columns).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeClusteringKey makes clustering keys comparable by dropping quotes and spaces
+// and uppercasing, e.g. `LINEAR("name", age)` becomes `LINEAR(NAME,AGE)`
+func normalizeClusteringKey(key string) string {
+	return strings.ToUpper(strings.NewReplacer(`"`, "", " ", "").Replace(key))
+}
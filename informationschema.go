@@ -0,0 +1,118 @@
+package snowflake
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InformationSchemaTable is a row of INFORMATION_SCHEMA.TABLES, the tables and views of the
+// current database. The INFORMATION_SCHEMA models name their columns uppercase, as Snowflake
+// does, so they are quoted correctly with Config.QuoteFields and scanned from the result columns.
+// Unquoted identifiers are stored uppercase:
+//
+//	var tables []snowflake.InformationSchemaTable
+//	err := db.Where(&snowflake.InformationSchemaTable{Schema: "PUBLIC", Type: "BASE TABLE"}).Find(&tables).Error
+type InformationSchemaTable struct {
+	Catalog          string         `gorm:"column:TABLE_CATALOG"`
+	Schema           string         `gorm:"column:TABLE_SCHEMA"`
+	Name             string         `gorm:"column:TABLE_NAME"`
+	Owner            sql.NullString `gorm:"column:TABLE_OWNER"`
+	Type             string         `gorm:"column:TABLE_TYPE"`
+	IsTransient      string         `gorm:"column:IS_TRANSIENT"`
+	ClusteringKey    sql.NullString `gorm:"column:CLUSTERING_KEY"`
+	RowCount         sql.NullInt64  `gorm:"column:ROW_COUNT"`
+	Bytes            sql.NullInt64  `gorm:"column:BYTES"`
+	RetentionTime    sql.NullInt64  `gorm:"column:RETENTION_TIME"`
+	Created          time.Time      `gorm:"column:CREATED"`
+	LastAltered      time.Time      `gorm:"column:LAST_ALTERED"`
+	AutoClusteringOn string         `gorm:"column:AUTO_CLUSTERING_ON"`
+	Comment          sql.NullString `gorm:"column:COMMENT"`
+}
+
+// TableName is INFORMATION_SCHEMA.TABLES
+func (InformationSchemaTable) TableName() string {
+	return "INFORMATION_SCHEMA.TABLES"
+}
+
+// InformationSchemaColumn is a row of INFORMATION_SCHEMA.COLUMNS, the columns of the tables and
+// views of the current database. Length, precision and scale are NULL for the types without them
+type InformationSchemaColumn struct {
+	Catalog                string         `gorm:"column:TABLE_CATALOG"`
+	Schema                 string         `gorm:"column:TABLE_SCHEMA"`
+	Table                  string         `gorm:"column:TABLE_NAME"`
+	Name                   string         `gorm:"column:COLUMN_NAME"`
+	OrdinalPosition        int64          `gorm:"column:ORDINAL_POSITION"`
+	Default                sql.NullString `gorm:"column:COLUMN_DEFAULT"`
+	IsNullable             string         `gorm:"column:IS_NULLABLE"`
+	DataType               string         `gorm:"column:DATA_TYPE"`
+	CharacterMaximumLength sql.NullInt64  `gorm:"column:CHARACTER_MAXIMUM_LENGTH"`
+	NumericPrecision       sql.NullInt64  `gorm:"column:NUMERIC_PRECISION"`
+	NumericScale           sql.NullInt64  `gorm:"column:NUMERIC_SCALE"`
+	IsIdentity             sql.NullString `gorm:"column:IS_IDENTITY"`
+	Comment                sql.NullString `gorm:"column:COMMENT"`
+}
+
+// TableName is INFORMATION_SCHEMA.COLUMNS
+func (InformationSchemaColumn) TableName() string {
+	return "INFORMATION_SCHEMA.COLUMNS"
+}
+
+// InformationSchemaTableConstraint is a row of INFORMATION_SCHEMA.TABLE_CONSTRAINTS, the primary,
+// unique and foreign keys of the tables of the current database
+type InformationSchemaTableConstraint struct {
+	ConstraintCatalog string         `gorm:"column:CONSTRAINT_CATALOG"`
+	ConstraintSchema  string         `gorm:"column:CONSTRAINT_SCHEMA"`
+	Name              string         `gorm:"column:CONSTRAINT_NAME"`
+	Catalog           string         `gorm:"column:TABLE_CATALOG"`
+	Schema            string         `gorm:"column:TABLE_SCHEMA"`
+	Table             string         `gorm:"column:TABLE_NAME"`
+	Type              string         `gorm:"column:CONSTRAINT_TYPE"`
+	IsDeferrable      string         `gorm:"column:IS_DEFERRABLE"`
+	InitiallyDeferred string         `gorm:"column:INITIALLY_DEFERRED"`
+	Enforced          string         `gorm:"column:ENFORCED"`
+	Comment           sql.NullString `gorm:"column:COMMENT"`
+}
+
+// TableName is INFORMATION_SCHEMA.TABLE_CONSTRAINTS
+func (InformationSchemaTableConstraint) TableName() string {
+	return "INFORMATION_SCHEMA.TABLE_CONSTRAINTS"
+}
+
+// informationSchema starts a query of the rows of the INFORMATION_SCHEMA view of model matching
+// query in the current database
+func (m Migrator) informationSchema(model interface{}, query string, args ...interface{}) *gorm.DB {
+	return m.DB.Session(&gorm.Session{NewDB: true}).Model(model).Where(query, args...).Where("table_catalog = ?", m.CurrentDatabase())
+}
+
+// informationSchemaTable is informationSchema matching the table of stmt, in the schema of its
+// name when qualified (e.g. many2many:analytics.user_tags) or in the current schema, and query
+func (m Migrator) informationSchemaTable(model interface{}, stmt *gorm.Statement, query string, args ...interface{}) *gorm.DB {
+	condition := "table_schema = CURRENT_SCHEMA() AND table_name = ?"
+	values := []interface{}{m.lookupName(stmt.Table)}
+	if schema, table, ok := qualifiedTableName(stmt); ok {
+		condition = "table_schema = ? AND table_name = ?"
+		values = []interface{}{m.lookupName(schema), m.lookupName(table)}
+	}
+	if query != "" {
+		condition = query + " AND " + condition
+		values = append(args, values...)
+	}
+	return m.informationSchema(model, condition, values...)
+}
+
+// qualifiedTableName returns the schema and table of the name of the table of stmt when
+// qualified by its schema
+func qualifiedTableName(stmt *gorm.Statement) (string, string, bool) {
+	name := stmt.Table
+	if stmt.Schema != nil && stmt.TableExpr != nil && strings.HasSuffix(stmt.Schema.Table, "."+stmt.Table) {
+		// gorm keeps the last part of the qualified names of models in Statement.Table
+		name = stmt.Schema.Table
+	}
+	if idx := strings.LastIndexByte(name, '.'); idx > 0 {
+		return name[:idx], name[idx+1:], true
+	}
+	return "", name, false
+}
@@ -0,0 +1,74 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+// queriesInformationSchema reports whether query reads the INFORMATION_SCHEMA view, quoted or not
+func queriesInformationSchema(query, view string) bool {
+	return strings.Contains(strings.ToUpper(strings.ReplaceAll(query, `"`, "")), "INFORMATION_SCHEMA."+view)
+}
+
+func TestInformationSchema(t *testing.T) {
+	t.Run("Quotes uppercase names", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, func(query string, args []interface{}) fakeResult {
+			return fakeResult{
+				columns: []string{"TABLE_NAME", "COLUMN_NAME", "DATA_TYPE", "NUMERIC_PRECISION", "COMMENT"},
+				rows:    [][]driver.Value{{"ORDERS", "ID", "NUMBER", int64(38), nil}, {"ORDERS", "NOTE", "TEXT", nil, "free text"}},
+			}
+		})
+
+		var columns []InformationSchemaColumn
+		if err := db.Where(&InformationSchemaColumn{Table: "ORDERS"}).Order("ordinal_position").Find(&columns).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := `SELECT * FROM "INFORMATION_SCHEMA"."COLUMNS" WHERE "COLUMNS"."TABLE_NAME" = ? ORDER BY ordinal_position`
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+		if len(columns) != 2 || columns[0].Name != "ID" || columns[0].NumericPrecision.Int64 != 38 ||
+			columns[1].DataType != "TEXT" || columns[1].NumericPrecision.Valid || columns[1].Comment.String != "free text" {
+			t.Errorf("Unexpected columns %+v", columns)
+		}
+	})
+
+	t.Run("Unquoted names", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		var constraints []InformationSchemaTableConstraint
+		db.Where(&InformationSchemaTableConstraint{Type: "PRIMARY KEY"}).Find(&constraints)
+		var tables []InformationSchemaTable
+		db.Where(&InformationSchemaTable{Schema: "PUBLIC"}).Find(&tables)
+
+		expected := []string{
+			"SELECT * FROM information_schema.table_constraints WHERE table_constraints.constraint_type = ?",
+			"SELECT * FROM information_schema.tables WHERE tables.table_schema = ?",
+		}
+		queries := fake.queries()
+		if len(queries) != len(expected) || queries[0] != expected[0] || queries[1] != expected[1] {
+			t.Errorf("Expected %v, got %v", expected, queries)
+		}
+	})
+
+	t.Run("Migrator", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, func(query string, args []interface{}) fakeResult {
+			if strings.Contains(query, "count(*)") {
+				return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(1)}}}
+			}
+			return fakeResult{columns: []string{"CURRENT_DATABASE()"}, rows: [][]driver.Value{{"ANALYTICS"}}}
+		})
+
+		if !db.Migrator().HasConstraint(&TestModel{}, "test_models_pkey") {
+			t.Error("Expected the constraint to exist")
+		}
+
+		last := fake.statements[len(fake.statements)-1]
+		expected := `SELECT count(*) FROM "INFORMATION_SCHEMA"."TABLE_CONSTRAINTS" WHERE (constraint_name = ? AND table_schema = CURRENT_SCHEMA() AND table_name = ?) AND table_catalog = ?`
+		if last.query != expected || len(last.args) != 3 || last.args[0] != "test_models_pkey" || last.args[2] != "ANALYTICS" {
+			t.Errorf("Expected %s with the current database, got %s %v", expected, last.query, last.args)
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultInListBindThreshold = 1000
+
+	// preloadKey marks the statements of the queries run by gorm:preload
+	preloadKey = "snowflake:preload"
+)
+
+// inListBindThreshold returns the number of values above which the IN lists of preloads are
+// bound as a single JSON array, 0 when the rewrite is disabled
+func (config *Config) inListBindThreshold() int {
+	switch {
+	case config.InListBindThreshold < 0:
+		return 0
+	case config.InListBindThreshold == 0:
+		return defaultInListBindThreshold
+	}
+	return config.InListBindThreshold
+}
+
+// registerPreloadCallbacks marks the queries run by gorm:preload, their statements copy the
+// settings of the query preloading, and registers RewritePreloadIN before gorm:query
+func registerPreloadCallbacks(db *gorm.DB) {
+	callback := db.Callback().Query()
+	_ = callback.After("gorm:query").Before("gorm:preload").Register("snowflake:mark_preload", func(db *gorm.DB) {
+		db.Statement.Settings.Store(preloadKey, true)
+	})
+	_ = callback.After("gorm:preload").Register("snowflake:unmark_preload", func(db *gorm.DB) {
+		db.Statement.Settings.Delete(preloadKey)
+	})
+	_ = callback.Before("gorm:query").Register("snowflake:preload_in_lists", RewritePreloadIN)
+}
+
+// RewritePreloadIN binds the IN conditions of the queries of a Preload having more than
+// Config.InListBindThreshold values (the keys of the parents) as one JSON array, so preloading
+// tens of thousands of parents does not exceed the bind variable limits. Other queries are
+// not rewritten
+func RewritePreloadIN(db *gorm.DB) {
+	if _, preloading := db.Statement.Settings.Load(preloadKey); !preloading || db.Error != nil {
+		return
+	}
+	config := dialectorConfig(db.Dialector)
+	if config == nil || config.inListBindThreshold() == 0 {
+		return
+	}
+	if c, ok := db.Statement.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			c.Expression = clause.Where{Exprs: rewriteLargeIN(where.Exprs, config.inListBindThreshold())}
+			db.Statement.Clauses["WHERE"] = c
+		}
+	}
+}
+
+// jsonArrayIN is an IN condition binding its values as one JSON array, expanded server side
+// with FLATTEN, so lists such as the parent keys of a large Preload do not require one bind
+// variable per value. The values of a condition on several columns are arrays of the values
+// of the columns
+type jsonArrayIN struct {
+	Column interface{}
+	Values []interface{}
+}
+
+func (in jsonArrayIN) Build(builder clause.Builder) {
+	in.build(builder, " IN ")
+}
+
+func (in jsonArrayIN) NegationBuild(builder clause.Builder) {
+	in.build(builder, " NOT IN ")
+}
+
+func (in jsonArrayIN) build(builder clause.Builder, operator string) {
+	array, err := json.Marshal(in.Values)
+	if err != nil {
+		addBuilderError(builder, fmt.Errorf("failed to encode IN list: %w", err))
+		return
+	}
+
+	if columns, composite := in.Column.([]clause.Column); composite {
+		builder.WriteByte('(')
+		for idx, column := range columns {
+			if idx > 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteQuoted(column)
+		}
+		builder.WriteByte(')')
+		builder.WriteString(operator)
+		builder.WriteString("(SELECT ")
+		for idx := range columns {
+			if idx > 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteString("value[" + strconv.Itoa(idx) + "]")
+		}
+		builder.WriteString(" FROM TABLE(FLATTEN(INPUT => PARSE_JSON(")
+	} else {
+		builder.WriteQuoted(in.Column)
+		builder.WriteString(operator)
+		builder.WriteString("(SELECT value FROM TABLE(FLATTEN(INPUT => PARSE_JSON(")
+	}
+	builder.AddVar(builder, string(array))
+	builder.WriteString("))))")
+}
+
+// jsonArrayValues returns values with the driver.Valuer values replaced by their value, false
+// when one is not a string or a finite number: JSON has no representation of the others Snowflake
+// compares equal to the column (e.g. time.Time or []byte)
+func jsonArrayValues(values []interface{}) ([]interface{}, bool) {
+	jsonValues := make([]interface{}, len(values))
+	for idx, value := range values {
+		if valuer, ok := value.(driver.Valuer); ok {
+			var err error
+			if value, err = valuer.Value(); err != nil {
+				return nil, false
+			}
+		}
+
+		switch reflectValue := reflect.ValueOf(value); reflectValue.Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		case reflect.Float32, reflect.Float64:
+			if math.IsNaN(reflectValue.Float()) || math.IsInf(reflectValue.Float(), 0) {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+		jsonValues[idx] = value
+	}
+	return jsonValues, true
+}
+
+// jsonArrayRows returns the values of an IN condition on column for jsonArrayIN, the values of
+// a condition on several columns are slices of the values of the columns
+func jsonArrayRows(column interface{}, values []interface{}) ([]interface{}, bool) {
+	columns, composite := column.([]clause.Column)
+	if !composite {
+		return jsonArrayValues(values)
+	}
+
+	rows := make([]interface{}, len(values))
+	for idx, value := range values {
+		row, ok := value.([]interface{})
+		if !ok || len(row) != len(columns) {
+			return nil, false
+		}
+		if rows[idx], ok = jsonArrayValues(row); !ok {
+			return nil, false
+		}
+	}
+	return rows, true
+}
+
+// addBuilderError reports err on the statement being built, if builder is one
+func addBuilderError(builder clause.Builder, err error) {
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		stmt.AddError(err)
+	}
+}
+
+// rewriteLargeIN replaces the IN conditions of exprs having more than threshold string or
+// number values with jsonArrayIN, descending into AND/OR/NOT groups
+func rewriteLargeIN(exprs []clause.Expression, threshold int) []clause.Expression {
+	rewritten := make([]clause.Expression, len(exprs))
+	for idx, expr := range exprs {
+		switch v := expr.(type) {
+		case clause.IN:
+			if len(v.Values) > threshold {
+				if values, ok := jsonArrayRows(v.Column, v.Values); ok {
+					expr = jsonArrayIN{Column: v.Column, Values: values}
+				}
+			}
+		case clause.AndConditions:
+			expr = clause.AndConditions{Exprs: rewriteLargeIN(v.Exprs, threshold)}
+		case clause.OrConditions:
+			expr = clause.OrConditions{Exprs: rewriteLargeIN(v.Exprs, threshold)}
+		case clause.NotConditions:
+			expr = clause.NotConditions{Exprs: rewriteLargeIN(v.Exprs, threshold)}
+		}
+		rewritten[idx] = expr
+	}
+	return rewritten
+}
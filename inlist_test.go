@@ -0,0 +1,191 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InListParent struct {
+	ID       uint
+	Children []InListChild `gorm:"foreignKey:ParentID"`
+}
+
+type InListChild struct {
+	ID       uint
+	ParentID uint
+}
+
+type InListRegionalParent struct {
+	ID       uint                  `gorm:"primaryKey"`
+	Region   string                `gorm:"primaryKey"`
+	Children []InListRegionalChild `gorm:"foreignKey:ParentID,ParentRegion"`
+}
+
+type InListRegionalChild struct {
+	ID           uint
+	ParentID     uint
+	ParentRegion string
+}
+
+func TestInListRewrite(t *testing.T) {
+	ids := []interface{}{1, 2, 3, 4}
+
+	t.Run("Large IN bound as JSON array", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{InListBindThreshold: 3}, nil)
+
+		var models []TestModel
+		stmt := db.Session(&gorm.Session{DryRun: true}).Set(preloadKey, true).Where(clause.IN{Column: clause.Column{Name: "id"}, Values: ids}).Find(&models).Statement
+
+		expected := "SELECT * FROM test_models WHERE id IN (SELECT value FROM TABLE(FLATTEN(INPUT => PARSE_JSON(?))))"
+		if stmt.SQL.String() != expected {
+			t.Errorf("Expected %s, got %s", expected, stmt.SQL.String())
+		}
+		if len(stmt.Vars) != 1 || stmt.Vars[0] != "[1,2,3,4]" {
+			t.Errorf("Expected a single JSON array var, got %v", stmt.Vars)
+		}
+	})
+
+	t.Run("Nested and negated conditions", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{InListBindThreshold: 3}, nil)
+
+		var models []TestModel
+		stmt := db.Session(&gorm.Session{DryRun: true}).Set(preloadKey, true).
+			Where("age > ?", 18).
+			Not(clause.IN{Column: clause.Column{Name: "id"}, Values: ids}).
+			Find(&models).Statement
+
+		if !strings.Contains(stmt.SQL.String(), "id NOT IN (SELECT value FROM TABLE(FLATTEN(") {
+			t.Errorf("Expected NOT IN rewrite, got %s", stmt.SQL.String())
+		}
+		if len(stmt.Vars) != 2 {
+			t.Errorf("Expected 2 vars, got %v", stmt.Vars)
+		}
+	})
+
+	t.Run("Queries other than preloads keep binds", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{InListBindThreshold: 3}, nil)
+
+		var models []TestModel
+		stmt := db.Session(&gorm.Session{DryRun: true}).Where(clause.IN{Column: clause.Column{Name: "id"}, Values: ids}).Find(&models).Statement
+
+		if !strings.Contains(stmt.SQL.String(), "id IN (?,?,?,?)") || len(stmt.Vars) != 4 {
+			t.Errorf("Expected one bind per value, got %s %v", stmt.SQL.String(), stmt.Vars)
+		}
+	})
+
+	t.Run("Small IN and disabled rewrite keep binds", func(t *testing.T) {
+		for _, config := range []Config{{}, {InListBindThreshold: 10}, {InListBindThreshold: -1}} {
+			db, _ := setupFakeDB(t, config, nil)
+
+			var models []TestModel
+			stmt := db.Session(&gorm.Session{DryRun: true}).Set(preloadKey, true).Where(clause.IN{Column: clause.Column{Name: "id"}, Values: ids}).Find(&models).Statement
+
+			if !strings.Contains(stmt.SQL.String(), "id IN (?,?,?,?)") || len(stmt.Vars) != 4 {
+				t.Errorf("Expected one bind per value with %+v, got %s %v", config.InListBindThreshold, stmt.SQL.String(), stmt.Vars)
+			}
+		}
+	})
+
+	t.Run("Values without a JSON representation keep binds", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{InListBindThreshold: 3}, nil)
+
+		day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		for _, values := range [][]interface{}{
+			{day, day.AddDate(0, 0, 1), day.AddDate(0, 0, 2), day.AddDate(0, 0, 3)},
+			{[]byte{1}, []byte{2}, []byte{3}, []byte{4}},
+			{1, 2, 3, math.NaN()},
+		} {
+			var models []TestModel
+			stmt := db.Session(&gorm.Session{DryRun: true}).Set(preloadKey, true).Where(clause.IN{Column: clause.Column{Name: "created_at"}, Values: values}).Find(&models).Statement
+
+			if !strings.Contains(stmt.SQL.String(), "created_at IN (?,?,?,?)") || len(stmt.Vars) != 4 {
+				t.Errorf("Expected one bind per value of %T, got %s %v", values[0], stmt.SQL.String(), stmt.Vars)
+			}
+		}
+	})
+
+	t.Run("Preload", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{InListBindThreshold: 3}, func(query string, args []interface{}) fakeResult {
+			if strings.Contains(query, "in_list_parents") {
+				return fakeResult{
+					columns: []string{"id"},
+					rows:    [][]driver.Value{{int64(1)}, {int64(2)}, {int64(3)}, {int64(4)}},
+				}
+			}
+			return fakeResult{}
+		})
+
+		var parents []InListParent
+		if err := db.Preload("Children").Find(&parents).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(fake.statements) != 2 {
+			t.Fatalf("Expected 2 statements, got %v", fake.queries())
+		}
+		preload := fake.statements[1]
+		if !strings.Contains(preload.query, "parent_id IN (SELECT value FROM TABLE(FLATTEN(") {
+			t.Errorf("Expected preload IN to be rewritten, got %s", preload.query)
+		}
+		if len(preload.args) != 1 || preload.args[0] != "[1,2,3,4]" {
+			t.Errorf("Expected a single JSON array arg, got %v", preload.args)
+		}
+	})
+	t.Run("Preload with the default threshold", func(t *testing.T) {
+		rows := make([][]driver.Value, defaultInListBindThreshold+1)
+		for idx := range rows {
+			rows[idx] = []driver.Value{int64(idx + 1)}
+		}
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			if strings.Contains(query, "in_list_parents") {
+				return fakeResult{columns: []string{"id"}, rows: rows}
+			}
+			return fakeResult{}
+		})
+
+		var parents []InListParent
+		if err := db.Preload("Children").Find(&parents).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(fake.statements) != 2 || len(fake.statements[1].args) != 1 {
+			t.Errorf("Expected the preload bound as one JSON array, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Composite key preload", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{InListBindThreshold: 1}, func(query string, args []interface{}) fakeResult {
+			if strings.Contains(query, "in_list_regional_parents") {
+				return fakeResult{
+					columns: []string{"id", "region"},
+					rows:    [][]driver.Value{{int64(1), "eu"}, {int64(1), "us"}},
+				}
+			}
+			return fakeResult{columns: []string{"id", "parent_id", "parent_region"}, rows: [][]driver.Value{{int64(7), int64(1), "us"}}}
+		})
+
+		var parents []InListRegionalParent
+		if err := db.Preload("Children").Find(&parents).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(fake.statements) != 2 {
+			t.Fatalf("Expected 2 statements, got %v", fake.queries())
+		}
+		preload := fake.statements[1]
+		if !strings.Contains(preload.query, "(in_list_regional_children.parent_id,in_list_regional_children.parent_region) IN (SELECT value[0],value[1] FROM TABLE(FLATTEN(") {
+			t.Errorf("Expected preload IN to be rewritten, got %s", preload.query)
+		}
+		if len(preload.args) != 1 || preload.args[0] != `[[1,"eu"],[1,"us"]]` {
+			t.Errorf("Expected a single JSON array of keys, got %v", preload.args)
+		}
+		if len(parents) != 2 || len(parents[1].Children) != 1 || len(parents[0].Children) != 0 {
+			t.Errorf("Expected the child preloaded into the us parent, got %+v", parents)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package snowflake
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/snowflakedb/gosnowflake"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InsertMode is the syntax of the INSERT statements built by Create
+type InsertMode string
+
+const (
+	// InsertDefault follows Config.UseUnionSelect
+	InsertDefault InsertMode = ""
+	// InsertValues writes the rows in a VALUES clause, which does not accept SQL functions
+	InsertValues InsertMode = "VALUES"
+	// InsertUnionSelect writes each row as a SELECT joined with UNION ALL, which accepts SQL
+	// functions (gorm.Expr, PARSE_JSON...) but is slower to compile
+	InsertUnionSelect InsertMode = "UNION SELECT"
+	// InsertArrayBind binds the values of each column as one array to a single row of
+	// placeholders, so the SQL text does not grow with the rows. Values are bound as text,
+	// rows holding SQL expressions, semi-structured or tokenized values use UNION SELECT
+	InsertArrayBind InsertMode = "ARRAY BIND"
+)
+
+// InsertStrategyer is implemented by models whose rows are inserted with their own InsertMode,
+// instead of the one of Config.UseUnionSelect:
+//
+//	func (Event) InsertStrategy() snowflake.InsertMode { return snowflake.InsertArrayBind }
+type InsertStrategyer interface {
+	InsertStrategy() InsertMode
+}
+
+// insertMode returns the InsertMode of the rows created by db
+func insertMode(db *gorm.DB) (InsertMode, error) {
+	mode := InsertDefault
+	if db.Statement.Schema != nil {
+		if strategyer, ok := reflect.New(db.Statement.Schema.ModelType).Interface().(InsertStrategyer); ok {
+			mode = strategyer.InsertStrategy()
+		}
+	}
+
+	switch mode {
+	case InsertDefault:
+		if shouldUseUnionSelect(db) {
+			return InsertUnionSelect, nil
+		}
+		return InsertValues, nil
+	case InsertValues, InsertUnionSelect, InsertArrayBind:
+		return mode, nil
+	}
+	return mode, fmt.Errorf("unsupported insert mode %q", mode)
+}
+
+// arrayBindColumns returns the values of each column of values, bound as text (NULL for nil)
+// and as JSON text for semi-structured columns
+func arrayBindColumns(values clause.Values, types []string) ([][]interface{}, error) {
+	columns := make([][]interface{}, len(values.Columns))
+	for idx := range columns {
+		columns[idx] = make([]interface{}, len(values.Values))
+	}
+	for rowIdx, row := range values.Values {
+		for idx, value := range row {
+			var err error
+			if idx < len(types) && types[idx] != "" {
+				if value, err = semiStructuredJSON(value); err != nil {
+					return nil, err
+				}
+			}
+			if columns[idx][rowIdx], err = mergeBindValue(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return columns, nil
+}
+
+// buildArrayBindInsert builds the rest of an INSERT statement binding an array per column,
+// after INSERT INTO <table>
+func buildArrayBindInsert(db *gorm.DB, values clause.Values) {
+	columns, err := arrayBindColumns(values, nil)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	db.Statement.WriteByte('(')
+	for idx, column := range values.Columns {
+		if idx > 0 {
+			db.Statement.WriteByte(',')
+		}
+		db.Statement.WriteQuoted(column)
+	}
+	db.Statement.WriteString(") VALUES (")
+	for idx := range columns {
+		if idx > 0 {
+			db.Statement.WriteByte(',')
+		}
+		db.Statement.AddVar(db.Statement, gosnowflake.Array(&columns[idx]))
+	}
+	db.Statement.WriteByte(')')
+	writeStatementEnd(db)
+}
@@ -0,0 +1,76 @@
+package snowflake
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type ArrayBindModel struct {
+	ID   uint `gorm:"primaryKey;autoIncrement:false"`
+	Name string
+}
+
+func (ArrayBindModel) InsertStrategy() InsertMode { return InsertArrayBind }
+
+type ValuesModel struct {
+	ID   uint `gorm:"primaryKey;autoIncrement:false"`
+	Name string
+}
+
+func (ValuesModel) InsertStrategy() InsertMode { return InsertValues }
+
+type InvalidInsertModeModel struct {
+	ID uint
+}
+
+func (InvalidInsertModeModel) InsertStrategy() InsertMode { return "BULK" }
+
+func TestInsertStrategy(t *testing.T) {
+	t.Run("Array binds", func(t *testing.T) {
+		db := setupMockDBWithConfig(t, true, true).Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true})
+		tx := db.Create(&[]ArrayBindModel{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3}})
+		if tx.Error != nil {
+			t.Fatalf("Expected no error, got %v", tx.Error)
+		}
+
+		if sql := tx.Statement.SQL.String(); sql != `INSERT INTO "array_bind_models" ("id","name") VALUES (?,?);` {
+			t.Errorf("Expected a single row of placeholders, got %s", sql)
+		}
+		if len(tx.Statement.Vars) != 2 {
+			t.Errorf("Expected one array per column, got %v", tx.Statement.Vars)
+		}
+	})
+
+	t.Run("SQL expressions are selected", func(t *testing.T) {
+		db := setupMockDBWithConfig(t, true, true).Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true})
+		tx := db.Model(&ArrayBindModel{}).Create(map[string]interface{}{"id": 1, "name": gorm.Expr("UPPER(?)", "a")})
+		if tx.Error != nil {
+			t.Fatalf("Expected no error, got %v", tx.Error)
+		}
+
+		if sql := tx.Statement.SQL.String(); !strings.Contains(sql, "SELECT ") || !strings.Contains(sql, "UPPER(?)") {
+			t.Errorf("Expected UNION SELECT, got %s", sql)
+		}
+	})
+
+	t.Run("Overrides UseUnionSelect", func(t *testing.T) {
+		db := setupMockDBWithConfig(t, true, true).Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true})
+		tx := db.Create(&[]ValuesModel{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}})
+		if tx.Error != nil {
+			t.Fatalf("Expected no error, got %v", tx.Error)
+		}
+
+		if sql := tx.Statement.SQL.String(); sql != `INSERT INTO "values_models" ("id","name") VALUES (?,?),(?,?);` {
+			t.Errorf("Expected VALUES, got %s", sql)
+		}
+	})
+
+	t.Run("Unsupported mode", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true})
+		if err := db.Create(&InvalidInsertModeModel{ID: 1}).Error; err == nil || !strings.Contains(err.Error(), "unsupported insert mode") {
+			t.Errorf("Expected an unsupported insert mode error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrUnsupportedInsertModifier is returned for clause.Insert modifiers Snowflake does not support
+var ErrUnsupportedInsertModifier = errors.New("unsupported INSERT modifier")
+
+// insertOverwrite is the modifier of INSERT OVERWRITE, which truncates the table in the same transaction
+const insertOverwrite = "OVERWRITE"
+
+// parseInsertModifier splits the modifier of a clause.Insert into OVERWRITE and /*+ hint */
+// comments, the modifiers Snowflake supports:
+//
+//	db.Clauses(clause.Insert{Modifier: "OVERWRITE"}).Create(&rows)
+//
+// Every statement of CreateInBatches truncates the table, OVERWRITE is refused with CreateBatchSize
+// and a Create split by Config.MaxStatementBytes only overwrites with its first statement
+func parseInsertModifier(modifier string) (overwrite bool, hints []string, err error) {
+	for rest := strings.TrimSpace(modifier); rest != ""; rest = strings.TrimSpace(rest) {
+		if strings.HasPrefix(rest, "/*+") {
+			end := strings.Index(rest, "*/")
+			if end < 0 {
+				return false, nil, fmt.Errorf("%w: unterminated hint %q", ErrUnsupportedInsertModifier, rest)
+			}
+			hints = append(hints, rest[:end+2])
+			rest = rest[end+2:]
+			continue
+		}
+
+		word := rest
+		if end := strings.IndexAny(rest, " \t\n/"); end >= 0 {
+			word = rest[:end]
+		}
+		if !strings.EqualFold(word, insertOverwrite) {
+			return false, nil, fmt.Errorf("%w: %s", ErrUnsupportedInsertModifier, word)
+		}
+		overwrite = true
+		rest = rest[len(word):]
+	}
+	return overwrite, hints, nil
+}
+
+// formatInsertModifier returns the modifier written after INSERT
+func formatInsertModifier(overwrite bool, hints []string) string {
+	if overwrite {
+		hints = append(append([]string(nil), hints...), insertOverwrite)
+	}
+	return strings.Join(hints, " ")
+}
+
+// prepareInsertModifier validates and normalizes the modifier of the INSERT clause of the
+// statement, overwrite reports whether it is an INSERT OVERWRITE
+func prepareInsertModifier(db *gorm.DB) (overwrite bool, err error) {
+	c, ok := db.Statement.Clauses["INSERT"]
+	if !ok {
+		return false, nil
+	}
+	insert, ok := c.Expression.(clause.Insert)
+	if !ok || insert.Modifier == "" {
+		return false, nil
+	}
+
+	overwrite, hints, err := parseInsertModifier(insert.Modifier)
+	if err != nil {
+		return false, err
+	}
+	insert.Modifier = formatInsertModifier(overwrite, hints)
+	c.Expression = insert
+	db.Statement.Clauses["INSERT"] = c
+	return overwrite, nil
+}
+
+// withoutInsertOverwrite drops OVERWRITE from the INSERT clause of the statement, for the
+// batches of a Create following the first one which must not truncate the table again
+func withoutInsertOverwrite(db *gorm.DB) {
+	c, ok := db.Statement.Clauses["INSERT"]
+	if !ok {
+		return
+	}
+	if insert, ok := c.Expression.(clause.Insert); ok {
+		_, hints, _ := parseInsertModifier(insert.Modifier)
+		insert.Modifier = formatInsertModifier(false, hints)
+		c.Expression = insert
+		db.Statement.Clauses["INSERT"] = c
+	}
+}
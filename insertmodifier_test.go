@@ -0,0 +1,90 @@
+package snowflake
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestParseInsertModifier(t *testing.T) {
+	for modifier, expected := range map[string]string{
+		"overwrite":                      "OVERWRITE",
+		"/*+ batch */":                   "/*+ batch */",
+		"OVERWRITE /*+ a */ /*+ b */":    "/*+ a */ /*+ b */ OVERWRITE",
+		"  /*+ load-job */   Overwrite ": "/*+ load-job */ OVERWRITE",
+	} {
+		overwrite, hints, err := parseInsertModifier(modifier)
+		if err != nil {
+			t.Fatalf("Expected %q to be supported, got %v", modifier, err)
+		}
+		if formatted := formatInsertModifier(overwrite, hints); formatted != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, modifier, formatted)
+		}
+	}
+
+	for _, modifier := range []string{"IGNORE", "LOW_PRIORITY", "/*+ unterminated"} {
+		if _, _, err := parseInsertModifier(modifier); !errors.Is(err, ErrUnsupportedInsertModifier) {
+			t.Errorf("Expected ErrUnsupportedInsertModifier for %q, got %v", modifier, err)
+		}
+	}
+}
+
+func TestCreateInsertModifier(t *testing.T) {
+	t.Run("Overwrite", func(t *testing.T) {
+		db := setupMockDB(t)
+		stmt := db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true}).Clauses(clause.Insert{Modifier: "overwrite /*+ nightly */"}).
+			Create(&[]TestModel{{Name: "a"}}).Statement
+
+		if sql := stmt.SQL.String(); !strings.HasPrefix(sql, `INSERT /*+ nightly */ OVERWRITE INTO "test_models"`) {
+			t.Errorf("Expected INSERT OVERWRITE, got %s", sql)
+		}
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		db := setupMockDB(t)
+		err := db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true}).Clauses(clause.Insert{Modifier: "IGNORE"}).Create(&TestModel{Name: "a"}).Error
+		if !errors.Is(err, ErrUnsupportedInsertModifier) {
+			t.Errorf("Expected ErrUnsupportedInsertModifier, got %v", err)
+		}
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		db := setupMockDB(t)
+		err := db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true}).Clauses(clause.Insert{Modifier: "OVERWRITE"}, clause.OnConflict{UpdateAll: true}).
+			Create(&TestModel{ID: 1, Name: "a"}).Error
+		if !errors.Is(err, ErrUnsupportedInsertModifier) {
+			t.Errorf("Expected ErrUnsupportedInsertModifier, got %v", err)
+		}
+	})
+
+	t.Run("CreateBatchSize", func(t *testing.T) {
+		db := setupMockDB(t)
+		err := db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true, CreateBatchSize: 10}).Clauses(clause.Insert{Modifier: "OVERWRITE"}).
+			Create(&[]TestModel{{Name: "a"}}).Error
+		if !errors.Is(err, ErrUnsupportedInsertModifier) {
+			t.Errorf("Expected ErrUnsupportedInsertModifier, got %v", err)
+		}
+	})
+
+	t.Run("Only the first batch overwrites", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true, MaxStatementBytes: 1}, nil)
+		err := db.Clauses(clause.Insert{Modifier: "OVERWRITE"}).
+			Create(&[]TestModel{{Name: "a"}, {Name: "b"}}).Error
+		if err != nil {
+			t.Fatalf("Expected create to succeed, got %v", err)
+		}
+
+		var inserts []string
+		for _, query := range fake.queries() {
+			if strings.HasPrefix(query, "INSERT") {
+				inserts = append(inserts, query)
+			}
+		}
+		if len(inserts) != 2 || !strings.HasPrefix(inserts[0], "INSERT OVERWRITE INTO") || !strings.HasPrefix(inserts[1], "INSERT INTO") {
+			t.Errorf("Expected only the first batch to overwrite, got %v", inserts)
+		}
+	})
+}
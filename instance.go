@@ -0,0 +1,101 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// InstanceError is the error of a statement run by a DB whose dialector has a Config.InstanceName,
+// so errors of connections to different accounts can be told apart. It unwraps to the error of
+// the statement, errors.Is and errors.As see through it
+type InstanceError struct {
+	Instance string
+	Err      error
+}
+
+func (err *InstanceError) Error() string {
+	return err.Instance + ": " + err.Err.Error()
+}
+
+func (err *InstanceError) Unwrap() error {
+	return err.Err
+}
+
+// InstanceName returns the Config.InstanceName of the dialector of db, e.g. to label metrics
+func InstanceName(db *gorm.DB) string {
+	if config := dialectorConfig(db.Dialector); config != nil {
+		return config.InstanceName
+	}
+	return ""
+}
+
+// registerInstanceCallbacks registers LabelError after every statement
+func registerInstanceCallbacks(db *gorm.DB) {
+	callback := db.Callback()
+	_ = callback.Create().After("*").Register("snowflake:label_error", LabelError)
+	_ = callback.Query().After("*").Register("snowflake:label_error", LabelError)
+	_ = callback.Update().After("*").Register("snowflake:label_error", LabelError)
+	_ = callback.Delete().After("*").Register("snowflake:label_error", LabelError)
+	_ = callback.Row().After("*").Register("snowflake:label_error", LabelError)
+	_ = callback.Raw().After("*").Register("snowflake:label_error", LabelError)
+}
+
+// LabelError wraps the error of the statement in an InstanceError naming Config.InstanceName.
+// gorm.ErrRecordNotFound is left as is, as it is commonly compared with ==
+func LabelError(db *gorm.DB) {
+	name := InstanceName(db)
+	if name == "" || db.Error == nil || errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		return
+	}
+
+	var labeled *InstanceError
+	if !errors.As(db.Error, &labeled) {
+		db.Error = &InstanceError{Instance: name, Err: db.Error}
+	}
+}
+
+// instanceLogger prefixes the messages and the SQL traces of a logger with the instance name
+type instanceLogger struct {
+	logger.Interface
+	prefix string
+}
+
+// newInstanceLogger returns l logging for the instance name
+func newInstanceLogger(l logger.Interface, name string) logger.Interface {
+	return instanceLogger{Interface: l, prefix: "[" + name + "] "}
+}
+
+func (l instanceLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return instanceLogger{Interface: l.Interface.LogMode(level), prefix: l.prefix}
+}
+
+func (l instanceLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	l.Interface.Info(ctx, l.prefix+msg, data...)
+}
+
+func (l instanceLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	l.Interface.Warn(ctx, l.prefix+msg, data...)
+}
+
+func (l instanceLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	l.Interface.Error(ctx, l.prefix+msg, data...)
+}
+
+func (l instanceLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	l.Interface.Trace(ctx, begin, func() (string, int64) {
+		sql, rows := fc()
+		return l.prefix + sql, rows
+	}, err)
+}
+
+// ParamsFilter delegates to the wrapped logger, which gorm would not see otherwise
+func (l instanceLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if filter, ok := l.Interface.(gorm.ParamsFilter); ok {
+		return filter.ParamsFilter(ctx, sql, params...)
+	}
+	return sql, params
+}
@@ -0,0 +1,97 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordingLogger records the messages and traced SQL it is given
+type recordingLogger struct {
+	logger.Interface
+	messages *[]string
+}
+
+func (l recordingLogger) LogMode(logger.LogLevel) logger.Interface { return l }
+
+func (l recordingLogger) Warn(_ context.Context, msg string, data ...interface{}) {
+	*l.messages = append(*l.messages, fmt.Sprintf(msg, data...))
+}
+
+func (l recordingLogger) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	*l.messages = append(*l.messages, sql)
+}
+
+func TestInstanceName(t *testing.T) {
+	failing := func(query string, args []interface{}) fakeResult {
+		if strings.HasPrefix(query, "DELETE") {
+			return fakeResult{err: errors.New("insufficient privileges")}
+		}
+		return fakeResult{}
+	}
+
+	t.Run("Errors are labeled", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{InstanceName: "analytics"}, failing)
+
+		err := db.Exec("DELETE FROM orders").Error
+		var labeled *InstanceError
+		if !errors.As(err, &labeled) || labeled.Instance != "analytics" {
+			t.Fatalf("Expected an InstanceError, got %v", err)
+		}
+		if !strings.HasPrefix(err.Error(), "analytics: ") || !strings.Contains(err.Error(), "insufficient privileges") {
+			t.Errorf("Unexpected message %q", err.Error())
+		}
+
+		err = db.Where("1 = 0").First(&TestModel{}).Error
+		if err != gorm.ErrRecordNotFound {
+			t.Errorf("Expected gorm.ErrRecordNotFound as is, got %v", err)
+		}
+	})
+
+	t.Run("Unlabeled by default", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, failing)
+
+		var labeled *InstanceError
+		if err := db.Exec("DELETE FROM orders").Error; err == nil || errors.As(err, &labeled) {
+			t.Errorf("Expected the error of the driver, got %v", err)
+		}
+		if name := InstanceName(db); name != "" {
+			t.Errorf("Expected no instance name, got %q", name)
+		}
+	})
+
+	t.Run("Logs are prefixed", func(t *testing.T) {
+		var messages []string
+		l := newInstanceLogger(recordingLogger{messages: &messages}, "prod").LogMode(logger.Info)
+
+		l.Warn(context.Background(), "slow query %d", 1)
+		l.Trace(context.Background(), time.Now(), func() (string, int64) { return "SELECT 1", 1 }, nil)
+		if fmt.Sprint(messages) != "[[prod] slow query 1 [prod] SELECT 1]" {
+			t.Errorf("Unexpected messages %q", messages)
+		}
+
+		db, _ := setupFakeDB(t, Config{InstanceName: "prod"}, nil)
+		if _, ok := db.Logger.(instanceLogger); !ok {
+			t.Errorf("Expected the logger of the instance, got %T", db.Logger)
+		}
+	})
+
+	t.Run("ExecWithStats", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{InstanceName: "prod"}, statsHandler)
+
+		stats, err := ExecWithStats(db, "UPDATE orders SET status = ?", "done")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if stats.Instance != "prod" {
+			t.Errorf("Expected the instance name, got %+v", stats)
+		}
+	})
+}
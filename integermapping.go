@@ -0,0 +1,28 @@
+package snowflake
+
+// IntegerMapping selects the column types of the integer fields of models
+type IntegerMapping string
+
+const (
+	// IntegerSized maps integer fields by their size: SMALLINT up to 16 bits, INT up to 32 bits,
+	// BIGINT otherwise (default)
+	IntegerSized IntegerMapping = ""
+	// IntegerAlwaysNumber38 maps every integer field to NUMBER(38,0), whatever its size
+	IntegerAlwaysNumber38 IntegerMapping = "number38"
+)
+
+// integerType returns the column type of an integer field of size bits
+func (dialector Dialector) integerType(size int) string {
+	if dialector.IntegerMapping == IntegerAlwaysNumber38 {
+		return "NUMBER(38,0)"
+	}
+
+	switch {
+	case size <= 16:
+		return "SMALLINT"
+	case size <= 32:
+		return "INT"
+	default:
+		return "BIGINT"
+	}
+}
@@ -0,0 +1,63 @@
+package snowflake
+
+import (
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+func TestIntegerMapping(t *testing.T) {
+	t.Run("Sized", func(t *testing.T) {
+		dialector := New(Config{})
+
+		tests := []struct {
+			size     int
+			expected string
+		}{
+			{8, "SMALLINT"},
+			{15, "SMALLINT"},
+			{16, "SMALLINT"},
+			{17, "INT"},
+			{31, "INT"},
+			{32, "INT"},
+			{33, "BIGINT"},
+			{64, "BIGINT"},
+		}
+		for _, test := range tests {
+			for _, dataType := range []schema.DataType{schema.Int, schema.Uint} {
+				if result := dialector.DataTypeOf(&schema.Field{DataType: dataType, Size: test.size}); result != test.expected {
+					t.Errorf("Expected %s for %s of size %d, got %s", test.expected, dataType, test.size, result)
+				}
+			}
+		}
+	})
+
+	t.Run("AlwaysNumber38", func(t *testing.T) {
+		dialector := New(Config{IntegerMapping: IntegerAlwaysNumber38})
+
+		for _, size := range []int{8, 16, 32, 64} {
+			if result := dialector.DataTypeOf(&schema.Field{DataType: schema.Int, Size: size}); result != "NUMBER(38,0)" {
+				t.Errorf("Expected NUMBER(38,0) for size %d, got %s", size, result)
+			}
+		}
+		if result := dialector.DataTypeOf(&schema.Field{DataType: schema.Uint, Size: 32, AutoIncrement: true}); result != "NUMBER(38,0) IDENTITY(1,1)" {
+			t.Errorf("Expected NUMBER(38,0) IDENTITY(1,1), got %s", result)
+		}
+	})
+
+	t.Run("Migrator", func(t *testing.T) {
+		type counter struct {
+			ID    uint
+			Small int16
+			Count int32
+		}
+
+		db, fake := setupFakeDB(t, Config{IntegerMapping: IntegerAlwaysNumber38}, nil)
+		if err := db.Migrator().CreateTable(&counter{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !hasQuery(fake.queries(), "CREATE TABLE counters (id NUMBER(38,0) IDENTITY(1,1),small NUMBER(38,0),count NUMBER(38,0),") {
+			t.Errorf("Expected NUMBER(38,0) columns, got %v", fake.queries())
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package snowflake
+
+import (
+	"database/sql"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrConnectionNotPinned is returned by LastQueryID for a DB whose statements may each run on
+// another connection of the pool
+var ErrConnectionNotPinned = errors.New("connection not pinned")
+
+// LastQueryID returns the query ID of the most recent statement run on the connection of tx, a
+// transaction or a session of db.Connection, as LAST_QUERY_ID() is scoped to the Snowflake
+// session. The ID feeds RESULT_SCAN, Before and QUERY_HISTORY:
+//
+//	err := db.Connection(func(tx *gorm.DB) error {
+//		tx.Exec("CALL refresh_orders()")
+//		queryID, err := snowflake.LastQueryID(tx)
+//		...
+//		return tx.Raw("SELECT * FROM TABLE(RESULT_SCAN(?))", queryID).Scan(&result).Error
+//	})
+//
+// Statements run by the dialector count: after a Create reading back default values, it is the
+// ID of the SELECT of CHANGES, not of the INSERT
+func LastQueryID(tx *gorm.DB) (string, error) {
+	if !pinnedConnPool(tx.Statement.ConnPool) {
+		return "", ErrConnectionNotPinned
+	}
+
+	var queryID sql.NullString
+	if err := tx.Raw("SELECT LAST_QUERY_ID()").Row().Scan(&queryID); err != nil {
+		return "", err
+	}
+	return queryID.String, nil
+}
@@ -0,0 +1,65 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestLastQueryID(t *testing.T) {
+	handler := func(query string, args []interface{}) fakeResult {
+		if query == "SELECT LAST_QUERY_ID()" {
+			return fakeResult{columns: []string{"LAST_QUERY_ID()"}, rows: [][]driver.Value{{"01b2c3d4-0000-1111"}}}
+		}
+		return fakeResult{}
+	}
+
+	t.Run("On a connection", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, handler)
+
+		err := db.Connection(func(tx *gorm.DB) error {
+			if err := tx.Exec("DELETE FROM test_models").Error; err != nil {
+				return err
+			}
+			queryID, err := LastQueryID(tx)
+			if queryID != "01b2c3d4-0000-1111" {
+				t.Errorf("Expected the query ID, got %q", queryID)
+			}
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if queries := fake.queries(); len(queries) != 2 || queries[1] != "SELECT LAST_QUERY_ID()" {
+			t.Errorf("Unexpected queries: %v", queries)
+		}
+	})
+
+	t.Run("In a transaction", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, handler)
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			queryID, err := LastQueryID(tx)
+			if queryID != "01b2c3d4-0000-1111" {
+				t.Errorf("Expected the query ID, got %q", queryID)
+			}
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Not on a pooled DB", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, handler)
+
+		if _, err := LastQueryID(db); !errors.Is(err, ErrConnectionNotPinned) {
+			t.Errorf("Expected ErrConnectionNotPinned, got %v", err)
+		}
+		if len(fake.statements) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// LintSeverity is the severity of a LintIssue
+type LintSeverity string
+
+const (
+	// LintWarning reports a construct that is migrated but not honored, e.g. UNIQUE is not enforced
+	LintWarning LintSeverity = "warning"
+	// LintError reports a construct that makes the migration fail
+	LintError LintSeverity = "error"
+)
+
+// LintIssue is a construct of a model Snowflake cannot honor, reported by Migrator.Lint
+type LintIssue struct {
+	Table    string
+	Column   string
+	Severity LintSeverity
+	Message  string
+}
+
+func (issue LintIssue) String() string {
+	if issue.Column == "" {
+		return fmt.Sprintf("%s: %s: %s", issue.Severity, issue.Table, issue.Message)
+	}
+	return fmt.Sprintf("%s: %s.%s: %s", issue.Severity, issue.Table, issue.Column, issue.Message)
+}
+
+// lintTypeReplacements are the types of other databases (mostly Postgres) that Snowflake does
+// not have, with the type to use instead
+var lintTypeReplacements = map[string]string{
+	"serial":      "autoIncrement",
+	"serial4":     "autoIncrement",
+	"serial8":     "autoIncrement",
+	"smallserial": "autoIncrement",
+	"bigserial":   "autoIncrement",
+	"uuid":        "VARCHAR(36)",
+	"json":        "VARIANT (or the semistructured serializer)",
+	"jsonb":       "VARIANT (or the semistructured serializer)",
+	"bytea":       "BINARY",
+	"citext":      "VARCHAR with COLLATE 'en-ci'",
+	"inet":        "VARCHAR",
+	"cidr":        "VARCHAR",
+	"macaddr":     "VARCHAR",
+	"money":       "NUMBER(19,4)",
+	"interval":    "a NUMBER of seconds",
+	"tsvector":    "VARCHAR with search optimization",
+	"hstore":      "OBJECT",
+	"enum":        "VARCHAR",
+	"mediumtext":  "VARCHAR",
+	"longtext":    "VARCHAR",
+	"tinyint(1)":  "BOOLEAN",
+}
+
+// Lint inspects the models before AutoMigrate and reports the constructs Snowflake cannot honor:
+// unenforced UNIQUE and FOREIGN KEY constraints, indexes, CHECK constraints, types of other
+// databases and, for existing tables, column changes ALTER TABLE does not support
+func (m Migrator) Lint(values ...interface{}) ([]LintIssue, error) {
+	var issues []LintIssue
+	for _, value := range values {
+		if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+			issues = append(issues, m.lintSchema(stmt)...)
+
+			if !m.HasTable(value) {
+				return nil
+			}
+			alterIssues, err := m.lintAlters(value, stmt)
+			issues = append(issues, alterIssues...)
+			return err
+		}); err != nil {
+			return issues, err
+		}
+	}
+	return issues, nil
+}
+
+// lintSchema reports the constructs of the schema of stmt Snowflake cannot honor
+func (m Migrator) lintSchema(stmt *gorm.Statement) (issues []LintIssue) {
+	report := func(column string, severity LintSeverity, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{Table: stmt.Table, Column: column, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	config := dialectorConfig(m.Dialector)
+	postgresCompat := config != nil && config.PostgresCompat
+
+	for _, dbName := range stmt.Schema.DBNames {
+		field := stmt.Schema.FieldsByDBName[dbName]
+		if field.IgnoreMigration {
+			continue
+		}
+
+		if field.Unique {
+			report(dbName, LintWarning, "UNIQUE is not enforced, only NOT NULL is")
+		}
+
+		if typ, ok := field.TagSettings["TYPE"]; ok && !(postgresCompat && postgresCompatType(field) != "") {
+			name := strings.ToLower(strings.TrimSpace(typ))
+			if replacement, ok := lintTypeReplacements[name]; ok {
+				report(dbName, LintError, "type %s does not exist, use %s", typ, replacement)
+			} else if base, _, _ := strings.Cut(name, "("); lintTypeReplacements[base] != "" {
+				report(dbName, LintError, "type %s does not exist, use %s", typ, lintTypeReplacements[base])
+			} else if strings.HasSuffix(name, "[]") {
+				report(dbName, LintError, "type %s does not exist, use ARRAY", typ)
+			}
+		}
+	}
+
+	strategy := m.indexStrategy()
+	for _, index := range stmt.Schema.ParseIndexes() {
+		if index.Class == "UNIQUE" {
+			report("", LintWarning, "unique index %s is not enforced", index.Name)
+		}
+		if index.Where != "" {
+			report("", LintWarning, "condition of partial index %s is ignored", index.Name)
+		}
+		if strategy == IndexIgnore {
+			report("", LintWarning, "index %s is ignored, Snowflake has no indexes (see Config.IndexStrategy)", index.Name)
+		}
+	}
+
+	if !m.DB.DisableForeignKeyConstraintWhenMigrating {
+		for _, rel := range stmt.Schema.Relationships.Relations {
+			if constraint := rel.ParseConstraint(); constraint != nil && constraint.Schema == stmt.Schema {
+				report("", LintWarning, "FOREIGN KEY %s is not enforced", constraint.Name)
+			}
+		}
+	}
+
+	for _, chk := range stmt.Schema.ParseCheckConstraints() {
+		report("", LintError, "CHECK constraint %s is not supported", chk.Name)
+	}
+	return issues
+}
+
+// lintAlters reports the fields of stmt whose existing column cannot be altered to their type:
+// ALTER TABLE only increases the length of VARCHAR and the precision of NUMBER columns
+func (m Migrator) lintAlters(value interface{}, stmt *gorm.Statement) ([]LintIssue, error) {
+	columnTypes, err := m.ColumnTypes(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []LintIssue
+	for _, columnType := range columnTypes {
+		field := lintLookUpField(stmt.Schema, columnType.Name())
+		if field == nil || field.IgnoreMigration {
+			continue
+		}
+
+		var (
+			current = columnType.DatabaseTypeName()
+			target  = m.DataTypeOf(field)
+		)
+		if dataTypeFamily(current) != dataTypeFamily(target) {
+			issues = append(issues, LintIssue{
+				Table: stmt.Table, Column: field.DBName, Severity: LintError,
+				Message: fmt.Sprintf("type cannot be altered from %s to %s, the column must be recreated", current, target),
+			})
+			continue
+		}
+
+		if length, ok := columnType.Length(); ok && dataTypeFamily(current) == "TEXT" && field.Size > 0 && int64(field.Size) < length {
+			issues = append(issues, LintIssue{
+				Table: stmt.Table, Column: field.DBName, Severity: LintError,
+				Message: fmt.Sprintf("length cannot be reduced from %d to %d", length, field.Size),
+			})
+		}
+	}
+	return issues, nil
+}
+
+// lintLookUpField returns the field of the column name, which Snowflake may report uppercased
+func lintLookUpField(sch *schema.Schema, name string) *schema.Field {
+	if field := sch.LookUpField(name); field != nil {
+		return field
+	}
+	for _, field := range sch.Fields {
+		if strings.EqualFold(field.DBName, name) {
+			return field
+		}
+	}
+	return nil
+}
+
+// dataTypeFamily returns the family of a Snowflake type, types of the same family can be
+// altered into one another (within the limits of lengths and precisions)
+func dataTypeFamily(dataType string) string {
+	name := strings.ToUpper(strings.TrimSpace(dataType))
+	if idx := strings.IndexAny(name, "( "); idx >= 0 {
+		name = name[:idx]
+	}
+
+	switch name {
+	case "FIXED", "NUMBER", "DECIMAL", "NUMERIC", "INT", "INTEGER", "BIGINT", "SMALLINT", "TINYINT", "BYTEINT":
+		return "NUMBER"
+	case "REAL", "FLOAT", "FLOAT4", "FLOAT8", "DOUBLE":
+		return "FLOAT"
+	case "TEXT", "VARCHAR", "STRING", "CHAR", "CHARACTER", "NCHAR", "NVARCHAR":
+		return "TEXT"
+	case "BINARY", "VARBINARY":
+		return "BINARY"
+	case "DATETIME", "TIMESTAMP":
+		return "TIMESTAMP_NTZ"
+	}
+	return name
+}
@@ -0,0 +1,91 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+type lintModel struct {
+	ID     uint   `gorm:"type:bigserial"`
+	Email  string `gorm:"unique"`
+	Code   string `gorm:"uniqueIndex"`
+	Data   string `gorm:"type:jsonb"`
+	Tags   string `gorm:"type:text[]"`
+	Amount int    `gorm:"check:amount > 0"`
+	Name   string
+}
+
+func TestLint(t *testing.T) {
+	t.Run("Reports constructs Snowflake cannot honor", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, nil)
+
+		issues, err := db.Migrator().(Migrator).Lint(&lintModel{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		var reported []string
+		for _, issue := range issues {
+			reported = append(reported, issue.String())
+		}
+		for _, expected := range []string{
+			"error: lint_models.id: type bigserial does not exist, use autoIncrement",
+			"warning: lint_models.email: UNIQUE is not enforced",
+			"warning: lint_models: unique index idx_lint_models_code is not enforced",
+			"warning: lint_models: index idx_lint_models_code is ignored",
+			"error: lint_models.data: type jsonb does not exist, use VARIANT",
+			"error: lint_models.tags: type text[] does not exist, use ARRAY",
+			"error: lint_models: CHECK constraint chk_lint_models_amount is not supported",
+		} {
+			if !hasQuery(reported, expected) {
+				t.Errorf("Expected issue %q, got:\n%s", expected, strings.Join(reported, "\n"))
+			}
+		}
+		if hasQuery(reported, "lint_models.name") {
+			t.Errorf("Expected no issue for name, got:\n%s", strings.Join(reported, "\n"))
+		}
+	})
+
+	t.Run("Reports unsupported alters of existing columns", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			switch {
+			case strings.Contains(query, "count(*)"):
+				return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(1)}}}
+			case queriesInformationSchema(query, "COLUMNS"):
+				return informationSchemaColumns(
+					[]driver.Value{"ID", "NUMBER", "NO", nil, nil, int64(38), int64(0), "YES", nil},
+					[]driver.Value{"NAME", "TEXT", "YES", nil, int64(16777216), nil, nil, "NO", nil},
+					[]driver.Value{"AGE", "TEXT", "YES", nil, int64(16777216), nil, nil, "NO", nil},
+				)
+			}
+			return fakeResult{}
+		})
+
+		issues, err := db.Migrator().(Migrator).Lint(&TestModel{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(issues) != 1 || issues[0].Column != "age" || issues[0].Severity != LintError {
+			t.Errorf("Expected a single issue for age, got %v", issues)
+		}
+	})
+}
+
+func TestDataTypeFamily(t *testing.T) {
+	tests := map[string]string{
+		"FIXED":                "NUMBER",
+		"BIGINT IDENTITY(1,1)": "NUMBER",
+		"VARCHAR(256)":         "TEXT",
+		"TEXT":                 "TEXT",
+		"REAL":                 "FLOAT",
+		"TIMESTAMP_NTZ(9)":     "TIMESTAMP_NTZ",
+		"timestamp":            "TIMESTAMP_NTZ",
+		"VARIANT":              "VARIANT",
+	}
+	for dataType, expected := range tests {
+		if family := dataTypeFamily(dataType); family != expected {
+			t.Errorf("dataTypeFamily(%q) = %q, expected %q", dataType, family, expected)
+		}
+	}
+}
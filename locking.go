@@ -0,0 +1,28 @@
+package snowflake
+
+import "gorm.io/gorm"
+
+// StripLocking is registered before gorm:query and gorm:row. It removes the clause.Locking
+// (SELECT ... FOR UPDATE/SHARE) of queries on standard tables, which have no row locks, so code
+// written for other databases runs unchanged, or fails them with ErrStrictMode when
+// Config.StrictMode is set. Queries on hybrid tables keep it, see TableHybrid:
+//
+//	db.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).First(&account, id)
+func StripLocking(db *gorm.DB) {
+	if _, ok := db.Statement.Clauses["FOR"]; !ok || db.Statement.SQL.Len() > 0 {
+		return
+	}
+	if declaredTableType(db, db.Statement) != TableHybrid {
+		if err := strictLocking(db); err != nil {
+			db.AddError(err)
+			return
+		}
+		delete(db.Statement.Clauses, "FOR")
+	}
+}
+
+// registerLockingCallbacks registers StripLocking, before any callback building the query
+func registerLockingCallbacks(db *gorm.DB) {
+	_ = db.Callback().Query().Before("gorm:query").Register("snowflake:strip_locking", StripLocking)
+	_ = db.Callback().Row().Before("gorm:row").Register("snowflake:strip_locking", StripLocking)
+}
@@ -0,0 +1,62 @@
+package snowflake
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type hybridAccount struct {
+	ID      uint `gorm:"primaryKey"`
+	Balance int
+}
+
+func (hybridAccount) TableType() TableType { return TableHybrid }
+
+func TestLocking(t *testing.T) {
+	forUpdate := clause.Locking{Strength: clause.LockingStrengthUpdate}
+
+	t.Run("Stripped for standard tables", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		stmt := db.Clauses(forUpdate).Where("id = ?", 1).Find(&[]TestModel{}).Statement
+
+		expected := `SELECT * FROM "test_models" WHERE id = ?`
+		if sql := stmt.SQL.String(); sql != expected {
+			t.Errorf("Expected %s, got %s", expected, sql)
+		}
+	})
+
+	t.Run("Kept for hybrid tables", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		stmt := db.Clauses(forUpdate).Where("id = ?", 1).Find(&[]hybridAccount{}).Statement
+
+		expected := `SELECT * FROM "hybrid_accounts" WHERE id = ? FOR UPDATE`
+		if sql := stmt.SQL.String(); sql != expected {
+			t.Errorf("Expected %s, got %s", expected, sql)
+		}
+	})
+
+	t.Run("WithTableType", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+		if err := WithTableType(db, TableHybrid).Table("accounts").Clauses(forUpdate).Find(&[]map[string]interface{}{}).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if queries := fake.queries(); len(queries) != 1 || !strings.HasSuffix(queries[0], " FOR UPDATE") {
+			t.Errorf("Expected FOR UPDATE, got %v", queries)
+		}
+	})
+
+	t.Run("Rows", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+		rows, err := db.Model(&TestModel{}).Clauses(forUpdate).Rows()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		rows.Close()
+		if hasQuery(fake.queries(), "FOR UPDATE") {
+			t.Errorf("Expected no FOR UPDATE, got %v", fake.queries())
+		}
+	})
+}
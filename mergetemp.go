@@ -0,0 +1,165 @@
+package snowflake
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+
+	"github.com/snowflakedb/gosnowflake"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mergesViaTempTable reports whether the rows of an upsert are merged from a temporary table,
+// i.e. Config.MergeViaTempTable is set, no transaction is open and all rows are plain values
+func mergesViaTempTable(db *gorm.DB, values clause.Values) bool {
+	config := dialectorConfig(db.Dialector)
+	if config == nil || !config.MergeViaTempTable || len(values.Columns) == 0 || len(values.Values) == 0 ||
+		db.DryRun || db.Statement.Table == "" {
+		return false
+	}
+
+	// CREATE TEMPORARY TABLE would commit the transaction
+	if _, inTransaction := db.Statement.ConnPool.(gorm.TxCommitter); inTransaction {
+		return false
+	}
+
+	// SQL expressions (e.g. gorm.Expr) cannot be array-bound
+	return !hasExpressionValues(values)
+}
+
+// mergeViaTempTable loads the rows of values into a temporary table with one INSERT binding an
+// array per column, then merges the table into the table of the statement. Temporary tables
+// belong to the session, everything runs on a single connection, the CHANGES query of the
+// default values included
+func mergeViaTempTable(db *gorm.DB, values clause.Values, onConflict clause.OnConflict) {
+	name, err := mergeTempTableName()
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	db.AddError(withPinnedConnPool(db, func() error {
+		var (
+			ctx   = db.Statement.Context
+			pool  = unpreparedConnPool(db.Statement.ConnPool)
+			table = db.Statement.Quote(clause.Table{Name: name})
+		)
+
+		// columns are loaded as text, Snowflake casts them when merging
+		db.Statement.SQL.Reset()
+		db.Statement.Vars = nil
+		db.Statement.WriteString("CREATE TEMPORARY TABLE " + table + " (")
+		for idx, column := range values.Columns {
+			if idx > 0 {
+				db.Statement.WriteByte(',')
+			}
+			db.Statement.WriteQuoted(column.Name)
+			db.Statement.WriteString(" VARCHAR")
+		}
+		db.Statement.WriteByte(')')
+		if _, err := pool.ExecContext(ctx, db.Statement.SQL.String()); err != nil {
+			return fmt.Errorf("failed to create merge table: %w", err)
+		}
+		defer func() { _, _ = pool.ExecContext(ctx, "DROP TABLE IF EXISTS "+table) }()
+
+		types := semiStructuredColumns(db, values.Columns)
+		if err := insertMergeRows(db, table, values, types); err != nil {
+			return err
+		}
+
+		db.Statement.SQL.Reset()
+		db.Statement.Vars = nil
+		db.Statement.WriteString("MERGE INTO ")
+		db.Statement.WriteQuoted(db.Statement.Table)
+		db.Statement.WriteString(" USING (SELECT ")
+		for idx, column := range values.Columns {
+			if idx > 0 {
+				db.Statement.WriteByte(',')
+			}
+			if idx < len(types) && types[idx] != "" {
+				db.Statement.WriteString("PARSE_JSON(")
+				db.Statement.WriteQuoted(column.Name)
+				db.Statement.WriteByte(')')
+				if types[idx] == "OBJECT" || types[idx] == "ARRAY" {
+					db.Statement.WriteString("::" + types[idx])
+				}
+			} else {
+				db.Statement.WriteQuoted(column.Name)
+			}
+		}
+		db.Statement.WriteString(" FROM " + table)
+		if onConflict.DoNothing {
+			writeDistinctKeysQualify(db, onConflict)
+		}
+		db.Statement.WriteByte(')')
+		writeMergeClauses(db, prepareOnConflictForMerge(db, onConflict), values.Columns)
+
+		execCreate(db)
+		return nil
+	}))
+}
+
+// insertMergeRows inserts the rows of values into the merge table with one array bind per
+// column, values are bound as text (NULL for nil) and semi-structured ones as JSON text
+func insertMergeRows(db *gorm.DB, table string, values clause.Values, types []string) error {
+	columns, err := arrayBindColumns(values, types)
+	if err != nil {
+		return err
+	}
+
+	db.Statement.SQL.Reset()
+	db.Statement.Vars = nil
+	db.Statement.WriteString("INSERT INTO " + table + " (")
+	for idx, column := range values.Columns {
+		if idx > 0 {
+			db.Statement.WriteByte(',')
+		}
+		db.Statement.WriteQuoted(column.Name)
+	}
+	db.Statement.WriteString(") VALUES (")
+	for idx := range columns {
+		if idx > 0 {
+			db.Statement.WriteByte(',')
+		}
+		db.Statement.AddVar(db.Statement, gosnowflake.Array(&columns[idx]))
+	}
+	db.Statement.WriteByte(')')
+
+	if _, err := unpreparedConnPool(db.Statement.ConnPool).ExecContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...); err != nil {
+		return fmt.Errorf("failed to load merge table: %w", err)
+	}
+	return nil
+}
+
+// mergeBindValue returns the text bound for value in the merge table, nil for NULL
+func mergeBindValue(value interface{}) (interface{}, error) {
+	for {
+		reflectValue := reflect.ValueOf(value)
+		if value == nil || (reflectValue.Kind() == reflect.Ptr && reflectValue.IsNil()) {
+			return nil, nil
+		}
+		if valuer, ok := value.(driver.Valuer); ok {
+			var err error
+			if value, err = valuer.Value(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if reflectValue.Kind() != reflect.Ptr {
+			return bulkLoadValue(value)
+		}
+		value = reflectValue.Elem().Interface()
+	}
+}
+
+// mergeTempTableName returns a unique name for the temporary table of a merge
+func mergeTempTableName() (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return "gorm_merge_" + hex.EncodeToString(suffix), nil
+}
@@ -0,0 +1,83 @@
+package snowflake
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestMergeViaTempTable(t *testing.T) {
+	db, fake := setupFakeDB(t, Config{QuoteFields: true, MergeViaTempTable: true}, nil)
+
+	rows := []TestModel{{ID: 1, Name: "a", Age: 10}, {ID: 2, Name: "b", Age: 20}}
+	err := db.Session(&gorm.Session{SkipDefaultTransaction: true}).
+		Clauses(clause.OnConflict{UpdateAll: true}).Create(&rows).Error
+	if err != nil {
+		t.Fatalf("Expected upsert to succeed, got %v", err)
+	}
+
+	var steps []string
+	for _, statement := range fake.statements {
+		for _, step := range []string{"CREATE TEMPORARY TABLE", "INSERT INTO", "MERGE INTO", "DROP TABLE IF EXISTS"} {
+			if strings.HasPrefix(statement.query, step) {
+				steps = append(steps, step)
+				if step == "INSERT INTO" && len(statement.args) != 3 {
+					t.Errorf("Expected one array bind per column, got %d args", len(statement.args))
+				}
+				if step == "MERGE INTO" && (!strings.Contains(statement.query, `USING (SELECT "name","age","id" FROM "gorm_merge_`) ||
+					strings.Contains(statement.query, "VALUES(") || len(statement.args) != 0) {
+					t.Errorf("Expected the merge table as source, got %s %v", statement.query, statement.args)
+				}
+			}
+		}
+	}
+	if strings.Join(steps, ",") != "CREATE TEMPORARY TABLE,INSERT INTO,MERGE INTO,DROP TABLE IF EXISTS" {
+		t.Errorf("Unexpected statements: %v", fake.queries())
+	}
+}
+
+func TestMergeViaTempTableInTransaction(t *testing.T) {
+	db, fake := setupFakeDB(t, Config{QuoteFields: true, MergeViaTempTable: true}, nil)
+
+	rows := []TestModel{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}
+	if err := db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&rows).Error; err != nil {
+		t.Fatalf("Expected upsert to succeed, got %v", err)
+	}
+
+	if hasQuery(fake.queries(), "CREATE TEMPORARY TABLE") || !hasQuery(fake.queries(), "USING (VALUES") {
+		t.Errorf("Expected the VALUES source in the default transaction, got %v", fake.queries())
+	}
+}
+
+func TestMergeBindValue(t *testing.T) {
+	var (
+		name    = "a"
+		nilName *string
+		at      = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	)
+	for _, tt := range []struct {
+		value    interface{}
+		expected interface{}
+	}{
+		{nil, nil},
+		{nilName, nil},
+		{&name, "a"},
+		{sql.NullString{}, nil},
+		{sql.NullInt64{Int64: 42, Valid: true}, "42"},
+		{true, "TRUE"},
+		{[]byte{0xca, 0xfe}, "cafe"},
+		{at, "2024-01-02T03:04:05Z"},
+	} {
+		value, err := mergeBindValue(tt.value)
+		if err != nil {
+			t.Fatalf("Expected no error for %v, got %v", tt.value, err)
+		}
+		if value != tt.expected {
+			t.Errorf("Expected %v for %v, got %v", tt.expected, tt.value, value)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package snowflake
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// migrationSessionKey marks a DB whose connection already runs with the MigratorConfig session
+const migrationSessionKey = "snowflake:migration_session"
+
+// CreateTableMode selects the CREATE TABLE statement of CreateTable and AutoMigrate, so migrations
+// can be re-run, e.g. by CI environments, while their tables exist
+type CreateTableMode string
+
+const (
+	// CreateTableStrict creates tables with CREATE TABLE, which fails when the table exists (default)
+	CreateTableStrict CreateTableMode = ""
+	// CreateTableIfNotExists creates tables with CREATE TABLE IF NOT EXISTS, existing tables are kept
+	CreateTableIfNotExists CreateTableMode = "if_not_exists"
+	// CreateTableOrReplace creates tables with CREATE OR REPLACE TABLE, existing tables are dropped
+	// along with their rows
+	CreateTableOrReplace CreateTableMode = "or_replace"
+)
+
+// MigratorConfig sets up the session of migration operations (AutoMigrate, CreateTable, DropTable,
+// RenameTable, AlterColumn), as long DDL such as CTAS on large tables often needs a bigger warehouse
+// and a longer timeout than the application queries
+type MigratorConfig struct {
+	// Warehouse is used for the migration, the previous warehouse is restored afterwards
+	Warehouse string
+	// Timeout is set as STATEMENT_TIMEOUT_IN_SECONDS for the migration (rounded up to the second),
+	// the parameter is unset afterwards so the user/account value applies again
+	Timeout time.Duration
+	// CreateMode selects how CreateTable and AutoMigrate create tables. DropTable always drops
+	// them IF EXISTS
+	// Default: CreateTableStrict
+	CreateMode CreateTableMode
+}
+
+// createTableKeywords returns the keywords creating a table (object is TABLE or DYNAMIC TABLE) of
+// tableType with Config.Migration.CreateMode, e.g. CREATE OR REPLACE TRANSIENT TABLE
+func (m Migrator) createTableKeywords(tableType TableType, object string) string {
+	mode := CreateTableStrict
+	if config := dialectorConfig(m.Dialector); config != nil {
+		mode = config.Migration.CreateMode
+	}
+
+	keywords := "CREATE "
+	if mode == CreateTableOrReplace {
+		keywords += "OR REPLACE "
+	}
+	if tableType != TablePermanent {
+		keywords += string(tableType) + " "
+	}
+	keywords += object
+	if mode == CreateTableIfNotExists {
+		keywords += " IF NOT EXISTS"
+	}
+	return keywords
+}
+
+// needsMigrationSession reports whether migration operations of m must be wrapped by withMigrationSession
+func (m Migrator) needsMigrationSession() bool {
+	config := dialectorConfig(m.Dialector)
+	if config == nil || (config.Migration.Warehouse == "" && config.Migration.Timeout <= 0) {
+		return false
+	}
+	_, inSession := m.DB.Get(migrationSessionKey)
+	return !inSession
+}
+
+// withMigrationSession runs fc on a dedicated connection using the warehouse and timeout of Config.Migration
+func (m Migrator) withMigrationSession(fc func(m Migrator) error) (err error) {
+	config := dialectorConfig(m.Dialector).Migration
+
+	// a DB already on a dedicated connection (e.g. an ephemeral test schema) or in a transaction
+	// keeps it, the statements below must run in its session
+	tx, release, err := PinConnection(m.DB)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// a new session, so the statements below do not leak their table into the migrator
+	tx = tx.Set(migrationSessionKey, true).Session(&gorm.Session{})
+
+	if config.Warehouse != "" {
+		if err := validateIdentifier(config.Warehouse); err != nil {
+			return err
+		}
+
+		var previous sql.NullString
+		if err := tx.Raw("SELECT CURRENT_WAREHOUSE()").Row().Scan(&previous); err != nil {
+			return err
+		}
+		if err := tx.Exec("USE WAREHOUSE ?", clause.Table{Name: config.Warehouse}).Error; err != nil {
+			return err
+		}
+		if previous.Valid && previous.String != "" {
+			defer func() {
+				// CURRENT_WAREHOUSE returns the name as stored, so it is restored as a quoted identifier
+				if restoreErr := tx.Exec("USE WAREHOUSE " + quoteIdentifier(previous.String)).Error; err == nil {
+					err = restoreErr
+				}
+			}()
+		}
+	}
+
+	if config.Timeout > 0 {
+		seconds := int64((config.Timeout + time.Second - 1) / time.Second)
+		if err := tx.Exec("ALTER SESSION SET STATEMENT_TIMEOUT_IN_SECONDS = " + strconv.FormatInt(seconds, 10)).Error; err != nil {
+			return err
+		}
+		defer func() {
+			if restoreErr := tx.Exec("ALTER SESSION UNSET STATEMENT_TIMEOUT_IN_SECONDS").Error; err == nil {
+				err = restoreErr
+			}
+		}()
+	}
+
+	return fc(tx.Migrator().(Migrator))
+}
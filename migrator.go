@@ -1,8 +1,11 @@
 package snowflake
 
 import (
+	"database/sql"
 	"fmt"
+	"reflect"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -14,17 +17,75 @@ type Migrator struct {
 	migrator.Migrator
 }
 
+// TableCommenter is implemented by models carrying a table comment,
+// written by CreateTable and kept in sync by AutoMigrate
+type TableCommenter interface {
+	TableComment() string
+}
+
+// deferForeignKeysKey makes CreateTable skip foreign keys, created by the final pass of AutoMigrate
+const deferForeignKeysKey = "snowflake:defer_foreign_keys"
+
+// ViewModel is implemented by models backed by a view, AutoMigrate creates them with
+// CREATE OR REPLACE VIEW once every table is migrated, see ViewOptioner for secure and
+// materialized views
+type ViewModel interface {
+	// ViewDefinition returns the SELECT statement of the view
+	ViewDefinition() string
+}
+
+// ViewDependencies is implemented by view models selecting from other view models,
+// so AutoMigrate creates the dependencies first
+type ViewDependencies interface {
+	ViewDependencies() []interface{}
+}
+
+// RunWithValue runs fc with the statement of value, its table rewritten by
+// Config.TableNameRewriter as by RewriteTableName
+func (m Migrator) RunWithValue(value interface{}, fc func(*gorm.Statement) error) error {
+	return m.Migrator.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if config := dialectorConfig(m.Dialector); config != nil && config.TableNameRewriter != nil {
+			rewriteStatementTable(stmt, config.TableNameRewriter)
+		}
+		return fc(stmt)
+	})
+}
+
 // AutoMigrate remove index
+// - tables are migrated in dependency order without foreign keys, which are created by a final
+// pass once every table exists, so cyclic references between models are supported
+// - ViewModel models are created last, after the views they depend on
 func (m Migrator) AutoMigrate(values ...interface{}) error {
-	for _, value := range m.ReorderModels(values, true) {
-		tx := m.DB.Session(&gorm.Session{})
+	if m.needsMigrationSession() {
+		return m.withMigrationSession(func(m Migrator) error { return m.AutoMigrate(values...) })
+	}
+
+	var tables, views []interface{}
+	for _, value := range values {
+		if _, ok := asViewModel(value); ok {
+			views = append(views, value)
+		} else {
+			tables = append(tables, value)
+		}
+	}
+
+	tables = m.ReorderModels(tables, true)
+	for _, value := range tables {
+		tx := m.DB.Session(&gorm.Session{}).Set(deferForeignKeysKey, true)
 		if !tx.Migrator().HasTable(value) {
 			if err := tx.Migrator().CreateTable(value); err != nil {
 				return err
 			}
 		} else {
 			if err := m.RunWithValue(value, func(stmt *gorm.Statement) (errr error) {
-				columnTypes, _ := m.DB.Migrator().ColumnTypes(value)
+				if errr = m.strictModel(stmt); errr != nil {
+					return errr
+				}
+
+				columnTypes, errr := m.DB.Migrator().ColumnTypes(value)
+				if errr != nil {
+					return errr
+				}
 
 				for _, field := range stmt.Schema.FieldsByDBName {
 					var foundColumn gorm.ColumnType
@@ -47,47 +108,214 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 					}
 				}
 
-				for _, rel := range stmt.Schema.Relationships.Relations {
-					if !m.DB.Config.DisableForeignKeyConstraintWhenMigrating {
-						if constraint := rel.ParseConstraint(); constraint != nil {
-							if constraint.Schema == stmt.Schema {
-								if !tx.Migrator().HasConstraint(value, constraint.Name) {
-									if err := tx.Migrator().CreateConstraint(value, constraint.Name); err != nil {
-										return err
-									}
-								}
-							}
+				for _, chk := range stmt.Schema.ParseCheckConstraints() {
+					if !tx.Migrator().HasConstraint(value, chk.Name) {
+						if err := tx.Migrator().CreateConstraint(value, chk.Name); err != nil {
+							return err
 						}
 					}
+				}
 
-					for _, chk := range stmt.Schema.ParseCheckConstraints() {
-						if !tx.Migrator().HasConstraint(value, chk.Name) {
-							if err := tx.Migrator().CreateConstraint(value, chk.Name); err != nil {
-								return err
-							}
-						}
-					}
+				if err := m.migrateTableOptions(stmt); err != nil {
+					return err
+				}
+				if err := m.migrateClusteringKey(stmt); err != nil {
+					return err
+				}
+				if err := m.migrateIndexStrategy(stmt); err != nil {
+					return err
+				}
+				if err := m.migrateGovernance(stmt); err != nil {
+					return err
 				}
 
-				return nil
+				return m.migrateTableComment(stmt)
 			}); err != nil {
 				return err
 			}
 		}
 	}
 
+	if !m.DB.Config.DisableForeignKeyConstraintWhenMigrating {
+		for _, value := range tables {
+			if err := m.migrateForeignKeys(value); err != nil {
+				return err
+			}
+		}
+	}
+
+	views, err := orderViews(views)
+	if err != nil {
+		return err
+	}
+	for _, value := range views {
+		if err := m.CreateViewModel(value); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// migrateForeignKeys creates the missing foreign keys owned by the table of value
+func (m Migrator) migrateForeignKeys(value interface{}) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		for _, rel := range stmt.Schema.Relationships.Relations {
+			if constraint := rel.ParseConstraint(); constraint != nil && constraint.Schema == stmt.Schema {
+				if !m.DB.Migrator().HasConstraint(value, constraint.Name) {
+					if err := m.DB.Migrator().CreateConstraint(value, constraint.Name); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// CreateViewModel creates or replaces the view of a ViewModel, with the options of its ViewOptioner
+func (m Migrator) CreateViewModel(value interface{}) error {
+	view, ok := asViewModel(value)
+	if !ok {
+		return fmt.Errorf("%T does not implement ViewModel", value)
+	}
+
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.createView(m.CurrentTable(stmt), true, viewOptionsOf(value), view.ViewDefinition())
+	})
+}
+
+// asViewModel returns value as a ViewModel, also when the method is declared on the pointer type
+func asViewModel(value interface{}) (ViewModel, bool) {
+	if view, ok := value.(ViewModel); ok {
+		return view, true
+	}
+
+	modelType := reflect.TypeOf(value)
+	for modelType != nil && modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType == nil {
+		return nil, false
+	}
+	view, ok := reflect.New(modelType).Interface().(ViewModel)
+	return view, ok
+}
+
+// orderViews sorts views so every view comes after its ViewDependencies, keeping the given
+// order otherwise. Dependencies missing from views are expected to exist already
+func orderViews(views []interface{}) ([]interface{}, error) {
+	modelType := func(value interface{}) reflect.Type {
+		t := reflect.TypeOf(value)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		return t
+	}
+
+	byType := make(map[reflect.Type]interface{}, len(views))
+	for _, view := range views {
+		byType[modelType(view)] = view
+	}
+
+	var (
+		ordered  = make([]interface{}, 0, len(views))
+		visiting = map[reflect.Type]bool{}
+		visited  = map[reflect.Type]bool{}
+		visit    func(value interface{}) error
+	)
+	visit = func(value interface{}) error {
+		t := modelType(value)
+		if visited[t] {
+			return nil
+		}
+		if visiting[t] {
+			return fmt.Errorf("cyclic view dependency on %s", t)
+		}
+		visiting[t] = true
+
+		if dependent, ok := reflect.New(t).Interface().(ViewDependencies); ok {
+			for _, dependency := range dependent.ViewDependencies() {
+				if view, ok := byType[modelType(dependency)]; ok {
+					if err := visit(view); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		visiting[t], visited[t] = false, true
+		ordered = append(ordered, value)
+		return nil
+	}
+
+	for _, view := range views {
+		if err := visit(view); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// tableComment returns the comment of the model parsed in stmt, ok is false
+// when the model does not implement TableCommenter
+func tableComment(stmt *gorm.Statement) (comment string, ok bool) {
+	if stmt.Schema == nil {
+		return "", false
+	}
+	if commenter, ok := reflect.New(stmt.Schema.ModelType).Interface().(TableCommenter); ok {
+		return commenter.TableComment(), true
+	}
+	return "", false
+}
+
+// migrateTableComment issues COMMENT ON TABLE when the comment of the model differs from the
+// one stored in INFORMATION_SCHEMA, tables of models without TableCommenter are left untouched
+func (m Migrator) migrateTableComment(stmt *gorm.Statement) error {
+	comment, ok := tableComment(stmt)
+	if !ok {
+		return nil
+	}
+
+	var current []sql.NullString
+	if err := m.informationSchemaTable(&InformationSchemaTable{}, stmt, "").
+		Pluck("COMMENT", &current).Error; err != nil {
+		return err
+	}
+
+	if len(current) > 0 && current[0].String == comment {
+		return nil
+	}
+	return m.DB.Exec("COMMENT ON TABLE ? IS ?", m.CurrentTable(stmt), clause.Expr{SQL: quoteLiteral(comment)}).Error
+}
+
 // CreateTable modified
-// - include CHANGE_TRACKING=true, for getting output back, may be removed once it can globally supported with table options
-// - remove index (unsupported)
+// - create transient, temporary and hybrid tables, see TableTyper and WithTableType
+// - create tables IF NOT EXISTS or OR REPLACE them, see MigratorConfig.CreateMode
+// - include CHANGE_TRACKING=true, for getting output back, and the other options of TableOptioner
+// - remove index (unsupported), converted to a clustering key or search optimization by Config.IndexStrategy
+// - attach the masking policies and tags of the columns, see columnGovernance
 func (m Migrator) CreateTable(values ...interface{}) error {
+	if m.needsMigrationSession() {
+		return m.withMigrationSession(func(m Migrator) error { return m.CreateTable(values...) })
+	}
+
 	for _, value := range m.ReorderModels(values, false) {
 		tx := m.DB.Session(&gorm.Session{})
 		if err := m.RunWithValue(value, func(stmt *gorm.Statement) (errr error) {
+			tableType, errr := m.tableType(stmt)
+			if errr != nil {
+				return errr
+			}
+			if errr = validateGovernance(stmt); errr != nil {
+				return errr
+			}
+			if errr = m.strictModel(stmt); errr != nil {
+				return errr
+			}
+
 			var (
-				createTableSQL          = "CREATE TABLE ? ("
+				createTableSQL          = m.createTableKeywords(tableType, "TABLE") + " ? ("
 				values                  = []interface{}{m.CurrentTable(stmt)}
 				hasPrimaryKeyInDataType bool
 			)
@@ -110,11 +338,18 @@ func (m Migrator) CreateTable(values ...interface{}) error {
 				values = append(values, primaryKeys)
 			}
 
+			_, deferForeignKeys := m.DB.Get(deferForeignKeysKey)
 			for _, rel := range stmt.Schema.Relationships.Relations {
-				if !m.DB.DisableForeignKeyConstraintWhenMigrating {
+				if !m.DB.DisableForeignKeyConstraintWhenMigrating && !deferForeignKeys {
 					if constraint := rel.ParseConstraint(); constraint != nil {
 						if constraint.Schema == stmt.Schema {
-							sql, vars := buildConstraint(constraint)
+							if err := m.strictForeignKey(stmt, constraint); err != nil {
+								return err
+							}
+							sql, vars, err := buildConstraint(constraint, dialectorConfig(m.Dialector))
+							if err != nil {
+								return err
+							}
 							createTableSQL += sql + ","
 							values = append(values, vars...)
 						}
@@ -134,10 +369,27 @@ func (m Migrator) CreateTable(values ...interface{}) error {
 			if tableOption, ok := m.DB.Get("gorm:table_options"); ok {
 				createTableSQL += fmt.Sprint(tableOption)
 			}
-			createTableSQL += " CHANGE_TRACKING = TRUE"
 
-			errr = tx.Exec(createTableSQL, values...).Error
-			return errr
+			if tableType != TableHybrid {
+				if columns := m.clusterByColumns(stmt); len(columns) > 0 {
+					createTableSQL += " CLUSTER BY ?"
+					values = append(values, columns)
+				}
+				createTableSQL += m.tableOptionsSQL(stmt)
+			}
+
+			if comment, ok := tableComment(stmt); ok && comment != "" {
+				createTableSQL += " COMMENT = ?"
+				values = append(values, clause.Expr{SQL: quoteLiteral(comment)})
+			}
+
+			if errr = m.createSequences(tx, stmt); errr != nil {
+				return errr
+			}
+			if errr = tx.Exec(createTableSQL, values...).Error; errr != nil {
+				return errr
+			}
+			return m.addSearchOptimization(stmt)
 		}); err != nil {
 			return err
 		}
@@ -149,20 +401,31 @@ func (m Migrator) CreateTable(values ...interface{}) error {
 func (m Migrator) HasTable(value interface{}) bool {
 	var count int64
 	m.RunWithValue(value, func(stmt *gorm.Statement) error {
-		currentDatabase := m.DB.Migrator().CurrentDatabase()
-		upperTable := strings.ToUpper(stmt.Table) // Pre-compute uppercase conversion
-		return m.DB.Raw(
-			"SELECT count(*) FROM INFORMATION_SCHEMA.TABLES WHERE table_name = ? AND table_catalog = ?",
-			upperTable, currentDatabase,
-		).Row().Scan(&count)
+		return m.informationSchemaTable(&InformationSchemaTable{}, stmt, "").Count(&count).Error
 	})
 	return count > 0
 }
 
+// lookupName returns name as stored in INFORMATION_SCHEMA: quoted identifiers keep their case
+// unless QUOTED_IDENTIFIERS_IGNORE_CASE is on, unquoted identifiers are stored uppercase
+func (m Migrator) lookupName(name string) string {
+	if config := dialectorConfig(m.Dialector); config != nil && config.QuoteFields && !config.QuotedIdentifiersIgnoreCase {
+		return name
+	}
+	return strings.ToUpper(name)
+}
+
 // RenameTable no change
 func (m Migrator) RenameTable(oldName, newName interface{}) error {
+	if m.needsMigrationSession() {
+		return m.withMigrationSession(func(m Migrator) error { return m.RenameTable(oldName, newName) })
+	}
+
 	var oldTable, newTable interface{}
 	if v, ok := oldName.(string); ok {
+		if err := validateIdentifier(v); err != nil {
+			return err
+		}
 		oldTable = clause.Table{Name: v}
 	} else {
 		stmt := &gorm.Statement{DB: m.DB}
@@ -174,6 +437,9 @@ func (m Migrator) RenameTable(oldName, newName interface{}) error {
 	}
 
 	if v, ok := newName.(string); ok {
+		if err := validateIdentifier(v); err != nil {
+			return err
+		}
 		newTable = clause.Table{Name: v}
 	} else {
 		stmt := &gorm.Statement{DB: m.DB}
@@ -187,8 +453,12 @@ func (m Migrator) RenameTable(oldName, newName interface{}) error {
 	return m.DB.Exec("ALTER TABLE ? RENAME TO ?", oldTable, newTable).Error
 }
 
-// DropTable no change
+// DropTable drops the tables IF EXISTS, so migrations can be re-run
 func (m Migrator) DropTable(values ...interface{}) error {
+	if m.needsMigrationSession() {
+		return m.withMigrationSession(func(m Migrator) error { return m.DropTable(values...) })
+	}
+
 	values = m.ReorderModels(values, false)
 	for i := len(values) - 1; i >= 0; i-- {
 		tx := m.DB.Session(&gorm.Session{})
@@ -205,38 +475,328 @@ func (m Migrator) DropTable(values ...interface{}) error {
 func (m Migrator) HasColumn(value interface{}, field string) bool {
 	var count int64
 	m.RunWithValue(value, func(stmt *gorm.Statement) error {
-		currentDatabase := m.DB.Migrator().CurrentDatabase()
 		name := field
 		if field := stmt.Schema.LookUpField(field); field != nil {
 			name = field.DBName
 		}
 
-		// Pre-compute uppercase conversions for better performance
-		upperTable := strings.ToUpper(stmt.Table)
-		upperName := strings.ToUpper(name)
-
-		return m.DB.Raw(
-			"SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE table_catalog = ? AND table_name = ? AND column_name = ?",
-			currentDatabase, upperTable, upperName,
-		).Row().Scan(&count)
+		return m.informationSchemaTable(&InformationSchemaColumn{}, stmt, "column_name = ?", m.lookupName(name)).
+			Count(&count).Error
 	})
 
 	return count > 0
 }
 
-// AlterColumn no change
+// ColumnTypes reads the columns of the table of value from INFORMATION_SCHEMA, in order, with their
+// nullability, default value, length, precision and scale and comment, which the driver does not
+// report, and their primary and single column unique keys. Names stored uppercase (unquoted
+// identifiers) are reported lowercase, as named by the model. Length and DecimalSize are 0 for
+// the types without them
+func (m Migrator) ColumnTypes(value interface{}) ([]gorm.ColumnType, error) {
+	primary, err := m.primaryColumns(value)
+	if err != nil {
+		return nil, err
+	}
+	unique, err := m.uniqueColumns(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var columnTypes []gorm.ColumnType
+	if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		var columns []InformationSchemaColumn
+		if err := m.informationSchemaTable(&InformationSchemaColumn{}, stmt, "").
+			Order("ordinal_position").Find(&columns).Error; err != nil {
+			return err
+		}
+
+		for _, column := range columns {
+			length, precision, scale := column.CharacterMaximumLength, column.NumericPrecision, column.NumericScale
+			columnTypes = append(columnTypes, migrator.ColumnType{
+				NameValue:          sql.NullString{String: m.modelName(column.Name), Valid: true},
+				DataTypeValue:      sql.NullString{String: column.DataType, Valid: true},
+				ColumnTypeValue:    sql.NullString{String: fullColumnType(column.DataType, length, precision, scale), Valid: true},
+				PrimaryKeyValue:    sql.NullBool{Bool: primary[column.Name], Valid: true},
+				UniqueValue:        sql.NullBool{Bool: unique[column.Name], Valid: true},
+				AutoIncrementValue: sql.NullBool{Bool: column.IsIdentity.String == "YES", Valid: true},
+				LengthValue:        sql.NullInt64{Int64: length.Int64, Valid: true},
+				DecimalSizeValue:   sql.NullInt64{Int64: precision.Int64, Valid: true},
+				ScaleValue:         sql.NullInt64{Int64: scale.Int64, Valid: true},
+				NullableValue:      sql.NullBool{Bool: column.IsNullable == "YES", Valid: true},
+				ScanTypeValue:      scanTypeOf(column.DataType, scale.Int64),
+				CommentValue:       sql.NullString{String: column.Comment.String, Valid: true},
+				DefaultValueValue:  sql.NullString{String: unquoteDefault(column.Default.String), Valid: column.Default.Valid},
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return columnTypes, nil
+}
+
+// modelName returns name as named by the model, the inverse of lookupName: names stored uppercase
+// when identifiers are not quoted (or their case is ignored) are lowercased
+func (m Migrator) modelName(name string) string {
+	if m.lookupName(name) == name && name == strings.ToUpper(name) {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// fullColumnType returns the type of a column of INFORMATION_SCHEMA with its length or precision
+// and scale, e.g. VARCHAR(255) or NUMBER(38,0)
+func fullColumnType(dataType string, length, precision, scale sql.NullInt64) string {
+	switch strings.ToUpper(dataType) {
+	case "TEXT":
+		if length.Valid {
+			return fmt.Sprintf("VARCHAR(%d)", length.Int64)
+		}
+	case "BINARY":
+		if length.Valid {
+			return fmt.Sprintf("BINARY(%d)", length.Int64)
+		}
+	case "NUMBER":
+		if precision.Valid {
+			return fmt.Sprintf("NUMBER(%d,%d)", precision.Int64, scale.Int64)
+		}
+	}
+	return dataType
+}
+
+// scanTypeOf returns the Go type the driver scans values of dataType into
+func scanTypeOf(dataType string, scale int64) reflect.Type {
+	switch dataTypeFamily(dataType) {
+	case "NUMBER":
+		if scale > 0 {
+			return reflect.TypeOf(float64(0))
+		}
+		return reflect.TypeOf(int64(0))
+	case "FLOAT":
+		return reflect.TypeOf(float64(0))
+	case "BOOLEAN":
+		return reflect.TypeOf(false)
+	case "BINARY":
+		return reflect.TypeOf([]byte(nil))
+	case "DATE", "TIME", "TIMESTAMP_NTZ", "TIMESTAMP_LTZ", "TIMESTAMP_TZ":
+		return reflect.TypeOf(time.Time{})
+	}
+	return reflect.TypeOf("")
+}
+
+// unquoteDefault returns the default value of a column of INFORMATION_SCHEMA as declared by the
+// default tag: string literals are unquoted, expressions are kept
+func unquoteDefault(value string) string {
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	return value
+}
+
+// typeAliases are the names of the Snowflake types by family, lowercase
+var typeAliases = map[string][]string{
+	"NUMBER":        {"number", "decimal", "numeric", "int", "bigint", "smallint", "tinyint", "byteint"},
+	"FLOAT":         {"float", "double", "real"},
+	"TEXT":          {"varchar", "text", "string", "char", "nchar", "nvarchar"},
+	"BINARY":        {"binary", "varbinary"},
+	"TIMESTAMP_NTZ": {"timestamp_ntz", "datetime"},
+}
+
+// GetTypeAliases returns the names of the type of a column reported by INFORMATION_SCHEMA, so
+// MigrateColumn does not alter columns declared with another name of their type, e.g. BIGINT
+// columns reported as NUMBER
+func (m Migrator) GetTypeAliases(databaseTypeName string) []string {
+	return typeAliases[dataTypeFamily(databaseTypeName)]
+}
+
+// GetTables returns the tables of the current schema, excluding views, named as by lookupName
+// reversed (unquoted names lowercase)
+func (m Migrator) GetTables() (tableList []string, err error) {
+	var names []string
+	if err = m.informationSchema(&InformationSchemaTable{}, "table_schema = CURRENT_SCHEMA() AND table_type NOT LIKE '%VIEW'").
+		Order("table_name").Pluck("TABLE_NAME", &names).Error; err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		tableList = append(tableList, m.modelName(name))
+	}
+	return tableList, nil
+}
+
+// TableType returns the schema, type (BASE TABLE, TEMPORARY TABLE, VIEW...) and comment of the
+// table of value from INFORMATION_SCHEMA
+func (m Migrator) TableType(value interface{}) (tableType gorm.TableType, err error) {
+	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		var table InformationSchemaTable
+		if err := m.informationSchemaTable(&InformationSchemaTable{}, stmt, "").
+			Take(&table).Error; err != nil {
+			return err
+		}
+
+		tableType = migrator.TableType{
+			SchemaValue:  table.Schema,
+			NameValue:    m.modelName(table.Name),
+			TypeValue:    table.Type,
+			CommentValue: table.Comment,
+		}
+		return nil
+	})
+	return tableType, err
+}
+
+// showKey is a row of SHOW PRIMARY KEYS and SHOW UNIQUE KEYS
+type showKey struct {
+	ColumnName     string
+	ConstraintName string
+}
+
+// primaryColumns returns the columns of the primary key of the table of value
+func (m Migrator) primaryColumns(value interface{}) (map[string]bool, error) {
+	columns, err := m.showKeys(value, "PRIMARY KEYS")
+	if err != nil {
+		return nil, err
+	}
+
+	primary := map[string]bool{}
+	for _, names := range columns {
+		for _, name := range names {
+			primary[name] = true
+		}
+	}
+	return primary, nil
+}
+
+// showKeys returns the columns of the keys of the table of value listed by SHOW <keys> IN TABLE,
+// by constraint name
+func (m Migrator) showKeys(value interface{}, keys string) (map[string][]string, error) {
+	var rows []showKey
+	if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return Show(m.DB, keys+" IN TABLE ?", m.CurrentTable(stmt), &rows)
+	}); err != nil {
+		return nil, err
+	}
+
+	columns := map[string][]string{}
+	for _, row := range rows {
+		columns[row.ConstraintName] = append(columns[row.ConstraintName], row.ColumnName)
+	}
+	return columns, nil
+}
+
+// uniqueColumns returns the columns of the table of value having a single column UNIQUE constraint
+func (m Migrator) uniqueColumns(value interface{}) (map[string]bool, error) {
+	columns, err := m.showKeys(value, "UNIQUE KEYS")
+	if err != nil {
+		return nil, err
+	}
+
+	unique := map[string]bool{}
+	for _, names := range columns {
+		if len(names) == 1 {
+			unique[names[0]] = true
+		}
+	}
+	return unique, nil
+}
+
+// syncedCommentColumnType reports the comment already applied by MigrateColumn,
+// so the generic diff does not fall back to ALTER COLUMN for it
+type syncedCommentColumnType struct {
+	migratorColumnType
+	comment string
+}
+
+// migratorColumnType embeds gorm.ColumnType, whose ColumnType method would clash with a field of
+// the same name
+type migratorColumnType interface {
+	gorm.ColumnType
+}
+
+func (ct syncedCommentColumnType) Comment() (string, bool) {
+	return ct.comment, true
+}
+
+// MigrateColumn updates a drifted column comment with ALTER COLUMN ... COMMENT, or UNSET COMMENT
+// when the comment tag was removed, the other attributes are migrated as usual
+func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnType gorm.ColumnType) error {
+	if comment, ok := columnType.Comment(); ok && comment != field.Comment && !field.IgnoreMigration {
+		if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+			if field.Comment == "" {
+				return m.DB.Exec("ALTER TABLE ? ALTER COLUMN ? UNSET COMMENT", m.CurrentTable(stmt), clause.Column{Name: field.DBName}).Error
+			}
+			return m.DB.Exec(
+				"ALTER TABLE ? ALTER COLUMN ? COMMENT ?",
+				m.CurrentTable(stmt), clause.Column{Name: field.DBName}, clause.Expr{SQL: quoteLiteral(field.Comment)},
+			).Error
+		}); err != nil {
+			return err
+		}
+		columnType = syncedCommentColumnType{migratorColumnType: columnType, comment: field.Comment}
+	}
+	return m.Migrator.MigrateColumn(value, field, columnType)
+}
+
+// MigrateColumnUnique adds or drops the UNIQUE constraint of a column whose unique tag changed,
+// the constraint is informational, Snowflake does not enforce it
+func (m Migrator) MigrateColumnUnique(value interface{}, field *schema.Field, columnType gorm.ColumnType) error {
+	unique, ok := columnType.Unique()
+	if !ok || unique == field.Unique || field.PrimaryKey || field.IgnoreMigration {
+		return nil
+	}
+
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if !field.Unique {
+			return m.DB.Exec("ALTER TABLE ? DROP UNIQUE (?)", m.CurrentTable(stmt), clause.Column{Name: field.DBName}).Error
+		}
+
+		name := m.DB.NamingStrategy.UniqueName(stmt.Table, field.DBName)
+		if err := validateIdentifier(name); err != nil {
+			return err
+		}
+		return m.DB.Exec(
+			"ALTER TABLE ? ADD CONSTRAINT ? UNIQUE (?)",
+			m.CurrentTable(stmt), clause.Column{Name: name}, clause.Column{Name: field.DBName},
+		).Error
+	})
+}
+
+// AddColumn validates the masking policy and tags of the column, attached by FullDataTypeOf
+func (m Migrator) AddColumn(value interface{}, name string) error {
+	if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		_, _, err := columnGovernance(stmt.Schema.LookUpField(name))
+		return err
+	}); err != nil {
+		return err
+	}
+	return m.Migrator.AddColumn(value, name)
+}
+
+// AlterColumn alters the type of the column, then sets its masking policy and tags
 func (m Migrator) AlterColumn(value interface{}, field string) error {
+	if m.needsMigrationSession() {
+		return m.withMigrationSession(func(m Migrator) error { return m.AlterColumn(value, field) })
+	}
+
 	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
 		if field := stmt.Schema.LookUpField(field); field != nil {
+			policy, tags, err := columnGovernance(field)
+			if err != nil {
+				return err
+			}
+
 			fileType := clause.Expr{SQL: m.DataTypeOf(field)}
 			if field.NotNull {
 				fileType.SQL += " NOT NULL"
 			}
 
-			return m.DB.Exec(
+			if err := m.DB.Exec(
 				"ALTER TABLE ? ALTER COLUMN ? ?",
 				clause.Table{Name: stmt.Table}, clause.Column{Name: field.DBName}, fileType,
-			).Error
+			).Error; err != nil {
+				return err
+			}
+			return m.setColumnGovernance(stmt, field, policy, tags)
 		}
 		return fmt.Errorf("failed to look up field with name: %s", field)
 	})
@@ -257,39 +817,36 @@ func (m Migrator) HasIndex(value interface{}, name string) bool {
 	return true
 }
 
-// RenameIndex return nil, SF does not support Index
+// RenameIndex return nil, SF does not support Index (ErrStrictMode with Config.StrictMode)
 func (m Migrator) RenameIndex(value interface{}, oldName, newName string) error {
-	return nil
+	return m.strictIndex("RENAME", oldName)
 }
 
-// CreateIndex return nil, SF does not support Index
+// CreateIndex return nil, SF does not support Index (ErrStrictMode with Config.StrictMode)
 func (m Migrator) CreateIndex(value interface{}, name string) error {
-	return nil
+	return m.strictIndex("CREATE", name)
 }
 
-// DropIndex return nil, SF does not support Index
+// DropIndex return nil, SF does not support Index (ErrStrictMode with Config.StrictMode)
 func (m Migrator) DropIndex(value interface{}, name string) error {
-	return nil
+	return m.strictIndex("DROP", name)
 }
 
 // HasConstraint SF flavor
 func (m Migrator) HasConstraint(value interface{}, name string) bool {
 	var count int64
 	m.RunWithValue(value, func(stmt *gorm.Statement) error {
-		// Pre-compute uppercase conversions for better performance
-		upperName := strings.ToUpper(name)
-		upperTable := strings.ToUpper(stmt.Table)
-
-		return m.DB.Raw(
-			`SELECT count(*) FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS WHERE CONSTRAINT_NAME = ?  AND TABLE_NAME = ? AND TABLE_CATALOG = ?;`,
-			upperName, upperTable, m.CurrentDatabase(),
-		).Row().Scan(&count)
+		return m.informationSchemaTable(&InformationSchemaTableConstraint{}, stmt, "constraint_name = ?", m.lookupName(name)).
+			Count(&count).Error
 	})
 	return count > 0
 }
 
 // CreateConstraint no change
 func (m Migrator) CreateConstraint(value interface{}, name string) error {
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
 	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
 		constraint, chk, table := m.GuessConstraintAndTable(stmt, name)
 		if chk != nil {
@@ -300,11 +857,17 @@ func (m Migrator) CreateConstraint(value interface{}, name string) error {
 		}
 
 		if constraint != nil {
+			if err := m.strictForeignKey(stmt, constraint); err != nil {
+				return err
+			}
 			var vars = []interface{}{clause.Table{Name: table}}
 			if stmt.TableExpr != nil {
 				vars[0] = stmt.TableExpr
 			}
-			sql, values := buildConstraint(constraint)
+			sql, values, err := buildConstraint(constraint, dialectorConfig(m.Dialector))
+			if err != nil {
+				return err
+			}
 			return m.DB.Exec("ALTER TABLE ? ADD "+sql, append(vars, values...)...).Error
 		}
 
@@ -321,6 +884,9 @@ func (m Migrator) DropConstraint(value interface{}, name string) error {
 		} else if chk != nil {
 			name = chk.Name
 		}
+		if err := validateIdentifier(name); err != nil {
+			return err
+		}
 		return m.DB.Exec("ALTER TABLE ? DROP CONSTRAINT ?", clause.Table{Name: table}, clause.Column{Name: name}).Error
 	})
 }
@@ -393,25 +959,93 @@ func (m Migrator) FullDataTypeOf(field *schema.Field) (expr clause.Expr) {
 			m.Dialector.BindVarTo(defaultStmt, defaultStmt, field.DefaultValueInterface)
 			expr.SQL += " DEFAULT " + m.Dialector.Explain(defaultStmt.SQL.String(), field.DefaultValueInterface)
 		} else if field.DefaultValue != "(-)" {
-			expr.SQL += " DEFAULT " + field.DefaultValue
+			defaultValue := field.DefaultValue
+			if config := dialectorConfig(m.Dialector); config != nil && config.PostgresCompat {
+				defaultValue = postgresCompatDefault(defaultValue)
+			}
+			expr.SQL += " DEFAULT " + defaultValue
 		}
 	}
 
+	// invalid names are reported by CreateTable, AddColumn and AlterColumn
+	if policy, tags, err := columnGovernance(field); err == nil {
+		expr.SQL += governanceSQL(policy, tags)
+	}
+
+	if field.Comment != "" {
+		expr.SQL += " COMMENT " + quoteLiteral(field.Comment)
+	}
+
 	return
 }
 
-func buildConstraint(constraint *schema.Constraint) (sql string, results []interface{}) {
+// ConstraintEnforcement is the enforcement keyword of foreign key constraints
+type ConstraintEnforcement string
+
+const (
+	ConstraintNotEnforced ConstraintEnforcement = "NOT ENFORCED"
+	ConstraintEnforced    ConstraintEnforcement = "ENFORCED"
+)
+
+// referentialActions lists the ON DELETE/ON UPDATE actions accepted by Snowflake
+var referentialActions = map[string]bool{
+	"CASCADE":     true,
+	"SET NULL":    true,
+	"SET DEFAULT": true,
+	"RESTRICT":    true,
+	"NO ACTION":   true,
+}
+
+// foreignKeyMatchTypes lists the MATCH types accepted by Snowflake
+var foreignKeyMatchTypes = map[string]bool{
+	"FULL":    true,
+	"SIMPLE":  true,
+	"PARTIAL": true,
+}
+
+func buildConstraint(constraint *schema.Constraint, config *Config) (sql string, results []interface{}, err error) {
+	if len(constraint.ForeignKeys) == 0 || len(constraint.ForeignKeys) != len(constraint.References) {
+		return "", nil, fmt.Errorf("constraint %s: %d foreign key columns do not match %d referenced columns", constraint.Name, len(constraint.ForeignKeys), len(constraint.References))
+	}
+
 	sql = "CONSTRAINT ? FOREIGN KEY ? REFERENCES ??"
+
+	enforcement := ConstraintNotEnforced
+	if config != nil {
+		if match := strings.ToUpper(strings.TrimSpace(config.ForeignKeyMatch)); match != "" {
+			if !foreignKeyMatchTypes[match] {
+				return "", nil, fmt.Errorf("constraint %s: unsupported MATCH type %q", constraint.Name, config.ForeignKeyMatch)
+			}
+			sql += " MATCH " + match
+		}
+
+		if config.ConstraintEnforcement != "" {
+			enforcement = config.ConstraintEnforcement
+		}
+	}
+
 	if constraint.OnDelete != "" {
-		sql += " ON DELETE " + constraint.OnDelete
+		action := strings.ToUpper(strings.TrimSpace(constraint.OnDelete))
+		if !referentialActions[action] {
+			return "", nil, fmt.Errorf("constraint %s: unsupported ON DELETE action %q", constraint.Name, constraint.OnDelete)
+		}
+		sql += " ON DELETE " + action
 	}
 
 	if constraint.OnUpdate != "" {
-		sql += " ON UPDATE " + constraint.OnUpdate
+		action := strings.ToUpper(strings.TrimSpace(constraint.OnUpdate))
+		if !referentialActions[action] {
+			return "", nil, fmt.Errorf("constraint %s: unsupported ON UPDATE action %q", constraint.Name, constraint.OnUpdate)
+		}
+		sql += " ON UPDATE " + action
 	}
 
-	// default enforced, but not actually enforced except for NOT NULL
-	sql += " ENFORCED"
+	switch enforcement {
+	case ConstraintNotEnforced, ConstraintEnforced:
+		sql += " " + string(enforcement)
+	default:
+		return "", nil, fmt.Errorf("constraint %s: unsupported enforcement %q", constraint.Name, enforcement)
+	}
 
 	var foreignKeys, references []interface{}
 	for _, field := range constraint.ForeignKeys {
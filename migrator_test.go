@@ -1,11 +1,18 @@
 package snowflake
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/migrator"
 	"gorm.io/gorm/schema"
 )
 
@@ -226,9 +233,12 @@ func TestBuildConstraint(t *testing.T) {
 		},
 	}
 
-	sql, results := buildConstraint(constraint)
+	sql, results, err := buildConstraint(constraint, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
 
-	expectedSQL := "CONSTRAINT ? FOREIGN KEY ? REFERENCES ?? ON DELETE CASCADE ON UPDATE RESTRICT ENFORCED"
+	expectedSQL := "CONSTRAINT ? FOREIGN KEY ? REFERENCES ?? ON DELETE CASCADE ON UPDATE RESTRICT NOT ENFORCED"
 	if sql != expectedSQL {
 		t.Errorf("Expected SQL to be '%s', got '%s'", expectedSQL, sql)
 	}
@@ -247,6 +257,74 @@ func TestBuildConstraint(t *testing.T) {
 	}
 }
 
+func TestBuildConstraintOptions(t *testing.T) {
+	newConstraint := func() *schema.Constraint {
+		return &schema.Constraint{
+			Name:            "fk_orders_customers",
+			ForeignKeys:     []*schema.Field{{DBName: "customer_id"}, {DBName: "region"}},
+			References:      []*schema.Field{{DBName: "id"}, {DBName: "region"}},
+			ReferenceSchema: &schema.Schema{Table: "customers"},
+		}
+	}
+
+	t.Run("Multi-column with MATCH SIMPLE and ENFORCED", func(t *testing.T) {
+		constraint := newConstraint()
+		constraint.OnDelete = "set null"
+
+		sql, results, err := buildConstraint(constraint, &Config{
+			ConstraintEnforcement: ConstraintEnforced,
+			ForeignKeyMatch:       "simple",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expectedSQL := "CONSTRAINT ? FOREIGN KEY ? REFERENCES ?? MATCH SIMPLE ON DELETE SET NULL ENFORCED"
+		if sql != expectedSQL {
+			t.Errorf("Expected SQL to be '%s', got '%s'", expectedSQL, sql)
+		}
+
+		if foreignKeys, ok := results[1].([]interface{}); !ok || len(foreignKeys) != 2 {
+			t.Errorf("Expected 2 foreign key columns, got %v", results[1])
+		}
+		if references, ok := results[3].([]interface{}); !ok || len(references) != 2 {
+			t.Errorf("Expected 2 referenced columns, got %v", results[3])
+		}
+	})
+
+	t.Run("Rendered SQL", func(t *testing.T) {
+		db := setupMockDB(t)
+		sql, vars, err := buildConstraint(newConstraint(), nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		stmt := db.Session(&gorm.Session{DryRun: true}).Exec("ALTER TABLE orders ADD "+sql, vars...).Statement
+		expected := `ALTER TABLE orders ADD CONSTRAINT "fk_orders_customers" FOREIGN KEY ("customer_id","region") REFERENCES "customers"("id","region") NOT ENFORCED`
+		if stmt.SQL.String() != expected {
+			t.Errorf("Expected %s, got %s", expected, stmt.SQL.String())
+		}
+	})
+
+	t.Run("Unsupported actions", func(t *testing.T) {
+		invalid := []func(c *schema.Constraint, config *Config){
+			func(c *schema.Constraint, config *Config) { c.OnDelete = "DROP TABLE users" },
+			func(c *schema.Constraint, config *Config) { c.OnUpdate = "CASCADE; --" },
+			func(c *schema.Constraint, config *Config) { config.ForeignKeyMatch = "ANY" },
+			func(c *schema.Constraint, config *Config) { config.ConstraintEnforcement = "MAYBE" },
+			func(c *schema.Constraint, config *Config) { c.References = c.References[:1] },
+		}
+
+		for idx, apply := range invalid {
+			constraint, config := newConstraint(), &Config{}
+			apply(constraint, config)
+			if _, _, err := buildConstraint(constraint, config); err == nil {
+				t.Errorf("Case %d: expected an error", idx)
+			}
+		}
+	})
+}
+
 func TestMigratorGuessConstraintAndTable(t *testing.T) {
 	db := setupMockDB(t)
 	migrator := db.Migrator().(Migrator)
@@ -363,4 +441,662 @@ func TestMigratorDropConstraint(t *testing.T) {
 	if err != nil {
 		t.Errorf("Expected DropConstraint to succeed, got error: %v", err)
 	}
-}
\ No newline at end of file
+}
+type CommentedModel struct {
+	ID   uint   `gorm:"primaryKey"`
+	Name string `gorm:"size:255;not null;comment:display name"`
+}
+
+func (CommentedModel) TableComment() string {
+	return "customer's orders"
+}
+
+func TestMigratorComments(t *testing.T) {
+	t.Run("FullDataTypeOf", func(t *testing.T) {
+		db := setupMockDB(t)
+		migrator := db.Migrator().(Migrator)
+
+		field := &schema.Field{DataType: schema.String, Size: 255, Comment: `it's a \ path`, IndirectFieldType: reflect.TypeOf("")}
+		expected := `VARCHAR(255) COMMENT 'it''s a \\ path'`
+		if sql := migrator.FullDataTypeOf(field).SQL; sql != expected {
+			t.Errorf("Expected %s, got %s", expected, sql)
+		}
+	})
+
+	t.Run("CreateTable with table comment", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		if err := db.Migrator().CreateTable(&CommentedModel{}); err != nil {
+			t.Fatalf("Expected CreateTable to succeed, got %v", err)
+		}
+
+		queries := fake.queries()
+		if !hasQuery(queries, `"name" VARCHAR(255) NOT NULL COMMENT 'display name'`) {
+			t.Errorf("Expected column comment in %v", queries)
+		}
+		if !hasQuery(queries, `CHANGE_TRACKING = TRUE COMMENT = 'customer''s orders'`) {
+			t.Errorf("Expected table comment in %v", queries)
+		}
+	})
+
+	t.Run("MigrateColumn comment drift", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(&CommentedModel{}); err != nil {
+			t.Fatalf("Failed to parse model: %v", err)
+		}
+
+		columnType := migrator.ColumnType{
+			NameValue:        sql.NullString{String: "name", Valid: true},
+			DataTypeValue:    sql.NullString{String: "VARCHAR", Valid: true},
+			ColumnTypeValue:  sql.NullString{String: "VARCHAR(255)", Valid: true},
+			LengthValue:      sql.NullInt64{Int64: 255, Valid: true},
+			DecimalSizeValue: sql.NullInt64{Valid: true},
+			ScaleValue:       sql.NullInt64{Valid: true},
+			NullableValue:    sql.NullBool{Bool: false, Valid: true},
+			UniqueValue:      sql.NullBool{Bool: false, Valid: true},
+			CommentValue:     sql.NullString{String: "old name", Valid: true},
+		}
+
+		if err := db.Migrator().MigrateColumn(&CommentedModel{}, stmt.Schema.LookUpField("name"), columnType); err != nil {
+			t.Fatalf("Expected MigrateColumn to succeed, got %v", err)
+		}
+
+		expected := `ALTER TABLE "commented_models" ALTER COLUMN "name" COMMENT 'display name'`
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected only %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("MigrateColumn removed comment", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(&MigratorTestModel{}); err != nil {
+			t.Fatalf("Failed to parse model: %v", err)
+		}
+
+		columnType := migrator.ColumnType{
+			NameValue:        sql.NullString{String: "email", Valid: true},
+			DataTypeValue:    sql.NullString{String: "TEXT", Valid: true},
+			LengthValue:      sql.NullInt64{Int64: 100, Valid: true},
+			DecimalSizeValue: sql.NullInt64{Valid: true},
+			NullableValue:    sql.NullBool{Bool: true, Valid: true},
+			CommentValue:     sql.NullString{String: "contact", Valid: true},
+		}
+		if err := db.Migrator().MigrateColumn(&MigratorTestModel{}, stmt.Schema.LookUpField("email"), columnType); err != nil {
+			t.Fatalf("Expected MigrateColumn to succeed, got %v", err)
+		}
+
+		expected := `ALTER TABLE "migrator_test_models" ALTER COLUMN "email" UNSET COMMENT`
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected only %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("AutoMigrate comment drift", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			switch {
+			case strings.Contains(query, "count(*)"):
+				return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(1)}}}
+			case queriesInformationSchema(query, "COLUMNS"):
+				return informationSchemaColumns(
+					[]driver.Value{"ID", "NUMBER", "NO", nil, nil, int64(38), int64(0), "YES", nil},
+					[]driver.Value{"NAME", "TEXT", "NO", nil, int64(255), nil, nil, "NO", nil},
+				)
+			case strings.HasPrefix(query, "SELECT comment FROM information_schema.tables"):
+				return fakeResult{columns: []string{"comment"}, rows: [][]driver.Value{{"customer's orders"}}}
+			}
+			return fakeResult{}
+		})
+
+		if err := db.AutoMigrate(&CommentedModel{}); err != nil {
+			t.Fatalf("Expected AutoMigrate to succeed, got %v", err)
+		}
+		queries := fake.queries()
+		if !hasQuery(queries, "ALTER TABLE commented_models ALTER COLUMN name COMMENT 'display name'") {
+			t.Errorf("Expected the comment of name to be set, got %v", queries)
+		}
+		if hasQuery(queries, "ADD name") || hasQuery(queries, "ALTER COLUMN id") || hasQuery(queries, "ALTER COLUMN name VARCHAR") {
+			t.Errorf("Expected only the comment to be migrated, got %v", queries)
+		}
+	})
+}
+
+func TestMigratorSession(t *testing.T) {
+	handler := func(query string, args []interface{}) fakeResult {
+		if query == "SELECT CURRENT_WAREHOUSE()" {
+			return fakeResult{columns: []string{"CURRENT_WAREHOUSE()"}, rows: [][]driver.Value{{"APP_WH"}}}
+		}
+		return fakeResult{}
+	}
+
+	t.Run("Warehouse and timeout", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{
+			Migration: MigratorConfig{Warehouse: "MIGRATE_WH", Timeout: 90*time.Minute + time.Millisecond},
+		}, handler)
+
+		if err := db.Migrator().DropTable(&MigratorTestModel{}); err != nil {
+			t.Fatalf("Expected DropTable to succeed, got %v", err)
+		}
+
+		expected := []string{
+			"SELECT CURRENT_WAREHOUSE()",
+			"USE WAREHOUSE migrate_wh",
+			"ALTER SESSION SET STATEMENT_TIMEOUT_IN_SECONDS = 5401",
+			"DROP TABLE IF EXISTS migrator_test_models",
+			"ALTER SESSION UNSET STATEMENT_TIMEOUT_IN_SECONDS",
+			`USE WAREHOUSE "APP_WH"`,
+		}
+		queries := fake.queries()
+		if len(queries) != len(expected) {
+			t.Fatalf("Expected %v, got %v", expected, queries)
+		}
+		for idx := range expected {
+			if queries[idx] != expected[idx] {
+				t.Errorf("Statement %d: expected %s, got %s", idx, expected[idx], queries[idx])
+			}
+		}
+	})
+
+	t.Run("In a transaction", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{Migration: MigratorConfig{Warehouse: "MIGRATE_WH"}}, handler)
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			tx.Exec("SELECT 1")
+			return tx.Migrator().DropTable(&MigratorTestModel{})
+		})
+		if err != nil {
+			t.Fatalf("Expected DropTable to succeed, got %v", err)
+		}
+		for _, statement := range fake.statements {
+			if statement.conn != fake.statements[0].conn {
+				t.Errorf("Expected the statements in the transaction, %s ran on another connection", statement.query)
+			}
+		}
+	})
+
+	t.Run("Named bind variables", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{BindVarStyle: BindVarNamed, Migration: MigratorConfig{Warehouse: "MIGRATE_WH"}}, handler)
+
+		if err := db.Migrator().AutoMigrate(&MigratorTestModel{}); err != nil {
+			t.Fatalf("Expected AutoMigrate to succeed, got %v", err)
+		}
+		for _, statement := range fake.statements {
+			for idx, name := range statement.names {
+				if name == "" {
+					t.Errorf("Expected the var %d of %s named", idx, statement.query)
+				}
+			}
+		}
+	})
+
+	t.Run("Not configured", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, handler)
+
+		if err := db.Migrator().DropTable(&MigratorTestModel{}); err != nil {
+			t.Fatalf("Expected DropTable to succeed, got %v", err)
+		}
+		if queries := fake.queries(); len(queries) != 1 {
+			t.Errorf("Expected only the DROP TABLE, got %v", queries)
+		}
+	})
+}
+
+func TestMigratorCreateMode(t *testing.T) {
+	tests := []struct {
+		mode      CreateTableMode
+		tableType TableType
+		expected  string
+	}{
+		{CreateTableStrict, TablePermanent, "CREATE TABLE migrator_test_models ("},
+		{CreateTableIfNotExists, TablePermanent, "CREATE TABLE IF NOT EXISTS migrator_test_models ("},
+		{CreateTableOrReplace, TablePermanent, "CREATE OR REPLACE TABLE migrator_test_models ("},
+		{CreateTableIfNotExists, TableTransient, "CREATE TRANSIENT TABLE IF NOT EXISTS migrator_test_models ("},
+		{CreateTableOrReplace, TableTransient, "CREATE OR REPLACE TRANSIENT TABLE migrator_test_models ("},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			db, fake := setupFakeDB(t, Config{Migration: MigratorConfig{CreateMode: tt.mode}}, nil)
+
+			if err := WithTableType(db, tt.tableType).Migrator().CreateTable(&MigratorTestModel{}); err != nil {
+				t.Fatalf("Expected CreateTable to succeed, got %v", err)
+			}
+			queries := fake.queries()
+			if len(queries) != 1 || !strings.HasPrefix(queries[0], tt.expected) {
+				t.Errorf("Expected %s, got %v", tt.expected, queries)
+			}
+		})
+	}
+
+	t.Run("Re-run", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{Migration: MigratorConfig{CreateMode: CreateTableIfNotExists}}, nil)
+
+		for i := 0; i < 2; i++ {
+			if err := db.Migrator().DropTable(&MigratorTestModel{}); err != nil {
+				t.Fatalf("Expected DropTable to succeed, got %v", err)
+			}
+			if err := db.Migrator().CreateTable(&MigratorTestModel{}); err != nil {
+				t.Fatalf("Expected CreateTable to succeed, got %v", err)
+			}
+		}
+		queries := fake.queries()
+		if len(queries) != 4 || queries[2] != "DROP TABLE IF EXISTS migrator_test_models" {
+			t.Errorf("Expected DROP TABLE IF EXISTS and CREATE TABLE IF NOT EXISTS twice, got %v", queries)
+		}
+	})
+}
+
+type IndexedModel struct {
+	ID     uint      `gorm:"primaryKey"`
+	Region string    `gorm:"size:32;index:idx_region_day,priority:1"`
+	Day    time.Time `gorm:"index:idx_region_day,priority:2"`
+	Email  string    `gorm:"size:100;uniqueIndex"`
+}
+
+func TestMigratorIndexStrategy(t *testing.T) {
+	t.Run("Cluster by first index", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true, IndexStrategy: IndexClusterByFirstIndex}, nil)
+
+		if err := db.Migrator().CreateTable(&IndexedModel{}); err != nil {
+			t.Fatalf("Expected CreateTable to succeed, got %v", err)
+		}
+		if !hasQuery(fake.queries(), `CLUSTER BY ("region","day") CHANGE_TRACKING = TRUE`) {
+			t.Errorf("Expected clustering key in %v", fake.queries())
+		}
+	})
+
+	t.Run("Search optimization", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true, IndexStrategy: IndexSearchOptimization}, nil)
+
+		if err := db.Migrator().CreateTable(&IndexedModel{}); err != nil {
+			t.Fatalf("Expected CreateTable to succeed, got %v", err)
+		}
+
+		queries := fake.queries()
+		for _, expected := range []string{
+			`ALTER TABLE "indexed_models" ADD SEARCH OPTIMIZATION ON EQUALITY("region","day")`,
+			`ALTER TABLE "indexed_models" ADD SEARCH OPTIMIZATION ON EQUALITY("email")`,
+		} {
+			if !hasQuery(queries, expected) {
+				t.Errorf("Expected %s in %v", expected, queries)
+			}
+		}
+		if hasQuery(queries, "CLUSTER BY") {
+			t.Errorf("Expected no clustering key, got %v", queries)
+		}
+	})
+
+	t.Run("Ignored by default", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		if err := db.Migrator().CreateTable(&IndexedModel{}); err != nil {
+			t.Fatalf("Expected CreateTable to succeed, got %v", err)
+		}
+		if queries := fake.queries(); len(queries) != 1 || hasQuery(queries, "CLUSTER BY") {
+			t.Errorf("Expected only CREATE TABLE, got %v", queries)
+		}
+	})
+
+	t.Run("Existing clustering key", func(t *testing.T) {
+		for current, alter := range map[string]bool{`LINEAR("region", "day")`: false, "LINEAR(region)": true} {
+			db, fake := setupFakeDB(t, Config{QuoteFields: true, IndexStrategy: IndexClusterByFirstIndex}, func(query string, args []interface{}) fakeResult {
+				if strings.Contains(query, "CLUSTERING_KEY") {
+					return fakeResult{columns: []string{"clustering_key"}, rows: [][]driver.Value{{current}}}
+				}
+				return fakeResult{}
+			})
+
+			stmt := &gorm.Statement{DB: db}
+			if err := stmt.Parse(&IndexedModel{}); err != nil {
+				t.Fatalf("Failed to parse model: %v", err)
+			}
+			if err := db.Migrator().(Migrator).migrateIndexStrategy(stmt); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if altered := hasQuery(fake.queries(), `ALTER TABLE "indexed_models" CLUSTER BY ("region","day")`); altered != alter {
+				t.Errorf("With clustering key %s: expected ALTER %v, got %v", current, alter, fake.queries())
+			}
+		}
+	})
+}
+
+type CyclicAuthor struct {
+	ID             uint
+	FavoriteBookID *uint
+	FavoriteBook   *CyclicBook
+}
+
+type CyclicBook struct {
+	ID       uint
+	AuthorID uint
+	Author   *CyclicAuthor
+}
+
+type AuthorStats struct {
+	AuthorID uint
+	Books    int
+}
+
+func (AuthorStats) ViewDefinition() string {
+	return "SELECT author_id, COUNT(*) AS books FROM cyclic_books GROUP BY author_id"
+}
+
+type ProlificAuthor struct {
+	AuthorID uint
+}
+
+func (ProlificAuthor) ViewDefinition() string {
+	return "SELECT author_id FROM author_stats WHERE books > 10"
+}
+
+func (ProlificAuthor) ViewDependencies() []interface{} {
+	return []interface{}{&AuthorStats{}}
+}
+
+func TestMigratorAutoMigrateOrdering(t *testing.T) {
+	db, fake := setupFakeDB(t, Config{}, nil)
+
+	if err := db.AutoMigrate(&ProlificAuthor{}, &CyclicAuthor{}, &AuthorStats{}, &CyclicBook{}); err != nil {
+		t.Fatalf("Expected AutoMigrate to succeed, got %v", err)
+	}
+
+	var creates, constraints, views []int
+	for idx, query := range fake.queries() {
+		switch {
+		case strings.HasPrefix(query, "CREATE TABLE"):
+			if strings.Contains(query, "FOREIGN KEY") {
+				t.Errorf("Expected foreign keys to be deferred, got %s", query)
+			}
+			creates = append(creates, idx)
+		case strings.Contains(query, "ADD CONSTRAINT"):
+			constraints = append(constraints, idx)
+		case strings.HasPrefix(query, "CREATE OR REPLACE VIEW"):
+			views = append(views, idx)
+		}
+	}
+
+	if len(creates) != 2 || len(constraints) != 2 || len(views) != 2 {
+		t.Fatalf("Expected 2 tables, 2 constraints and 2 views, got %v", fake.queries())
+	}
+	if creates[1] > constraints[0] || constraints[1] > views[0] {
+		t.Errorf("Expected tables, then constraints, then views, got %v", fake.queries())
+	}
+
+	queries := fake.queries()
+	if !strings.HasPrefix(queries[views[0]], "CREATE OR REPLACE VIEW author_stats AS") ||
+		!strings.HasPrefix(queries[views[1]], "CREATE OR REPLACE VIEW prolific_authors AS") {
+		t.Errorf("Expected author_stats before prolific_authors, got %s then %s", queries[views[0]], queries[views[1]])
+	}
+}
+
+type CycleViewA struct{ ID uint }
+
+func (CycleViewA) ViewDefinition() string          { return "SELECT id FROM cycle_view_bs" }
+func (CycleViewA) ViewDependencies() []interface{} { return []interface{}{&CycleViewB{}} }
+
+type CycleViewB struct{ ID uint }
+
+func (CycleViewB) ViewDefinition() string          { return "SELECT id FROM cycle_view_as" }
+func (CycleViewB) ViewDependencies() []interface{} { return []interface{}{&CycleViewA{}} }
+
+func TestOrderViews(t *testing.T) {
+	views, err := orderViews([]interface{}{&ProlificAuthor{}})
+	if err != nil || len(views) != 1 {
+		t.Errorf("Expected dependencies missing from views to be ignored, got %v %v", views, err)
+	}
+
+	if _, err := orderViews([]interface{}{&CycleViewA{}, &CycleViewB{}}); err == nil {
+		t.Error("Expected an error for cyclic view dependencies")
+	}
+}
+
+func TestMigratorMigrateColumnUnique(t *testing.T) {
+	stmt := &gorm.Statement{DB: setupMockDB(t)}
+	if err := stmt.Parse(&MigratorTestModel{}); err != nil {
+		t.Fatalf("Failed to parse model: %v", err)
+	}
+	columnType := func(name string, unique sql.NullBool) migrator.ColumnType {
+		return migrator.ColumnType{NameValue: sql.NullString{String: name, Valid: true}, UniqueValue: unique}
+	}
+
+	t.Run("Adds the constraint of unique fields", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		err := db.Migrator().MigrateColumnUnique(&MigratorTestModel{}, stmt.Schema.LookUpField("email"), columnType("email", sql.NullBool{Valid: true}))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if queries := fake.queries(); !hasQuery(queries, `ALTER TABLE "migrator_test_models" ADD CONSTRAINT "uni_migrator_test_models_email" UNIQUE ("email")`) {
+			t.Errorf("Expected ADD CONSTRAINT, got %v", queries)
+		}
+	})
+
+	t.Run("Drops the constraint of fields no longer unique", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		err := db.Migrator().MigrateColumnUnique(&MigratorTestModel{}, stmt.Schema.LookUpField("name"), columnType("name", sql.NullBool{Bool: true, Valid: true}))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if queries := fake.queries(); !hasQuery(queries, `ALTER TABLE "migrator_test_models" DROP UNIQUE ("name")`) {
+			t.Errorf("Expected DROP UNIQUE, got %v", queries)
+		}
+	})
+
+	t.Run("Unchanged or unknown", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		for _, ct := range []migrator.ColumnType{columnType("email", sql.NullBool{Bool: true, Valid: true}), columnType("name", sql.NullBool{})} {
+			if err := db.Migrator().MigrateColumnUnique(&MigratorTestModel{}, stmt.Schema.LookUpField(ct.Name()), ct); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+		if queries := fake.queries(); len(queries) != 0 {
+			t.Errorf("Expected no statement, got %v", queries)
+		}
+	})
+
+	t.Run("ColumnTypes reports single column unique keys", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{QuoteFields: true}, func(query string, args []interface{}) fakeResult {
+			switch {
+			case strings.HasPrefix(query, "SHOW UNIQUE KEYS IN TABLE "):
+				return fakeResult{
+					columns: []string{"column_name", "key_sequence", "constraint_name"},
+					rows: [][]driver.Value{
+						{"email", int64(1), "SYS_CONSTRAINT_1"},
+						{"name", int64(1), "composite"},
+						{"age", int64(2), "composite"},
+					},
+				}
+			case queriesInformationSchema(query, "COLUMNS"):
+				return informationSchemaColumns(
+					[]driver.Value{"id", "NUMBER", "NO", nil, nil, int64(38), int64(0), "YES", nil},
+					[]driver.Value{"name", "TEXT", "NO", nil, int64(255), nil, nil, "NO", nil},
+					[]driver.Value{"email", "TEXT", "YES", nil, int64(100), nil, nil, "NO", nil},
+					[]driver.Value{"age", "NUMBER", "YES", nil, nil, int64(38), int64(0), "NO", nil},
+				)
+			}
+			return fakeResult{}
+		})
+
+		columnTypes, err := db.Migrator().ColumnTypes(&MigratorTestModel{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		unique := map[string]bool{}
+		for _, ct := range columnTypes {
+			value, ok := ct.Unique()
+			if !ok {
+				t.Errorf("Expected the unique state of %s", ct.Name())
+			}
+			unique[ct.Name()] = value
+		}
+		if !unique["email"] || unique["name"] || unique["age"] || unique["id"] || len(unique) != 4 {
+			t.Errorf("Expected email only to be unique, got %v", unique)
+		}
+	})
+}
+
+// informationSchemaColumns returns the rows of INFORMATION_SCHEMA.COLUMNS queried by ColumnTypes
+func informationSchemaColumns(rows ...[]driver.Value) fakeResult {
+	return fakeResult{
+		columns: []string{"COLUMN_NAME", "DATA_TYPE", "IS_NULLABLE", "COLUMN_DEFAULT", "CHARACTER_MAXIMUM_LENGTH", "NUMERIC_PRECISION", "NUMERIC_SCALE", "IS_IDENTITY", "COMMENT"},
+		rows:    rows,
+	}
+}
+
+func TestMigratorColumnTypes(t *testing.T) {
+	handler := func(query string, args []interface{}) fakeResult {
+		switch {
+		case strings.HasPrefix(query, "SHOW PRIMARY KEYS IN TABLE "):
+			return fakeResult{
+				columns: []string{"column_name", "key_sequence", "constraint_name"},
+				rows:    [][]driver.Value{{"ID", int64(1), "SYS_CONSTRAINT_0"}},
+			}
+		case queriesInformationSchema(query, "COLUMNS"):
+			return informationSchemaColumns(
+				[]driver.Value{"ID", "NUMBER", "NO", nil, nil, int64(38), int64(0), "YES", nil},
+				[]driver.Value{"NAME", "TEXT", "NO", "'it''s'", int64(255), nil, nil, "NO", "display name"},
+				[]driver.Value{"PRICE", "NUMBER", "YES", nil, nil, int64(10), int64(2), "NO", nil},
+				[]driver.Value{"CREATED_AT", "TIMESTAMP_NTZ", "YES", "CURRENT_TIMESTAMP()", nil, nil, nil, "NO", nil},
+			)
+		}
+		return fakeResult{}
+	}
+
+	db, fake := setupFakeDB(t, Config{}, handler)
+	columnTypes, err := db.Migrator().ColumnTypes(&MigratorTestModel{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !hasQuery(fake.queries(), "(table_schema = CURRENT_SCHEMA() AND table_name = ?) AND table_catalog = ? ORDER BY ordinal_position") {
+		t.Errorf("Expected the columns of the table in the current schema, got %v", fake.queries())
+	}
+	if len(columnTypes) != 4 {
+		t.Fatalf("Expected 4 columns, got %d", len(columnTypes))
+	}
+
+	id, name, price, createdAt := columnTypes[0], columnTypes[1], columnTypes[2], columnTypes[3]
+	if id.Name() != "id" || createdAt.Name() != "created_at" {
+		t.Errorf("Expected unquoted names lowercase, got %s and %s", id.Name(), createdAt.Name())
+	}
+	if primary, ok := id.PrimaryKey(); !primary || !ok {
+		t.Errorf("Expected id to be the primary key")
+	}
+	if autoIncrement, _ := id.AutoIncrement(); !autoIncrement {
+		t.Errorf("Expected id to be an identity column")
+	}
+	if nullable, ok := name.Nullable(); nullable || !ok {
+		t.Errorf("Expected name not to be nullable")
+	}
+	if length, ok := name.Length(); length != 255 || !ok {
+		t.Errorf("Expected a length of 255, got %d", length)
+	}
+	if columnType, _ := name.ColumnType(); columnType != "VARCHAR(255)" {
+		t.Errorf("Expected VARCHAR(255), got %s", columnType)
+	}
+	if value, ok := name.DefaultValue(); value != "it's" || !ok {
+		t.Errorf("Expected the unquoted default value, got %q", value)
+	}
+	if comment, _ := name.Comment(); comment != "display name" {
+		t.Errorf("Expected the comment, got %q", comment)
+	}
+	if precision, scale, ok := price.DecimalSize(); precision != 10 || scale != 2 || !ok {
+		t.Errorf("Expected NUMBER(10,2), got NUMBER(%d,%d)", precision, scale)
+	}
+	if price.ScanType() != reflect.TypeOf(float64(0)) || id.ScanType() != reflect.TypeOf(int64(0)) {
+		t.Errorf("Unexpected scan types %v and %v", price.ScanType(), id.ScanType())
+	}
+	if value, _ := createdAt.DefaultValue(); value != "CURRENT_TIMESTAMP()" {
+		t.Errorf("Expected the default expression, got %q", value)
+	}
+	if _, ok := price.DefaultValue(); ok {
+		t.Errorf("Expected no default value for price")
+	}
+}
+
+func TestMigratorAutoMigrateColumnTypesError(t *testing.T) {
+	db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+		switch {
+		case strings.HasPrefix(query, "SHOW UNIQUE KEYS IN TABLE "):
+			return fakeResult{err: errors.New("insufficient privileges")}
+		case strings.Contains(query, "count(*)"):
+			return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(1)}}}
+		}
+		return fakeResult{}
+	})
+
+	if err := db.AutoMigrate(&MigratorTestModel{}); err == nil || !strings.Contains(err.Error(), "insufficient privileges") {
+		t.Errorf("Expected the error of ColumnTypes, got %v", err)
+	}
+	if hasQuery(fake.queries(), "ADD COLUMN") {
+		t.Errorf("Expected no column added, got %v", fake.queries())
+	}
+}
+
+func TestMigratorTypeAliases(t *testing.T) {
+	db := setupMockDB(t)
+	m := db.Migrator()
+
+	for _, tt := range []struct {
+		databaseType, declared string
+	}{
+		{"NUMBER", "bigint"},
+		{"TEXT", "varchar(255)"},
+		{"FLOAT", "double"},
+		{"TIMESTAMP_NTZ", "datetime"},
+	} {
+		found := false
+		for _, alias := range m.GetTypeAliases(tt.databaseType) {
+			found = found || strings.HasPrefix(tt.declared, alias)
+		}
+		if !found {
+			t.Errorf("Expected %s to be an alias of %s", tt.declared, tt.databaseType)
+		}
+	}
+	for _, alias := range m.GetTypeAliases("TIMESTAMP_NTZ") {
+		if strings.HasPrefix("timestamp_ltz", alias) {
+			t.Errorf("Expected timestamp_ltz not to be an alias of TIMESTAMP_NTZ")
+		}
+	}
+}
+
+func TestMigratorTables(t *testing.T) {
+	db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+		switch {
+		case strings.HasPrefix(query, "SELECT table_name FROM information_schema.tables"):
+			return fakeResult{columns: []string{"TABLE_NAME"}, rows: [][]driver.Value{{"MIGRATOR_TEST_MODELS"}, {"Quoted"}}}
+		case strings.HasPrefix(query, "SELECT * FROM information_schema.tables"):
+			return fakeResult{
+				columns: []string{"TABLE_SCHEMA", "TABLE_NAME", "TABLE_TYPE", "COMMENT"},
+				rows:    [][]driver.Value{{"PUBLIC", "MIGRATOR_TEST_MODELS", "BASE TABLE", "test models"}},
+			}
+		}
+		return fakeResult{}
+	})
+
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if fmt.Sprint(tables) != "[migrator_test_models Quoted]" {
+		t.Errorf("Expected [migrator_test_models Quoted], got %v", tables)
+	}
+	if !hasQuery(fake.queries(), "table_type NOT LIKE '%VIEW'") {
+		t.Errorf("Expected views to be excluded, got %v", fake.queries())
+	}
+
+	tableType, err := db.Migrator().TableType(&MigratorTestModel{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if comment, ok := tableType.Comment(); tableType.Schema() != "PUBLIC" || tableType.Name() != "migrator_test_models" ||
+		tableType.Type() != "BASE TABLE" || comment != "test models" || !ok {
+		t.Errorf("Unexpected table type %+v", tableType)
+	}
+}
@@ -0,0 +1,119 @@
+package snowflake
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+const defaultNPlusOneThreshold = 10
+
+type queryTraceKey struct{}
+
+// queryTrace counts the statements run with a traced context
+type queryTrace struct {
+	mu       sync.Mutex
+	total    int
+	selects  map[string]int
+	reported map[string]bool
+}
+
+// TraceQueries returns a copy of ctx in which the statements are counted by NPlusOneDetector,
+// typically created once per incoming request
+func TraceQueries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryTraceKey{}, &queryTrace{selects: map[string]int{}, reported: map[string]bool{}})
+}
+
+// TracedQueryCount returns the number of statements run so far with ctx, 0 if ctx is not traced
+func TracedQueryCount(ctx context.Context) int {
+	trace, ok := ctx.Value(queryTraceKey{}).(*queryTrace)
+	if !ok {
+		return 0
+	}
+
+	trace.mu.Lock()
+	defer trace.mu.Unlock()
+	return trace.total
+}
+
+// NPlusOneDetector is a plugin reporting SELECT statements that run more than Threshold times
+// with the same SQL (only their bind values differ) within a context returned by TraceQueries,
+// the pattern of associations loaded row by row instead of with Preload.
+// Every query has a warehouse overhead on Snowflake, which makes such storms costly.
+//
+//	db.Use(&snowflake.NPlusOneDetector{})
+//	db.WithContext(snowflake.TraceQueries(ctx)).Find(&users)
+type NPlusOneDetector struct {
+	// Threshold is the number of identical SELECTs allowed per context (default 10)
+	Threshold int
+	// OnDetect is called once per context and statement when the threshold is exceeded,
+	// by default a warning is written to the logger of the DB
+	OnDetect func(ctx context.Context, sql string, count int)
+}
+
+func (detector *NPlusOneDetector) Name() string {
+	return "snowflake:n_plus_one_detector"
+}
+
+func (detector *NPlusOneDetector) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	if err := callback.Query().After("gorm:query").Register(detector.Name(), detector.trace); err != nil {
+		return err
+	}
+	if err := callback.Row().After("gorm:row").Register(detector.Name(), detector.trace); err != nil {
+		return err
+	}
+	if err := callback.Raw().After("gorm:raw").Register(detector.Name(), detector.trace); err != nil {
+		return err
+	}
+	if err := callback.Create().After("gorm:create").Register(detector.Name(), detector.trace); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:update").Register(detector.Name(), detector.trace); err != nil {
+		return err
+	}
+	return callback.Delete().After("gorm:delete").Register(detector.Name(), detector.trace)
+}
+
+// trace counts the statement of db in its traced context
+func (detector *NPlusOneDetector) trace(db *gorm.DB) {
+	ctx := db.Statement.Context
+	if ctx == nil || db.DryRun {
+		return
+	}
+	trace, ok := ctx.Value(queryTraceKey{}).(*queryTrace)
+	if !ok {
+		return
+	}
+
+	sql := db.Statement.SQL.String()
+
+	trace.mu.Lock()
+	trace.total++
+	var count int
+	if isSelectStatement(sql) {
+		trace.selects[sql]++
+		count = trace.selects[sql]
+	}
+
+	threshold := detector.Threshold
+	if threshold <= 0 {
+		threshold = defaultNPlusOneThreshold
+	}
+	report := count > threshold && !trace.reported[sql]
+	if report {
+		trace.reported[sql] = true
+	}
+	trace.mu.Unlock()
+
+	if !report {
+		return
+	}
+
+	if detector.OnDetect != nil {
+		detector.OnDetect(ctx, sql, count)
+	} else {
+		db.Logger.Warn(ctx, "possible N+1 queries, statement ran %d times in the same context, consider Preload: %s", count, sql)
+	}
+}
@@ -0,0 +1,72 @@
+package snowflake
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNPlusOneDetector(t *testing.T) {
+	type detection struct {
+		sql   string
+		count int
+	}
+
+	setup := func(t *testing.T) (*NPlusOneDetector, *[]detection, func(context.Context, int)) {
+		db, _ := setupFakeDB(t, Config{}, nil)
+
+		var detections []detection
+		detector := &NPlusOneDetector{
+			Threshold: 3,
+			OnDetect: func(ctx context.Context, sql string, count int) {
+				detections = append(detections, detection{sql, count})
+			},
+		}
+		if err := db.Use(detector); err != nil {
+			t.Fatalf("Failed to register detector: %v", err)
+		}
+
+		run := func(ctx context.Context, n int) {
+			for i := 0; i < n; i++ {
+				var model TestModel
+				db.WithContext(ctx).Where("id = ?", i).Find(&model)
+			}
+		}
+		return detector, &detections, run
+	}
+
+	t.Run("Reports repeated statement once", func(t *testing.T) {
+		_, detections, run := setup(t)
+
+		ctx := TraceQueries(context.Background())
+		run(ctx, 6)
+
+		if len(*detections) != 1 || (*detections)[0].count != 4 {
+			t.Fatalf("Expected one detection at the 4th query, got %+v", *detections)
+		}
+		if TracedQueryCount(ctx) != 6 {
+			t.Errorf("Expected 6 traced queries, got %d", TracedQueryCount(ctx))
+		}
+	})
+
+	t.Run("Counts per context", func(t *testing.T) {
+		_, detections, run := setup(t)
+
+		run(TraceQueries(context.Background()), 3)
+		run(TraceQueries(context.Background()), 3)
+
+		if len(*detections) != 0 {
+			t.Errorf("Expected no detection, got %+v", *detections)
+		}
+	})
+
+	t.Run("Untraced context", func(t *testing.T) {
+		_, detections, run := setup(t)
+
+		ctx := context.Background()
+		run(ctx, 6)
+
+		if len(*detections) != 0 || TracedQueryCount(ctx) != 0 {
+			t.Errorf("Expected untraced context to be ignored, got %+v", *detections)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"regexp"
+
+	"gorm.io/gorm/clause"
+)
+
+// nullComparisonRegex matches the =, <> or != operator ending a condition before its placeholder,
+// group 1 ends right before the operator (>=, <= and := are not matched)
+var nullComparisonRegex = regexp.MustCompile(`([^<>!=:\s])\s*(<>|!=|=)\s*$`)
+
+// rewriteNullComparisons rewrites the comparisons of conditions bound to NULL (nil, nil pointer,
+// invalid sql.Null*): `active = ?` with a nil *bool becomes `active IS NULL`, `active <> ?`
+// becomes `active IS NOT NULL`, as comparisons with NULL match no row.
+// Conditions built by gorm (clause.Eq, clause.Neq) already do so. It returns nil when no
+// condition is rewritten
+func rewriteNullComparisons(exprs []clause.Expression) []clause.Expression {
+	var rewritten []clause.Expression
+	for idx, expr := range exprs {
+		var updated clause.Expression
+		switch e := expr.(type) {
+		case clause.Expr:
+			if nullSafe, ok := rewriteNullComparisonExpr(e); ok {
+				updated = nullSafe
+			}
+		case clause.AndConditions:
+			if nullSafe := rewriteNullComparisons(e.Exprs); nullSafe != nil {
+				updated = clause.AndConditions{Exprs: nullSafe}
+			}
+		case clause.OrConditions:
+			if nullSafe := rewriteNullComparisons(e.Exprs); nullSafe != nil {
+				updated = clause.OrConditions{Exprs: nullSafe}
+			}
+		case clause.NotConditions:
+			if nullSafe := rewriteNullComparisons(e.Exprs); nullSafe != nil {
+				updated = clause.NotConditions{Exprs: nullSafe}
+			}
+		}
+
+		if updated != nil {
+			if rewritten == nil {
+				rewritten = append([]clause.Expression(nil), exprs...)
+			}
+			rewritten[idx] = updated
+		}
+	}
+	return rewritten
+}
+
+// rewriteNullComparisonExpr rewrites the comparisons of expr bound to NULL, ok is false when
+// expr has none
+func rewriteNullComparisonExpr(expr clause.Expr) (_ clause.Expr, ok bool) {
+	var (
+		sql    = make([]byte, 0, len(expr.SQL)+8)
+		vars   = make([]interface{}, 0, len(expr.Vars))
+		varIdx int
+	)
+	for idx := 0; idx < len(expr.SQL); idx++ {
+		if expr.SQL[idx] != '?' || varIdx >= len(expr.Vars) {
+			sql = append(sql, expr.SQL[idx])
+			continue
+		}
+
+		value := expr.Vars[varIdx]
+		varIdx++
+		if isNullBind(value) {
+			if loc := nullComparisonRegex.FindSubmatchIndex(sql); loc != nil {
+				operator := string(sql[loc[4]:loc[5]])
+				sql = sql[:loc[3]]
+				if operator == "=" {
+					sql = append(sql, " IS NULL"...)
+				} else {
+					sql = append(sql, " IS NOT NULL"...)
+				}
+				ok = true
+				continue
+			}
+		}
+		sql = append(sql, '?')
+		vars = append(vars, value)
+	}
+	if !ok {
+		return expr, false
+	}
+
+	expr.SQL = string(sql)
+	expr.Vars = append(vars, expr.Vars[varIdx:]...)
+	return expr, true
+}
+
+// isNullBind reports whether value is bound as NULL
+func isNullBind(value interface{}) bool {
+	for {
+		if value == nil {
+			return true
+		}
+		reflectValue := reflect.ValueOf(value)
+		if reflectValue.Kind() == reflect.Ptr && reflectValue.IsNil() {
+			return true
+		}
+		if valuer, ok := value.(driver.Valuer); ok {
+			driverValue, err := valuer.Value()
+			return err == nil && driverValue == nil
+		}
+		if reflectValue.Kind() != reflect.Ptr {
+			return false
+		}
+		value = reflectValue.Elem().Interface()
+	}
+}
@@ -0,0 +1,73 @@
+package snowflake
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FlaggedModel struct {
+	ID     uint
+	Active *bool
+}
+
+func TestRewriteNullComparisons(t *testing.T) {
+	var (
+		active  = true
+		nilFlag *bool
+	)
+
+	for _, tt := range []struct {
+		name     string
+		query    func(tx *gorm.DB) *gorm.DB
+		expected string
+		vars     int
+	}{
+		{"nil pointer", func(tx *gorm.DB) *gorm.DB { return tx.Where("active = ?", nilFlag) }, `WHERE active IS NULL`, 0},
+		{"untyped nil", func(tx *gorm.DB) *gorm.DB { return tx.Where("active <> ?", nil) }, `WHERE active IS NOT NULL`, 0},
+		{"invalid null", func(tx *gorm.DB) *gorm.DB { return tx.Where("active != ?", sql.NullBool{}) }, `WHERE active IS NOT NULL`, 0},
+		{"non-nil pointer", func(tx *gorm.DB) *gorm.DB { return tx.Where("active = ?", &active) }, `WHERE active = ?`, 1},
+		{"mixed", func(tx *gorm.DB) *gorm.DB { return tx.Where("id >= ? AND active=? AND id = ?", 1, nilFlag, 2) }, `WHERE id >= ? AND active IS NULL AND id = ?`, 2},
+		{"other operators", func(tx *gorm.DB) *gorm.DB { return tx.Where("id >= ?", nil) }, `WHERE id >= ?`, 1},
+		{"grouped", func(tx *gorm.DB) *gorm.DB {
+			return tx.Where(tx.Session(&gorm.Session{NewDB: true}).Where("active = ?", nilFlag).Or("id = ?", 1))
+		}, `WHERE active IS NULL OR id = ?`, 1},
+		{"map condition", func(tx *gorm.DB) *gorm.DB {
+			return tx.Where(map[string]interface{}{"active": nilFlag})
+		}, `WHERE "flagged_models"."active" IS NULL`, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupMockDB(t)
+			stmt := tt.query(db.Session(&gorm.Session{DryRun: true})).Find(&[]FlaggedModel{}).Statement
+
+			sql := stmt.SQL.String()
+			if !strings.Contains(sql, tt.expected) || len(stmt.Vars) != tt.vars {
+				t.Errorf("Expected %s with %d vars, got %s %v", tt.expected, tt.vars, sql, stmt.Vars)
+			}
+		})
+	}
+}
+
+func TestMergeCreatePointerKey(t *testing.T) {
+	type PointerKeyModel struct {
+		Code *string `gorm:"primaryKey"`
+		Name string
+	}
+
+	db := setupMockDB(t)
+	stmt := db.Session(&gorm.Session{DryRun: true}).Model(&PointerKeyModel{})
+	if err := stmt.Statement.Parse(&PointerKeyModel{}); err != nil {
+		t.Fatalf("Failed to parse model: %v", err)
+	}
+
+	MergeCreate(stmt, clause.OnConflict{UpdateAll: true}, clause.Values{
+		Columns: []clause.Column{{Name: "code"}, {Name: "name"}},
+		Values:  [][]interface{}{{nil, "a"}},
+	})
+	if sql := stmt.Statement.SQL.String(); !strings.Contains(sql, `ON EQUAL_NULL("pointer_key_models"."code",EXCLUDED."code")`) {
+		t.Errorf("Expected null-safe join on the pointer key, got %s", sql)
+	}
+}
@@ -0,0 +1,162 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// numberCast is appended to binds of integers that do not fit in an int64
+	numberCast = "::NUMBER(38,0)"
+	// floatCast is appended to binds of NaN and infinite floats
+	floatCast = "::FLOAT"
+)
+
+// BigInt is a NUMBER(38,0) value that does not fit in an int64 (e.g. uint64 IDs above math.MaxInt64).
+// It is bound as a string and cast back to NUMBER in generated inserts
+type BigInt struct {
+	big.Int
+}
+
+// NewBigInt returns a BigInt holding x
+func NewBigInt(x *big.Int) BigInt {
+	var b BigInt
+	if x != nil {
+		b.Set(x)
+	}
+	return b
+}
+
+// GormDataType gorm common data type
+func (BigInt) GormDataType() string {
+	return "NUMBER(38,0)"
+}
+
+// Value implements driver.Valuer
+func (b BigInt) Value() (driver.Value, error) {
+	return b.String(), nil
+}
+
+// Scan implements sql.Scanner, NUMBER values are returned by the driver as strings
+func (b *BigInt) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		b.SetInt64(0)
+	case string:
+		return b.setString(v)
+	case []byte:
+		return b.setString(string(v))
+	case int64:
+		b.SetInt64(v)
+	case uint64:
+		b.SetUint64(v)
+	case float64:
+		if v != math.Trunc(v) {
+			return fmt.Errorf("cannot scan non integral %v into BigInt", v)
+		}
+		new(big.Float).SetFloat64(v).Int(&b.Int)
+	case *big.Int:
+		b.Set(v)
+	default:
+		return fmt.Errorf("cannot scan %T into BigInt", src)
+	}
+	return nil
+}
+
+func (b *BigInt) setString(s string) error {
+	if _, ok := b.SetString(s, 10); !ok {
+		return fmt.Errorf("cannot scan %q into BigInt", s)
+	}
+	return nil
+}
+
+// numberBindValue returns the decimal representation of integers the driver cannot bind
+// as int64, ok is false for every other value
+func numberBindValue(v interface{}) (_ string, ok bool) {
+	switch v := v.(type) {
+	case uint64:
+		if v > math.MaxInt64 {
+			return strconv.FormatUint(v, 10), true
+		}
+	case *uint64:
+		if v != nil && *v > math.MaxInt64 {
+			return strconv.FormatUint(*v, 10), true
+		}
+	case uint:
+		if uint64(v) > math.MaxInt64 {
+			return strconv.FormatUint(uint64(v), 10), true
+		}
+	case *big.Int:
+		if v != nil {
+			return v.String(), true
+		}
+	case BigInt:
+		return v.String(), true
+	case *BigInt:
+		if v != nil {
+			return v.String(), true
+		}
+	}
+	return "", false
+}
+
+// specialFloatBindValue returns Snowflake's string form of NaN and infinite floats, which the
+// driver cannot bind as numbers, ok is false for every other value
+func specialFloatBindValue(v interface{}) (_ string, ok bool) {
+	var f float64
+	switch v := v.(type) {
+	case float64:
+		f = v
+	case float32:
+		f = float64(v)
+	case *float64:
+		if v == nil {
+			return "", false
+		}
+		f = *v
+	case *float32:
+		if v == nil {
+			return "", false
+		}
+		f = float64(*v)
+	default:
+		return "", false
+	}
+
+	switch {
+	case math.IsNaN(f):
+		return "NaN", true
+	case math.IsInf(f, 1):
+		return "inf", true
+	case math.IsInf(f, -1):
+		return "-inf", true
+	}
+	return "", false
+}
+
+// addRowVars writes the comma separated binds of a row of values into writer,
+// integers above int64 are bound as strings and cast to NUMBER(38,0),
+// NaN and infinite floats are bound as strings and cast to FLOAT
+func addRowVars(db *gorm.DB, writer clause.Writer, row []interface{}) {
+	for idx, value := range row {
+		if idx > 0 {
+			writer.WriteByte(',')
+		}
+
+		if number, ok := numberBindValue(value); ok {
+			db.Statement.AddVar(writer, number)
+			writer.WriteString(numberCast)
+		} else if special, ok := specialFloatBindValue(value); ok {
+			db.Statement.AddVar(writer, special)
+			writer.WriteString(floatCast)
+		} else {
+			rowBuilder{Writer: writer, stmt: db.Statement}.AddVar(writer, value)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package snowflake
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestNumberBindValue(t *testing.T) {
+	huge := uint64(math.MaxUint64)
+	bigInt, _ := new(big.Int).SetString("99999999999999999999999999999999999999", 10)
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+		ok       bool
+	}{
+		{"uint64 above MaxInt64", uint64(math.MaxInt64) + 1, "9223372036854775808", true},
+		{"uint64 pointer", &huge, "18446744073709551615", true},
+		{"uint64 within int64", uint64(42), "", false},
+		{"uint above MaxInt64", uint(math.MaxUint64), "18446744073709551615", true},
+		{"big.Int", bigInt, "99999999999999999999999999999999999999", true},
+		{"BigInt", NewBigInt(bigInt), "99999999999999999999999999999999999999", true},
+		{"int64", int64(-1), "", false},
+		{"string", "1", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, ok := numberBindValue(test.value)
+			if ok != test.ok || result != test.expected {
+				t.Errorf("Expected (%q, %v), got (%q, %v)", test.expected, test.ok, result, ok)
+			}
+		})
+	}
+}
+
+func TestBigIntScan(t *testing.T) {
+	tests := []struct {
+		src      interface{}
+		expected string
+	}{
+		{"18446744073709551615", "18446744073709551615"},
+		{[]byte("-12345678901234567890"), "-12345678901234567890"},
+		{int64(7), "7"},
+		{uint64(math.MaxUint64), "18446744073709551615"},
+		{float64(1e20), "100000000000000000000"},
+		{nil, "0"},
+	}
+
+	for _, test := range tests {
+		var b BigInt
+		if err := b.Scan(test.src); err != nil {
+			t.Errorf("Scan(%v): unexpected error %v", test.src, err)
+			continue
+		}
+		if b.String() != test.expected {
+			t.Errorf("Scan(%v): expected %s, got %s", test.src, test.expected, b.String())
+		}
+	}
+
+	var b BigInt
+	if err := b.Scan("not a number"); err == nil {
+		t.Error("Expected an error for invalid string")
+	}
+	if err := b.Scan(1.5); err == nil {
+		t.Error("Expected an error for non integral float")
+	}
+
+	value, err := NewBigInt(big.NewInt(12)).Value()
+	if err != nil || value != "12" {
+		t.Errorf("Expected Value to be \"12\", got %v (%v)", value, err)
+	}
+}
+
+func TestCreateWithLargeUnsignedIDs(t *testing.T) {
+	type LargeIDModel struct {
+		ID       uint64 `gorm:"primaryKey"`
+		Name     string
+		External BigInt
+	}
+
+	db := setupMockDB(t)
+	models := []LargeIDModel{
+		{ID: math.MaxUint64, Name: "max", External: NewBigInt(big.NewInt(1))},
+		{ID: 1, Name: "one", External: NewBigInt(big.NewInt(2))},
+	}
+
+	for _, useUnionSelect := range []bool{true, false} {
+		db.Dialector.(*Dialector).UseUnionSelect = useUnionSelect
+
+		stmt := db.Session(&gorm.Session{DryRun: true}).Model(&LargeIDModel{})
+		if err := stmt.Statement.Parse(&LargeIDModel{}); err != nil {
+			t.Fatalf("Failed to parse model: %v", err)
+		}
+		stmt.Statement.Dest = models
+		stmt.Statement.ReflectValue = reflect.ValueOf(models)
+
+		Create(stmt)
+
+		sql := stmt.Statement.SQL.String()
+		if strings.Count(sql, numberCast) != 3 {
+			t.Errorf("Expected the large ID and both BigInt values to be cast, got: %s", sql)
+		}
+		var hasLargeID, hasSmallID bool
+		for _, v := range stmt.Statement.Vars {
+			hasLargeID = hasLargeID || v == "18446744073709551615"
+			hasSmallID = hasSmallID || v == uint64(1)
+		}
+		if !hasLargeID || !hasSmallID {
+			t.Errorf("Expected large uint64 ID bound as string and small one unchanged, got %#v", stmt.Statement.Vars)
+		}
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&LargeIDModel{}); err != nil {
+		t.Fatalf("Failed to parse model: %v", err)
+	}
+	if dataType := db.Dialector.DataTypeOf(stmt.Schema.LookUpField("External")); dataType != "NUMBER(38,0)" {
+		t.Errorf("Expected BigInt to map to NUMBER(38,0), got %s", dataType)
+	}
+}
+
+func TestSpecialFloatBindValue(t *testing.T) {
+	nan, inf := math.NaN(), float32(math.Inf(-1))
+	tests := []struct {
+		value    interface{}
+		expected string
+		ok       bool
+	}{
+		{math.NaN(), "NaN", true},
+		{math.Inf(1), "inf", true},
+		{float32(math.Inf(-1)), "-inf", true},
+		{&nan, "NaN", true},
+		{&inf, "-inf", true},
+		{1.5, "", false},
+		{(*float64)(nil), "", false},
+		{"NaN", "", false},
+	}
+
+	for _, test := range tests {
+		value, ok := specialFloatBindValue(test.value)
+		if ok != test.ok || value != test.expected {
+			t.Errorf("specialFloatBindValue(%v): expected (%q, %v), got (%q, %v)", test.value, test.expected, test.ok, value, ok)
+		}
+	}
+}
+
+func TestCreateWithSpecialFloats(t *testing.T) {
+	type MeasureModel struct {
+		ID    uint `gorm:"primaryKey"`
+		Value float64
+	}
+
+	db := setupMockDB(t)
+	models := []MeasureModel{{Value: math.NaN()}, {Value: math.Inf(1)}, {Value: 2.5}}
+
+	for _, useUnionSelect := range []bool{true, false} {
+		db.Dialector.(*Dialector).UseUnionSelect = useUnionSelect
+
+		stmt := db.Session(&gorm.Session{DryRun: true}).Model(&MeasureModel{})
+		if err := stmt.Statement.Parse(&MeasureModel{}); err != nil {
+			t.Fatalf("Failed to parse model: %v", err)
+		}
+		stmt.Statement.Dest = models
+		stmt.Statement.ReflectValue = reflect.ValueOf(models)
+
+		Create(stmt)
+
+		sql := stmt.Statement.SQL.String()
+		if strings.Count(sql, floatCast) != 2 {
+			t.Errorf("Expected NaN and inf to be cast to FLOAT, got: %s", sql)
+		}
+		var hasNaN, hasInf, hasFinite bool
+		for _, v := range stmt.Statement.Vars {
+			hasNaN = hasNaN || v == "NaN"
+			hasInf = hasInf || v == "inf"
+			hasFinite = hasFinite || v == 2.5
+		}
+		if !hasNaN || !hasInf || !hasFinite {
+			t.Errorf("Expected special floats bound as strings and finite ones unchanged, got %#v", stmt.Statement.Vars)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+// PinConnection returns a session of db whose statements all run on a single connection of the
+// pool, for sequences of statements relying on the Snowflake session: LAST_QUERY_ID, temporary
+// tables, ALTER SESSION. release returns the connection to the pool, the session must not be
+// used afterwards:
+//
+//	tx, release, err := snowflake.PinConnection(db)
+//	if err != nil {
+//		return err
+//	}
+//	defer release()
+//	tx.Exec("ALTER SESSION SET TIMEZONE = 'UTC'")
+//	tx.Find(&orders)
+//
+// A db already in a transaction or on a single connection is returned as is. Creates pin a
+// connection on their own while they read back default values
+func PinConnection(db *gorm.DB) (tx *gorm.DB, release func() error, err error) {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// a new context clones the statement, the connection must not leak to db
+	tx = db.Session(&gorm.Session{Context: ctx})
+	if pinnedConnPool(tx.Statement.ConnPool) {
+		return tx, func() error { return nil }, nil
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx.Statement.ConnPool = pinnedPool(tx.Statement.ConnPool, conn)
+	return tx, conn.Close, nil
+}
+
+// withPinnedConnPool runs fc with the statement on a single connection of the pool, for
+// statements relying on the session (temporary tables, LAST_QUERY_ID). Statements already on a
+// connection (e.g. routed with ModelRouting) or in a transaction keep it
+func withPinnedConnPool(db *gorm.DB, fc func() error) error {
+	pool := db.Statement.ConnPool
+	if pinnedConnPool(pool) {
+		return fc()
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(db.Statement.Context)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	defer func() { db.Statement.ConnPool = pool }()
+	db.Statement.ConnPool = pinnedPool(pool, conn)
+	return fc()
+}
+
+// pinnedPool returns conn in place of pool, keeping the named bind variables of pool. Prepared
+// statements are not kept, they belong to the connections of the pool
+func pinnedPool(pool gorm.ConnPool, conn *sql.Conn) gorm.ConnPool {
+	if _, ok := unpreparedConnPool(pool).(*namedArgsConnPool); ok {
+		return &namedArgsConnPool{ConnPool: conn}
+	}
+	return conn
+}
+
+// pinnedConnPool reports whether the statements run on pool share a connection, a transaction
+// or a single connection of the pool
+func pinnedConnPool(pool gorm.ConnPool) bool {
+	inner := unpreparedConnPool(pool)
+	if named, ok := inner.(*namedArgsConnPool); ok {
+		inner = named.ConnPool
+	}
+	if _, pinned := inner.(*sql.Conn); pinned {
+		return true
+	}
+	_, inTransaction := inner.(gorm.TxCommitter)
+	return inTransaction
+}
@@ -0,0 +1,85 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// unpooledFakeDB returns a fake DB whose connections are closed once released, so statements
+// that are not pinned run on a new connection each
+func unpooledFakeDB(t *testing.T, config Config, handler func(query string, args []interface{}) fakeResult) (*gorm.DB, *fakeDriver) {
+	db, fake := setupFakeDB(t, config, handler)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	sqlDB.SetMaxIdleConns(0)
+	return db, fake
+}
+
+func TestPinConnection(t *testing.T) {
+	t.Run("Statements share a connection", func(t *testing.T) {
+		db, fake := unpooledFakeDB(t, Config{}, nil)
+
+		tx, release, err := PinConnection(db)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		tx.Exec("ALTER SESSION SET TIMEZONE = 'UTC'")
+		if err := tx.Find(&[]TestModel{}).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := release(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := db.Find(&[]TestModel{}).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(fake.statements) != 3 || fake.statements[0].conn != fake.statements[1].conn || fake.statements[1].conn == fake.statements[2].conn {
+			t.Errorf("Expected the pinned statements only to share a connection, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Keeps transactions", func(t *testing.T) {
+		db, fake := unpooledFakeDB(t, Config{}, nil)
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			pinned, release, err := PinConnection(tx)
+			if err != nil {
+				return err
+			}
+			defer release()
+			return pinned.Exec("DELETE FROM test_models").Error
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(fake.statements) != 1 {
+			t.Errorf("Expected the statement in the transaction, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Creates read back default values on the connection of the insert", func(t *testing.T) {
+		db, fake := unpooledFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			if strings.Contains(query, "CHANGES(") {
+				return fakeResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}}}
+			}
+			return fakeResult{rowsAffected: 1}
+		})
+
+		model := TestModel{Name: "jinzhu"}
+		if err := db.Create(&model).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if model.ID != 1 {
+			t.Errorf("Expected the ID to be read back, got %d", model.ID)
+		}
+		if len(fake.statements) != 2 || fake.statements[0].conn != fake.statements[1].conn {
+			t.Errorf("Expected the INSERT and CHANGES on the same connection, got %v", fake.queries())
+		}
+	})
+}
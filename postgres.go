@@ -0,0 +1,81 @@
+package snowflake
+
+import (
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// postgresTypes maps the Postgres types of type tags to Snowflake types under Config.PostgresCompat
+var postgresTypes = map[string]string{
+	"serial":                   "INT IDENTITY(1,1)",
+	"serial4":                  "INT IDENTITY(1,1)",
+	"smallserial":              "SMALLINT IDENTITY(1,1)",
+	"serial2":                  "SMALLINT IDENTITY(1,1)",
+	"bigserial":                "BIGINT IDENTITY(1,1)",
+	"serial8":                  "BIGINT IDENTITY(1,1)",
+	"json":                     "VARIANT",
+	"jsonb":                    "VARIANT",
+	"hstore":                   "OBJECT",
+	"uuid":                     "VARCHAR(36)",
+	"bytea":                    "BINARY",
+	"citext":                   "VARCHAR",
+	"inet":                     "VARCHAR",
+	"cidr":                     "VARCHAR",
+	"macaddr":                  "VARCHAR",
+	"money":                    "NUMBER(19,4)",
+	"timestamptz":              "TIMESTAMP_TZ",
+	"timestamp with time zone": "TIMESTAMP_TZ",
+}
+
+// postgresDefaults maps the Postgres default expressions of default tags to Snowflake
+// expressions under Config.PostgresCompat
+var postgresDefaults = map[string]string{
+	"now()":              "CURRENT_TIMESTAMP()",
+	"gen_random_uuid()":  "UUID_STRING()",
+	"uuid_generate_v4()": "UUID_STRING()",
+}
+
+// postgresCompatType returns the Snowflake type of the Postgres type tag of field,
+// empty if field has no Postgres specific type
+func postgresCompatType(field *schema.Field) string {
+	typ, ok := field.TagSettings["TYPE"]
+	if !ok {
+		return ""
+	}
+
+	name := strings.ToLower(strings.TrimSpace(typ))
+	if strings.HasSuffix(name, "[]") {
+		return "ARRAY"
+	}
+	if dataType, ok := postgresTypes[name]; ok {
+		return dataType
+	}
+	if base, _, found := strings.Cut(name, "("); found {
+		return postgresTypes[strings.TrimSpace(base)]
+	}
+	return ""
+}
+
+// postgresCompatDefault returns the Snowflake expression of a Postgres default value
+func postgresCompatDefault(value string) string {
+	if expr, ok := postgresDefaults[strings.ToLower(strings.TrimSpace(value))]; ok {
+		return expr
+	}
+	return value
+}
+
+// semiStructuredType returns the semi-structured type of the column of field, Postgres
+// json/jsonb and array columns included under PostgresCompat
+func (config *Config) semiStructuredType(field *schema.Field) string {
+	if dataType := semiStructuredType(field); dataType != "" || field == nil {
+		return dataType
+	}
+	if config != nil && config.PostgresCompat {
+		switch dataType := postgresCompatType(field); dataType {
+		case "VARIANT", "OBJECT", "ARRAY":
+			return dataType
+		}
+	}
+	return ""
+}
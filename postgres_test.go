@@ -0,0 +1,51 @@
+package snowflake
+
+import "testing"
+
+type postgresModel struct {
+	ID       uint   `gorm:"type:bigserial;primaryKey"`
+	ExternID string `gorm:"type:uuid;default:gen_random_uuid()"`
+	Payload  string `gorm:"type:jsonb"`
+	Tags     string `gorm:"type:text[]"`
+	Seen     string `gorm:"type:timestamptz;default:now()"`
+}
+
+func TestPostgresCompat(t *testing.T) {
+	t.Run("Migrates Postgres types and defaults", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{PostgresCompat: true}, nil)
+
+		if err := db.Migrator().CreateTable(&postgresModel{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		for _, expected := range []string{
+			"id BIGINT IDENTITY(1,1)",
+			"extern_id VARCHAR(36) DEFAULT UUID_STRING()",
+			"payload VARIANT",
+			"tags ARRAY",
+			"seen TIMESTAMP_TZ DEFAULT CURRENT_TIMESTAMP()",
+		} {
+			if !hasQuery(fake.queries(), expected) {
+				t.Errorf("Expected %q, got %v", expected, fake.queries())
+			}
+		}
+	})
+
+	t.Run("Binds json and array values with PARSE_JSON", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{PostgresCompat: true}, nil)
+
+		db.Create(&postgresModel{ExternID: "x", Payload: `{"a":1}`, Tags: `["b"]`})
+		if !hasQuery(fake.queries(), "PARSE_JSON(?)") || !hasQuery(fake.queries(), "PARSE_JSON(?)::ARRAY") {
+			t.Errorf("Expected PARSE_JSON, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Leaves types unchanged when disabled", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		db.Migrator().CreateTable(&postgresModel{})
+		if !hasQuery(fake.queries(), "payload jsonb") {
+			t.Errorf("Expected jsonb to be kept, got %v", fake.queries())
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package snowflake
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrNamedBindVarsPrepareStmt is returned by Initialize when BindVarNamed is combined with
+// PrepareStmt, prepared statements receive the vars before they are converted to sql.NamedArg
+var ErrNamedBindVarsPrepareStmt = errors.New("BindVarNamed is not supported with PrepareStmt")
+
+// unpreparedConnPool returns the connection pool (or transaction) wrapped by the prepared
+// statement manager of a PrepareStmt session, pool itself otherwise.
+// Statements whose SQL depends on the number of rows (multi-row INSERT, MERGE) run on it,
+// one prepared statement per batch shape would only fill the cache
+func unpreparedConnPool(pool gorm.ConnPool) gorm.ConnPool {
+	switch prepared := pool.(type) {
+	case *gorm.PreparedStmtDB:
+		return prepared.ConnPool
+	case *gorm.PreparedStmtTX:
+		return prepared.Tx
+	}
+	return pool
+}
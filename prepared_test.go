@@ -0,0 +1,64 @@
+package snowflake
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestPrepareStmt(t *testing.T) {
+	t.Run("Bulk creates bypass the statement cache", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+		tx := db.Session(&gorm.Session{PrepareStmt: true})
+
+		for _, rows := range []int{3, 2, 1} {
+			models := make([]TestModel, rows)
+			for idx := range models {
+				models[idx].Name = "user"
+			}
+			if err := tx.Create(&models).Error; err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+
+		var models []TestModel
+		for i := 0; i < 2; i++ {
+			if err := tx.Where("age > ?", i).Find(&models).Error; err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+
+		if count := countQueries(fake.queries(), "INSERT INTO"); count != 3 {
+			t.Errorf("Expected 3 inserts, got %d: %v", count, fake.queries())
+		}
+		var selects int
+		for _, query := range fake.prepared {
+			if strings.HasPrefix(query, "INSERT") || strings.HasPrefix(query, "MERGE") {
+				t.Errorf("Expected inserts not to be prepared, got %s", query)
+			}
+			if strings.Contains(query, "WHERE age > ?") {
+				selects++
+			}
+		}
+		if selects != 1 {
+			t.Errorf("Expected the SELECT to be prepared once, got %v", fake.prepared)
+		}
+	})
+
+	t.Run("Named bind vars are rejected", func(t *testing.T) {
+		sqlDB := sql.OpenDB(&fakeDriver{})
+		defer sqlDB.Close()
+
+		_, err := gorm.Open(New(Config{Conn: sqlDB, BindVarStyle: BindVarNamed}), &gorm.Config{
+			PrepareStmt: true,
+			Logger:      logger.Default.LogMode(logger.Silent),
+		})
+		if !errors.Is(err, ErrNamedBindVarsPrepareStmt) {
+			t.Errorf("Expected ErrNamedBindVarsPrepareStmt, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package snowflake
+
+import "gorm.io/gorm/clause"
+
+// queryClauses are the clauses of SELECT statements, with QUALIFY between GROUP BY (and its
+// HAVING) and ORDER BY
+var queryClauses = []string{"SELECT", "FROM", "WHERE", "GROUP BY", "QUALIFY", "ORDER BY", "LIMIT", "FOR"}
+
+// qualify holds the conditions of the QUALIFY clause, which filters the rows of a query on the
+// result of window functions
+type qualify struct {
+	Exprs []clause.Expression
+}
+
+// Qualify filters the rows of a query on window functions with a QUALIFY clause, e.g. to keep the
+// latest row of each key:
+//
+//	db.Clauses(snowflake.Qualify("ROW_NUMBER() OVER (PARTITION BY customer_id ORDER BY updated_at DESC) = 1")).Find(&orders)
+//
+// Several Qualify clauses are joined with AND
+func Qualify(condition string, vars ...interface{}) clause.Interface {
+	return qualify{Exprs: []clause.Expression{clause.Expr{SQL: condition, Vars: vars}}}
+}
+
+func (qualify) Name() string {
+	return "QUALIFY"
+}
+
+func (q qualify) Build(builder clause.Builder) {
+	clause.Where{Exprs: q.Exprs}.Build(builder)
+}
+
+func (q qualify) MergeClause(c *clause.Clause) {
+	if existing, ok := c.Expression.(qualify); ok {
+		q.Exprs = append(append([]clause.Expression{}, existing.Exprs...), q.Exprs...)
+	}
+	c.Expression = q
+}
@@ -0,0 +1,38 @@
+package snowflake
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestQualify(t *testing.T) {
+	t.Run("Between GROUP BY and ORDER BY", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		stmt := db.Model(&TestModel{}).Select("name", "age").
+			Where("age > ?", 18).
+			Clauses(Qualify("ROW_NUMBER() OVER (PARTITION BY name ORDER BY age DESC) = ?", 1)).
+			Order("name").
+			Find(&[]TestModel{}).Statement
+
+		expected := `SELECT "name","age" FROM "test_models" WHERE age > ? QUALIFY ROW_NUMBER() OVER (PARTITION BY name ORDER BY age DESC) = ? ORDER BY name`
+		if sql := stmt.SQL.String(); sql != expected {
+			t.Errorf("Expected %s, got %s", expected, sql)
+		}
+		if len(stmt.Vars) != 2 || stmt.Vars[0] != 18 || stmt.Vars[1] != 1 {
+			t.Errorf("Expected the vars in order, got %v", stmt.Vars)
+		}
+	})
+
+	t.Run("Several conditions are joined with AND", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		stmt := db.Clauses(Qualify("COUNT(*) OVER (PARTITION BY name) > 1")).
+			Clauses(Qualify("RANK() OVER (ORDER BY age) <= 10")).
+			Find(&[]TestModel{}).Statement
+
+		expected := `SELECT * FROM "test_models" QUALIFY COUNT(*) OVER (PARTITION BY name) > 1 AND RANK() OVER (ORDER BY age) <= 10`
+		if sql := stmt.SQL.String(); sql != expected {
+			t.Errorf("Expected %s, got %s", expected, sql)
+		}
+	})
+}
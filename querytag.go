@@ -0,0 +1,54 @@
+package snowflake
+
+import (
+	"context"
+
+	"github.com/snowflakedb/gosnowflake"
+	"gorm.io/gorm"
+)
+
+// queryTagKey is the context key of the query tag set by WithQueryTag
+type queryTagKey struct{}
+
+// WithQueryTag returns a context whose statements run with tag as QUERY_TAG, so they can be found
+// in QUERY_HISTORY (e.g. for cost attribution), overriding Config.QueryTag:
+//
+//	db.WithContext(snowflake.WithQueryTag(ctx, "nightly-export")).Find(&orders)
+func WithQueryTag(ctx context.Context, tag string) context.Context {
+	return gosnowflake.WithQueryTag(context.WithValue(ctx, queryTagKey{}, tag), tag)
+}
+
+// queryTagFrom returns the query tag set on ctx by WithQueryTag
+func queryTagFrom(ctx context.Context) (string, bool) {
+	tag, ok := ctx.Value(queryTagKey{}).(string)
+	return tag, ok
+}
+
+// registerQueryTagCallbacks registers TagQuery before every statement, and before the
+// transaction of creates, updates and deletes
+func registerQueryTagCallbacks(db *gorm.DB) {
+	callback := db.Callback()
+	_ = callback.Create().Before("gorm:begin_transaction").Register("snowflake:query_tag", TagQuery)
+	_ = callback.Query().Before("gorm:query").Register("snowflake:query_tag", TagQuery)
+	_ = callback.Update().Before("gorm:begin_transaction").Register("snowflake:query_tag", TagQuery)
+	_ = callback.Delete().Before("gorm:begin_transaction").Register("snowflake:query_tag", TagQuery)
+	_ = callback.Row().Before("gorm:row").Register("snowflake:query_tag", TagQuery)
+	_ = callback.Raw().Before("gorm:raw").Register("snowflake:query_tag", TagQuery)
+}
+
+// TagQuery runs the statement with Config.QueryTag as QUERY_TAG, unless its context already has
+// a tag set by WithQueryTag
+func TagQuery(db *gorm.DB) {
+	config := dialectorConfig(db.Dialector)
+	if config == nil || config.QueryTag == "" {
+		return
+	}
+
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, tagged := queryTagFrom(ctx); !tagged {
+		db.Statement.Context = WithQueryTag(ctx, config.QueryTag)
+	}
+}
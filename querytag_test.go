@@ -0,0 +1,58 @@
+package snowflake
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestQueryTag(t *testing.T) {
+	capture := func() (*string, func(*gorm.DB)) {
+		tag := new(string)
+		return tag, func(tx *gorm.DB) { *tag, _ = queryTagFrom(tx.Statement.Context) }
+	}
+
+	t.Run("Config tag on every statement", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{QueryTag: "billing"}, nil)
+		tag, fc := capture()
+		_ = db.Callback().Query().After("snowflake:query_tag").Register("test:capture", fc)
+		_ = db.Callback().Raw().After("snowflake:query_tag").Register("test:capture", fc)
+
+		if err := db.Find(&[]TestModel{}).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if *tag != "billing" {
+			t.Errorf("Expected query tag billing, got %q", *tag)
+		}
+
+		*tag = ""
+		if err := db.Exec("DELETE FROM test_models WHERE age < 0").Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if *tag != "billing" {
+			t.Errorf("Expected raw statement tag billing, got %q", *tag)
+		}
+	})
+
+	t.Run("Context tag overrides config", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{QueryTag: "billing"}, nil)
+		tag, fc := capture()
+		_ = db.Callback().Query().After("snowflake:query_tag").Register("test:capture", fc)
+
+		ctx := WithQueryTag(context.Background(), "nightly-export")
+		if err := db.WithContext(ctx).Find(&[]TestModel{}).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if *tag != "nightly-export" {
+			t.Errorf("Expected query tag nightly-export, got %q", *tag)
+		}
+	})
+
+	t.Run("No callbacks without config tag", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, nil)
+		if db.Callback().Query().Get("snowflake:query_tag") != nil {
+			t.Error("Expected no query tag callback")
+		}
+	})
+}
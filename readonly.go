@@ -0,0 +1,74 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrReadOnly is returned for mutating statements when Config.ReadOnly is set
+var ErrReadOnly = errors.New("read-only mode")
+
+// readStatementPrefixes are the statements allowed by Config.ReadOnly
+var readStatementPrefixes = []string{"SELECT", "WITH", "SHOW", "DESCRIBE", "DESC", "EXPLAIN", "USE", "ALTER SESSION"}
+
+// registerReadOnlyCallbacks registers the callbacks enforcing Config.ReadOnly: Create, Update
+// and Delete are refused before any hook or transaction runs, Exec and Raw queries are refused
+// unless they only read data
+func registerReadOnlyCallbacks(db *gorm.DB) {
+	refuse := func(operation string) func(db *gorm.DB) {
+		return func(db *gorm.DB) {
+			if isReadOnly(db) {
+				db.AddError(fmt.Errorf("%w: %s refused", ErrReadOnly, operation))
+			}
+		}
+	}
+
+	callback := db.Callback()
+	_ = callback.Create().Before("gorm:begin_transaction").Register("snowflake:read_only", refuse("create"))
+	_ = callback.Update().Before("gorm:begin_transaction").Register("snowflake:read_only", refuse("update"))
+	_ = callback.Delete().Before("gorm:begin_transaction").Register("snowflake:read_only", refuse("delete"))
+	_ = callback.Raw().Before("gorm:raw").Register("snowflake:read_only", ReadOnlyGuard)
+	_ = callback.Query().Before("gorm:query").Register("snowflake:read_only", ReadOnlyGuard)
+	_ = callback.Row().Before("gorm:row").Register("snowflake:read_only", ReadOnlyGuard)
+}
+
+// isReadOnly reports whether the dialector of db is in read-only mode
+func isReadOnly(db *gorm.DB) bool {
+	config := dialectorConfig(db.Dialector)
+	return config != nil && config.ReadOnly
+}
+
+// ReadOnlyGuard refuses raw statements that are not reads when Config.ReadOnly is set,
+// statements built by Find/First... are always SELECTs and are not inspected
+func ReadOnlyGuard(db *gorm.DB) {
+	if db.Error != nil || !isReadOnly(db) || db.Statement.SQL.Len() == 0 {
+		return
+	}
+
+	if !isReadStatement(db.Statement.SQL.String()) {
+		db.AddError(fmt.Errorf("%w: statement refused: %s", ErrReadOnly, db.Statement.SQL.String()))
+	}
+}
+
+// isReadStatement reports whether query starts with one of readStatementPrefixes
+func isReadStatement(query string) bool {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	for _, prefix := range readStatementPrefixes {
+		if len(query) < len(prefix) || !strings.EqualFold(query[:len(prefix)], prefix) {
+			continue
+		}
+		// the prefix must be a whole word, e.g. DESC but not DESCEND
+		if len(query) == len(prefix) || !isIdentifierChar(query[len(prefix)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdentifierChar reports whether c can be part of an unquoted identifier
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,77 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReadOnly(t *testing.T) {
+	t.Run("Refuses mutations", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{ReadOnly: true}, nil)
+
+		model := TestModel{ID: 1, Name: "John"}
+		errs := map[string]error{
+			"create": db.Create(&TestModel{Name: "John"}).Error,
+			"update": db.Model(&model).Update("name", "Jane").Error,
+			"delete": db.Delete(&model).Error,
+			"exec":   db.Exec("TRUNCATE TABLE test_models").Error,
+			"raw":    db.Raw("DELETE FROM test_models").Scan(&[]TestModel{}).Error,
+		}
+		for operation, err := range errs {
+			if !errors.Is(err, ErrReadOnly) {
+				t.Errorf("Expected ErrReadOnly for %s, got %v", operation, err)
+			}
+		}
+
+		if len(fake.queries()) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Allows reads", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{ReadOnly: true}, nil)
+
+		var models []TestModel
+		if err := db.Where("age > ?", 18).Find(&models).Error; err != nil {
+			t.Errorf("Expected Find to succeed, got %v", err)
+		}
+		if err := db.Raw("SHOW TABLES").Scan(&models).Error; err != nil {
+			t.Errorf("Expected SHOW to succeed, got %v", err)
+		}
+		if err := db.Exec("ALTER SESSION SET TIMEZONE = 'UTC'").Error; err != nil {
+			t.Errorf("Expected ALTER SESSION to succeed, got %v", err)
+		}
+
+		if len(fake.queries()) != 3 {
+			t.Errorf("Expected 3 statements, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, nil)
+
+		if err := db.Exec("TRUNCATE TABLE test_models").Error; err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
+
+func TestIsReadStatement(t *testing.T) {
+	tests := map[string]bool{
+		"SELECT 1":                     true,
+		"  (select 1)":                 true,
+		"desc table users":             true,
+		"DESCRIBE TABLE users":         true,
+		"SHOW TABLES":                  true,
+		"alter session set x = 1":      true,
+		"ALTER TABLE users ADD c INT":  false,
+		"DESCEND":                      false,
+		"INSERT INTO users VALUES (1)": false,
+		"MERGE INTO users":             false,
+	}
+	for query, expected := range tests {
+		if got := isReadStatement(query); got != expected {
+			t.Errorf("isReadStatement(%q): expected %v, got %v", query, expected, got)
+		}
+	}
+}
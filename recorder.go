@@ -0,0 +1,130 @@
+package snowflake
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// statementRecorderKey is the context key of the StatementRecorder of a DB returned by RecordStatements
+type statementRecorderKey struct{}
+
+// RecordedStatement is a statement built by a DryRun session and its bind values
+type RecordedStatement struct {
+	SQL  string
+	Vars []interface{}
+}
+
+// StatementRecorder holds the statements built by the DryRun sessions of a DB returned by
+// RecordStatements, in the order they were built
+type StatementRecorder struct {
+	mu         sync.Mutex
+	statements []RecordedStatement
+}
+
+// RecordStatements returns a DryRun session of db recording the statements it builds, so tests
+// can assert the whole sequence of a call rather than the last SQL of its Statement. The SELECT
+// reading back the default values of created rows from CHANGES is recorded after its INSERT or
+// MERGE, as it would run:
+//
+//	tx, recorder := snowflake.RecordStatements(db)
+//	tx.Create(&user)
+//	recorder.SQL() // INSERT INTO "users" ..., SELECT "id" FROM "users" CHANGES(...) ...
+func RecordStatements(db *gorm.DB) (*gorm.DB, *StatementRecorder) {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	recorder := &StatementRecorder{}
+	return db.Session(&gorm.Session{
+		DryRun:  true,
+		Context: context.WithValue(ctx, statementRecorderKey{}, recorder),
+	}), recorder
+}
+
+// Statements returns the statements recorded so far
+func (recorder *StatementRecorder) Statements() []RecordedStatement {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return append([]RecordedStatement(nil), recorder.statements...)
+}
+
+// SQL returns the SQL of the statements recorded so far
+func (recorder *StatementRecorder) SQL() []string {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	sqls := make([]string, len(recorder.statements))
+	for idx, statement := range recorder.statements {
+		sqls[idx] = statement.SQL
+	}
+	return sqls
+}
+
+// Reset forgets the statements recorded so far
+func (recorder *StatementRecorder) Reset() {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.statements = nil
+}
+
+func (recorder *StatementRecorder) record(sql string, vars []interface{}) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.statements = append(recorder.statements, RecordedStatement{SQL: sql, Vars: append([]interface{}(nil), vars...)})
+}
+
+// statementRecorderFrom returns the recorder of a context of a DB returned by RecordStatements
+func statementRecorderFrom(ctx context.Context) (*StatementRecorder, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	recorder, ok := ctx.Value(statementRecorderKey{}).(*StatementRecorder)
+	return recorder, ok
+}
+
+// registerRecorderCallbacks registers RecordStatement after every statement
+func registerRecorderCallbacks(db *gorm.DB) {
+	callback := db.Callback()
+	_ = callback.Create().After("gorm:create").Register("snowflake:record_statement", RecordStatement)
+	_ = callback.Query().After("gorm:query").Register("snowflake:record_statement", RecordStatement)
+	_ = callback.Update().After("gorm:update").Register("snowflake:record_statement", RecordStatement)
+	_ = callback.Delete().After("gorm:delete").Register("snowflake:record_statement", RecordStatement)
+	_ = callback.Row().After("gorm:row").Register("snowflake:record_statement", RecordStatement)
+	_ = callback.Raw().After("gorm:raw").Register("snowflake:record_statement", RecordStatement)
+}
+
+// RecordStatement records the statement built by a DryRun session returned by RecordStatements,
+// followed by the CHANGES query reading back the default values of the rows it creates
+func RecordStatement(db *gorm.DB) {
+	recorder, ok := statementRecorderFrom(db.Statement.Context)
+	if !ok || !db.DryRun || db.Error != nil || db.Statement.SQL.Len() == 0 {
+		return
+	}
+	recorder.record(db.Statement.SQL.String(), db.Statement.Vars)
+
+	if !isCreateStatement(db.Statement) {
+		return
+	}
+	if _, changes := defaultValueFetcherOf(db); changes {
+		// the statement keeps its SQL, as any DryRun statement
+		sql := db.Statement.SQL.String()
+		db.Statement.SQL.Reset()
+		writeChangesQuery(db, db.Statement.Schema.FieldsWithDefaultDBValue)
+		recorder.record(db.Statement.SQL.String(), nil)
+		db.Statement.SQL.Reset()
+		db.Statement.SQL.WriteString(sql)
+	}
+}
+
+// isCreateStatement reports whether stmt builds an INSERT or a MERGE of Create
+func isCreateStatement(stmt *gorm.Statement) bool {
+	for _, name := range stmt.BuildClauses {
+		if name == "INSERT" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/gorm/clause"
+)
+
+func TestRecordStatements(t *testing.T) {
+	t.Run("Records the sequence of a create", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		tx, recorder := RecordStatements(db)
+		if err := tx.Create(&TestModel{Name: "jinzhu", Age: 18}).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := tx.Where("age > ?", 10).Find(&[]TestModel{}).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := []string{
+			`INSERT INTO "test_models" ("name","age") VALUES (?,?);`,
+			`SELECT "id" FROM "test_models" CHANGES(INFORMATION => APPEND_ONLY) BEFORE(statement=>LAST_QUERY_ID());`,
+			`SELECT * FROM "test_models" WHERE age > ?`,
+		}
+		if sqls := recorder.SQL(); strings.Join(sqls, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected %v, got %v", expected, sqls)
+		}
+
+		statements := recorder.Statements()
+		if len(statements[0].Vars) != 2 || statements[0].Vars[0] != "jinzhu" || len(statements[1].Vars) != 0 {
+			t.Errorf("Unexpected vars: %+v", statements)
+		}
+		if len(fake.statements) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Upserts and statements without default values", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		tx, recorder := RecordStatements(db)
+		tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&[]TestModel{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}})
+		tx.Model(&TestModel{}).Where("id = ?", 1).Update("age", 3)
+		tx.Exec("DELETE FROM test_models WHERE age < ?", 0)
+
+		sqls := recorder.SQL()
+		if len(sqls) != 4 || !strings.HasPrefix(sqls[0], "MERGE INTO") || !strings.Contains(sqls[1], "CHANGES(") ||
+			!strings.HasPrefix(sqls[2], "UPDATE") || sqls[3] != "DELETE FROM test_models WHERE age < ?" {
+			t.Errorf("Unexpected statements: %v", sqls)
+		}
+
+		recorder.Reset()
+		if statements := recorder.Statements(); len(statements) != 0 {
+			t.Errorf("Expected no statement after Reset, got %v", statements)
+		}
+	})
+
+	t.Run("Keeps the SQL of the statement", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		tx, recorder := RecordStatements(db)
+		result := tx.Create(&TestModel{Name: "jinzhu"})
+		if sql := result.Statement.SQL.String(); !strings.HasPrefix(sql, "INSERT INTO") {
+			t.Errorf("Expected the INSERT in the statement, got %s", sql)
+		}
+		if len(recorder.SQL()) != 2 {
+			t.Errorf("Expected the INSERT and CHANGES queries, got %v", recorder.SQL())
+		}
+	})
+
+	t.Run("Other sessions are not recorded", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		_, recorder := RecordStatements(db)
+		db.Exec("DELETE FROM test_models")
+		if len(recorder.SQL()) != 0 || len(fake.statements) != 1 {
+			t.Errorf("Expected only the statement to run, got %v and %v", recorder.SQL(), fake.queries())
+		}
+	})
+}
@@ -0,0 +1,293 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+)
+
+const (
+	resultCacheTTLKey = "snowflake:result_cache_ttl"
+
+	defaultResultCacheMaxEntries = 1000
+)
+
+// errReplayOnly is returned by the connection pool replaying a cached result for anything but the query
+var errReplayOnly = errors.New("cached results can only be queried")
+
+// Cached opts the queries of db into the ResultCache plugin, their results are reused for ttl:
+//
+//	db.Scopes(snowflake.Cached(time.Minute)).Find(&totals)
+func Cached(ttl time.Duration) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Set(resultCacheTTLKey, ttl)
+	}
+}
+
+// ResultCache is a plugin caching in process the results of the SELECTs run with Cached, keyed
+// on their SQL and bind values, e.g. for dashboards repeating the same queries: every Snowflake
+// round trip has a high latency and keeps a warehouse running.
+// The key does not include the session, DBs whose role or database see different rows for the
+// same SQL need their own ResultCache. Queries run in a transaction are not cached.
+//
+//	db.Use(&snowflake.ResultCache{})
+type ResultCache struct {
+	// MaxEntries bounds the number of cached results, the closest to expire are evicted first
+	// (default 1000)
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*cachedResult
+	query   func(*gorm.DB)
+}
+
+// cachedResult is the result of a query read in memory
+type cachedResult struct {
+	columns []string
+	types   []string
+	rows    [][]interface{}
+	expires time.Time
+}
+
+func (cache *ResultCache) Name() string {
+	return "snowflake:result_cache"
+}
+
+func (cache *ResultCache) Initialize(db *gorm.DB) error {
+	cache.entries = map[string]*cachedResult{}
+
+	// cached results are scanned by the query callback of the dialector
+	cache.query = callbacks.Query
+	if config := dialectorConfig(db.Dialector); config != nil && config.ScanTimesIn != nil {
+		cache.query = Query
+	}
+	return db.Callback().Query().Replace("gorm:query", cache.cachedQuery)
+}
+
+// Purge removes every cached result
+func (cache *ResultCache) Purge() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.entries = map[string]*cachedResult{}
+}
+
+// cachedQuery runs the query of db, replaying its cached result if the statement was run with
+// Cached and has one that has not expired
+func (cache *ResultCache) cachedQuery(db *gorm.DB) {
+	value, _ := db.Get(resultCacheTTLKey)
+	if ttl, ok := value.(time.Duration); !ok || ttl <= 0 || db.Error != nil || db.DryRun {
+		cache.query(db)
+		return
+	}
+
+	callbacks.BuildQuerySQL(db)
+	_, inTransaction := db.Statement.ConnPool.(gorm.TxCommitter)
+	if db.Error != nil || inTransaction || !isSelectStatement(db.Statement.SQL.String()) {
+		cache.query(db)
+		return
+	}
+
+	key := resultCacheKey(db.Statement.SQL.String(), db.Statement.Vars)
+	result := cache.get(key)
+	if result == nil {
+		var err error
+		if result, err = fetchResult(db); err != nil {
+			db.AddError(err)
+			return
+		}
+		result.expires = time.Now().Add(value.(time.Duration))
+		cache.put(key, result)
+	}
+
+	// the result is replayed through database/sql so it is scanned like the rows of the driver
+	pool := db.Statement.ConnPool
+	db.Statement.ConnPool = replayConnPool{result}
+	cache.query(db)
+	db.Statement.ConnPool = pool
+}
+
+// get returns the cached result of key, nil if there is none or it expired
+func (cache *ResultCache) get(key string) *cachedResult {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	result, ok := cache.entries[key]
+	if !ok {
+		return nil
+	}
+	if !time.Now().Before(result.expires) {
+		delete(cache.entries, key)
+		return nil
+	}
+	return result
+}
+
+// put caches result under key, evicting expired results then the closest to expire when full
+func (cache *ResultCache) put(key string, result *cachedResult) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	maxEntries := cache.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultResultCacheMaxEntries
+	}
+
+	if _, ok := cache.entries[key]; !ok && len(cache.entries) >= maxEntries {
+		now := time.Now()
+		for k, entry := range cache.entries {
+			if !now.Before(entry.expires) {
+				delete(cache.entries, k)
+			}
+		}
+		for len(cache.entries) >= maxEntries {
+			var evicted string
+			for k, entry := range cache.entries {
+				if evicted == "" || entry.expires.Before(cache.entries[evicted].expires) {
+					evicted = k
+				}
+			}
+			delete(cache.entries, evicted)
+		}
+	}
+	cache.entries[key] = result
+}
+
+// resultCacheKey returns the cache key of a query, its SQL followed by the type and value of
+// its bind variables
+func resultCacheKey(sql string, vars []interface{}) string {
+	var key strings.Builder
+	key.WriteString(sql)
+	for _, v := range vars {
+		if valuer, ok := v.(driver.Valuer); ok {
+			if value, err := valuer.Value(); err == nil {
+				v = value
+			}
+		}
+		if reflectValue := reflect.ValueOf(v); reflectValue.Kind() == reflect.Ptr && !reflectValue.IsNil() {
+			v = reflectValue.Elem().Interface()
+		}
+		if t, ok := v.(time.Time); ok {
+			// without the monotonic clock reading printed by %v
+			v = t.Format(time.RFC3339Nano)
+		}
+		fmt.Fprintf(&key, "\x00%T:%v", v, v)
+	}
+	return key.String()
+}
+
+// fetchResult runs the query of db and reads its result in memory
+func fetchResult(db *gorm.DB) (*cachedResult, error) {
+	rows, err := db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := &cachedResult{}
+	if result.columns, err = rows.Columns(); err != nil {
+		return nil, err
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	result.types = make([]string, len(columnTypes))
+	for idx, columnType := range columnTypes {
+		result.types[idx] = columnType.DatabaseTypeName()
+	}
+
+	values := make([]interface{}, len(result.columns))
+	for rows.Next() {
+		row := make([]interface{}, len(result.columns))
+		for idx := range row {
+			values[idx] = &row[idx]
+		}
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		result.rows = append(result.rows, row)
+	}
+	return result, rows.Err()
+}
+
+// replayConnPool is the connection pool of a statement whose cached result is replayed
+type replayConnPool struct {
+	result *cachedResult
+}
+
+func (pool replayConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, errReplayOnly
+}
+
+func (pool replayConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, errReplayOnly
+}
+
+func (pool replayConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return replayDB.QueryContext(ctx, query, pool.result)
+}
+
+func (pool replayConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return replayDB.QueryRowContext(ctx, query, pool.result)
+}
+
+// replayDB returns the cachedResult given as its only argument to every query
+var replayDB = sql.OpenDB(replayConnector{})
+
+type replayConnector struct{}
+
+func (replayConnector) Connect(context.Context) (driver.Conn, error) { return replayConn{}, nil }
+func (replayConnector) Driver() driver.Driver                        { return replayConnector{} }
+func (replayConnector) Open(string) (driver.Conn, error)             { return replayConn{}, nil }
+
+type replayConn struct{}
+
+func (replayConn) Prepare(string) (driver.Stmt, error)      { return nil, errReplayOnly }
+func (replayConn) Close() error                             { return nil }
+func (replayConn) Begin() (driver.Tx, error)                { return nil, errReplayOnly }
+func (replayConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (replayConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if len(args) != 1 {
+		return nil, errReplayOnly
+	}
+	result, ok := args[0].Value.(*cachedResult)
+	if !ok {
+		return nil, errReplayOnly
+	}
+	return &replayRows{result: result}, nil
+}
+
+// replayRows iterates over the rows of a cachedResult
+type replayRows struct {
+	result *cachedResult
+	pos    int
+}
+
+func (r *replayRows) Columns() []string { return r.result.columns }
+func (r *replayRows) Close() error      { return nil }
+
+func (r *replayRows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.result.types[index]
+}
+
+func (r *replayRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.result.rows) {
+		return io.EOF
+	}
+	for idx, value := range r.result.rows[r.pos] {
+		dest[idx] = value
+	}
+	r.pos++
+	return nil
+}
@@ -0,0 +1,95 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestResultCache(t *testing.T) {
+	t.Run("Reuses the result of cached queries", func(t *testing.T) {
+		cache := &ResultCache{}
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			return fakeResult{
+				columns: []string{"id", "name", "age"},
+				rows:    [][]driver.Value{{int64(1), "a", int64(30)}, {int64(2), "b", int64(40)}},
+			}
+		})
+		if err := db.Use(cache); err != nil {
+			t.Fatalf("Failed to use the result cache: %v", err)
+		}
+
+		for i := 0; i < 3; i++ {
+			var models []TestModel
+			if err := db.Scopes(Cached(time.Minute)).Where("age > ?", 20).Find(&models).Error; err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(models) != 2 || models[0].Name != "a" || models[1].Age != 40 {
+				t.Fatalf("Unexpected models: %+v", models)
+			}
+		}
+		if len(fake.queries()) != 1 {
+			t.Errorf("Expected a single query, got %v", fake.queries())
+		}
+
+		// other bind values are another entry
+		var models []TestModel
+		db.Scopes(Cached(time.Minute)).Where("age > ?", 35).Find(&models)
+		if len(fake.queries()) != 2 {
+			t.Errorf("Expected a query for other bind values, got %v", fake.queries())
+		}
+
+		// queries without Cached always run
+		db.Where("age > ?", 20).Find(&models)
+		if len(fake.queries()) != 3 {
+			t.Errorf("Expected uncached query to run, got %v", fake.queries())
+		}
+
+		cache.Purge()
+		db.Scopes(Cached(time.Minute)).Where("age > ?", 20).Find(&models)
+		if len(fake.queries()) != 4 {
+			t.Errorf("Expected query to run after Purge, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Expires and evicts results", func(t *testing.T) {
+		cache := &ResultCache{MaxEntries: 1}
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			return fakeResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}}}
+		})
+		if err := db.Use(cache); err != nil {
+			t.Fatalf("Failed to use the result cache: %v", err)
+		}
+
+		var models []TestModel
+		db.Scopes(Cached(time.Nanosecond)).Find(&models)
+		time.Sleep(time.Millisecond)
+		db.Scopes(Cached(time.Nanosecond)).Find(&models)
+		if len(fake.queries()) != 2 {
+			t.Errorf("Expected expired result to be queried again, got %v", fake.queries())
+		}
+
+		db.Scopes(Cached(time.Minute)).Where("id = ?", 1).Find(&models)
+		db.Scopes(Cached(time.Minute)).Where("id = ?", 2).Find(&models)
+		db.Scopes(Cached(time.Minute)).Where("id = ?", 1).Find(&models)
+		if len(fake.queries()) != 5 {
+			t.Errorf("Expected evicted result to be queried again, got %v", fake.queries())
+		}
+		if len(cache.entries) != 1 {
+			t.Errorf("Expected 1 entry, got %d", len(cache.entries))
+		}
+	})
+}
+
+func TestResultCacheKey(t *testing.T) {
+	one, two := 1, 1
+	if resultCacheKey("SELECT ?", []interface{}{&one}) != resultCacheKey("SELECT ?", []interface{}{&two}) {
+		t.Error("Expected pointers to be keyed on their value")
+	}
+	if resultCacheKey("SELECT ?", []interface{}{1}) == resultCacheKey("SELECT ?", []interface{}{"1"}) {
+		t.Error("Expected values of different types to have different keys")
+	}
+	if resultCacheKey("SELECT ?", []interface{}{time.Now()}) == resultCacheKey("SELECT ?", []interface{}{time.Now().Add(time.Second)}) {
+		t.Error("Expected different times to have different keys")
+	}
+}
@@ -0,0 +1,135 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const defaultRetentionSchedule = "60 MINUTE"
+
+// ErrInvalidRetention is returned when a retention task cannot be built from the given options
+var ErrInvalidRetention = errors.New("invalid retention")
+
+// RetentionOptions configures Retention and DropRetention
+type RetentionOptions struct {
+	// TaskName is the name of the task (default: <table>_retention)
+	TaskName string
+	// Warehouse runs the task on a user-managed warehouse, empty creates a serverless task
+	Warehouse string
+	// Schedule is the SCHEDULE of the task, "<n> MINUTE" or "USING CRON <expr> <tz>" (default: 60 MINUTE)
+	Schedule string
+	// DataRetentionDays, if set, also sets DATA_RETENTION_TIME_IN_DAYS on the table so deleted
+	// rows do not linger in Time Travel longer than required
+	DataRetentionDays *int
+	// DryRun returns the statements without running them
+	DryRun bool
+}
+
+// Retention creates (or replaces) and resumes a task periodically deleting the rows of model
+// whose olderThanColumn is more than d in the past, the row-level TTL Snowflake lacks.
+// It returns the statements it ran, or would run with opts.DryRun.
+// The task keeps running until DropRetention is called.
+func Retention(db *gorm.DB, model interface{}, olderThanColumn string, d time.Duration, opts ...RetentionOptions) ([]string, error) {
+	var options RetentionOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	if d < time.Second {
+		return nil, fmt.Errorf("%w: retention must be at least 1s, got %s", ErrInvalidRetention, d)
+	}
+
+	schedule := options.Schedule
+	if schedule == "" {
+		schedule = defaultRetentionSchedule
+	}
+	if !taskScheduleRegex.MatchString(schedule) {
+		return nil, fmt.Errorf("%w: unsupported schedule %q", ErrInvalidRetention, schedule)
+	}
+
+	stmt, taskName, err := retentionStatement(db, model, options)
+	if err != nil {
+		return nil, err
+	}
+
+	field := stmt.Schema.LookUpField(olderThanColumn)
+	if field == nil {
+		return nil, fmt.Errorf("%w: unknown column %s", ErrInvalidRetention, olderThanColumn)
+	}
+
+	// the task body is stored as text, so every value is rendered inline instead of bound
+	createTaskSQL := "CREATE OR REPLACE TASK " + stmt.Quote(taskName)
+	if options.Warehouse != "" {
+		if err := validateIdentifier(options.Warehouse); err != nil {
+			return nil, err
+		}
+		createTaskSQL += " WAREHOUSE = " + stmt.Quote(clause.Table{Name: options.Warehouse})
+	}
+	createTaskSQL += " SCHEDULE = " + quoteLiteral(schedule) +
+		" AS DELETE FROM " + stmt.Quote(stmt.Table) +
+		" WHERE " + stmt.Quote(field.DBName) + " < DATEADD(SECOND, -" + strconv.FormatInt(int64(d/time.Second), 10) + ", CURRENT_TIMESTAMP())"
+
+	statements := []string{createTaskSQL, "ALTER TASK " + stmt.Quote(taskName) + " RESUME"}
+
+	if options.DataRetentionDays != nil {
+		if *options.DataRetentionDays < 0 {
+			return nil, fmt.Errorf("%w: negative data retention %d", ErrInvalidRetention, *options.DataRetentionDays)
+		}
+		statements = append(statements, "ALTER TABLE "+stmt.Quote(stmt.Table)+" SET DATA_RETENTION_TIME_IN_DAYS = "+strconv.Itoa(*options.DataRetentionDays))
+	}
+
+	return statements, runRetentionStatements(db, statements, options.DryRun)
+}
+
+// DropRetention drops the task created by Retention for model, opts.TaskName must match
+// the one given to Retention. It returns the statement it ran, or would run with opts.DryRun
+func DropRetention(db *gorm.DB, model interface{}, opts ...RetentionOptions) ([]string, error) {
+	var options RetentionOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	stmt, taskName, err := retentionStatement(db, model, options)
+	if err != nil {
+		return nil, err
+	}
+
+	statements := []string{"DROP TASK IF EXISTS " + stmt.Quote(taskName)}
+	return statements, runRetentionStatements(db, statements, options.DryRun)
+}
+
+// retentionStatement parses model and returns the name of its retention task
+func retentionStatement(db *gorm.DB, model interface{}, options RetentionOptions) (*gorm.Statement, clause.Table, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, clause.Table{}, err
+	}
+
+	taskName := options.TaskName
+	if taskName == "" {
+		taskName = stmt.Table + "_retention"
+	}
+	if err := validateIdentifier(taskName); err != nil {
+		return nil, clause.Table{}, err
+	}
+	return stmt, clause.Table{Name: taskName}, nil
+}
+
+// runRetentionStatements executes statements in order unless dryRun is set
+func runRetentionStatements(db *gorm.DB, statements []string, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+
+	for _, sql := range statements {
+		if err := db.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetention(t *testing.T) {
+	t.Run("Creates and resumes task", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		days := 1
+		statements, err := Retention(db, &MigratorTestModel{}, "CreatedAt", 30*24*time.Hour, RetentionOptions{
+			Warehouse:         "maintenance_wh",
+			DataRetentionDays: &days,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := []string{
+			`CREATE OR REPLACE TASK "migrator_test_models_retention" WAREHOUSE = "maintenance_wh" SCHEDULE = '60 MINUTE' ` +
+				`AS DELETE FROM "migrator_test_models" WHERE "created_at" < DATEADD(SECOND, -2592000, CURRENT_TIMESTAMP())`,
+			`ALTER TASK "migrator_test_models_retention" RESUME`,
+			`ALTER TABLE "migrator_test_models" SET DATA_RETENTION_TIME_IN_DAYS = 1`,
+		}
+
+		queries := fake.queries()
+		if len(statements) != len(expected) || len(queries) != len(expected) {
+			t.Fatalf("Expected %d statements, got %v (ran %v)", len(expected), statements, queries)
+		}
+		for idx := range expected {
+			if statements[idx] != expected[idx] || queries[idx] != expected[idx] {
+				t.Errorf("Statement %d: expected %s, got %s (ran %s)", idx, expected[idx], statements[idx], queries[idx])
+			}
+		}
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		statements, err := Retention(db, &MigratorTestModel{}, "created_at", time.Hour, RetentionOptions{
+			TaskName: "purge_models",
+			Schedule: "USING CRON 0 3 * * * UTC",
+			DryRun:   true,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := `CREATE OR REPLACE TASK "purge_models" SCHEDULE = 'USING CRON 0 3 * * * UTC' ` +
+			`AS DELETE FROM "migrator_test_models" WHERE "created_at" < DATEADD(SECOND, -3600, CURRENT_TIMESTAMP())`
+		if len(statements) != 2 || statements[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, statements)
+		}
+		if len(fake.queries()) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Teardown", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		if _, err := DropRetention(db, &MigratorTestModel{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := `DROP TASK IF EXISTS "migrator_test_models_retention"`
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("Invalid options", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+		negative := -1
+
+		cases := []struct {
+			column string
+			d      time.Duration
+			opts   RetentionOptions
+		}{
+			{"created_at", time.Millisecond, RetentionOptions{}},
+			{"unknown", time.Hour, RetentionOptions{}},
+			{"created_at", time.Hour, RetentionOptions{Schedule: "1 HOUR'; DROP TABLE x; --"}},
+			{"created_at", time.Hour, RetentionOptions{DataRetentionDays: &negative}},
+		}
+		for _, c := range cases {
+			if _, err := Retention(db, &MigratorTestModel{}, c.column, c.d, c.opts); !errors.Is(err, ErrInvalidRetention) {
+				t.Errorf("Expected ErrInvalidRetention for %+v, got %v", c, err)
+			}
+		}
+
+		if len(fake.queries()) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// routeInstanceKey holds the routedConn of a statement between RouteModel and releaseRoute
+const routeInstanceKey = "snowflake:route"
+
+// SessionTarget is the warehouse and role statements on a table run with, see Config.ModelRouting
+type SessionTarget struct {
+	Warehouse string
+	Role      string
+}
+
+// routedConn is the connection a statement is pinned to, with the session to restore
+type routedConn struct {
+	conn              *sql.Conn
+	pool              gorm.ConnPool
+	previousWarehouse sql.NullString
+	previousRole      sql.NullString
+}
+
+// registerRoutingCallbacks pins the queries, creates, updates and deletes on routed tables to a
+// connection using their SessionTarget. Row and Rows are not routed, as their rows are read after
+// the callbacks ran
+func registerRoutingCallbacks(db *gorm.DB) {
+	callback := db.Callback()
+	_ = callback.Query().Before("gorm:query").Register("snowflake:route", RouteModel)
+	_ = callback.Query().After("gorm:after_query").Register("snowflake:route_release", releaseRoute)
+	_ = callback.Create().Before("gorm:begin_transaction").Register("snowflake:route", RouteModel)
+	_ = callback.Create().After("gorm:commit_or_rollback_transaction").Register("snowflake:route_release", releaseRoute)
+	_ = callback.Update().Before("gorm:begin_transaction").Register("snowflake:route", RouteModel)
+	_ = callback.Update().After("gorm:commit_or_rollback_transaction").Register("snowflake:route_release", releaseRoute)
+	_ = callback.Delete().Before("gorm:begin_transaction").Register("snowflake:route", RouteModel)
+	_ = callback.Delete().After("gorm:commit_or_rollback_transaction").Register("snowflake:route_release", releaseRoute)
+}
+
+// RouteModel runs the statement on a dedicated connection switched to the SessionTarget of its
+// table in Config.ModelRouting, the connection is restored and released once the statement is done.
+// Statements within a transaction or a Connection keep their session
+func RouteModel(db *gorm.DB) {
+	config := dialectorConfig(db.Dialector)
+	if db.Error != nil || db.DryRun || config == nil || db.Statement.Table == "" {
+		return
+	}
+	target, ok := config.ModelRouting[db.Statement.Table]
+	if !ok || (target.Warehouse == "" && target.Role == "") {
+		return
+	}
+
+	var sqlDB *sql.DB
+	switch pool := db.Statement.ConnPool.(type) {
+	case *sql.DB:
+		sqlDB = pool
+	case *namedArgsConnPool, *gorm.PreparedStmtDB:
+		var err error
+		if sqlDB, err = pool.(gorm.GetDBConnector).GetDBConn(); err != nil {
+			db.AddError(err)
+			return
+		}
+	default:
+		// transactions and pinned connections
+		return
+	}
+
+	ctx := db.Statement.Context
+	routed, err := pinRoute(ctx, db, sqlDB, target)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	routed.pool = db.Statement.ConnPool
+	if _, ok := routed.pool.(*namedArgsConnPool); ok {
+		db.Statement.ConnPool = &namedArgsConnPool{routed.conn}
+	} else {
+		db.Statement.ConnPool = routed.conn
+	}
+	db.InstanceSet(routeInstanceKey, routed)
+}
+
+// pinRoute takes a connection of sqlDB and switches it to target
+func pinRoute(ctx context.Context, db *gorm.DB, sqlDB *sql.DB, target SessionTarget) (*routedConn, error) {
+	for _, name := range []string{target.Warehouse, target.Role} {
+		if name != "" {
+			if err := validateIdentifier(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	routed := &routedConn{conn: conn}
+
+	err = conn.QueryRowContext(ctx, "SELECT CURRENT_WAREHOUSE(), CURRENT_ROLE()").Scan(&routed.previousWarehouse, &routed.previousRole)
+	if err == nil && target.Role != "" {
+		_, err = conn.ExecContext(ctx, "USE ROLE "+db.Statement.Quote(clause.Table{Name: target.Role}))
+	}
+	if err == nil && target.Warehouse != "" {
+		_, err = conn.ExecContext(ctx, "USE WAREHOUSE "+db.Statement.Quote(clause.Table{Name: target.Warehouse}))
+	}
+	if err != nil {
+		routed.release(ctx)
+		return nil, err
+	}
+	return routed, nil
+}
+
+// releaseRoute restores the connection pool of a statement routed by RouteModel and releases its connection
+func releaseRoute(db *gorm.DB) {
+	value, ok := db.InstanceGet(routeInstanceKey)
+	if !ok {
+		return
+	}
+	routed, ok := value.(*routedConn)
+	if !ok || routed == nil {
+		return
+	}
+
+	db.Statement.ConnPool = routed.pool
+	db.InstanceSet(routeInstanceKey, (*routedConn)(nil))
+	routed.release(db.Statement.Context)
+}
+
+// release restores the previous warehouse and role of the connection and returns it to the pool,
+// a connection that cannot be restored is discarded instead
+func (routed *routedConn) release(ctx context.Context) {
+	var err error
+	if routed.previousRole.Valid && routed.previousRole.String != "" {
+		_, err = routed.conn.ExecContext(ctx, "USE ROLE "+quoteIdentifier(routed.previousRole.String))
+	}
+	if err == nil && routed.previousWarehouse.Valid && routed.previousWarehouse.String != "" {
+		_, err = routed.conn.ExecContext(ctx, "USE WAREHOUSE "+quoteIdentifier(routed.previousWarehouse.String))
+	}
+
+	if err != nil {
+		_ = routed.conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+	}
+	_ = routed.conn.Close()
+}
@@ -0,0 +1,81 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func routingHandler(query string, args []interface{}) fakeResult {
+	if strings.HasPrefix(query, "SELECT CURRENT_WAREHOUSE(), CURRENT_ROLE()") {
+		return fakeResult{columns: []string{"warehouse", "role"}, rows: [][]driver.Value{{"APP_WH", "APP_ROLE"}}}
+	}
+	return fakeResult{rowsAffected: 1}
+}
+
+// indexOfQuery returns the index of the first query containing substr, -1 if none
+func indexOfQuery(queries []string, substr string) int {
+	for idx, query := range queries {
+		if strings.Contains(query, substr) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func TestModelRouting(t *testing.T) {
+	config := Config{ModelRouting: map[string]SessionTarget{
+		"test_models": {Warehouse: "BIG_WH", Role: "ANALYST"},
+	}}
+
+	t.Run("Routes queries on listed tables", func(t *testing.T) {
+		db, fake := setupFakeDB(t, config, routingHandler)
+
+		var models []TestModel
+		if err := db.Find(&models).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		queries := fake.queries()
+		order := []string{"CURRENT_WAREHOUSE()", "USE ROLE analyst", "USE WAREHOUSE big_wh", "FROM test_models", `USE ROLE "APP_ROLE"`, `USE WAREHOUSE "APP_WH"`}
+		previous := -1
+		for _, substr := range order {
+			idx := indexOfQuery(queries, substr)
+			if idx <= previous {
+				t.Fatalf("Expected %q after the previous statements, got %v", substr, queries)
+			}
+			previous = idx
+		}
+	})
+
+	t.Run("Routes writes", func(t *testing.T) {
+		db, fake := setupFakeDB(t, config, routingHandler)
+
+		if err := db.Model(&TestModel{ID: 1}).Update("name", "Jane").Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		queries := fake.queries()
+		if update := indexOfQuery(queries, "UPDATE"); update < indexOfQuery(queries, "USE WAREHOUSE big_wh") || update > indexOfQuery(queries, `USE WAREHOUSE "APP_WH"`) {
+			t.Errorf("Expected the update between the USE statements, got %v", queries)
+		}
+	})
+
+	t.Run("Skips other tables and transactions", func(t *testing.T) {
+		db, fake := setupFakeDB(t, config, routingHandler)
+
+		if err := db.Find(&[]VariantModel{}).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			return tx.Find(&[]TestModel{}).Error
+		}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if hasQuery(fake.queries(), "USE ") {
+			t.Errorf("Expected no routing, got %v", fake.queries())
+		}
+	})
+}
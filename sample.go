@@ -0,0 +1,111 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tableSampleClauseName is the statement clause holding the TableSample of a query
+const tableSampleClauseName = "SNOWFLAKE_TABLE_SAMPLE"
+
+// maxSampleRows is the largest number of rows of a fixed-size sample
+const maxSampleRows = 1000000
+
+// ErrInvalidSample is added to the statement when a TableSample cannot be rendered
+var ErrInvalidSample = errors.New("invalid table sample")
+
+// SampleMethod is the sampling method of a TableSample
+type SampleMethod string
+
+const (
+	// SampleBernoulli includes each row with the given probability (default)
+	SampleBernoulli SampleMethod = "BERNOULLI"
+	// SampleSystem includes each block of rows with the given probability, faster on large
+	// tables but less random. It does not support fixed-size samples
+	SampleSystem SampleMethod = "SYSTEM"
+)
+
+// TableSample samples the table of a SELECT statement, rendered as SAMPLE after the table of the
+// FROM clause. Sample and SampleRows cover the common cases:
+//
+//	db.Clauses(snowflake.Sample(10)).Where("amount < 0").Count(&negatives)
+//	db.Clauses(snowflake.TableSample{Method: snowflake.SampleSystem, Percent: 1, Seed: &seed}).Find(&events)
+//
+// Joined tables are read in full
+type TableSample struct {
+	// Method is the sampling method
+	// Default: SampleBernoulli
+	Method SampleMethod
+	// Percent is the probability (0 to 100) of each row or block to be included
+	Percent float64
+	// Rows is the number of rows of a fixed-size sample, instead of Percent
+	Rows int
+	// Seed makes the sample deterministic, for percentage samples
+	Seed *int
+}
+
+// Sample reads percent percent of the rows of the table of a query: SAMPLE (<percent>)
+func Sample(percent float64) TableSample {
+	return TableSample{Percent: percent}
+}
+
+// SampleRows reads n rows of the table of a query (all of them if it has fewer): SAMPLE (<n> ROWS)
+func SampleRows(n int) TableSample {
+	return TableSample{Rows: n}
+}
+
+func (TableSample) Name() string {
+	return tableSampleClauseName
+}
+
+func (TableSample) Build(clause.Builder) {}
+
+func (sample TableSample) MergeClause(c *clause.Clause) {
+	c.Expression = sample
+}
+
+// validate reports why sample cannot be rendered
+func (sample TableSample) validate() error {
+	switch {
+	case sample.Method != "" && sample.Method != SampleBernoulli && sample.Method != SampleSystem:
+		return fmt.Errorf("%w: unknown method %q", ErrInvalidSample, sample.Method)
+	case sample.Rows < 0 || sample.Rows > maxSampleRows:
+		return fmt.Errorf("%w: %d rows is not between 0 and %d", ErrInvalidSample, sample.Rows, maxSampleRows)
+	case sample.Rows > 0 && (sample.Method == SampleSystem || sample.Seed != nil):
+		return fmt.Errorf("%w: fixed-size samples only support the BERNOULLI method without seed", ErrInvalidSample)
+	case sample.Rows == 0 && (sample.Percent < 0 || sample.Percent > 100):
+		return fmt.Errorf("%w: %g percent is not between 0 and 100", ErrInvalidSample, sample.Percent)
+	}
+	return nil
+}
+
+// build writes the SAMPLE clause of sample, its values are validated numbers written as literals
+func (sample TableSample) build(stmt *gorm.Statement) {
+	if err := sample.validate(); err != nil {
+		stmt.AddError(err)
+		return
+	}
+
+	stmt.WriteString("SAMPLE ")
+	if sample.Method != "" {
+		stmt.WriteString(string(sample.Method))
+		stmt.WriteByte(' ')
+	}
+	stmt.WriteByte('(')
+	if sample.Rows > 0 {
+		stmt.WriteString(strconv.Itoa(sample.Rows))
+		stmt.WriteString(" ROWS")
+	} else {
+		stmt.WriteString(strconv.FormatFloat(sample.Percent, 'f', -1, 64))
+	}
+	stmt.WriteByte(')')
+	if sample.Seed != nil {
+		stmt.WriteString(" SEED (")
+		stmt.WriteString(strconv.Itoa(*sample.Seed))
+		stmt.WriteByte(')')
+	}
+}
@@ -0,0 +1,69 @@
+package snowflake
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestTableSample(t *testing.T) {
+	seed := 42
+	tests := []struct {
+		name     string
+		sample   TableSample
+		expected string
+	}{
+		{"Percent", Sample(10), `SELECT * FROM "test_models" SAMPLE (10) WHERE age > ?`},
+		{"Fractional percent", Sample(0.5), `SELECT * FROM "test_models" SAMPLE (0.5) WHERE age > ?`},
+		{"Rows", SampleRows(1000), `SELECT * FROM "test_models" SAMPLE (1000 ROWS) WHERE age > ?`},
+		{"Method and seed", TableSample{Method: SampleSystem, Percent: 1, Seed: &seed}, `SELECT * FROM "test_models" SAMPLE SYSTEM (1) SEED (42) WHERE age > ?`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+			stmt := db.Clauses(tt.sample).Where("age > ?", 18).Find(&[]TestModel{}).Statement
+			if stmt.Error != nil {
+				t.Fatalf("Expected no error, got %v", stmt.Error)
+			}
+			if sql := stmt.SQL.String(); sql != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, sql)
+			}
+		})
+	}
+
+	t.Run("With time travel and an alias", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		stmt := db.Model(&TestModel{}).Clauses(
+			clause.From{Tables: []clause.Table{{Name: "test_models", Alias: "t"}}},
+			Sample(10),
+			AsOf(time.Now()),
+		).Find(&[]TestModel{}).Statement
+
+		expected := `FROM "test_models" AT(TIMESTAMP => TO_TIMESTAMP_TZ(?)) AS "t" SAMPLE (10)`
+		if sql := stmt.SQL.String(); !strings.Contains(sql, expected) {
+			t.Errorf("Expected %s, got %s", expected, sql)
+		}
+	})
+
+	t.Run("Invalid samples", func(t *testing.T) {
+		for _, sample := range []TableSample{Sample(150), SampleRows(-1), {Method: SampleSystem, Rows: 10}, {Method: "RANDOM", Percent: 5}} {
+			db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+			if err := db.Clauses(sample).Find(&[]TestModel{}).Error; !errors.Is(err, ErrInvalidSample) {
+				t.Errorf("Expected ErrInvalidSample for %+v, got %v", sample, err)
+			}
+		}
+	})
+
+	t.Run("Only SELECT statements", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		stmt := db.Clauses(Sample(10)).Where("id = ?", 1).Delete(&TestModel{}).Statement
+		if sql := stmt.SQL.String(); strings.Contains(sql, "SAMPLE") {
+			t.Errorf("Expected no sample in DELETE, got %s", sql)
+		}
+	})
+}
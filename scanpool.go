@@ -0,0 +1,110 @@
+package snowflake
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// scanBuffer holds the destinations passed to rows.Scan, values[i] is scanned through ptrs[i]
+type scanBuffer struct {
+	values []interface{}
+	ptrs   []interface{}
+}
+
+var scanBufferPool = sync.Pool{New: func() interface{} { return &scanBuffer{} }}
+
+// getScanBuffer returns a pooled buffer for columns destinations, released with putScanBuffer
+func getScanBuffer(columns int) *scanBuffer {
+	buffer := scanBufferPool.Get().(*scanBuffer)
+	if cap(buffer.values) < columns {
+		buffer.values = make([]interface{}, columns)
+		buffer.ptrs = make([]interface{}, columns)
+	}
+	buffer.values = buffer.values[:columns]
+	buffer.ptrs = buffer.ptrs[:columns]
+	for idx := range buffer.values {
+		buffer.ptrs[idx] = &buffer.values[idx]
+	}
+	return buffer
+}
+
+// putScanBuffer clears buffer, so the pool does not retain scanned values, and returns it to the pool
+func putScanBuffer(buffer *scanBuffer) {
+	for idx := range buffer.values {
+		buffer.values[idx] = nil
+	}
+	scanBufferPool.Put(buffer)
+}
+
+// FindPooled runs the query of db for T, like db.Find(dest), and stores the result in dest.
+// Scan destinations are taken from a pool and the capacity of dest is reused, which cuts
+// allocations of small lookup queries run at a high rate:
+//
+//	var users []User
+//	err := snowflake.FindPooled(db.Where("team_id = ?", teamID), &users)
+//
+// Hooks (AfterFind) and Preload are not applied, use Find for them
+func FindPooled[T any](db *gorm.DB, dest *[]T) error {
+	rows, err := db.Model(new(T)).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	*dest = (*dest)[:0]
+	return ScanRowsPooled(db, rows, dest)
+}
+
+// ScanRowsPooled appends the remaining rows of rows to dest using pooled scan destinations,
+// columns are matched to the fields of T by column name
+func ScanRowsPooled[T any](db *gorm.DB, rows *sql.Rows, dest *[]T) error {
+	if elemType := reflect.TypeOf(dest).Elem().Elem(); elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("scan: destination must be a slice of structs, got %T", dest)
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return err
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	fields := make([]*schema.Field, len(columns))
+	for idx, column := range columns {
+		if fields[idx] = stmt.Schema.LookUpField(column); fields[idx] == nil {
+			fields[idx] = stmt.Schema.LookUpField(strings.ToLower(column))
+		}
+	}
+
+	buffer := getScanBuffer(len(columns))
+	defer putScanBuffer(buffer)
+
+	ctx := db.Statement.Context
+	for rows.Next() {
+		if err := rows.Scan(buffer.ptrs...); err != nil {
+			return err
+		}
+
+		var elem T
+		elemValue := reflect.ValueOf(&elem).Elem()
+		for idx, field := range fields {
+			if field == nil {
+				continue
+			}
+			if err := field.Set(ctx, elemValue, buffer.values[idx]); err != nil {
+				return fmt.Errorf("scan: column %s: %w", columns[idx], err)
+			}
+		}
+		*dest = append(*dest, elem)
+	}
+	return rows.Err()
+}
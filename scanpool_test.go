@@ -0,0 +1,69 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+func testModelsHandler(query string, args []interface{}) fakeResult {
+	if !strings.HasPrefix(query, "SELECT") {
+		return fakeResult{}
+	}
+	return fakeResult{
+		columns: []string{"ID", "NAME", "AGE", "EXTRA"},
+		rows: [][]driver.Value{
+			{int64(1), "John", int64(30), "x"},
+			{int64(2), "Jane", nil, "y"},
+		},
+	}
+}
+
+func TestFindPooled(t *testing.T) {
+	db, fake := setupFakeDB(t, Config{}, testModelsHandler)
+
+	models := make([]TestModel, 1, 8)
+	models[0] = TestModel{ID: 99}
+	if err := FindPooled(db.Where("age > ?", 18), &models); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(models) != 2 || cap(models) != 8 {
+		t.Fatalf("Expected 2 models reusing the capacity, got %d (cap %d)", len(models), cap(models))
+	}
+	if models[0] != (TestModel{ID: 1, Name: "John", Age: 30}) || models[1] != (TestModel{ID: 2, Name: "Jane"}) {
+		t.Errorf("Unexpected models: %+v", models)
+	}
+	if !hasQuery(fake.queries(), "test_models") || !hasQuery(fake.queries(), "WHERE age > ?") {
+		t.Errorf("Expected SELECT on test_models, got %v", fake.queries())
+	}
+}
+
+func TestFindPooledRejectsNonStruct(t *testing.T) {
+	db, _ := setupFakeDB(t, Config{}, testModelsHandler)
+
+	var models []*TestModel
+	if err := FindPooled(db, &models); err == nil {
+		t.Error("Expected an error for a slice of pointers")
+	}
+}
+
+func TestScanBufferPool(t *testing.T) {
+	buffer := getScanBuffer(3)
+	buffer.values[0] = "value"
+	putScanBuffer(buffer)
+
+	buffer = getScanBuffer(2)
+	defer putScanBuffer(buffer)
+	if len(buffer.values) != 2 || len(buffer.ptrs) != 2 {
+		t.Fatalf("Expected 2 destinations, got %d/%d", len(buffer.values), len(buffer.ptrs))
+	}
+	for idx, value := range buffer.values {
+		if value != nil {
+			t.Errorf("Expected cleared value %d, got %v", idx, value)
+		}
+		if buffer.ptrs[idx] != &buffer.values[idx] {
+			t.Errorf("Expected ptrs[%d] to point to values[%d]", idx, idx)
+		}
+	}
+}
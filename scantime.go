@@ -0,0 +1,172 @@
+package snowflake
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/schema"
+)
+
+// Query replaces gorm:query when Config.ScanTimesIn is set, it runs the query like gorm
+// then converts the scanned timestamps according to the type of their column
+func Query(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+
+	callbacks.BuildQuerySQL(db)
+	if db.DryRun || db.Error != nil {
+		return
+	}
+
+	rows, err := db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	defer func() {
+		db.AddError(rows.Close())
+	}()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	gorm.Scan(rows, db, 0)
+
+	if db.Statement.Result != nil {
+		db.Statement.Result.RowsAffected = db.RowsAffected
+	}
+
+	if config := dialectorConfig(db.Dialector); config != nil && config.ScanTimesIn != nil && db.Error == nil {
+		convertScannedTimes(db, columnTypes, config.ScanTimesIn)
+	}
+}
+
+// convertTime converts t, scanned from a column of type dbType, to loc:
+// TIMESTAMP_NTZ values have no time zone, the driver returns their wall clock in UTC,
+// they keep that wall clock in loc. TIMESTAMP_LTZ and TIMESTAMP_TZ values are instants,
+// returned in the session time zone or with their offset, they are converted to loc.
+// Other types (DATE, TIME) are returned unchanged, nanoseconds are always preserved
+func convertTime(t time.Time, dbType string, loc *time.Location) time.Time {
+	if t.IsZero() {
+		return t
+	}
+
+	switch strings.ToUpper(dbType) {
+	case "TIMESTAMP_NTZ", "TIMESTAMP", "DATETIME":
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	case "TIMESTAMP_LTZ", "TIMESTAMP_TZ":
+		return t.In(loc)
+	}
+	return t
+}
+
+// convertScannedTimes applies convertTime to the time fields of db.Statement.ReflectValue,
+// and to the time values of map destinations, scanned from columnTypes
+func convertScannedTimes(db *gorm.DB, columnTypes []*sql.ColumnType, loc *time.Location) {
+	switch dest := db.Statement.Dest.(type) {
+	case map[string]interface{}:
+		convertMapTimes(dest, columnTypes, loc)
+		return
+	case *map[string]interface{}:
+		convertMapTimes(*dest, columnTypes, loc)
+		return
+	case *[]map[string]interface{}:
+		for _, row := range *dest {
+			convertMapTimes(row, columnTypes, loc)
+		}
+		return
+	case *time.Time:
+		if len(columnTypes) == 1 {
+			*dest = convertTime(*dest, columnTypes[0].DatabaseTypeName(), loc)
+		}
+		return
+	}
+
+	sch := db.Statement.Schema
+	if sch == nil {
+		return
+	}
+
+	var (
+		fields  []*schema.Field
+		dbTypes []string
+	)
+	for _, columnType := range columnTypes {
+		field := sch.LookUpField(columnType.Name())
+		if field == nil {
+			field = sch.LookUpField(strings.ToLower(columnType.Name()))
+		}
+		if field != nil && field.DataType == schema.Time {
+			fields = append(fields, field)
+			dbTypes = append(dbTypes, columnType.DatabaseTypeName())
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	ctx := db.Statement.Context
+	convert := func(value reflect.Value) {
+		if reflect.Indirect(value).Kind() != reflect.Struct {
+			return
+		}
+		for idx, field := range fields {
+			convertFieldTime(fieldAccessorOf(field)(ctx, value), dbTypes[idx], loc)
+		}
+	}
+
+	switch reflectValue := db.Statement.ReflectValue; reflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for idx := 0; idx < reflectValue.Len(); idx++ {
+			if elem := reflectValue.Index(idx); elem.Kind() != reflect.Ptr || !elem.IsNil() {
+				convert(elem)
+			}
+		}
+	case reflect.Struct:
+		convert(reflectValue)
+	}
+}
+
+// convertFieldTime converts the time.Time, *time.Time, sql.NullTime or gorm.DeletedAt held by value in place
+func convertFieldTime(value reflect.Value, dbType string, loc *time.Location) {
+	if !value.CanSet() {
+		return
+	}
+
+	switch v := value.Interface().(type) {
+	case time.Time:
+		value.Set(reflect.ValueOf(convertTime(v, dbType, loc)))
+	case *time.Time:
+		if v != nil {
+			converted := convertTime(*v, dbType, loc)
+			value.Set(reflect.ValueOf(&converted))
+		}
+	case sql.NullTime:
+		if v.Valid {
+			v.Time = convertTime(v.Time, dbType, loc)
+			value.Set(reflect.ValueOf(v))
+		}
+	case gorm.DeletedAt:
+		if v.Valid {
+			v.Time = convertTime(v.Time, dbType, loc)
+			value.Set(reflect.ValueOf(v))
+		}
+	}
+}
+
+// convertMapTimes converts the time values of row scanned from columnTypes
+func convertMapTimes(row map[string]interface{}, columnTypes []*sql.ColumnType, loc *time.Location) {
+	for _, columnType := range columnTypes {
+		if t, ok := row[columnType.Name()].(time.Time); ok {
+			row[columnType.Name()] = convertTime(t, columnType.DatabaseTypeName(), loc)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package snowflake
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TimestampModel struct {
+	ID  uint
+	Ntz time.Time
+	Ltz *time.Time
+	Tz  sql.NullTime
+}
+
+var (
+	scanTimesIn = time.FixedZone("UTC+1", 3600)
+	ntzValue    = time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	ltzValue    = time.Date(2024, 1, 1, 19, 4, 5, 123456789, time.FixedZone("UTC-8", -8*3600))
+	tzValue     = time.Date(2024, 1, 2, 12, 4, 5, 123456789, time.FixedZone("UTC+9", 9*3600))
+)
+
+func timestampsHandler(query string, args []interface{}) fakeResult {
+	return fakeResult{
+		columns: []string{"id", "ntz", "ltz", "tz"},
+		types:   []string{"FIXED", "TIMESTAMP_NTZ", "TIMESTAMP_LTZ", "TIMESTAMP_TZ"},
+		rows:    [][]driver.Value{{int64(1), ntzValue, ltzValue, tzValue}},
+	}
+}
+
+func TestScanTimesIn(t *testing.T) {
+	t.Run("Converts by column type", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{ScanTimesIn: scanTimesIn}, timestampsHandler)
+
+		var models []TimestampModel
+		if err := db.Find(&models).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(models) != 1 || models[0].Ltz == nil || !models[0].Tz.Valid {
+			t.Fatalf("Unexpected models: %+v", models)
+		}
+		model := models[0]
+
+		// NTZ keeps its wall clock, with nanoseconds
+		if expected := time.Date(2024, 1, 2, 3, 4, 5, 123456789, scanTimesIn); !model.Ntz.Equal(expected) || model.Ntz.Location() != scanTimesIn {
+			t.Errorf("Expected NTZ %v, got %v", expected, model.Ntz)
+		}
+		// LTZ and TZ keep their instant
+		if !model.Ltz.Equal(ltzValue) || model.Ltz.Location() != scanTimesIn || model.Ltz.Hour() != 4 {
+			t.Errorf("Expected LTZ %v in %v, got %v", ltzValue, scanTimesIn, model.Ltz)
+		}
+		if !model.Tz.Time.Equal(tzValue) || model.Tz.Time.Location() != scanTimesIn || model.Tz.Time.Hour() != 4 {
+			t.Errorf("Expected TZ %v in %v, got %v", tzValue, scanTimesIn, model.Tz.Time)
+		}
+	})
+
+	t.Run("Converts map destinations", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{ScanTimesIn: scanTimesIn}, timestampsHandler)
+
+		var rows []map[string]interface{}
+		if err := db.Model(&TimestampModel{}).Find(&rows).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if ntz, ok := rows[0]["ntz"].(time.Time); !ok || ntz.Hour() != 3 || ntz.Location() != scanTimesIn {
+			t.Errorf("Expected NTZ wall clock in %v, got %v", scanTimesIn, rows[0]["ntz"])
+		}
+	})
+
+	t.Run("Converts raw queries read with Find, not Scan", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{ScanTimesIn: scanTimesIn}, timestampsHandler)
+
+		var found, scanned []TimestampModel
+		if err := db.Raw("SELECT * FROM timestamp_models").Find(&found).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := db.Raw("SELECT * FROM timestamp_models").Scan(&scanned).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(found) != 1 || found[0].Ntz.Location() != scanTimesIn {
+			t.Errorf("Expected Find to convert NTZ to %v, got %+v", scanTimesIn, found)
+		}
+		if len(scanned) != 1 || scanned[0].Ntz.Location() != time.UTC {
+			t.Errorf("Expected Scan to keep NTZ as scanned, got %+v", scanned)
+		}
+	})
+
+	t.Run("Reports rows affected in the result", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{ScanTimesIn: scanTimesIn}, timestampsHandler)
+
+		result := gorm.WithResult()
+		var models []TimestampModel
+		if err := db.Clauses(result).Find(&models).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.RowsAffected != 1 {
+			t.Errorf("Expected 1 row affected in the result, got %d", result.RowsAffected)
+		}
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, timestampsHandler)
+
+		var model TimestampModel
+		if err := db.First(&model).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if model.Ntz.Location() != time.UTC || model.Ltz.Location() == scanTimesIn {
+			t.Errorf("Expected times as scanned, got %v and %v", model.Ntz, model.Ltz)
+		}
+	})
+}
+
+func TestConvertTime(t *testing.T) {
+	date := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	if converted := convertTime(date, "DATE", scanTimesIn); converted != date {
+		t.Errorf("Expected DATE to be unchanged, got %v", converted)
+	}
+	if converted := convertTime(time.Time{}, "TIMESTAMP_NTZ", scanTimesIn); !converted.IsZero() {
+		t.Errorf("Expected zero time to be unchanged, got %v", converted)
+	}
+}
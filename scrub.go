@@ -0,0 +1,137 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// scrubOptionsKey holds the ScrubOptions set by WithScrubOptions
+	scrubOptionsKey = "snowflake:scrub_options"
+
+	defaultScrubChunkSize = 10000
+)
+
+// ErrScrubColumn is returned by ScrubColumns for a column it cannot scrub
+var ErrScrubColumn = errors.New("cannot scrub column")
+
+// ScrubOptions configures ScrubColumns, see WithScrubOptions
+type ScrubOptions struct {
+	// ChunkSize is the number of rows scrubbed by each UPDATE
+	// Default: 10000
+	ChunkSize int
+	// Hash replaces the values with their SHA-256 hex digest (64 characters) instead of NULL, so
+	// scrubbed values still join and group. Only for string columns
+	Hash bool
+	// Retries is the number of times the UPDATE of a failed chunk is run again, after RetryDelay
+	// Default: 0
+	Retries int
+	// RetryDelay is the time waited before retrying a chunk
+	// Default: 1s
+	RetryDelay time.Duration
+	// OnProgress, if set, is called after every chunk with the number of rows scrubbed so far
+	OnProgress func(scrubbed int64)
+}
+
+// WithScrubOptions makes ScrubColumns of db scrub with options
+func WithScrubOptions(db *gorm.DB, options ScrubOptions) *gorm.DB {
+	return db.Set(scrubOptionsKey, options)
+}
+
+// ScrubColumns erases the personal data of the rows of model matching where (a condition without
+// args of db.Where: a map, a struct, a clause.Expression or gorm.Expr) by setting columns to NULL,
+// or to their hash with ScrubOptions.Hash, and returns the number of rows scrubbed:
+//
+//	scrubbed, err := snowflake.ScrubColumns(db, &Customer{}, gorm.Expr("customer_id = ?", id), "email", "phone")
+//
+// Rows are updated in chunks of ScrubOptions.ChunkSize rows in primary key order, so a large
+// erasure does not run as one long UPDATE and the chunks scrubbed before a failure stay scrubbed.
+// Setting NULL can be run again, hashing cannot: values already hashed are hashed again. Soft
+// deleted rows are scrubbed too, hooks are not run and the auto update time of the model is not
+// touched. The model must have a single primary key
+func ScrubColumns(db *gorm.DB, model interface{}, where interface{}, columns ...string) (int64, error) {
+	if where == nil {
+		return 0, gorm.ErrMissingWhereClause
+	}
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("%w: no column", ErrScrubColumn)
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return 0, err
+	}
+	if len(stmt.Schema.PrimaryFields) != 1 {
+		return 0, fmt.Errorf("%w: %s must have a single primary key", ErrScrubColumn, stmt.Schema.Name)
+	}
+	primaryKey := clause.Column{Name: stmt.Schema.PrimaryFields[0].DBName}
+
+	options, _ := db.Get(scrubOptionsKey)
+	scrubOptions, _ := options.(ScrubOptions)
+	if scrubOptions.ChunkSize <= 0 {
+		scrubOptions.ChunkSize = defaultScrubChunkSize
+	}
+	if scrubOptions.RetryDelay <= 0 {
+		scrubOptions.RetryDelay = time.Second
+	}
+
+	updates := make(map[string]interface{}, len(columns))
+	for _, column := range columns {
+		field := stmt.Schema.LookUpField(column)
+		if field == nil || field.DBName == "" {
+			return 0, fmt.Errorf("%w: %s has no column %s", ErrScrubColumn, stmt.Schema.Name, column)
+		}
+		if field.PrimaryKey {
+			return 0, fmt.Errorf("%w: %s is the primary key", ErrScrubColumn, field.DBName)
+		}
+		updates[field.DBName] = nil
+		if scrubOptions.Hash {
+			updates[field.DBName] = gorm.Expr("SHA2(?, 256)", clause.Column{Name: field.DBName})
+		}
+	}
+
+	var (
+		tx       = db.Session(&gorm.Session{NewDB: true, SkipDefaultTransaction: true})
+		scrubbed int64
+		last     interface{}
+	)
+	for {
+		chunk := tx.Unscoped().Model(model).Select(primaryKey.Name).Where(where).Order(clause.OrderByColumn{Column: primaryKey}).Limit(scrubOptions.ChunkSize)
+		if last != nil {
+			chunk = chunk.Where("? > ?", primaryKey, last)
+		}
+
+		var boundary interface{}
+		if err := tx.Table("(?) AS scrub_chunk", chunk).Select("MAX(?)", primaryKey).Row().Scan(&boundary); err != nil {
+			return scrubbed, err
+		} else if boundary == nil {
+			return scrubbed, nil
+		}
+
+		update := func() *gorm.DB {
+			chunk := tx.Unscoped().Model(model).Where(where).Where("? <= ?", primaryKey, boundary)
+			if last != nil {
+				chunk = chunk.Where("? > ?", primaryKey, last)
+			}
+			return chunk.UpdateColumns(updates)
+		}
+		result := update()
+		for retry := 0; result.Error != nil && retry < scrubOptions.Retries; retry++ {
+			time.Sleep(scrubOptions.RetryDelay)
+			result = update()
+		}
+		if result.Error != nil {
+			return scrubbed, fmt.Errorf("failed to scrub %s after %d rows: %w", stmt.Schema.Table, scrubbed, result.Error)
+		}
+
+		scrubbed += result.RowsAffected
+		last = boundary
+		if scrubOptions.OnProgress != nil {
+			scrubOptions.OnProgress(scrubbed)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestScrubColumns(t *testing.T) {
+	boundaries := func(values ...interface{}) func(query string, args []interface{}) fakeResult {
+		chunk := 0
+		return func(query string, args []interface{}) fakeResult {
+			switch {
+			case strings.HasPrefix(query, "SELECT MAX("):
+				var value driver.Value
+				if chunk < len(values) {
+					value = values[chunk]
+				}
+				chunk++
+				return fakeResult{columns: []string{"MAX"}, rows: [][]driver.Value{{value}}}
+			case strings.HasPrefix(query, "UPDATE"):
+				return fakeResult{rowsAffected: 2}
+			}
+			return fakeResult{}
+		}
+	}
+
+	t.Run("Scrubs in chunks", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, boundaries(int64(2), int64(4)))
+
+		var progress []int64
+		scrubbed, err := ScrubColumns(
+			WithScrubOptions(db, ScrubOptions{ChunkSize: 2, OnProgress: func(scrubbed int64) { progress = append(progress, scrubbed) }}),
+			&TestModel{}, gorm.Expr("age < ?", 18), "Name",
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if scrubbed != 4 || len(progress) != 2 || progress[1] != 4 {
+			t.Errorf("Expected 4 rows scrubbed in 2 chunks, got %d and %v", scrubbed, progress)
+		}
+
+		expected := []string{
+			"SELECT MAX(id) FROM (SELECT id FROM test_models WHERE age < ? ORDER BY id OFFSET 0 ROW FETCH NEXT 2 ROWS ONLY) AS scrub_chunk",
+			"UPDATE test_models SET name=? WHERE age < ? AND id <= ?",
+			"SELECT MAX(id) FROM (SELECT id FROM test_models WHERE age < ? AND id > ? ORDER BY id OFFSET 0 ROW FETCH NEXT 2 ROWS ONLY) AS scrub_chunk",
+			"UPDATE test_models SET name=? WHERE age < ? AND id <= ? AND id > ?",
+			"SELECT MAX(id) FROM (SELECT id FROM test_models WHERE age < ? AND id > ? ORDER BY id OFFSET 0 ROW FETCH NEXT 2 ROWS ONLY) AS scrub_chunk",
+		}
+		if queries := fake.queries(); strings.Join(queries, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected %v, got %v", expected, queries)
+		}
+		if args := fake.statements[3].args; len(args) != 4 || args[0] != nil || args[2] != int64(4) || args[3] != int64(2) {
+			t.Errorf("Unexpected chunk bounds: %v", args)
+		}
+	})
+
+	t.Run("Hash", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, boundaries(int64(7)))
+
+		if _, err := ScrubColumns(WithScrubOptions(db, ScrubOptions{Hash: true}), &TestModel{}, map[string]interface{}{"id": 7}, "name"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if queries := fake.queries(); !hasQuery(queries, `UPDATE "test_models" SET "name"=SHA2("name", 256) WHERE "test_models"."id" = ? AND "id" <= ?`) {
+			t.Errorf("Expected the values to be hashed, got %v", queries)
+		}
+	})
+
+	t.Run("Retries failed chunks", func(t *testing.T) {
+		failures := 1
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			switch {
+			case strings.HasPrefix(query, "SELECT MAX(") && !strings.Contains(query, "id >"):
+				return fakeResult{columns: []string{"MAX"}, rows: [][]driver.Value{{int64(1)}}}
+			case strings.HasPrefix(query, "SELECT MAX("):
+				return fakeResult{columns: []string{"MAX"}, rows: [][]driver.Value{{nil}}}
+			case strings.HasPrefix(query, "UPDATE") && failures > 0:
+				failures--
+				return fakeResult{err: errors.New("statement timed out")}
+			}
+			return fakeResult{rowsAffected: 1}
+		})
+
+		scrubbed, err := ScrubColumns(WithScrubOptions(db, ScrubOptions{Retries: 1, RetryDelay: 1}), &TestModel{}, gorm.Expr("id = 1"), "name")
+		if err != nil || scrubbed != 1 {
+			t.Fatalf("Expected 1 row scrubbed, got %d and %v", scrubbed, err)
+		}
+		if updates := strings.Count(strings.Join(fake.queries(), "\n"), "UPDATE"); updates != 2 {
+			t.Errorf("Expected the UPDATE to be retried once, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		if _, err := ScrubColumns(db, &TestModel{}, nil, "name"); !errors.Is(err, gorm.ErrMissingWhereClause) {
+			t.Errorf("Expected ErrMissingWhereClause, got %v", err)
+		}
+		if _, err := ScrubColumns(db, &TestModel{}, gorm.Expr("id = 1"), "email"); !errors.Is(err, ErrScrubColumn) {
+			t.Errorf("Expected ErrScrubColumn, got %v", err)
+		}
+		if _, err := ScrubColumns(db, &TestModel{}, gorm.Expr("id = 1"), "id"); !errors.Is(err, ErrScrubColumn) {
+			t.Errorf("Expected ErrScrubColumn, got %v", err)
+		}
+		if len(fake.statements) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+}
@@ -0,0 +1,238 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// SemiStructuredSerializerName is the name SemiStructured is registered with
+const SemiStructuredSerializerName = "semistructured"
+
+var jsonRawMessageType = reflect.TypeOf(json.RawMessage{})
+
+func init() {
+	schema.RegisterSerializer(SemiStructuredSerializerName, SemiStructured{})
+}
+
+// SemiStructured is a GORM serializer storing Go maps, structs and slices as JSON in
+// semi-structured columns, maps and structs in OBJECT columns, slices and arrays in ARRAY
+// columns unless the type tag says otherwise:
+//
+//	Tags       []string          `gorm:"serializer:semistructured"`
+//	Attributes map[string]string `gorm:"serializer:semistructured"`
+//	Document   Document          `gorm:"type:VARIANT;serializer:semistructured"`
+//
+// Unlike the json serializer, nil maps and slices are stored as NULL
+type SemiStructured struct{}
+
+// Scan decodes the JSON text returned by Snowflake into the field
+func (SemiStructured) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+
+	if dbValue != nil {
+		var data []byte
+		switch v := dbValue.(type) {
+		case []byte:
+			data = v
+		case string:
+			data = []byte(v)
+		default:
+			return fmt.Errorf("failed to scan %T into semi-structured field %s", dbValue, field.Name)
+		}
+
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, fieldValue.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// Value returns the JSON text of the field, nil for nil maps, slices and pointers
+func (SemiStructured) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	return semiStructuredJSON(fieldValue)
+}
+
+// semiStructuredType returns the semi-structured type (VARIANT, OBJECT or ARRAY) of the column
+// of field, empty if it is not semi-structured
+func semiStructuredType(field *schema.Field) string {
+	if field == nil {
+		return ""
+	}
+
+	switch dataType := strings.ToUpper(string(field.DataType)); dataType {
+	case "VARIANT", "OBJECT", "ARRAY":
+		return dataType
+	}
+
+	if field.IndirectFieldType == jsonRawMessageType {
+		return "VARIANT"
+	}
+
+	if _, ok := field.Serializer.(SemiStructured); ok {
+		switch field.IndirectFieldType.Kind() {
+		case reflect.Map, reflect.Struct:
+			return "OBJECT"
+		case reflect.Slice, reflect.Array:
+			return "ARRAY"
+		}
+		return "VARIANT"
+	}
+	return ""
+}
+
+// semiStructuredColumns returns the semi-structured type of each of columns in the statement
+// schema, nil if none is semi-structured
+func semiStructuredColumns(db *gorm.DB, columns []clause.Column) []string {
+	if db.Statement.Schema == nil {
+		return nil
+	}
+
+	var (
+		config = dialectorConfig(db.Dialector)
+		types  []string
+	)
+	for idx, column := range columns {
+		if dataType := config.semiStructuredType(db.Statement.Schema.LookUpField(column.Name)); dataType != "" {
+			if types == nil {
+				types = make([]string, len(columns))
+			}
+			types[idx] = dataType
+		}
+	}
+	return types
+}
+
+// hasSemiStructuredColumns reports whether one of columns is a semi-structured field of the statement schema
+func hasSemiStructuredColumns(db *gorm.DB, columns []clause.Column) bool {
+	return semiStructuredColumns(db, columns) != nil
+}
+
+// semiStructuredJSON returns the JSON text of a value written to a semi-structured column,
+// nil for NULL. Strings and byte slices are taken as JSON text, other values are encoded
+// with encoding/json
+func semiStructuredJSON(value interface{}) (interface{}, error) {
+	if valuer, ok := value.(driver.Valuer); ok {
+		var err error
+		if value, err = valuer.Value(); err != nil {
+			return nil, err
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return v, nil
+	case []byte:
+		if len(v) == 0 {
+			return nil, nil
+		}
+		return string(v), nil
+	case json.RawMessage:
+		if len(v) == 0 {
+			return nil, nil
+		}
+		return string(v), nil
+	}
+
+	switch reflectValue := reflect.ValueOf(value); reflectValue.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if reflectValue.IsNil() {
+			return nil, nil
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode semi-structured value: %w", err)
+	}
+	return string(data), nil
+}
+
+// semiStructuredExpr returns the expression writing value to a column of dataType,
+// PARSE_JSON returns a VARIANT which is cast for OBJECT and ARRAY columns
+func semiStructuredExpr(value interface{}, dataType string) (interface{}, error) {
+	text, err := semiStructuredJSON(value)
+	if err != nil || text == nil {
+		return nil, err
+	}
+
+	sql := "PARSE_JSON(?)"
+	if dataType == "OBJECT" || dataType == "ARRAY" {
+		sql += "::" + dataType
+	}
+	return clause.Expr{SQL: sql, Vars: []interface{}{text}}, nil
+}
+
+// bindSemiStructuredValues returns values with the values of semi-structured columns wrapped
+// in PARSE_JSON. Function calls are not allowed in a VALUES clause, statements built from the
+// result use SELECT rows instead when it has semi-structured columns
+func bindSemiStructuredValues(db *gorm.DB, values clause.Values) (clause.Values, error) {
+	types := semiStructuredColumns(db, values.Columns)
+	if types == nil {
+		return values, nil
+	}
+
+	rows := make([][]interface{}, len(values.Values))
+	for rowIdx, row := range values.Values {
+		rows[rowIdx] = make([]interface{}, len(row))
+		for idx, value := range row {
+			if idx < len(types) && types[idx] != "" {
+				var err error
+				if value, err = semiStructuredExpr(value, types[idx]); err != nil {
+					return values, err
+				}
+			}
+			rows[rowIdx][idx] = value
+		}
+	}
+	return clause.Values{Columns: values.Columns, Values: rows}, nil
+}
+
+// bindSemiStructuredAssignments wraps the values assigned to semi-structured columns of the
+// statement schema in PARSE_JSON, for UPDATE statements
+func bindSemiStructuredAssignments(stmt *gorm.Statement, set clause.Set) (clause.Set, error) {
+	if stmt.Schema == nil {
+		return set, nil
+	}
+
+	var (
+		config      = dialectorConfig(stmt.DB.Dialector)
+		assignments clause.Set
+	)
+	for idx, assignment := range set {
+		dataType := config.semiStructuredType(stmt.Schema.LookUpField(assignment.Column.Name))
+		if dataType == "" {
+			continue
+		}
+		if _, ok := assignment.Value.(clause.Expression); ok {
+			continue
+		}
+
+		if assignments == nil {
+			assignments = append(clause.Set{}, set...)
+		}
+		value, err := semiStructuredExpr(assignment.Value, dataType)
+		if err != nil {
+			return set, err
+		}
+		assignments[idx].Value = value
+	}
+
+	if assignments == nil {
+		return set, nil
+	}
+	return assignments, nil
+}
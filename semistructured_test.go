@@ -0,0 +1,81 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type SemiStructuredAddress struct {
+	City string `json:"city"`
+}
+
+type SemiStructuredModel struct {
+	ID         uint                   `gorm:"primaryKey;autoIncrement:false"`
+	Tags       []string               `gorm:"serializer:semistructured"`
+	Attributes map[string]string      `gorm:"serializer:semistructured"`
+	Address    SemiStructuredAddress  `gorm:"serializer:semistructured"`
+	Document   map[string]interface{} `gorm:"type:VARIANT;serializer:semistructured"`
+}
+
+func TestSemiStructuredDataType(t *testing.T) {
+	db := setupMockDB(t)
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&SemiStructuredModel{}); err != nil {
+		t.Fatalf("Failed to parse model: %v", err)
+	}
+
+	expected := map[string]string{"Tags": "ARRAY", "Attributes": "OBJECT", "Address": "OBJECT", "Document": "VARIANT"}
+	for name, dataType := range expected {
+		if got := db.Dialector.DataTypeOf(stmt.Schema.LookUpField(name)); got != dataType {
+			t.Errorf("Expected %s for %s, got %s", dataType, name, got)
+		}
+	}
+}
+
+func TestSemiStructuredCreate(t *testing.T) {
+	db := setupMockDBWithConfig(t, true, true)
+	tx := db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true}).Create(&SemiStructuredModel{
+		ID:         1,
+		Attributes: map[string]string{"color": "red"},
+		Address:    SemiStructuredAddress{City: "Paris"},
+		Document:   map[string]interface{}{"n": 1},
+	})
+	if tx.Error != nil {
+		t.Fatalf("Expected no error, got %v", tx.Error)
+	}
+
+	// nil Tags are stored as NULL
+	if sql := tx.Statement.SQL.String(); !strings.Contains(sql, "SELECT ?,?,PARSE_JSON(?)::OBJECT,PARSE_JSON(?)::OBJECT,PARSE_JSON(?)") {
+		t.Errorf("Expected casted PARSE_JSON values, got %s", sql)
+	}
+	expected := []interface{}{uint(1), nil, `{"color":"red"}`, `{"city":"Paris"}`, `{"n":1}`}
+	if len(tx.Statement.Vars) != len(expected) {
+		t.Fatalf("Expected vars %v, got %v", expected, tx.Statement.Vars)
+	}
+	for idx, value := range expected {
+		if tx.Statement.Vars[idx] != value {
+			t.Errorf("Expected var %d to be %v, got %v", idx, value, tx.Statement.Vars[idx])
+		}
+	}
+}
+
+func TestSemiStructuredScan(t *testing.T) {
+	db, _ := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+		return fakeResult{
+			columns: []string{"id", "tags", "attributes", "address", "document"},
+			rows:    [][]driver.Value{{int64(1), "[\n  \"a\",\n  \"b\"\n]", `{"color":"red"}`, `{"city":"Paris"}`, nil}},
+		}
+	})
+
+	var model SemiStructuredModel
+	if err := db.First(&model).Error; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(model.Tags) != 2 || model.Tags[1] != "b" || model.Attributes["color"] != "red" || model.Address.City != "Paris" || model.Document != nil {
+		t.Errorf("Unexpected model: %+v", model)
+	}
+}
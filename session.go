@@ -0,0 +1,252 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSessionParamMismatch is returned by AssertSessionParam when a parameter does not have the expected value
+	ErrSessionParamMismatch = errors.New("session parameter mismatch")
+	// ErrSessionParamsPool is returned by Initialize for Config.SessionParams with a Conn of
+	// several connections, only the connection running ALTER SESSION would have them. Set them in
+	// the DSN or the connector of the pool instead
+	ErrSessionParamsPool = errors.New("session parameters require a single connection")
+)
+
+// GetSessionParams returns the parameters of the current session keyed by their uppercased name,
+// as reported by SHOW PARAMETERS IN SESSION
+func GetSessionParams(db *gorm.DB) (map[string]string, error) {
+	return showSessionParams(db, "SHOW PARAMETERS IN SESSION")
+}
+
+// AssertSessionParam checks that the session parameter name is set to expected (case-insensitive).
+// It is meant for parameters that silently change query semantics, e.g. TIMEZONE, WEEK_START
+// or QUOTED_IDENTIFIERS_IGNORE_CASE
+func AssertSessionParam(db *gorm.DB, name, expected string) error {
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+
+	params, err := showSessionParams(db, "SHOW PARAMETERS LIKE ? IN SESSION", name)
+	if err != nil {
+		return err
+	}
+	return matchSessionParam(params, name, expected)
+}
+
+// WithSessionParams runs fc in a transaction whose session parameters are set to params
+// (e.g. TIMEZONE, DATE_OUTPUT_FORMAT), for jobs that need their own formatting.
+// The transaction pins the connection, the previous values are restored before it ends,
+// whether fc succeeds or not
+func WithSessionParams(db *gorm.DB, params map[string]string, fc func(tx *gorm.DB) error) error {
+	names, values, err := sessionParamNames(params)
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) (err error) {
+		previous, err := GetSessionParams(tx)
+		if err != nil {
+			return err
+		}
+
+		for idx, name := range names {
+			if _, ok := previous[name]; !ok {
+				err = fmt.Errorf("%w: %s is not a session parameter", ErrSessionParamMismatch, name)
+			} else {
+				err = setSessionParam(tx, name, values[name])
+			}
+
+			if err != nil {
+				return errors.Join(err, restoreSessionParams(tx, names[:idx], previous))
+			}
+		}
+
+		defer func() {
+			if restoreErr := restoreSessionParams(tx, names, previous); err == nil {
+				err = restoreErr
+			}
+		}()
+		return fc(tx)
+	})
+}
+
+// sessionParamNames validates the names of params and returns them uppercased and sorted, with
+// the values of params keyed by them
+func sessionParamNames(params map[string]string) ([]string, map[string]string, error) {
+	names := make([]string, 0, len(params))
+	values := make(map[string]string, len(params))
+	for name, value := range params {
+		if RequiresQuoting(name) {
+			return nil, nil, fmt.Errorf("%w: %q is not a session parameter name", ErrInvalidIdentifier, name)
+		}
+		names = append(names, strings.ToUpper(name))
+		values[strings.ToUpper(name)] = value
+	}
+	sort.Strings(names)
+	return names, values, nil
+}
+
+// sessionParamsDSN adds params to the query string of dsn, the driver sets them on the session of
+// every connection it opens
+func sessionParamsDSN(dsn string, params map[string]string) (string, error) {
+	names, values, err := sessionParamNames(params)
+	if err != nil || len(names) == 0 {
+		return dsn, err
+	}
+
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	for _, name := range names {
+		dsn += separator + name + "=" + url.QueryEscape(values[name])
+		separator = "&"
+	}
+	return dsn, nil
+}
+
+// applySessionParams runs ALTER SESSION SET for params on pool. It runs on the connection pool
+// directly as it is used before the gorm.DB is fully initialized
+func applySessionParams(pool gorm.ConnPool, params map[string]string) error {
+	names, values, err := sessionParamNames(params)
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 && !singleConnPool(pool) {
+		return ErrSessionParamsPool
+	}
+
+	for _, name := range names {
+		sql := "ALTER SESSION SET " + name + " = " + sessionParamLiteral(values[name])
+		if _, err := pool.ExecContext(context.Background(), sql); err != nil {
+			return fmt.Errorf("failed to set session parameter %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// singleConnPool reports whether the statements run on pool all share one connection: a
+// connection, a transaction or a pool limited to one connection
+func singleConnPool(pool gorm.ConnPool) bool {
+	if pinnedConnPool(pool) {
+		return true
+	}
+	sqlDB, ok := pool.(*sql.DB)
+	return ok && sqlDB.Stats().MaxOpenConnections == 1
+}
+
+// setSessionParam runs ALTER SESSION SET name = value, name must have been validated
+func setSessionParam(db *gorm.DB, name, value string) error {
+	return db.Exec("ALTER SESSION SET " + name + " = " + sessionParamLiteral(value)).Error
+}
+
+// restoreSessionParams sets names back to their previous value
+func restoreSessionParams(db *gorm.DB, names []string, previous map[string]string) error {
+	var errs []error
+	for _, name := range names {
+		if err := setSessionParam(db, name, previous[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sessionParamLiteral writes numbers and booleans as is and quotes other values
+func sessionParamLiteral(value string) string {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return value
+	}
+	if strings.EqualFold(value, "true") || strings.EqualFold(value, "false") {
+		return strings.ToUpper(value)
+	}
+	return quoteLiteral(value)
+}
+
+// matchSessionParam compares the value of name in params with expected
+func matchSessionParam(params map[string]string, name, expected string) error {
+	actual, ok := params[strings.ToUpper(name)]
+	if !ok {
+		return fmt.Errorf("%w: %s is not a session parameter", ErrSessionParamMismatch, name)
+	}
+
+	if !strings.EqualFold(strings.TrimSpace(actual), strings.TrimSpace(expected)) {
+		return fmt.Errorf("%w: %s is %q, expected %q", ErrSessionParamMismatch, name, actual, expected)
+	}
+	return nil
+}
+
+// showSessionParams runs a SHOW PARAMETERS statement
+func showSessionParams(db *gorm.DB, query string, vars ...interface{}) (map[string]string, error) {
+	rows, err := db.Raw(query, vars...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanSessionParams(rows)
+}
+
+// scanSessionParams collects the key/value columns of SHOW PARAMETERS rows
+func scanSessionParams(rows *sql.Rows) (map[string]string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	keyIdx, valueIdx := -1, -1
+	for idx, column := range columns {
+		switch strings.ToLower(column) {
+		case "key":
+			keyIdx = idx
+		case "value":
+			valueIdx = idx
+		}
+	}
+	if keyIdx < 0 || valueIdx < 0 {
+		return nil, fmt.Errorf("unexpected SHOW PARAMETERS columns: %v", columns)
+	}
+
+	var (
+		params = map[string]string{}
+		values = make([]sql.NullString, len(columns))
+		dest   = make([]interface{}, len(columns))
+	)
+	for idx := range values {
+		dest[idx] = &values[idx]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		params[strings.ToUpper(values[keyIdx].String)] = values[valueIdx].String
+	}
+
+	return params, rows.Err()
+}
+
+// detectQuotedIdentifiersIgnoreCase reads QUOTED_IDENTIFIERS_IGNORE_CASE from the session of pool.
+// It runs on the connection pool directly as it is used before the gorm.DB is fully initialized
+func detectQuotedIdentifiersIgnoreCase(pool gorm.ConnPool) (bool, error) {
+	rows, err := pool.QueryContext(context.Background(), "SHOW PARAMETERS LIKE 'QUOTED_IDENTIFIERS_IGNORE_CASE' IN SESSION")
+	if err != nil {
+		return false, fmt.Errorf("failed to detect QUOTED_IDENTIFIERS_IGNORE_CASE: %w", err)
+	}
+	defer rows.Close()
+
+	params, err := scanSessionParams(rows)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(params["QUOTED_IDENTIFIERS_IGNORE_CASE"], "true"), nil
+}
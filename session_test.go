@@ -0,0 +1,335 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func showParametersHandler(query string, args []interface{}) fakeResult {
+	if !strings.HasPrefix(query, "SHOW PARAMETERS") {
+		return fakeResult{}
+	}
+	return fakeResult{
+		columns: []string{"key", "value", "default", "level", "description", "type"},
+		rows: [][]driver.Value{
+			{"TIMEZONE", "America/Los_Angeles", "America/Los_Angeles", "", "", "STRING"},
+			{"WEEK_START", "1", "0", "SESSION", "", "NUMBER"},
+			{"QUOTED_IDENTIFIERS_IGNORE_CASE", "false", "false", "", "", "BOOLEAN"},
+		},
+	}
+}
+
+func TestGetSessionParams(t *testing.T) {
+	db, fake := setupFakeDB(t, Config{}, showParametersHandler)
+
+	params, err := GetSessionParams(db)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if params["TIMEZONE"] != "America/Los_Angeles" {
+		t.Errorf("Expected TIMEZONE to be America/Los_Angeles, got %q", params["TIMEZONE"])
+	}
+	if params["WEEK_START"] != "1" {
+		t.Errorf("Expected WEEK_START to be 1, got %q", params["WEEK_START"])
+	}
+	if !hasQuery(fake.queries(), "SHOW PARAMETERS IN SESSION") {
+		t.Errorf("Expected SHOW PARAMETERS IN SESSION, got %v", fake.queries())
+	}
+}
+
+func TestAssertSessionParam(t *testing.T) {
+	db, _ := setupFakeDB(t, Config{}, showParametersHandler)
+
+	if err := AssertSessionParam(db, "timezone", "America/Los_Angeles"); err != nil {
+		t.Errorf("Expected TIMEZONE to match, got %v", err)
+	}
+
+	if err := AssertSessionParam(db, "QUOTED_IDENTIFIERS_IGNORE_CASE", "FALSE"); err != nil {
+		t.Errorf("Expected boolean comparison to be case-insensitive, got %v", err)
+	}
+
+	if err := AssertSessionParam(db, "WEEK_START", "0"); !errors.Is(err, ErrSessionParamMismatch) {
+		t.Errorf("Expected ErrSessionParamMismatch, got %v", err)
+	}
+
+	if err := AssertSessionParam(db, "UNKNOWN_PARAM", "x"); !errors.Is(err, ErrSessionParamMismatch) {
+		t.Errorf("Expected ErrSessionParamMismatch for unknown parameter, got %v", err)
+	}
+
+	if err := AssertSessionParam(db, "TIMEZONE\n", "x"); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected ErrInvalidIdentifier, got %v", err)
+	}
+}
+
+func TestShowSessionParamsUnexpectedColumns(t *testing.T) {
+	db, _ := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+		return fakeResult{columns: []string{"name"}}
+	})
+
+	if _, err := GetSessionParams(db); err == nil {
+		t.Error("Expected an error for unexpected columns")
+	}
+}
+
+func TestDetectQuotedIdentifiersIgnoreCase(t *testing.T) {
+	handler := func(value string) func(string, []interface{}) fakeResult {
+		return func(query string, args []interface{}) fakeResult {
+			return fakeResult{
+				columns: []string{"key", "value"},
+				rows:    [][]driver.Value{{"QUOTED_IDENTIFIERS_IGNORE_CASE", value}},
+			}
+		}
+	}
+
+	db, fake := setupFakeDB(t, Config{QuoteFields: true, DetectQuotedIdentifiersIgnoreCase: true}, handler("true"))
+	config := dialectorConfig(db.Dialector)
+	if !config.QuotedIdentifiersIgnoreCase {
+		t.Error("Expected QuotedIdentifiersIgnoreCase to be detected as true")
+	}
+	if !hasQuery(fake.queries(), "QUOTED_IDENTIFIERS_IGNORE_CASE") {
+		t.Errorf("Expected the parameter to be queried, got %v", fake.queries())
+	}
+
+	quoted := db.Statement.Quote("users.first_name")
+	if quoted != `"USERS"."FIRST_NAME"` {
+		t.Errorf("Expected uppercased quoted identifier, got %s", quoted)
+	}
+
+	db, _ = setupFakeDB(t, Config{QuoteFields: true, DetectQuotedIdentifiersIgnoreCase: true}, handler("false"))
+	if dialectorConfig(db.Dialector).QuotedIdentifiersIgnoreCase {
+		t.Error("Expected QuotedIdentifiersIgnoreCase to be detected as false")
+	}
+	if quoted := db.Statement.Quote("users"); quoted != `"users"` {
+		t.Errorf("Expected case-preserving quoted identifier, got %s", quoted)
+	}
+}
+
+func TestMigratorLookupName(t *testing.T) {
+	tests := []struct {
+		config   Config
+		expected string
+	}{
+		{Config{}, "USERS"},
+		{Config{QuoteFields: true}, "users"},
+		{Config{QuoteFields: true, QuotedIdentifiersIgnoreCase: true}, "USERS"},
+	}
+
+	for _, test := range tests {
+		db, fake := setupFakeDB(t, test.config, nil)
+		db.Migrator().HasTable("users")
+
+		found := false
+		for _, stmt := range fake.statements {
+			if queriesInformationSchema(stmt.query, "TABLES") && len(stmt.args) > 0 && stmt.args[0] == test.expected {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected HasTable lookup with %s for config %+v", test.expected, test.config)
+		}
+	}
+}
+
+func TestWithSessionParams(t *testing.T) {
+	t.Run("Sets and restores", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, showParametersHandler)
+
+		var inside []string
+		err := WithSessionParams(db, map[string]string{"timezone": "UTC", "WEEK_START": "3"}, func(tx *gorm.DB) error {
+			inside = fake.queries()
+			return tx.Exec("SELECT 1").Error
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := []string{
+			"SHOW PARAMETERS IN SESSION",
+			"ALTER SESSION SET TIMEZONE = 'UTC'",
+			"ALTER SESSION SET WEEK_START = 3",
+		}
+		if len(inside) != len(expected) {
+			t.Fatalf("Expected %v before fc, got %v", expected, inside)
+		}
+		for idx := range expected {
+			if inside[idx] != expected[idx] {
+				t.Errorf("Statement %d: expected %s, got %s", idx, expected[idx], inside[idx])
+			}
+		}
+
+		queries := fake.queries()[len(inside):]
+		restored := []string{"SELECT 1", "ALTER SESSION SET TIMEZONE = 'America/Los_Angeles'", "ALTER SESSION SET WEEK_START = 1"}
+		if strings.Join(queries, ";") != strings.Join(restored, ";") {
+			t.Errorf("Expected %v, got %v", restored, queries)
+		}
+	})
+
+	t.Run("Restores on error", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, showParametersHandler)
+		fcErr := errors.New("job failed")
+
+		err := WithSessionParams(db, map[string]string{"TIMEZONE": "UTC"}, func(tx *gorm.DB) error { return fcErr })
+		if !errors.Is(err, fcErr) {
+			t.Fatalf("Expected fc error, got %v", err)
+		}
+		if !hasQuery(fake.queries(), "ALTER SESSION SET TIMEZONE = 'America/Los_Angeles'") {
+			t.Errorf("Expected TIMEZONE to be restored, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, showParametersHandler)
+		noop := func(tx *gorm.DB) error { return nil }
+
+		if err := WithSessionParams(db, map[string]string{"TIMEZONE = 'UTC'; --": "x"}, noop); !errors.Is(err, ErrInvalidIdentifier) {
+			t.Errorf("Expected ErrInvalidIdentifier, got %v", err)
+		}
+		if err := WithSessionParams(db, map[string]string{"NOT_A_PARAM": "x"}, noop); !errors.Is(err, ErrSessionParamMismatch) {
+			t.Errorf("Expected ErrSessionParamMismatch, got %v", err)
+		}
+		if hasQuery(fake.queries(), "ALTER SESSION") {
+			t.Errorf("Expected no ALTER SESSION, got %v", fake.queries())
+		}
+	})
+}
+
+func TestSessionParamLiteral(t *testing.T) {
+	tests := map[string]string{
+		"1":          "1",
+		"-5":         "-5",
+		"true":       "TRUE",
+		"UTC":        "'UTC'",
+		"YYYY-MM-DD": "'YYYY-MM-DD'",
+		"it's":       "'it''s'",
+	}
+	for value, expected := range tests {
+		if got := sessionParamLiteral(value); got != expected {
+			t.Errorf("sessionParamLiteral(%q): expected %s, got %s", value, expected, got)
+		}
+	}
+}
+
+func TestSessionParamsConfig(t *testing.T) {
+	t.Run("DSN", func(t *testing.T) {
+		tests := []struct {
+			dsn      string
+			params   map[string]string
+			expected string
+		}{
+			{"user:pass@account/db/schema", nil, "user:pass@account/db/schema"},
+			{
+				"user:pass@account/db/schema",
+				map[string]string{"timezone": "Europe/Paris", "STATEMENT_TIMEOUT_IN_SECONDS": "60"},
+				"user:pass@account/db/schema?STATEMENT_TIMEOUT_IN_SECONDS=60&TIMEZONE=Europe%2FParis",
+			},
+			{
+				"user:pass@account/db/schema?warehouse=wh",
+				map[string]string{"QUERY_TAG": "billing job"},
+				"user:pass@account/db/schema?warehouse=wh&QUERY_TAG=billing+job",
+			},
+		}
+		for _, test := range tests {
+			dsn, err := sessionParamsDSN(test.dsn, test.params)
+			if err != nil || dsn != test.expected {
+				t.Errorf("Expected %s, got %s (%v)", test.expected, dsn, err)
+			}
+		}
+
+		if _, err := sessionParamsDSN("user:pass@account", map[string]string{"TIMEZONE=UTC&x": "1"}); !errors.Is(err, ErrInvalidIdentifier) {
+			t.Errorf("Expected ErrInvalidIdentifier, got %v", err)
+		}
+	})
+
+	t.Run("Conn", func(t *testing.T) {
+		fake := &fakeDriver{handler: showParametersHandler}
+		sqlDB := sql.OpenDB(fake)
+		sqlDB.SetMaxOpenConns(1)
+		t.Cleanup(func() { sqlDB.Close() })
+
+		db, err := gorm.Open(New(Config{
+			Conn: sqlDB,
+			SessionParams: map[string]string{
+				"TIMEZONE":                     "UTC",
+				"timestamp_type_mapping":       "TIMESTAMP_TZ",
+				"STATEMENT_TIMEOUT_IN_SECONDS": "300",
+			},
+			DetectQuotedIdentifiersIgnoreCase: true,
+		}), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := []string{
+			"ALTER SESSION SET STATEMENT_TIMEOUT_IN_SECONDS = 300",
+			"ALTER SESSION SET TIMESTAMP_TYPE_MAPPING = 'TIMESTAMP_TZ'",
+			"ALTER SESSION SET TIMEZONE = 'UTC'",
+			"SHOW PARAMETERS LIKE 'QUOTED_IDENTIFIERS_IGNORE_CASE' IN SESSION",
+		}
+		queries := fake.queries()
+		if len(queries) != len(expected) {
+			t.Fatalf("Expected %v, got %v", expected, queries)
+		}
+		for idx, query := range expected {
+			if queries[idx] != query {
+				t.Errorf("Expected %s, got %s", query, queries[idx])
+			}
+		}
+		if db.Error != nil {
+			t.Errorf("Expected no error, got %v", db.Error)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		fake := &fakeDriver{handler: func(query string, args []interface{}) fakeResult {
+			return fakeResult{err: errors.New("invalid value")}
+		}}
+		sqlDB := sql.OpenDB(fake)
+		sqlDB.SetMaxOpenConns(1)
+		t.Cleanup(func() { sqlDB.Close() })
+
+		_, err := gorm.Open(New(Config{Conn: sqlDB, SessionParams: map[string]string{"TIMEZONE": "Mars/Olympus"}}), &gorm.Config{
+			Logger: logger.Default.LogMode(logger.Silent),
+		})
+		if err == nil || !strings.Contains(err.Error(), "TIMEZONE") {
+			t.Errorf("Expected the parameter error, got %v", err)
+		}
+	})
+
+	t.Run("Refuses a pool of several connections", func(t *testing.T) {
+		fake := &fakeDriver{}
+		sqlDB := sql.OpenDB(fake)
+		t.Cleanup(func() { sqlDB.Close() })
+
+		_, err := gorm.Open(New(Config{Conn: sqlDB, SessionParams: map[string]string{"TIMEZONE": "UTC"}}), &gorm.Config{
+			Logger: logger.Default.LogMode(logger.Silent),
+		})
+		if !errors.Is(err, ErrSessionParamsPool) {
+			t.Errorf("Expected ErrSessionParamsPool, got %v", err)
+		}
+		if queries := fake.queries(); len(queries) != 0 {
+			t.Errorf("Expected no ALTER SESSION, got %v", queries)
+		}
+
+		// a failed Open closes the pool
+		sqlDB = sql.OpenDB(fake)
+		t.Cleanup(func() { sqlDB.Close() })
+		conn, err := sqlDB.Conn(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer conn.Close()
+		if _, err := gorm.Open(New(Config{Conn: conn, SessionParams: map[string]string{"TIMEZONE": "UTC"}}), &gorm.Config{
+			Logger: logger.Default.LogMode(logger.Silent),
+		}); err != nil {
+			t.Errorf("Expected a single connection to be accepted, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ShowTable is a row of SHOW TABLES
+type ShowTable struct {
+	CreatedOn      time.Time
+	Name           string
+	DatabaseName   string
+	SchemaName     string
+	Kind           string
+	Comment        string
+	ClusterBy      string
+	Rows           int64
+	Bytes          int64
+	Owner          string
+	RetentionTime  int64
+	ChangeTracking string
+	IsExternal     string
+}
+
+// ShowWarehouse is a row of SHOW WAREHOUSES
+type ShowWarehouse struct {
+	Name            string
+	State           string
+	Type            string
+	Size            string
+	MinClusterCount int64
+	MaxClusterCount int64
+	StartedClusters int64
+	Running         int64
+	Queued          int64
+	IsDefault       string
+	IsCurrent       string
+	AutoSuspend     int64
+	AutoResume      string
+	CreatedOn       time.Time
+	ResumedOn       time.Time
+	UpdatedOn       time.Time
+	Owner           string
+	Comment         string
+	ResourceMonitor string
+}
+
+// ShowDatabase is a row of SHOW DATABASES
+type ShowDatabase struct {
+	CreatedOn     time.Time
+	Name          string
+	IsDefault     string
+	IsCurrent     string
+	Origin        string
+	Owner         string
+	Comment       string
+	Options       string
+	RetentionTime int64
+	Kind          string
+}
+
+// ShowSchema is a row of SHOW SCHEMAS
+type ShowSchema struct {
+	CreatedOn     time.Time
+	Name          string
+	IsDefault     string
+	IsCurrent     string
+	DatabaseName  string
+	Owner         string
+	Comment       string
+	Options       string
+	RetentionTime int64
+}
+
+// ShowColumn is a row of SHOW COLUMNS, DataType holds the JSON description of the type
+type ShowColumn struct {
+	TableName     string
+	SchemaName    string
+	ColumnName    string
+	DataType      string
+	Null          string `gorm:"column:null"`
+	Default       string
+	Kind          string
+	Expression    string
+	Comment       string
+	DatabaseName  string
+	Autoincrement string
+}
+
+// Show runs SHOW <command> and scans the result into the last argument, a pointer to a slice
+// of structs (e.g. *[]ShowTable), the other arguments are bind variables of command:
+//
+//	var tables []snowflake.ShowTable
+//	err := snowflake.Show(db, "TABLES LIKE ?", "ORDER%", &tables)
+//
+// Output columns are matched to fields by their normalized name ("null?" matches column null),
+// columns without a field are ignored
+func Show(db *gorm.DB, command string, args ...interface{}) error {
+	if len(args) == 0 {
+		return errors.New("show: missing destination")
+	}
+	return queryInto(db, "SHOW "+strings.TrimSpace(command), args[:len(args)-1], args[len(args)-1])
+}
+
+// queryInto runs the statement sql returning rows, e.g. SHOW or LIST, and scans them into dest
+// as Show does
+func queryInto(db *gorm.DB, sql string, vars []interface{}, dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("show: destination must be a pointer to a slice, got %T", dest)
+	}
+
+	sliceValue := destValue.Elem()
+	elemType, isPtr := sliceValue.Type().Elem(), false
+	if elemType.Kind() == reflect.Ptr {
+		elemType, isPtr = elemType.Elem(), true
+	}
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("show: destination must be a slice of structs, got %T", dest)
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(reflect.New(elemType).Interface()); err != nil {
+		return err
+	}
+
+	rows, err := db.Raw(sql, vars...).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	fields := make([]*schema.Field, len(columns))
+	for idx, column := range columns {
+		fields[idx] = stmt.Schema.LookUpField(normalizeShowColumn(column))
+	}
+
+	buffer := getScanBuffer(len(columns))
+	defer putScanBuffer(buffer)
+
+	var (
+		ctx    = db.Statement.Context
+		values = buffer.values
+		result = reflect.MakeSlice(sliceValue.Type(), 0, 0)
+	)
+
+	for rows.Next() {
+		if err := rows.Scan(buffer.ptrs...); err != nil {
+			return err
+		}
+
+		elem := reflect.New(elemType)
+		for idx, field := range fields {
+			if field == nil {
+				continue
+			}
+			if err := field.Set(ctx, elem.Elem(), values[idx]); err != nil {
+				return fmt.Errorf("show: column %s: %w", columns[idx], err)
+			}
+		}
+
+		if isPtr {
+			result = reflect.Append(result, elem)
+		} else {
+			result = reflect.Append(result, elem.Elem())
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	sliceValue.Set(result)
+	return nil
+}
+
+// normalizeShowColumn turns a SHOW output column (e.g. "null?", "created_on") into a
+// lowercase snake case name, dropping characters that cannot be part of a column name
+func normalizeShowColumn(column string) string {
+	column = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		case r == ' ' || r == '-':
+			return '_'
+		}
+		return -1
+	}, column)
+	return strings.Trim(column, "_")
+}
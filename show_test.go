@@ -0,0 +1,93 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+)
+
+func showTablesHandler(query string, args []interface{}) fakeResult {
+	if !strings.HasPrefix(query, "SHOW TABLES") {
+		return fakeResult{}
+	}
+	createdOn := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	return fakeResult{
+		columns: []string{"created_on", "name", "database_name", "schema_name", "kind", "comment", "cluster_by", "rows", "bytes", "owner", "retention_time", "unknown_column"},
+		rows: [][]driver.Value{
+			{createdOn, "ORDERS", "DB", "PUBLIC", "TABLE", "", "", "1200", "40960", "SYSADMIN", "1", "x"},
+			{createdOn, "ORDER_ITEMS", "DB", "PUBLIC", "TABLE", nil, nil, "0", "0", "SYSADMIN", "1", "y"},
+		},
+	}
+}
+
+func TestShow(t *testing.T) {
+	t.Run("Scans built-in struct", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, showTablesHandler)
+
+		var tables []ShowTable
+		if err := Show(db, "TABLES LIKE ?", "ORDER%", &tables); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(tables) != 2 {
+			t.Fatalf("Expected 2 tables, got %d", len(tables))
+		}
+		if tables[0].Name != "ORDERS" || tables[0].Rows != 1200 || tables[0].Bytes != 40960 || tables[0].SchemaName != "PUBLIC" {
+			t.Errorf("Unexpected first table: %+v", tables[0])
+		}
+		if tables[0].CreatedOn.Year() != 2024 {
+			t.Errorf("Expected created_on to be scanned, got %v", tables[0].CreatedOn)
+		}
+		if tables[1].Comment != "" {
+			t.Errorf("Expected NULL comment to be empty, got %q", tables[1].Comment)
+		}
+
+		if len(fake.statements) != 1 || fake.statements[0].query != "SHOW TABLES LIKE ?" || fake.statements[0].args[0] != "ORDER%" {
+			t.Errorf("Unexpected statements: %+v", fake.statements)
+		}
+	})
+
+	t.Run("Scans pointer elements and special columns", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			return fakeResult{
+				columns: []string{"table_name", "column_name", "null?", "default"},
+				rows:    [][]driver.Value{{"ORDERS", "ID", "false", "IDENTITY"}},
+			}
+		})
+
+		var columns []*ShowColumn
+		if err := Show(db, "COLUMNS IN TABLE orders", &columns); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(columns) != 1 || columns[0].Null != "false" || columns[0].Default != "IDENTITY" {
+			t.Errorf("Unexpected columns: %+v", columns)
+		}
+	})
+
+	t.Run("Invalid destination", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, showTablesHandler)
+
+		var table ShowTable
+		for _, args := range [][]interface{}{nil, {&table}, {[]ShowTable{}}, {&[]string{}}} {
+			if err := Show(db, "TABLES", args...); err == nil {
+				t.Errorf("Expected an error for %v", args)
+			}
+		}
+	})
+}
+
+func TestNormalizeShowColumn(t *testing.T) {
+	tests := map[string]string{
+		"created_on":      "created_on",
+		"null?":           "null",
+		"Budget":          "budget",
+		"owner role type": "owner_role_type",
+	}
+
+	for column, expected := range tests {
+		if got := normalizeShowColumn(column); got != expected {
+			t.Errorf("normalizeShowColumn(%q): expected %q, got %q", column, expected, got)
+		}
+	}
+}
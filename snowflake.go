@@ -7,12 +7,12 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/snowflakedb/gosnowflake"
 	"gorm.io/gorm"
 	"gorm.io/gorm/callbacks"
 	"gorm.io/gorm/clause"
-	"gorm.io/gorm/logger"
 	"gorm.io/gorm/migrator"
 	"gorm.io/gorm/schema"
 )
@@ -43,6 +43,164 @@ type Config struct {
 	// Required for using SQL functions in values, but slower than VALUES syntax
 	// Default: true (maintains backward compatibility)
 	UseUnionSelect bool
+	// AppendSemicolon terminates the INSERT, MERGE and CHANGES statements built by Create with
+	// a semicolon, set it to false for proxies rejecting terminators or to embed the statements
+	// Default: nil (true)
+	AppendSemicolon *bool
+	// BindVarStyle selects the placeholder syntax for bind variables, required by
+	// some proxy/pooling layers that only accept numbered binds
+	// Default: BindVarQuestion
+	BindVarStyle BindVarStyle
+	// ConstraintEnforcement is the enforcement keyword written for foreign keys,
+	// Snowflake only enforces NOT NULL so some editions reject ENFORCED
+	// Default: ConstraintNotEnforced
+	ConstraintEnforcement ConstraintEnforcement
+	// ForeignKeyMatch sets the MATCH type (FULL, SIMPLE or PARTIAL) of foreign keys,
+	// SIMPLE allows nullable foreign key columns. Empty omits the MATCH clause
+	ForeignKeyMatch string
+	// QuotedIdentifiersIgnoreCase mirrors the account/session parameter QUOTED_IDENTIFIERS_IGNORE_CASE.
+	// When true Snowflake resolves quoted identifiers as uppercase, so QuoteTo and the
+	// migrator lookups uppercase them as well instead of preserving their case
+	QuotedIdentifiersIgnoreCase bool
+	// DetectQuotedIdentifiersIgnoreCase reads QUOTED_IDENTIFIERS_IGNORE_CASE from the session
+	// during Initialize and sets QuotedIdentifiersIgnoreCase accordingly
+	DetectQuotedIdentifiersIgnoreCase bool
+	// SkipNoopUpdates only updates matched rows of an upsert when at least one updated
+	// column differs from the incoming value (WHEN MATCHED AND ... IS DISTINCT FROM ...),
+	// reducing churn and Time Travel storage on large tables
+	SkipNoopUpdates bool
+	// NullSafeMergeJoin matches the primary key columns of upserts with EQUAL_NULL in the ON
+	// clause of MERGE, so rows whose (nullable) key columns are NULL update the existing row
+	// instead of inserting a duplicate. It may prune micro-partitions less than =
+	NullSafeMergeJoin bool
+	// SkipUnchangedSaves tracks a hash of the values of the rows loaded by queries, so Save skips
+	// the UPDATE (or drops from the MERGE of a slice) the rows whose values did not change since.
+	// A skipped Save of a struct reports one row affected. Rows loaded in a transaction or with
+	// Select are not tracked, every row of a table is forgotten after other updates or deletes of
+	// it; changes made by raw statements or other processes are not detected
+	// Default: false
+	SkipUnchangedSaves bool
+	// DefaultValueFetcher replaces the CHANGES-based query that populates fields with
+	// database defaults (e.g. IDENTITY IDs) after an insert, for tables where change
+	// tracking is not available (hybrid, external tables, views...)
+	DefaultValueFetcher func(db *gorm.DB, fields []*schema.Field) error
+	// DefaultValueStrategy selects how the auto increment IDs of inserted rows are populated:
+	// read back with CHANGES after the insert, taken from a sequence before it, or read back above
+	// a MAX(id) snapshot taken before it
+	// Default: DefaultValueChanges
+	DefaultValueStrategy DefaultValueStrategy
+	// ClientSideIDs generates the auto increment IDs of created rows in the client, 64-bit IDs
+	// ordered by time (the snowflake algorithm) assigned before the BeforeCreate hooks, so tables
+	// need neither IDENTITY columns nor change tracking to read the IDs back. The migrator
+	// creates such columns as plain BIGINT. Other database defaults are not read back, unless
+	// Config.DefaultValueFetcher is set
+	// Default: false
+	ClientSideIDs bool
+	// ClientSideIDNode is the node (0 to 1023) of the IDs generated with ClientSideIDs, each
+	// process creating rows of the same tables needs its own to avoid duplicates
+	// Default: 0
+	ClientSideIDNode int64
+	// MaxEstimatedBytesScanned makes queries fail with ErrEstimatedBytesExceeded when their
+	// EXPLAIN plan assigns more bytes than the limit, protecting against accidental full
+	// scans of large tables. Every checked query costs an extra EXPLAIN round trip.
+	// Default: 0 (disabled)
+	MaxEstimatedBytesScanned int64
+	// InListBindThreshold is the number of values above which the IN condition of the queries
+	// of a Preload (the keys of the parents) is bound as one JSON array expanded with FLATTEN,
+	// instead of one bind variable per value. Only lists of strings and numbers are rewritten,
+	// other queries never are. Negative disables the rewrite
+	// Default: 1000
+	InListBindThreshold int
+	// Migration sets the warehouse and statement timeout of migration operations
+	Migration MigratorConfig
+	// IndexStrategy converts index declarations into a clustering key or search optimization
+	// during CreateTable and AutoMigrate
+	// Default: IndexIgnore
+	IndexStrategy IndexStrategy
+	// ReadOnly refuses Create, Update, Delete and raw statements other than reads with
+	// ErrReadOnly, e.g. for analytics replicas or during incident freezes
+	ReadOnly bool
+	// HeavyStatements tags MERGE upserts, CTAS and statements flagged with HeavyStatementKey
+	// with a warehouse size hint and checks a resource monitor budget before running them
+	HeavyStatements HeavyStatementConfig
+	// MaxStatementBytes splits the rows of a Create into several statements so the estimated
+	// size of each, placeholders and bind values included, stays under the limit, on top of
+	// CreateBatchSize. A single row larger than the limit is still sent on its own
+	// Default: 0 (no limit)
+	MaxStatementBytes int
+	// ScanTimesIn converts the timestamps scanned by queries (Find, First, Take...) to this
+	// location according to their column type: TIMESTAMP_NTZ values keep their wall clock,
+	// TIMESTAMP_LTZ and TIMESTAMP_TZ values keep their instant. Rows read with Scan, Row or
+	// Rows are not converted, raw queries are with db.Raw(...).Find(&dest)
+	// Default: nil (times are returned as the driver scans them)
+	ScanTimesIn *time.Location
+	// ModelRouting runs the queries, creates, updates and deletes on the tables it lists with their
+	// warehouse and/or role, e.g. a larger warehouse for heavy fact tables. Routed statements take
+	// a dedicated connection switched with USE ROLE/WAREHOUSE, restored once they are done
+	ModelRouting map[string]SessionTarget
+	// BulkLoadThreshold loads the rows of a Create having more rows than the threshold by
+	// writing them to a temporary CSV file, PUT to the table stage and loaded with COPY INTO,
+	// instead of a multi-row INSERT. Upserts and rows holding SQL expressions are still inserted.
+	// The string \N is loaded as NULL
+	// Default: 0 (disabled)
+	BulkLoadThreshold int
+	// TableNameRewriter rewrites the table names, e.g. to prefix them with DEV_ or TEST_ per
+	// environment: those of the naming strategy, join tables included, those returned by
+	// TableName methods and those given to db.Table. The rewritten name is used by every
+	// statement, the Migrator and ModelRouting. Table expressions (with an alias or vars) and
+	// the names given to the Migrator as strings are used as is
+	TableNameRewriter func(string) string
+	// PostgresCompat maps the Postgres types and defaults of model tags to Snowflake ones, so models
+	// written for Postgres migrate unchanged: json/jsonb to VARIANT, <type>[] to ARRAY, uuid to
+	// VARCHAR(36), serial types to IDENTITY, now() to CURRENT_TIMESTAMP(), gen_random_uuid() to
+	// UUID_STRING()... json/jsonb and array values are written with PARSE_JSON, so array fields must
+	// encode to JSON arrays (e.g. serializer:json), not Postgres array literals
+	PostgresCompat bool
+	// MergeViaTempTable merges the rows of upserts from a temporary table loaded with a single
+	// array-bound INSERT, instead of a VALUES source holding every value of the batch, which is
+	// much faster for wide rows. Creating the table is DDL, which commits an open transaction,
+	// so upserts in a transaction (including the default transaction of gorm, see
+	// SkipDefaultTransaction) and rows holding SQL expressions keep the VALUES source
+	// Default: false
+	MergeViaTempTable bool
+	// QueryTag is the QUERY_TAG of every statement run through gorm, so the queries of the
+	// application can be attributed in QUERY_HISTORY. WithQueryTag overrides it per context
+	// Default: "" (no tag)
+	QueryTag string
+	// InstanceName labels the dialector when a process connects to several accounts, e.g. "prod"
+	// and "analytics": log messages and SQL traces are prefixed with [InstanceName], statement
+	// errors are wrapped in an InstanceError and ExecWithStats reports it, see InstanceName
+	// Default: "" (unlabeled)
+	InstanceName string
+	// StrictMode returns ErrStrictMode instead of silently ignoring the GORM features Snowflake
+	// does not support: savepoints of nested transactions, index operations, indexes declared
+	// without IndexStrategy, unique and foreign key constraints of tables other than hybrid tables
+	// and locking clauses of queries on them, so incompatibilities surface in tests
+	// Default: false
+	StrictMode bool
+	// IntegerMapping selects the column types of integer fields: by their size, or NUMBER(38,0)
+	// for all of them so no field overflows its column
+	// Default: IntegerSized
+	IntegerMapping IntegerMapping
+	// SessionParams sets session parameters, e.g. TIMEZONE, TIMESTAMP_TYPE_MAPPING, QUERY_TAG or
+	// STATEMENT_TIMEOUT_IN_SECONDS, during Initialize. They are added to the DSN so every
+	// connection of the pool has them. With Conn they are set by ALTER SESSION SET on it, which
+	// must be a single connection or a pool limited to one (ErrSessionParamsPool)
+	// Default: nil (the user and account defaults)
+	SessionParams map[string]string
+}
+
+// dialectorConfig returns the snowflake Config of d, or nil if d is not a snowflake dialector
+func dialectorConfig(d gorm.Dialector) *Config {
+	switch dialector := d.(type) {
+	case *Dialector:
+		if dialector != nil {
+			return dialector.Config
+		}
+	case Dialector:
+		return dialector.Config
+	}
+	return nil
 }
 
 func (dialector Dialector) Name() string {
@@ -65,8 +223,58 @@ func New(config Config) gorm.Dialector {
 
 func (dialector Dialector) Initialize(db *gorm.DB) (err error) {
 	// register callbacks
-	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{
+		QueryClauses:  queryClauses,
+		UpdateClauses: updateClauses,
+		DeleteClauses: deleteClauses,
+	})
 	_ = db.Callback().Create().Replace("gorm:create", Create)
+	_ = db.Callback().Update().Replace("gorm:update", Update)
+	registerDeleteCallbacks(db)
+	registerReadOnlyCallbacks(db)
+	registerLockingCallbacks(db)
+	registerPreloadCallbacks(db)
+	registerHeavyStatementCallbacks(db)
+	registerBatchCallbacks(db)
+	registerRecorderCallbacks(db)
+
+	if dialector.SkipUnchangedSaves {
+		registerUnchangedSaveCallbacks(db)
+	}
+
+	if dialector.ClientSideIDs {
+		if err = registerClientSideIDCallbacks(db, dialector.ClientSideIDNode); err != nil {
+			return err
+		}
+	}
+
+	if dialector.QueryTag != "" {
+		registerQueryTagCallbacks(db)
+	}
+
+	if dialector.InstanceName != "" {
+		registerInstanceCallbacks(db)
+		db.Logger = newInstanceLogger(db.Logger, dialector.InstanceName)
+	}
+
+	if dialector.TableNameRewriter != nil {
+		db.NamingStrategy = tableNameRewriter{Namer: db.NamingStrategy, rewrite: dialector.TableNameRewriter}
+		registerTableNameCallbacks(db)
+	}
+	db.NamingStrategy = qualifiedJoinTableNamer{Namer: db.NamingStrategy}
+
+	if len(dialector.ModelRouting) > 0 {
+		registerRoutingCallbacks(db)
+	}
+
+	if dialector.ScanTimesIn != nil {
+		_ = db.Callback().Query().Replace("gorm:query", Query)
+	}
+
+	if dialector.MaxEstimatedBytesScanned > 0 {
+		_ = db.Callback().Query().Before("gorm:query").Register("snowflake:cost_guard", CostGuard)
+		_ = db.Callback().Row().Before("gorm:row").Register("snowflake:cost_guard", CostGuard)
+	}
 
 	if dialector.DriverName == "" {
 		dialector.DriverName = SnowflakeDriverName
@@ -74,11 +282,30 @@ func (dialector Dialector) Initialize(db *gorm.DB) (err error) {
 
 	if dialector.Conn != nil {
 		db.ConnPool = dialector.Conn
+		if err = applySessionParams(db.ConnPool, dialector.SessionParams); err != nil {
+			return err
+		}
 	} else {
-		db.ConnPool, err = sql.Open(dialector.DriverName, dialector.DSN)
+		dsn, err := sessionParamsDSN(dialector.DSN, dialector.SessionParams)
 		if err != nil {
 			return err
 		}
+		if db.ConnPool, err = sql.Open(dialector.DriverName, dsn); err != nil {
+			return err
+		}
+	}
+
+	if dialector.BindVarStyle == BindVarNamed {
+		if db.PrepareStmt {
+			return ErrNamedBindVarsPrepareStmt
+		}
+		db.ConnPool = &namedArgsConnPool{ConnPool: db.ConnPool}
+	}
+
+	if dialector.DetectQuotedIdentifiersIgnoreCase {
+		if dialector.QuotedIdentifiersIgnoreCase, err = detectQuotedIdentifiersIgnoreCase(db.ConnPool); err != nil {
+			return err
+		}
 	}
 
 	for k, v := range dialector.ClauseBuilders() {
@@ -89,6 +316,57 @@ func (dialector Dialector) Initialize(db *gorm.DB) (err error) {
 
 func (dialector Dialector) ClauseBuilders() map[string]clause.ClauseBuilder {
 	return map[string]clause.ClauseBuilder{
+		"WHERE": func(c clause.Clause, builder clause.Builder) {
+			if where, ok := c.Expression.(clause.Where); ok {
+				if nullSafe := rewriteNullComparisons(where.Exprs); nullSafe != nil {
+					where = clause.Where{Exprs: nullSafe}
+					c.Expression = where
+				}
+			}
+			// build with the default builder
+			c.Builder = nil
+			c.Build(builder)
+		},
+		"SET": func(c clause.Clause, builder clause.Builder) {
+			if set, ok := c.Expression.(clause.Set); ok {
+				set = unqualifiedAssignments(set)
+				c.Expression = set
+				if stmt, ok := builder.(*gorm.Statement); ok {
+					var err error
+					if set, err = bindSemiStructuredAssignments(stmt, set); err != nil {
+						stmt.AddError(err)
+					} else if set, err = bindTokenizedAssignments(stmt, set); err != nil {
+						stmt.AddError(err)
+					}
+					c.Expression = set
+				}
+			}
+			// build with the default builder
+			c.Builder = nil
+			c.Build(builder)
+		},
+		"SELECT": func(c clause.Clause, builder clause.Builder) {
+			if sel, ok := c.Expression.(clause.Select); ok {
+				if stmt, ok := builder.(*gorm.Statement); ok {
+					if detokenized, ok, err := detokenizedSelect(stmt, sel); err != nil {
+						stmt.AddError(err)
+					} else if ok {
+						c.Expression = detokenized
+					}
+				}
+			}
+			// build with the default builder
+			c.Builder = nil
+			c.Build(builder)
+		},
+		"FROM": func(c clause.Clause, builder clause.Builder) {
+			if buildSelectFrom(c, builder) {
+				return
+			}
+			// build with the default builder
+			c.Builder = nil
+			c.Build(builder)
+		},
 		"LIMIT": func(c clause.Clause, builder clause.Builder) {
 			if limit, ok := c.Expression.(clause.Limit); ok {
 				if stmt, ok := builder.(*gorm.Statement); ok {
@@ -134,7 +412,8 @@ func (dialector Dialector) Migrator(db *gorm.DB) gorm.Migrator {
 }
 
 func (dialector Dialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement, v interface{}) {
-	writer.WriteByte('?')
+	// v has already been appended to stmt.Vars, so its position is len(stmt.Vars)
+	writeBindVar(writer, dialector.BindVarStyle, len(stmt.Vars))
 }
 
 func (dialector Dialector) QuoteTo(writer clause.Writer, str string) {
@@ -149,6 +428,10 @@ func (dialector Dialector) QuoteTo(writer clause.Writer, str string) {
 			quoteString = matches[2]
 		}
 
+		if dialector.QuotedIdentifiersIgnoreCase {
+			quoteString = strings.ToUpper(quoteString)
+		}
+
 		writer.WriteByte('"')
 		if strings.Contains(quoteString, ".") {
 			parts := strings.Split(quoteString, ".")
@@ -156,11 +439,11 @@ func (dialector Dialector) QuoteTo(writer clause.Writer, str string) {
 				if idx > 0 {
 					writer.WriteString(`."`)
 				}
-				writer.WriteString(splitStr)
+				writer.WriteString(escapeIdentifier(splitStr))
 				writer.WriteByte('"')
 			}
 		} else {
-			writer.WriteString(quoteString)
+			writer.WriteString(escapeIdentifier(quoteString))
 			writer.WriteByte('"')
 		}
 
@@ -168,30 +451,56 @@ func (dialector Dialector) QuoteTo(writer clause.Writer, str string) {
 			writer.WriteByte(')')
 		}
 	} else {
-		writer.WriteString(strings.ToLower(str))
+		str = strings.ToLower(cleanIdentifier(str))
+		if functionRegex.MatchString(str) {
+			writer.WriteString(str)
+			return
+		}
+
+		// reserved words and names with special characters cannot be written unquoted, reserved
+		// words are quoted uppercase: the name Snowflake resolves unquoted identifiers to
+		for idx, part := range strings.Split(str, ".") {
+			if idx > 0 {
+				writer.WriteByte('.')
+			}
+			if part != "" && part != "*" && IsReservedWord(part) {
+				writer.WriteString(quoteIdentifier(strings.ToUpper(part)))
+			} else if part != "" && part != "*" && RequiresQuoting(part) {
+				writer.WriteString(quoteIdentifier(part))
+			} else {
+				writer.WriteString(part)
+			}
+		}
 	}
 }
 
 func (dialector Dialector) Explain(sql string, vars ...interface{}) string {
-	return logger.ExplainSQL(sql, nil, `'`, vars...)
+	return explainSQL(sql, dialector.BindVarStyle, vars...)
 }
 
 func (dialector Dialector) DataTypeOf(field *schema.Field) string {
+	if dialector.PostgresCompat {
+		if dataType := postgresCompatType(field); dataType != "" {
+			return dataType
+		}
+	}
+	if dataType := semiStructuredType(field); dataType != "" {
+		return dataType
+	}
+
 	switch field.DataType {
 	case schema.Bool:
 		return "BOOLEAN"
 	case schema.Int, schema.Uint:
-		var sqlType string
-		switch {
-		case field.Size < 16:
-			sqlType = "SMALLINT"
-		case field.Size < 31:
-			sqlType = "INT"
-		default:
-			sqlType = "BIGINT"
-		}
-
+		sqlType := dialector.integerType(field.Size)
 		if field.AutoIncrement {
+			if sequenceField(dialector.Config, field.Schema) == field {
+				return sqlType + " " + dialector.sequenceDefault(field)
+			}
+			if clientSideIDField(dialector.Config, field.Schema) == field {
+				// the IDs are generated by the client, they need the 64 bits
+				return dialector.integerType(64)
+			}
 			return sqlType + " IDENTITY(1,1)"
 		}
 		return sqlType
@@ -217,14 +526,20 @@ func (dialector Dialector) DataTypeOf(field *schema.Field) string {
 	return string(field.DataType)
 }
 
-// no support for savepoint
+// no support for savepoint, nested transactions run in the outer transaction, or fail with
+// ErrStrictMode when Config.StrictMode is set
 func (dialectopr Dialector) SavePoint(tx *gorm.DB, name string) error {
+	if dialectopr.Config != nil && dialectopr.StrictMode {
+		return fmt.Errorf("%w: SAVEPOINT %s, nested transactions cannot be rolled back on their own", ErrStrictMode, name)
+	}
 	return nil
 }
 
 func (dialectopr Dialector) RollbackTo(tx *gorm.DB, name string) error {
-	tx.Exec("ROLLBACK TRANSACTION " + name)
-	return nil
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+	return tx.Exec("ROLLBACK TRANSACTION ?", clause.Table{Name: name}).Error
 }
 
 // NamingStrategy for snowflake (always uppercase)
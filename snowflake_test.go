@@ -3,6 +3,7 @@ package snowflake
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -505,11 +506,11 @@ func TestBatchInsertWithConflict(t *testing.T) {
 	})
 }
 
-func setupMockDB(t *testing.T) *gorm.DB {
+func setupMockDB(t testing.TB) *gorm.DB {
 	return setupMockDBWithConfig(t, true, true) // Default to UNION SELECT for backward compatibility
 }
 
-func setupMockDBWithConfig(t *testing.T, useUnionSelect bool, quoteFields bool) *gorm.DB {
+func setupMockDBWithConfig(t testing.TB, useUnionSelect bool, quoteFields bool) *gorm.DB {
 	// Create a dialector with a mock connection
 	mockPool := &mockConnPool{}
 	dialector := &Dialector{
@@ -725,9 +726,10 @@ func TestDialectorDataTypeOf(t *testing.T) {
 	}{
 		{&schema.Field{DataType: schema.Bool}, "BOOLEAN"},
 		{&schema.Field{DataType: schema.Int, Size: 8}, "SMALLINT"},
-		{&schema.Field{DataType: schema.Int, Size: 16}, "INT"},
+		{&schema.Field{DataType: schema.Int, Size: 16}, "SMALLINT"},
 		{&schema.Field{DataType: schema.Int, Size: 64}, "BIGINT"},
-		{&schema.Field{DataType: schema.Int, Size: 32, AutoIncrement: true}, "BIGINT IDENTITY(1,1)"},
+		{&schema.Field{DataType: schema.Int, Size: 32}, "INT"},
+		{&schema.Field{DataType: schema.Int, Size: 64, AutoIncrement: true}, "BIGINT IDENTITY(1,1)"},
 		{&schema.Field{DataType: schema.Uint, Size: 8}, "SMALLINT"},
 		{&schema.Field{DataType: schema.Float}, "FLOAT"},
 		{&schema.Field{DataType: schema.String, Size: 100}, "VARCHAR(100)"},
@@ -769,6 +771,17 @@ func TestDialectorRollbackTo(t *testing.T) {
 	if err != nil {
 		t.Errorf("Expected RollbackTo to return nil, got %v", err)
 	}
+
+	// A failed rollback is reported to the caller
+	failing, _ := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+		if strings.HasPrefix(query, "ROLLBACK TRANSACTION") {
+			return fakeResult{err: errors.New("savepoint does not exist")}
+		}
+		return fakeResult{}
+	})
+	if err := dialector.RollbackTo(failing, "test_savepoint"); err == nil || !strings.Contains(err.Error(), "savepoint does not exist") {
+		t.Errorf("Expected RollbackTo to return the rollback error, got %v", err)
+	}
 }
 
 // TestDialectorMigrator tests the Migrator method
@@ -0,0 +1,130 @@
+package snowflake
+
+import (
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxPooledSQLBuffer is the capacity above which buffers are left to the GC instead of pooled,
+// so one exceptionally large batch does not pin its memory
+const maxPooledSQLBuffer = 4 << 20
+
+// sqlBuffer is a clause.Writer appending to a byte slice, used to build the rows of bulk
+// statements before copying them into the statement at once
+type sqlBuffer struct {
+	buf []byte
+}
+
+var sqlBufferPool = sync.Pool{New: func() interface{} { return &sqlBuffer{} }}
+
+// getSQLBuffer returns an empty pooled buffer, released with putSQLBuffer
+func getSQLBuffer() *sqlBuffer {
+	return sqlBufferPool.Get().(*sqlBuffer)
+}
+
+// putSQLBuffer empties buffer and returns it to the pool
+func putSQLBuffer(buffer *sqlBuffer) {
+	if cap(buffer.buf) > maxPooledSQLBuffer {
+		return
+	}
+	buffer.buf = buffer.buf[:0]
+	sqlBufferPool.Put(buffer)
+}
+
+func (buffer *sqlBuffer) WriteString(s string) (int, error) {
+	buffer.buf = append(buffer.buf, s...)
+	return len(s), nil
+}
+
+func (buffer *sqlBuffer) WriteByte(c byte) error {
+	buffer.buf = append(buffer.buf, c)
+	return nil
+}
+
+func (buffer *sqlBuffer) Len() int {
+	return len(buffer.buf)
+}
+
+// rowBuilder builds the SQL expressions of rows (e.g. gorm.Expr values) into its writer, the
+// statement builds them into its own SQL otherwise
+type rowBuilder struct {
+	clause.Writer
+	stmt *gorm.Statement
+}
+
+func (builder rowBuilder) WriteQuoted(field interface{}) {
+	builder.stmt.QuoteTo(builder.Writer, field)
+}
+
+func (builder rowBuilder) AddVar(writer clause.Writer, vars ...interface{}) {
+	for idx, v := range vars {
+		if idx > 0 {
+			writer.WriteByte(',')
+		}
+
+		switch v := v.(type) {
+		case clause.Column, clause.Table:
+			builder.stmt.AddVar(writer, v)
+		case gorm.Valuer:
+			if reflectValue := reflect.ValueOf(v); reflectValue.Kind() == reflect.Ptr && reflectValue.IsNil() {
+				builder.stmt.AddVar(writer, nil)
+			} else {
+				builder.AddVar(writer, v.GormValue(builder.stmt.Context, builder.stmt.DB))
+			}
+		case clause.Expression:
+			v.Build(rowBuilder{Writer: writer, stmt: builder.stmt})
+		default:
+			builder.stmt.AddVar(writer, v)
+		}
+	}
+}
+
+func (builder rowBuilder) AddError(err error) error {
+	return builder.stmt.AddError(err)
+}
+
+// writeRowsTo writes the binds of rows into a pooled buffer, each row between prefix and suffix
+// and rows separated by separator, the bind variables are appended to the statement vars.
+// The statement SQL is grown once by the size of the rows plus extra and the rows copied
+// into it, instead of growing it row by row through intermediate copies
+func writeRowsTo(db *gorm.DB, rows [][]interface{}, prefix, separator, suffix string, extra int, header func()) {
+	buffer := getSQLBuffer()
+	defer putSQLBuffer(buffer)
+
+	for idx, row := range rows {
+		if idx > 0 {
+			buffer.WriteString(separator)
+		}
+		buffer.WriteString(prefix)
+		addRowVars(db, buffer, row)
+		buffer.WriteString(suffix)
+	}
+
+	db.Statement.SQL.Grow(buffer.Len() + extra)
+	header()
+	db.Statement.SQL.Write(buffer.buf)
+}
+
+// estimateRowBytes estimates the size of a row of values in a statement, its placeholders
+// and casts plus its bind values, as sent to Snowflake
+func estimateRowBytes(row []interface{}) int {
+	size := 2
+	for _, value := range row {
+		size += 2
+		switch v := value.(type) {
+		case string:
+			size += len(v)
+		case []byte:
+			size += len(v)
+		case clause.Expr:
+			size += len(v.SQL)
+		default:
+			// numbers, times and booleans, including their ::NUMBER/::FLOAT cast
+			size += 16
+		}
+	}
+	return size
+}
@@ -0,0 +1,65 @@
+package snowflake
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestWriteRowsTo(t *testing.T) {
+	db := setupMockDB(t)
+	stmt := db.Session(&gorm.Session{DryRun: true}).Model(&TestModel{})
+	stmt.Statement.SQL.Reset()
+	stmt.Statement.Vars = nil
+
+	writeRowsTo(stmt, [][]interface{}{{"John", 25}, {"Jane", 30}}, "(", ",", ")", 0, func() {
+		stmt.Statement.WriteString("VALUES ")
+	})
+
+	if sql := stmt.Statement.SQL.String(); sql != "VALUES (?,?),(?,?)" {
+		t.Errorf("Unexpected SQL: %s", sql)
+	}
+	if len(stmt.Statement.Vars) != 4 || stmt.Statement.Vars[2] != "Jane" {
+		t.Errorf("Unexpected vars: %v", stmt.Statement.Vars)
+	}
+}
+
+func TestWriteRowsToExpressions(t *testing.T) {
+	db := setupMockDB(t)
+	stmt := db.Session(&gorm.Session{DryRun: true}).Model(&TestModel{})
+	stmt.Statement.SQL.Reset()
+	stmt.Statement.Vars = nil
+
+	writeRowsTo(stmt, [][]interface{}{{gorm.Expr("UPPER(?)", "john"), 25}}, "(", ",", ")", 0, func() {
+		stmt.Statement.WriteString("VALUES ")
+	})
+
+	if sql := stmt.Statement.SQL.String(); sql != "VALUES (UPPER(?),?)" {
+		t.Errorf("Expected the expression built into its row, got %s", sql)
+	}
+	if len(stmt.Statement.Vars) != 2 || stmt.Statement.Vars[0] != "john" {
+		t.Errorf("Unexpected vars: %v", stmt.Statement.Vars)
+	}
+}
+
+func TestSQLBufferPool(t *testing.T) {
+	buffer := getSQLBuffer()
+	buffer.WriteString("SELECT ")
+	buffer.WriteByte('1')
+	if string(buffer.buf) != "SELECT 1" || buffer.Len() != 8 {
+		t.Errorf("Unexpected buffer content: %q", buffer.buf)
+	}
+	putSQLBuffer(buffer)
+
+	if buffer = getSQLBuffer(); buffer.Len() != 0 {
+		t.Errorf("Expected an empty buffer from the pool, got %q", buffer.buf)
+	}
+	putSQLBuffer(buffer)
+
+	large := &sqlBuffer{buf: []byte(strings.Repeat("x", maxPooledSQLBuffer+1))}
+	putSQLBuffer(large)
+	if large.Len() == 0 {
+		t.Error("Expected oversized buffers not to be reset into the pool")
+	}
+}
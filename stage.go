@@ -0,0 +1,249 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/snowflakedb/gosnowflake"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidStage is returned when a stage operation cannot run with the given arguments
+var ErrInvalidStage = errors.New("invalid stage")
+
+// StageOptions configures Stage.Create
+type StageOptions struct {
+	// URL is the location of an external stage, e.g. s3://bucket/path/, an internal stage when empty
+	URL string
+	// StorageIntegration is the storage integration authenticating an external stage
+	StorageIntegration string
+	// FileFormat is the default file format of the stage, e.g. TYPE = CSV SKIP_HEADER = 1
+	FileFormat string
+	// Comment is the comment of the stage
+	Comment string
+}
+
+// PutOptions configures Stage.Put and Stage.PutReader
+type PutOptions struct {
+	// Overwrite replaces the staged files of the same name, which are otherwise skipped
+	Overwrite bool
+	// NoCompress uploads the files as is instead of compressing them with gzip (and appending .gz
+	// to their name)
+	NoCompress bool
+	// Parallel is the number of threads uploading the files
+	// Default: 0 (4 threads)
+	Parallel int
+}
+
+// StageTransfer is a row of the output of PUT
+type StageTransfer struct {
+	Source            string
+	Target            string
+	SourceSize        int64
+	TargetSize        int64
+	SourceCompression string
+	TargetCompression string
+	Status            string
+	Message           string
+}
+
+// StageDownload is a row of the output of GET
+type StageDownload struct {
+	File    string
+	Size    int64
+	Status  string
+	Message string
+}
+
+// StagedFile is a row of the output of LIST, Name is prefixed with the stage of the file
+type StagedFile struct {
+	Name         string
+	Size         int64
+	MD5          string
+	LastModified string
+}
+
+// stageRemoved is a row of the output of REMOVE
+type stageRemoved struct {
+	Name   string
+	Result string
+}
+
+// Stage is a named stage or the stage of a table, uploading and downloading files with the PUT
+// and GET file transfers of the driver:
+//
+//	stage, err := snowflake.NamedStage(db, "landing")
+//	err = stage.Create()
+//	transfers, err := stage.Put("/tmp/exports/orders_*.csv", "orders/", snowflake.PutOptions{Overwrite: true})
+//	files, err := stage.List("orders/")
+//
+// Paths are relative to the stage, "" is the whole stage
+type Stage struct {
+	db       *gorm.DB
+	name     string
+	location string
+}
+
+// NamedStage returns the named stage name, optionally qualified by its database and schema
+func NamedStage(db *gorm.DB, name string) (*Stage, error) {
+	if err := validateIdentifier(name); err != nil {
+		return nil, err
+	}
+	return &Stage{db: db, name: name, location: "@" + db.Statement.Quote(name)}, nil
+}
+
+// TableStage returns the stage of the table of model, which exists as long as the table does
+func TableStage(db *gorm.DB, model interface{}) (*Stage, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	return &Stage{db: db, location: "@%" + db.Statement.Quote(stmt.Table)}, nil
+}
+
+// Location returns the stage as written in statements, e.g. @landing or @%orders
+func (s *Stage) Location() string {
+	return s.location
+}
+
+// Create creates the named stage if it does not exist yet
+func (s *Stage) Create(opts ...StageOptions) error {
+	if s.name == "" {
+		return fmt.Errorf("%w: table stages are created with their table", ErrInvalidStage)
+	}
+
+	var options StageOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	sql := "CREATE STAGE IF NOT EXISTS ?"
+	values := []interface{}{clause.Table{Name: s.name}}
+	if options.URL != "" {
+		sql += " URL = " + quoteLiteral(options.URL)
+	}
+	if options.StorageIntegration != "" {
+		if err := validateIdentifier(options.StorageIntegration); err != nil {
+			return err
+		}
+		sql += " STORAGE_INTEGRATION = ?"
+		values = append(values, clause.Table{Name: options.StorageIntegration})
+	}
+	if options.FileFormat != "" {
+		sql += " FILE_FORMAT = (" + options.FileFormat + ")"
+	}
+	if options.Comment != "" {
+		sql += " COMMENT = " + quoteLiteral(options.Comment)
+	}
+	return s.db.Exec(sql, values...).Error
+}
+
+// Drop drops the named stage and its files
+func (s *Stage) Drop() error {
+	if s.name == "" {
+		return fmt.Errorf("%w: table stages are dropped with their table", ErrInvalidStage)
+	}
+	return s.db.Exec("DROP STAGE IF EXISTS ?", clause.Table{Name: s.name}).Error
+}
+
+// Put uploads the local files matching pattern (a path with * and ? wildcards) to path
+func (s *Stage) Put(pattern, path string, opts ...PutOptions) ([]StageTransfer, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("%w: no local file", ErrInvalidStage)
+	}
+	local, err := filepath.Abs(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return s.put(s.db, local, path, opts)
+}
+
+// PutReader uploads the content of reader as the file name of path, without a local file
+func (s *Stage) PutReader(reader io.Reader, name, path string, opts ...PutOptions) ([]StageTransfer, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("%w: file name %q", ErrInvalidStage, name)
+	}
+	db := s.db.WithContext(gosnowflake.WithFileStream(s.db.Statement.Context, reader))
+	return s.put(db, name, path, opts)
+}
+
+// put runs PUT of the local file to path
+func (s *Stage) put(db *gorm.DB, local, path string, opts []PutOptions) ([]StageTransfer, error) {
+	var options PutOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	sql := "PUT " + quoteLiteral("file://"+filepath.ToSlash(local)) + " " + s.path(path)
+	if options.NoCompress {
+		sql += " AUTO_COMPRESS = FALSE"
+	}
+	if options.Overwrite {
+		sql += " OVERWRITE = TRUE"
+	}
+	if options.Parallel > 0 {
+		sql += " PARALLEL = " + strconv.Itoa(options.Parallel)
+	}
+
+	var transfers []StageTransfer
+	return transfers, queryInto(db, sql, nil, &transfers)
+}
+
+// Get downloads the staged files of path (a file or a directory), whose names match the regular
+// expression pattern when not empty, to the local directory dir
+func (s *Stage) Get(path, dir, pattern string) ([]StageDownload, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("%w: no local directory", ErrInvalidStage)
+	}
+	local, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := "GET " + s.path(path) + " " + quoteLiteral("file://"+strings.TrimSuffix(filepath.ToSlash(local), "/")+"/")
+	if pattern != "" {
+		sql += " PATTERN = " + quoteLiteral(pattern)
+	}
+
+	var downloads []StageDownload
+	return downloads, queryInto(s.db, sql, nil, &downloads)
+}
+
+// List returns the staged files of path
+func (s *Stage) List(path string) ([]StagedFile, error) {
+	var files []StagedFile
+	return files, queryInto(s.db, "LIST "+s.path(path), nil, &files)
+}
+
+// Remove removes the staged files of path, whose names match the regular expression pattern when
+// not empty, and returns their names
+func (s *Stage) Remove(path, pattern string) ([]string, error) {
+	sql := "REMOVE " + s.path(path)
+	if pattern != "" {
+		sql += " PATTERN = " + quoteLiteral(pattern)
+	}
+
+	var removed []stageRemoved
+	if err := queryInto(s.db, sql, nil, &removed); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(removed))
+	for _, row := range removed {
+		names = append(names, row.Name)
+	}
+	return names, nil
+}
+
+// path returns path in the stage as written in statements, quoted so it may hold any character
+func (s *Stage) path(path string) string {
+	if path = strings.TrimPrefix(path, "/"); path == "" {
+		return s.location
+	}
+	return quoteLiteral(s.location + "/" + path)
+}
@@ -0,0 +1,165 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStage(t *testing.T) {
+	t.Run("Named stage", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		stage, err := NamedStage(db, "raw.landing")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := stage.Create(StageOptions{
+			URL:                "s3://exports/orders/",
+			StorageIntegration: "s3_exports",
+			FileFormat:         "TYPE = CSV SKIP_HEADER = 1",
+			Comment:            "orders' exports",
+		}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := stage.Drop(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := []string{
+			"CREATE STAGE IF NOT EXISTS raw.landing URL = 's3://exports/orders/' STORAGE_INTEGRATION = s3_exports " +
+				"FILE_FORMAT = (TYPE = CSV SKIP_HEADER = 1) COMMENT = 'orders'' exports'",
+			"DROP STAGE IF EXISTS raw.landing",
+		}
+		queries := fake.queries()
+		if len(queries) != len(expected) || queries[0] != expected[0] || queries[1] != expected[1] {
+			t.Errorf("Expected %v, got %v", expected, queries)
+		}
+	})
+
+	t.Run("Put and Get", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, func(query string, args []interface{}) fakeResult {
+			switch {
+			case strings.HasPrefix(query, "PUT "):
+				return fakeResult{
+					columns: []string{"source", "target", "source_size", "target_size", "source_compression", "target_compression", "status", "message"},
+					rows:    [][]driver.Value{{"orders.csv", "orders.csv.gz", int64(120), int64(64), "NONE", "GZIP", "UPLOADED", ""}},
+				}
+			case strings.HasPrefix(query, "GET "):
+				return fakeResult{
+					columns: []string{"file", "size", "status", "message"},
+					rows:    [][]driver.Value{{"orders/orders.csv.gz", int64(64), "DOWNLOADED", ""}},
+				}
+			}
+			return fakeResult{}
+		})
+
+		stage, err := TableStage(db, &TestModel{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		transfers, err := stage.Put("/tmp/exports/orders.csv", "orders/", PutOptions{Overwrite: true, Parallel: 8})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(transfers) != 1 || transfers[0].Target != "orders.csv.gz" || transfers[0].TargetSize != 64 || transfers[0].Status != "UPLOADED" {
+			t.Errorf("Unexpected transfers %+v", transfers)
+		}
+
+		downloads, err := stage.Get("orders", "/tmp/imports", ".*[.]gz")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(downloads) != 1 || downloads[0].File != "orders/orders.csv.gz" || downloads[0].Size != 64 {
+			t.Errorf("Unexpected downloads %+v", downloads)
+		}
+
+		local, _ := filepath.Abs("/tmp/exports/orders.csv")
+		expected := []string{
+			"PUT 'file://" + filepath.ToSlash(local) + `' '@%"test_models"/orders/' OVERWRITE = TRUE PARALLEL = 8`,
+			`GET '@%"test_models"/orders' 'file:///tmp/imports/' PATTERN = '.*[.]gz'`,
+		}
+		queries := fake.queries()
+		if len(queries) != len(expected) || queries[0] != expected[0] || queries[1] != expected[1] {
+			t.Errorf("Expected %v, got %v", expected, queries)
+		}
+	})
+
+	t.Run("PutReader", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		stage, _ := NamedStage(db, "landing")
+		if _, err := stage.PutReader(strings.NewReader("id,name\n1,a\n"), "orders.csv", "", PutOptions{NoCompress: true}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := stage.PutReader(strings.NewReader(""), "../orders.csv", ""); !errors.Is(err, ErrInvalidStage) {
+			t.Errorf("Expected ErrInvalidStage, got %v", err)
+		}
+
+		expected := "PUT 'file://orders.csv' @landing AUTO_COMPRESS = FALSE"
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("List and Remove", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			switch {
+			case strings.HasPrefix(query, "LIST "):
+				return fakeResult{
+					columns: []string{"name", "size", "md5", "last_modified"},
+					rows:    [][]driver.Value{{"landing/orders/a.csv.gz", int64(64), "9b2f", "Fri, 16 Oct 2026 10:00:00 GMT"}},
+				}
+			case strings.HasPrefix(query, "REMOVE "):
+				return fakeResult{
+					columns: []string{"name", "result"},
+					rows:    [][]driver.Value{{"landing/orders/a.csv.gz", "removed"}, {"landing/orders/b.csv.gz", "removed"}},
+				}
+			}
+			return fakeResult{}
+		})
+
+		stage, _ := NamedStage(db, "landing")
+		files, err := stage.List("orders/")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(files) != 1 || files[0].Name != "landing/orders/a.csv.gz" || files[0].Size != 64 || files[0].MD5 != "9b2f" || files[0].LastModified == "" {
+			t.Errorf("Unexpected files %+v", files)
+		}
+
+		removed, err := stage.Remove("", "orders/.*")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(removed) != 2 || removed[1] != "landing/orders/b.csv.gz" {
+			t.Errorf("Unexpected removed files %v", removed)
+		}
+
+		expected := []string{"LIST '@landing/orders/'", "REMOVE @landing PATTERN = 'orders/.*'"}
+		queries := fake.queries()
+		if len(queries) != len(expected) || queries[0] != expected[0] || queries[1] != expected[1] {
+			t.Errorf("Expected %v, got %v", expected, queries)
+		}
+	})
+
+	t.Run("Table stages are not created", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		stage, _ := TableStage(db, &TestModel{})
+		if err := stage.Create(); !errors.Is(err, ErrInvalidStage) {
+			t.Errorf("Expected ErrInvalidStage, got %v", err)
+		}
+		if err := stage.Drop(); !errors.Is(err, ErrInvalidStage) {
+			t.Errorf("Expected ErrInvalidStage, got %v", err)
+		}
+		if _, err := NamedStage(db, ""); !errors.Is(err, ErrInvalidIdentifier) {
+			t.Errorf("Expected ErrInvalidIdentifier, got %v", err)
+		}
+		if len(fake.statements) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+}
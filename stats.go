@@ -0,0 +1,129 @@
+package snowflake
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Stats describes a statement run by ExecWithStats
+type Stats struct {
+	// QueryID is the Snowflake query ID, e.g. to look the statement up in QUERY_HISTORY
+	QueryID  string
+	Inserted int64
+	Updated  int64
+	Deleted  int64
+	// Duration is the time the statement took, as seen by the client
+	Duration time.Duration
+	// Instance is the Config.InstanceName of the dialector of the DB running the statement
+	Instance string
+}
+
+// ExecWithStats executes sql like db.Exec and returns its query ID and the number of rows it
+// inserted, updated and deleted, for orchestration code that needs more than RowsAffected.
+// The counts of INSERT, UPDATE and DELETE statements come from RowsAffected, those of other
+// statements (MERGE...) are read from their result with RESULT_SCAN.
+// The statement and the lookups run on the same connection, or in the transaction of db
+func ExecWithStats(db *gorm.DB, sql string, vars ...interface{}) (Stats, error) {
+	stats := Stats{Instance: InstanceName(db)}
+
+	run := func(tx *gorm.DB) error {
+		start := time.Now()
+		result := tx.Exec(sql, vars...)
+		stats.Duration = time.Since(start)
+		if result.Error != nil {
+			return result.Error
+		}
+		// read before the next statement, which may run on the same instance
+		rowsAffected := result.RowsAffected
+
+		if err := tx.Raw("SELECT LAST_QUERY_ID()").Row().Scan(&stats.QueryID); err != nil {
+			return err
+		}
+
+		switch statementKeyword(sql) {
+		case "INSERT":
+			stats.Inserted = rowsAffected
+		case "UPDATE":
+			stats.Updated = rowsAffected
+		case "DELETE":
+			stats.Deleted = rowsAffected
+		default:
+			return scanDMLStats(tx, &stats)
+		}
+		return nil
+	}
+
+	tx, release, err := PinConnection(db)
+	if err != nil {
+		return stats, err
+	}
+	defer release()
+	return stats, run(tx)
+}
+
+// scanDMLStats reads the "number of rows inserted/updated/deleted" columns of the result of
+// stats.QueryID, statements without such columns (DDL...) leave the counts to 0
+func scanDMLStats(db *gorm.DB, stats *Stats) error {
+	rows, err := db.Raw("SELECT * FROM TABLE(RESULT_SCAN(?))", stats.QueryID).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	var (
+		values = make([]sql.NullInt64, len(columns))
+		dest   = make([]interface{}, len(columns))
+		counts = make([]*int64, len(columns))
+		found  bool
+	)
+	for idx, column := range columns {
+		column = strings.ToLower(column)
+		switch {
+		case strings.HasPrefix(column, "number of rows inserted"):
+			counts[idx] = &stats.Inserted
+		case strings.HasPrefix(column, "number of rows updated"):
+			counts[idx] = &stats.Updated
+		case strings.HasPrefix(column, "number of rows deleted"):
+			counts[idx] = &stats.Deleted
+		}
+
+		if counts[idx] != nil {
+			dest[idx], found = &values[idx], true
+		} else {
+			dest[idx] = new(interface{})
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		for idx, count := range counts {
+			if count != nil {
+				*count += values[idx].Int64
+			}
+		}
+	}
+	return rows.Err()
+}
+
+// statementKeyword returns the first keyword of query, uppercased
+func statementKeyword(query string) string {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	end := 0
+	for end < len(query) && isIdentifierChar(query[end]) {
+		end++
+	}
+	return strings.ToUpper(query[:end])
+}
@@ -0,0 +1,97 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+func statsHandler(query string, args []interface{}) fakeResult {
+	switch {
+	case strings.HasPrefix(query, "SELECT LAST_QUERY_ID()"):
+		return fakeResult{columns: []string{"LAST_QUERY_ID()"}, rows: [][]driver.Value{{"01b2c3d4-0000-1234"}}}
+	case strings.Contains(query, "RESULT_SCAN"):
+		return fakeResult{
+			columns: []string{"number of rows inserted", "number of rows updated", "number of rows deleted"},
+			rows:    [][]driver.Value{{"3", "5", "1"}},
+		}
+	}
+	return fakeResult{rowsAffected: 9}
+}
+
+func TestExecWithStats(t *testing.T) {
+	t.Run("Reads MERGE counts with RESULT_SCAN", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, statsHandler)
+
+		stats, err := ExecWithStats(db, "MERGE INTO orders USING staged ON orders.id = staged.id WHEN MATCHED THEN UPDATE SET status = ?", "done")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if stats.QueryID != "01b2c3d4-0000-1234" || stats.Inserted != 3 || stats.Updated != 5 || stats.Deleted != 1 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+		for _, statement := range fake.statements {
+			if strings.Contains(statement.query, "RESULT_SCAN") && (len(statement.args) != 1 || statement.args[0] != stats.QueryID) {
+				t.Errorf("Expected RESULT_SCAN of the query ID, got %v", statement.args)
+			}
+		}
+	})
+
+	t.Run("Uses RowsAffected for single kind DML", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, statsHandler)
+
+		stats, err := ExecWithStats(db, "DELETE FROM orders WHERE id = ?", 1)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if stats.Deleted != 9 || stats.Inserted != 0 || stats.Updated != 0 || stats.QueryID == "" {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+		if hasQuery(fake.queries(), "RESULT_SCAN") {
+			t.Errorf("Expected no RESULT_SCAN, got %v", fake.queries())
+		}
+	})
+	t.Run("Runs on a pinned connection", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, statsHandler)
+		tx, release, err := PinConnection(db)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer release()
+
+		if _, err := ExecWithStats(tx, "DELETE FROM orders WHERE id = ?", 1); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(fake.statements) != 2 || fake.statements[0].conn != fake.statements[1].conn {
+			t.Errorf("Expected the statements on one connection, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Passes named vars with BindVarNamed", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{BindVarStyle: BindVarNamed}, statsHandler)
+
+		if _, err := ExecWithStats(db, "DELETE FROM orders WHERE id = ?", 1); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		statement := fake.statements[0]
+		if statement.query != "DELETE FROM orders WHERE id = :p1" || len(statement.names) != 1 || statement.names[0] != "p1" {
+			t.Errorf("Expected the var named p1, got %s %v", statement.query, statement.names)
+		}
+	})
+}
+
+func TestStatementKeyword(t *testing.T) {
+	tests := map[string]string{
+		"  insert into t values (1)": "INSERT",
+		"(SELECT 1)":                 "SELECT",
+		"MERGE INTO t":               "MERGE",
+		"":                           "",
+	}
+	for query, expected := range tests {
+		if keyword := statementKeyword(query); keyword != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, query, keyword)
+		}
+	}
+}
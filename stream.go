@@ -0,0 +1,96 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultStreamBatchSize     = 1000
+	defaultStreamFlushInterval = time.Second
+)
+
+// StreamUpsertOptions configures StreamUpsert
+type StreamUpsertOptions struct {
+	// BatchSize is the maximum number of rows per MERGE statement (default 1000)
+	BatchSize int
+	// FlushInterval is the maximum time a row waits in a partial batch before it is flushed (default 1s)
+	FlushInterval time.Duration
+	// OnConflict is the upsert clause applied to every batch (default: update all columns)
+	OnConflict *clause.OnConflict
+	// OnFlush, if set, is called after every flushed batch with its size
+	OnFlush func(rows int)
+}
+
+// StreamUpsert reads rows from the channel and upserts them in batches bounded by
+// opts.BatchSize and opts.FlushInterval, for event-driven ingestion.
+// Rows are only read while no batch is being written, so a slow warehouse applies
+// backpressure to the producer. The pending batch is flushed when the channel is closed,
+// StreamUpsert then returns nil. It stops at the first failed batch or when ctx is done,
+// rows of the pending batch are not written in that case.
+func StreamUpsert[T any](ctx context.Context, db *gorm.DB, rows <-chan T, opts StreamUpsertOptions) error {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultStreamBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultStreamFlushInterval
+	}
+
+	onConflict := clause.OnConflict{UpdateAll: true}
+	if opts.OnConflict != nil {
+		onConflict = *opts.OnConflict
+	}
+
+	var (
+		tx      = db.WithContext(ctx).Clauses(onConflict)
+		batch   = make([]T, 0, opts.BatchSize)
+		written int
+		ticker  = time.NewTicker(opts.FlushInterval)
+	)
+	defer ticker.Stop()
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := tx.Create(&batch).Error; err != nil {
+			return fmt.Errorf("stream upsert failed after %d rows: %w", written, err)
+		}
+
+		written += len(batch)
+		if opts.OnFlush != nil {
+			opts.OnFlush(len(batch))
+		}
+		// a new slice is required as the previous one may still be referenced by the statement
+		batch = make([]T, 0, opts.BatchSize)
+		ticker.Reset(opts.FlushInterval)
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case row, ok := <-rows:
+			if !ok {
+				return flush()
+			}
+
+			batch = append(batch, row)
+			if len(batch) >= opts.BatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		case <-ticker.C:
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func countQueries(queries []string, prefix string) int {
+	count := 0
+	for _, query := range queries {
+		if strings.HasPrefix(query, prefix) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestStreamUpsert(t *testing.T) {
+	t.Run("Size bounded batches and flush on close", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		rows := make(chan TestModel)
+		go func() {
+			for i := 1; i <= 5; i++ {
+				rows <- TestModel{ID: uint(i), Name: "name", Age: i}
+			}
+			close(rows)
+		}()
+
+		var flushed []int
+		err := StreamUpsert(context.Background(), db, rows, StreamUpsertOptions{
+			BatchSize:     2,
+			FlushInterval: time.Hour,
+			OnFlush:       func(n int) { flushed = append(flushed, n) },
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if merges := countQueries(fake.queries(), "MERGE INTO"); merges != 3 {
+			t.Errorf("Expected 3 MERGE statements, got %d: %v", merges, fake.queries())
+		}
+		if len(flushed) != 3 || flushed[0] != 2 || flushed[2] != 1 {
+			t.Errorf("Expected flushes of 2,2,1 rows, got %v", flushed)
+		}
+	})
+
+	t.Run("Time bounded batches", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		rows := make(chan TestModel)
+		flushed := make(chan int, 1)
+		done := make(chan error)
+		go func() {
+			done <- StreamUpsert(context.Background(), db, rows, StreamUpsertOptions{
+				BatchSize:     100,
+				FlushInterval: 10 * time.Millisecond,
+				OnFlush:       func(n int) { flushed <- n },
+			})
+		}()
+
+		rows <- TestModel{ID: 1, Name: "name"}
+		select {
+		case n := <-flushed:
+			if n != 1 {
+				t.Errorf("Expected 1 row flushed, got %d", n)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected partial batch to be flushed after FlushInterval")
+		}
+
+		close(rows)
+		if err := <-done; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if merges := countQueries(fake.queries(), "MERGE INTO"); merges != 1 {
+			t.Errorf("Expected 1 MERGE statement, got %d", merges)
+		}
+	})
+
+	t.Run("Context cancellation", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := StreamUpsert(ctx, db, make(chan TestModel), StreamUpsertOptions{})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("Stops on failed batch", func(t *testing.T) {
+		failure := errors.New("warehouse suspended")
+		db, _ := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			if strings.HasPrefix(query, "MERGE") {
+				return fakeResult{err: failure}
+			}
+			return fakeResult{}
+		})
+
+		rows := make(chan TestModel, 1)
+		rows <- TestModel{ID: 1, Name: "name"}
+		close(rows)
+
+		err := StreamUpsert(context.Background(), db, rows, StreamUpsertOptions{})
+		if !errors.Is(err, failure) {
+			t.Errorf("Expected batch error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package snowflake
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ShowStream is a row of SHOW STREAMS
+type ShowStream struct {
+	CreatedOn     time.Time
+	Name          string
+	DatabaseName  string
+	SchemaName    string
+	Owner         string
+	Comment       string
+	TableName     string
+	SourceType    string
+	BaseTables    string
+	Type          string
+	Stale         string
+	Mode          string
+	StaleAfter    time.Time
+	InvalidReason string
+}
+
+// CreateStream creates the stream name on the table of model if it does not exist yet, recording
+// its inserts, updates and deletes, or only its inserts when appendOnly (cheaper, for tables
+// that are only appended to). An existing stream keeps its offset
+func CreateStream(db *gorm.DB, model interface{}, name string, appendOnly bool) error {
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+
+	sql := "CREATE STREAM IF NOT EXISTS ? ON TABLE ?"
+	if appendOnly {
+		sql += " APPEND_ONLY = TRUE"
+	}
+	return db.Exec(sql, clause.Table{Name: name}, clause.Table{Name: stmt.Table}).Error
+}
+
+// DropStream drops the stream name
+func DropStream(db *gorm.DB, name string) error {
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+	return db.Exec("DROP STREAM IF EXISTS ?", clause.Table{Name: name}).Error
+}
+
+// ShowStreams returns the streams of the current schema
+func ShowStreams(db *gorm.DB) ([]ShowStream, error) {
+	var streams []ShowStream
+	return streams, Show(db, "STREAMS IN SCHEMA", &streams)
+}
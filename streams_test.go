@@ -0,0 +1,65 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamHelpers(t *testing.T) {
+	t.Run("CreateStream and DropStream", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		if err := CreateStream(db, &TestModel{}, "test_models_stream", false); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := CreateStream(db, &TestModel{}, "test_models_inserts", true); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := DropStream(db, "test_models_stream"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := []string{
+			`CREATE STREAM IF NOT EXISTS "test_models_stream" ON TABLE "test_models"`,
+			`CREATE STREAM IF NOT EXISTS "test_models_inserts" ON TABLE "test_models" APPEND_ONLY = TRUE`,
+			`DROP STREAM IF EXISTS "test_models_stream"`,
+		}
+		if queries := fake.queries(); strings.Join(queries, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected %v, got %v", expected, queries)
+		}
+	})
+
+	t.Run("ShowStreams", func(t *testing.T) {
+		createdOn := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		db, _ := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			if query != "SHOW STREAMS IN SCHEMA" {
+				return fakeResult{}
+			}
+			return fakeResult{
+				columns: []string{"created_on", "name", "table_name", "type", "stale", "mode", "stale_after"},
+				rows:    [][]driver.Value{{createdOn, "ORDERS_STREAM", "DB.PUBLIC.ORDERS", "DELTA", "false", "APPEND_ONLY", nil}},
+			}
+		})
+
+		streams, err := ShowStreams(db)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(streams) != 1 || streams[0].Name != "ORDERS_STREAM" || streams[0].Mode != "APPEND_ONLY" || streams[0].TableName != "DB.PUBLIC.ORDERS" {
+			t.Errorf("Unexpected streams: %+v", streams)
+		}
+	})
+
+	t.Run("Invalid name", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		if err := CreateStream(db, &TestModel{}, "", false); err == nil {
+			t.Error("Expected an error for an empty name")
+		}
+		if len(fake.statements) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+}
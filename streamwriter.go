@@ -0,0 +1,210 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// streamWriterKey marks the Create of the rows flushed by a StreamWriter, which are bulk
+	// loaded whatever their number
+	streamWriterKey = "snowflake:stream_writer"
+
+	defaultStreamWriterFlushSize = 10000
+)
+
+// ErrStreamWriterClosed is returned when rows are written to a closed StreamWriter
+var ErrStreamWriterClosed = errors.New("stream writer is closed")
+
+// StreamWriterOptions configures NewStreamWriter
+type StreamWriterOptions struct {
+	// FlushSize is the number of buffered rows that triggers a flush
+	// Default: 10000
+	FlushSize int
+	// FlushInterval is the maximum time a row stays buffered before it is flushed
+	// Default: 1s
+	FlushInterval time.Duration
+	// OnFlush, if set, is called after every flush with the number of rows loaded
+	OnFlush func(rows int)
+	// OnError, if set, is called with the error and the rows (a slice of the model) of every
+	// failed flush, so they can be retried or dead-lettered. The rows are not written again
+	OnError func(err error, rows interface{})
+}
+
+// StreamWriter buffers rows of a model and loads them in micro-batches through the stage of
+// the table (PUT and COPY INTO, as Config.BulkLoadThreshold does), for high throughput event
+// ingestion where an INSERT per row or per small batch is far too slow. See NewStreamWriter
+type StreamWriter struct {
+	db        *gorm.DB
+	opts      StreamWriterOptions
+	sliceType reflect.Type
+
+	mu     sync.Mutex
+	rows   reflect.Value
+	closed bool
+
+	// flushMu serializes the flushes, a single load runs at a time
+	flushMu sync.Mutex
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// NewStreamWriter returns a StreamWriter loading rows of model into its table, flushed every
+// opts.FlushSize rows and opts.FlushInterval:
+//
+//	writer, err := snowflake.NewStreamWriter(db, &Event{}, snowflake.StreamWriterOptions{
+//		FlushSize: 50000,
+//		OnError:   func(err error, rows interface{}) { deadLetter(rows.([]Event), err) },
+//	})
+//	...
+//	err = writer.Write(&event)
+//	...
+//	err = writer.Close()
+//
+// Flushes triggered by Write, Flush and Close return their error, the ones of the interval
+// report it to opts.OnError only. Rows are created with the hooks of the model, the default
+// values set by the database (e.g. auto increment IDs) are not read back.
+// Snowpipe Streaming is not available through the Go driver, rows are loaded by COPY INTO
+func NewStreamWriter(db *gorm.DB, model interface{}, opts StreamWriterOptions) (*StreamWriter, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+
+	if opts.FlushSize <= 0 {
+		opts.FlushSize = defaultStreamWriterFlushSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultStreamFlushInterval
+	}
+
+	writer := &StreamWriter{
+		db:        db.Session(&gorm.Session{NewDB: true}).Set(streamWriterKey, true).Session(&gorm.Session{}),
+		opts:      opts,
+		sliceType: reflect.SliceOf(stmt.Schema.ModelType),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	writer.rows = reflect.MakeSlice(writer.sliceType, 0, opts.FlushSize)
+
+	go writer.flushPeriodically()
+	return writer, nil
+}
+
+// Write buffers rows, structs of the model of the writer, pointers to them or slices of
+// either, and flushes the buffer once it holds FlushSize rows
+func (writer *StreamWriter) Write(rows ...interface{}) error {
+	modelType := writer.sliceType.Elem()
+	values := make([]reflect.Value, 0, len(rows))
+	for _, row := range rows {
+		value := reflect.Indirect(reflect.ValueOf(row))
+		switch {
+		case value.IsValid() && value.Type() == modelType:
+			values = append(values, value)
+		case value.IsValid() && (value.Kind() == reflect.Slice || value.Kind() == reflect.Array):
+			for idx := 0; idx < value.Len(); idx++ {
+				elem := reflect.Indirect(value.Index(idx))
+				if !elem.IsValid() || elem.Type() != modelType {
+					return fmt.Errorf("stream writer of %s cannot write %T", modelType, value.Index(idx).Interface())
+				}
+				values = append(values, elem)
+			}
+		default:
+			return fmt.Errorf("stream writer of %s cannot write %T", modelType, row)
+		}
+	}
+
+	writer.mu.Lock()
+	if writer.closed {
+		writer.mu.Unlock()
+		return ErrStreamWriterClosed
+	}
+	writer.rows = reflect.Append(writer.rows, values...)
+	if writer.rows.Len() < writer.opts.FlushSize {
+		writer.mu.Unlock()
+		return nil
+	}
+	batch := writer.takeRows()
+	writer.mu.Unlock()
+	return writer.flush(batch)
+}
+
+// Flush loads the buffered rows
+func (writer *StreamWriter) Flush() error {
+	writer.mu.Lock()
+	batch := writer.takeRows()
+	writer.mu.Unlock()
+	return writer.flush(batch)
+}
+
+// Close stops the periodic flushes and loads the buffered rows, the writer cannot be written
+// to afterwards
+func (writer *StreamWriter) Close() error {
+	writer.mu.Lock()
+	if writer.closed {
+		writer.mu.Unlock()
+		return nil
+	}
+	writer.closed = true
+	writer.mu.Unlock()
+
+	close(writer.done)
+	<-writer.stopped
+	return writer.Flush()
+}
+
+// flushPeriodically flushes the buffered rows every FlushInterval until the writer is closed
+func (writer *StreamWriter) flushPeriodically() {
+	defer close(writer.stopped)
+
+	ticker := time.NewTicker(writer.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-writer.done:
+			return
+		case <-ticker.C:
+			// the error is reported to OnError
+			_ = writer.Flush()
+		}
+	}
+}
+
+// takeRows returns the buffered rows and empties the buffer, writer.mu must be held
+func (writer *StreamWriter) takeRows() reflect.Value {
+	batch := writer.rows
+	if batch.Len() > 0 {
+		writer.rows = reflect.MakeSlice(writer.sliceType, 0, writer.opts.FlushSize)
+	}
+	return batch
+}
+
+// flush loads batch into the table of the writer
+func (writer *StreamWriter) flush(batch reflect.Value) error {
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	writer.flushMu.Lock()
+	defer writer.flushMu.Unlock()
+
+	rows := reflect.New(writer.sliceType)
+	rows.Elem().Set(batch)
+	if err := writer.db.Create(rows.Interface()).Error; err != nil {
+		err = fmt.Errorf("stream writer failed to load %d rows: %w", batch.Len(), err)
+		if writer.opts.OnError != nil {
+			writer.opts.OnError(err, batch.Interface())
+		}
+		return err
+	}
+
+	if writer.opts.OnFlush != nil {
+		writer.opts.OnFlush(batch.Len())
+	}
+	return nil
+}
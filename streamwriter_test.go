@@ -0,0 +1,142 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamWriter(t *testing.T) {
+	copyHandler := func(failure error) func(query string, args []interface{}) fakeResult {
+		return func(query string, args []interface{}) fakeResult {
+			if strings.HasPrefix(query, "COPY INTO ") {
+				if failure != nil {
+					return fakeResult{err: failure}
+				}
+				return fakeResult{
+					columns: []string{"file", "status", "rows_parsed", "rows_loaded"},
+					rows:    [][]driver.Value{{"x.csv.gz", "LOADED", int64(1), int64(1)}},
+				}
+			}
+			return fakeResult{}
+		}
+	}
+
+	t.Run("Flushes every FlushSize rows and on Close", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, copyHandler(nil))
+
+		var flushed []int
+		writer, err := NewStreamWriter(db, &TestModel{}, StreamWriterOptions{
+			FlushSize:     2,
+			FlushInterval: time.Hour,
+			OnFlush:       func(rows int) { flushed = append(flushed, rows) },
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if err := writer.Write(&TestModel{Name: "a"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if queries := fake.queries(); len(queries) != 0 {
+			t.Errorf("Expected the row to be buffered, got %v", queries)
+		}
+
+		if err := writer.Write([]TestModel{{Name: "b"}, {Name: "c"}}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := writer.Write(TestModel{Name: "d"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		queries := fake.queries()
+		if countQueries(queries, "PUT ") != 2 || countQueries(queries, "COPY INTO ") != 2 {
+			t.Errorf("Expected two loads, got %v", queries)
+		}
+		if hasQuery(queries, "INSERT") || hasQuery(queries, "CHANGES(") {
+			t.Errorf("Expected no INSERT nor default values query, got %v", queries)
+		}
+		if len(flushed) != 2 || flushed[0] != 3 || flushed[1] != 1 {
+			t.Errorf("Expected flushes of 3 and 1 rows, got %v", flushed)
+		}
+
+		if err := writer.Write(&TestModel{Name: "e"}); !errors.Is(err, ErrStreamWriterClosed) {
+			t.Errorf("Expected ErrStreamWriterClosed, got %v", err)
+		}
+	})
+
+	t.Run("Flushes every FlushInterval", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, copyHandler(nil))
+
+		flushed := make(chan int, 1)
+		writer, err := NewStreamWriter(db, &TestModel{}, StreamWriterOptions{
+			FlushInterval: 10 * time.Millisecond,
+			OnFlush:       func(rows int) { flushed <- rows },
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer writer.Close()
+
+		if err := writer.Write(&TestModel{Name: "a"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		select {
+		case rows := <-flushed:
+			if rows != 1 {
+				t.Errorf("Expected 1 row, got %d", rows)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected a periodic flush, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Failed flushes are reported", func(t *testing.T) {
+		failure := errors.New("copy failed")
+		db, _ := setupFakeDB(t, Config{}, copyHandler(failure))
+
+		var failed interface{}
+		writer, err := NewStreamWriter(db, &TestModel{}, StreamWriterOptions{
+			FlushInterval: time.Hour,
+			OnError:       func(err error, rows interface{}) { failed = rows },
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer writer.Close()
+
+		if err := writer.Write(&TestModel{Name: "a"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := writer.Flush(); !errors.Is(err, failure) {
+			t.Errorf("Expected the COPY error, got %v", err)
+		}
+		if rows, ok := failed.([]TestModel); !ok || len(rows) != 1 || rows[0].Name != "a" {
+			t.Errorf("Expected the failed rows, got %#v", failed)
+		}
+	})
+
+	t.Run("Rows of other models", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, copyHandler(nil))
+
+		writer, err := NewStreamWriter(db, &TestModel{}, StreamWriterOptions{FlushInterval: time.Hour})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if err := writer.Write(&TestModel{Name: "a"}, "b"); err == nil {
+			t.Error("Expected an error")
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if queries := fake.queries(); len(queries) != 0 {
+			t.Errorf("Expected no row to be written, got %v", queries)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrStrictMode is returned when Config.StrictMode is set for GORM features Snowflake would
+// silently ignore
+var ErrStrictMode = errors.New("unsupported by snowflake")
+
+// isStrict reports whether the dialector d is in strict mode
+func isStrict(d gorm.Dialector) bool {
+	config := dialectorConfig(d)
+	return config != nil && config.StrictMode
+}
+
+// strictModel returns ErrStrictMode in strict mode for the declarations of the model of stmt that
+// Snowflake ignores: indexes without Config.IndexStrategy and, on tables other than hybrid tables,
+// unique columns
+func (m Migrator) strictModel(stmt *gorm.Statement) error {
+	if !isStrict(m.Dialector) {
+		return nil
+	}
+
+	if m.indexStrategy() == IndexIgnore {
+		for _, index := range stmt.Schema.ParseIndexes() {
+			return fmt.Errorf("%w: index %s of %s is not created, set Config.IndexStrategy", ErrStrictMode, index.Name, stmt.Table)
+		}
+	}
+	tableType, err := m.tableType(stmt)
+	if err != nil {
+		return err
+	}
+	if tableType != TableHybrid {
+		for _, dbName := range stmt.Schema.DBNames {
+			if field := stmt.Schema.FieldsByDBName[dbName]; field.Unique {
+				return fmt.Errorf("%w: unique constraint of %s.%s is not enforced, only hybrid tables enforce it", ErrStrictMode, stmt.Table, dbName)
+			}
+		}
+	}
+	return nil
+}
+
+// strictForeignKey returns ErrStrictMode in strict mode for the foreign key constraint of a table
+// other than a hybrid table, which Snowflake does not enforce
+func (m Migrator) strictForeignKey(stmt *gorm.Statement, constraint *schema.Constraint) error {
+	if !isStrict(m.Dialector) {
+		return nil
+	}
+
+	tableType, err := m.tableType(stmt)
+	if err != nil {
+		return err
+	}
+	if tableType != TableHybrid {
+		return fmt.Errorf("%w: foreign key %s of %s is not enforced, only hybrid tables enforce it, see DisableForeignKeyConstraintWhenMigrating",
+			ErrStrictMode, constraint.Name, stmt.Table)
+	}
+	return nil
+}
+
+// strictIndex returns ErrStrictMode in strict mode for the index operation of the Migrator
+func (m Migrator) strictIndex(operation, name string) error {
+	if isStrict(m.Dialector) {
+		return fmt.Errorf("%w: %s of index %s, Snowflake has no indexes", ErrStrictMode, operation, name)
+	}
+	return nil
+}
+
+// strictLocking returns ErrStrictMode in strict mode for the locking clause of a query on a table
+// other than a hybrid table, which has no row locks
+func strictLocking(db *gorm.DB) error {
+	if !isStrict(db.Dialector) {
+		return nil
+	}
+
+	strength := "locking"
+	if locking, ok := db.Statement.Clauses["FOR"].Expression.(clause.Locking); ok {
+		strength = "FOR " + locking.Strength
+	}
+	return fmt.Errorf("%w: %s of %s, only hybrid tables have row locks", ErrStrictMode, strength, db.Statement.Table)
+}
@@ -0,0 +1,104 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type strictCustomer struct {
+	ID    uint   `gorm:"primaryKey"`
+	Email string `gorm:"size:100;unique"`
+}
+
+type strictOrder struct {
+	ID         uint `gorm:"primaryKey"`
+	CustomerID uint
+	Customer   strictCustomer
+}
+
+func TestStrictMode(t *testing.T) {
+	t.Run("Savepoints", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{StrictMode: true}, nil)
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return tx.Transaction(func(tx *gorm.DB) error { return nil })
+		})
+		if !errors.Is(err, ErrStrictMode) {
+			t.Errorf("Expected ErrStrictMode, got %v", err)
+		}
+	})
+
+	t.Run("Index operations", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{StrictMode: true}, nil)
+
+		if err := db.Migrator().CreateIndex(&TestModel{}, "idx_name"); !errors.Is(err, ErrStrictMode) {
+			t.Errorf("Expected ErrStrictMode, got %v", err)
+		}
+		if err := db.Migrator().DropIndex(&TestModel{}, "idx_name"); !errors.Is(err, ErrStrictMode) {
+			t.Errorf("Expected ErrStrictMode, got %v", err)
+		}
+		if err := db.Migrator().CreateTable(&IndexedModel{}); !errors.Is(err, ErrStrictMode) {
+			t.Errorf("Expected ErrStrictMode for the indexes, got %v", err)
+		}
+		if len(fake.statements) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+
+		db, _ = setupFakeDB(t, Config{StrictMode: true, IndexStrategy: IndexClusterByFirstIndex}, nil)
+		if err := db.Migrator().CreateTable(&IndexedModel{}); err != nil {
+			t.Errorf("Expected the indexes to be migrated with IndexStrategy, got %v", err)
+		}
+	})
+
+	t.Run("Constraints", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{StrictMode: true}, nil)
+
+		if err := db.Migrator().CreateTable(&strictCustomer{}); !errors.Is(err, ErrStrictMode) {
+			t.Errorf("Expected ErrStrictMode for the unique column, got %v", err)
+		}
+		if err := WithTableType(db, TableHybrid).Migrator().CreateTable(&strictCustomer{}); err != nil {
+			t.Errorf("Expected hybrid tables to enforce unique columns, got %v", err)
+		}
+		if err := db.Migrator().CreateTable(&strictOrder{}); !errors.Is(err, ErrStrictMode) {
+			t.Errorf("Expected ErrStrictMode for the foreign key, got %v", err)
+		}
+		if err := db.Migrator().CreateConstraint(&strictOrder{}, "Customer"); !errors.Is(err, ErrStrictMode) {
+			t.Errorf("Expected ErrStrictMode for the foreign key, got %v", err)
+		}
+		db.Config.DisableForeignKeyConstraintWhenMigrating = true
+		if err := db.Migrator().CreateTable(&strictOrder{}); err != nil {
+			t.Errorf("Expected no foreign key to be created, got %v", err)
+		}
+	})
+
+	t.Run("Locking", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{StrictMode: true}, nil)
+		forUpdate := clause.Locking{Strength: clause.LockingStrengthUpdate}
+
+		if err := db.Clauses(forUpdate).Find(&[]TestModel{}).Error; !errors.Is(err, ErrStrictMode) {
+			t.Errorf("Expected ErrStrictMode, got %v", err)
+		}
+		if len(fake.statements) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+		if err := db.Clauses(forUpdate).Find(&[]hybridAccount{}).Error; err != nil {
+			t.Errorf("Expected hybrid tables to be locked, got %v", err)
+		}
+	})
+
+	t.Run("Ignored by default", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, nil)
+
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			return tx.Transaction(func(tx *gorm.DB) error { return nil })
+		}); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if err := db.Migrator().CreateTable(&strictCustomer{}, &IndexedModel{}); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package snowflake
+
+import (
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// rewrittenTableKey holds the table of a statement rewritten by rewriteStatementTable
+const rewrittenTableKey = "snowflake:rewritten_table"
+
+// tableNameRewriter applies Config.TableNameRewriter to the table names of the naming strategy
+type tableNameRewriter struct {
+	schema.Namer
+	rewrite func(string) string
+}
+
+func (namer tableNameRewriter) TableName(table string) string {
+	return namer.rewrite(namer.Namer.TableName(table))
+}
+
+func (namer tableNameRewriter) JoinTableName(joinTable string) string {
+	return namer.rewrite(namer.Namer.JoinTableName(joinTable))
+}
+
+// registerTableNameCallbacks registers RewriteTableName first on the processors building
+// statements of a model or a table
+func registerTableNameCallbacks(db *gorm.DB) {
+	callback := db.Callback()
+	_ = callback.Create().Before("*").Register("snowflake:rewrite_table_name", RewriteTableName)
+	_ = callback.Query().Before("*").Register("snowflake:rewrite_table_name", RewriteTableName)
+	_ = callback.Update().Before("*").Register("snowflake:rewrite_table_name", RewriteTableName)
+	_ = callback.Delete().Before("*").Register("snowflake:rewrite_table_name", RewriteTableName)
+	_ = callback.Row().Before("*").Register("snowflake:rewrite_table_name", RewriteTableName)
+}
+
+// RewriteTableName applies Config.TableNameRewriter to the table of the statement when it does
+// not come from the naming strategy, which rewrites its names: the TableName of the model or
+// the table given to db.Table
+func RewriteTableName(db *gorm.DB) {
+	if config := dialectorConfig(db.Dialector); config != nil && config.TableNameRewriter != nil {
+		rewriteStatementTable(db.Statement, config.TableNameRewriter)
+	}
+}
+
+// rewriteStatementTable rewrites the table of stmt named by the TableName of its model or given
+// to db.Table, once: statements derived from a rewritten one keep its table. Table expressions
+// other than a name, e.g. with an alias, are kept
+func rewriteStatementTable(stmt *gorm.Statement, rewrite func(string) string) {
+	if stmt.Table == "" {
+		return
+	}
+	if rewritten, ok := stmt.Settings.Load(rewrittenTableKey); ok && rewritten == stmt.Table {
+		return
+	}
+
+	table := rewrite(stmt.Table)
+	if stmt.TableExpr != nil {
+		quoted := stmt.Quote(stmt.Table)
+		switch {
+		case len(stmt.TableExpr.Vars) > 0:
+			return
+		case stmt.TableExpr.SQL == quoted:
+			stmt.TableExpr = &clause.Expr{SQL: stmt.Quote(table)}
+		case strings.HasSuffix(stmt.TableExpr.SQL, "."+quoted):
+			prefix := strings.TrimSuffix(stmt.TableExpr.SQL, quoted)
+			stmt.TableExpr = &clause.Expr{SQL: prefix + stmt.Quote(table)}
+		default:
+			return
+		}
+	} else if stmt.Schema == nil || stmt.Table != stmt.Schema.Table || !namedByModel(stmt.Schema) {
+		return
+	}
+
+	stmt.Table = table
+	stmt.Settings.Store(rewrittenTableKey, table)
+}
+
+// namedByModel reports whether the table of sch is named by a TableName method of its model
+func namedByModel(sch *schema.Schema) bool {
+	switch reflect.New(sch.ModelType).Interface().(type) {
+	case schema.Tabler, schema.TablerWithNamer:
+		return true
+	}
+	return false
+}
+
+// qualifiedJoinTableNamer names the join tables of many2many tags qualified by their schema, e.g.
+// many2many:analytics.user_tags, by naming their table only: the schema is kept as is
+type qualifiedJoinTableNamer struct {
+	schema.Namer
+}
+
+func (namer qualifiedJoinTableNamer) JoinTableName(joinTable string) string {
+	if idx := strings.LastIndexByte(joinTable, '.'); idx > 0 {
+		return joinTable[:idx+1] + namer.Namer.JoinTableName(joinTable[idx+1:])
+	}
+	return namer.Namer.JoinTableName(joinTable)
+}
@@ -0,0 +1,130 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type namedTableModel struct {
+	ID   uint
+	Name string
+}
+
+func (namedTableModel) TableName() string { return "named_table" }
+
+func TestTableNameRewriter(t *testing.T) {
+	db, fake := setupFakeDB(t, Config{
+		TableNameRewriter: func(table string) string { return "dev_" + table },
+	}, nil)
+
+	if err := db.Create(&TestModel{Name: "a"}).Error; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !hasQuery(fake.queries(), "INSERT INTO dev_test_models") || !hasQuery(fake.queries(), "FROM dev_test_models CHANGES(") {
+		t.Errorf("Expected rewritten table in INSERT and CHANGES, got %v", fake.queries())
+	}
+
+	db.Where("name = ?", "a").Find(&[]TestModel{})
+	if !hasQuery(fake.queries(), "SELECT * FROM dev_test_models WHERE") {
+		t.Errorf("Expected rewritten table in SELECT, got %v", fake.queries())
+	}
+
+	db.Migrator().HasTable(&TestModel{})
+	if last := fake.statements[len(fake.statements)-1]; len(last.args) == 0 || last.args[0] != "DEV_TEST_MODELS" {
+		t.Errorf("Expected rewritten table in the migrator, got %v", last.args)
+	}
+
+	db.Where("name = ?", "a").Find(&[]namedTableModel{})
+	if !hasQuery(fake.queries(), "SELECT * FROM dev_named_table WHERE name = ?") {
+		t.Errorf("Expected the TableName rewritten, got %v", fake.queries())
+	}
+
+	db.Model(&namedTableModel{ID: 1}).Update("name", "b")
+	if !hasQuery(fake.queries(), "UPDATE dev_named_table SET name=? WHERE id = ?") {
+		t.Errorf("Expected the TableName rewritten in UPDATE, got %v", fake.queries())
+	}
+
+	db.Migrator().HasTable(&namedTableModel{})
+	if last := fake.statements[len(fake.statements)-1]; len(last.args) == 0 || last.args[0] != "DEV_NAMED_TABLE" {
+		t.Errorf("Expected the TableName rewritten in the migrator, got %v", last.args)
+	}
+
+	var count int64
+	db.Table("events").Count(&count)
+	db.Table("ANALYTICS.events").Where("id = ?", 1).Delete(&map[string]interface{}{})
+	db.Table("events e").Find(&[]map[string]interface{}{})
+	for _, expected := range []string{"FROM dev_events", "DELETE FROM analytics.dev_events WHERE id = ?", "FROM events e"} {
+		if !hasQuery(fake.queries(), expected) {
+			t.Errorf("Expected %s, got %v", expected, fake.queries())
+		}
+	}
+	if hasQuery(fake.queries(), "dev_dev_") {
+		t.Errorf("Expected the tables rewritten once, got %v", fake.queries())
+	}
+}
+
+type analyticsTag struct {
+	ID   uint
+	Name string
+}
+
+type analyticsUser struct {
+	ID   uint
+	Name string
+	Tags []analyticsTag `gorm:"many2many:ANALYTICS.user_tags"`
+}
+
+func TestQualifiedJoinTable(t *testing.T) {
+	t.Run("Migrator", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			if strings.Contains(query, "count(*)") {
+				return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(0)}}}
+			}
+			return fakeResult{}
+		})
+
+		if err := db.AutoMigrate(&analyticsUser{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !hasQuery(fake.queries(), "CREATE TABLE analytics.user_tags (") {
+			t.Errorf("Expected the join table created in its schema, got %v", fake.queries())
+		}
+
+		var lookup *fakeStatement
+		for idx := range fake.statements {
+			if statement := &fake.statements[idx]; strings.Contains(statement.query, "FROM information_schema.tables WHERE (table_schema = ? AND table_name = ?)") {
+				lookup = statement
+				break
+			}
+		}
+		if lookup == nil || len(lookup.args) < 2 || lookup.args[0] != "ANALYTICS" || lookup.args[1] != "USER_TAGS" {
+			t.Errorf("Expected the join table looked up in its schema, got %v", fake.statements)
+		}
+	})
+
+	t.Run("Quoted association", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		db.Model(&analyticsUser{ID: 1}).Association("Tags").Find(&[]analyticsTag{})
+		if !hasQuery(fake.queries(), `JOIN "ANALYTICS"."user_tags" ON`) {
+			t.Errorf("Expected the qualified join table quoted, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Rewriter", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{
+			TableNameRewriter: func(table string) string { return "dev_" + table },
+		}, nil)
+
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(&analyticsUser{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if table := stmt.Schema.Relationships.Relations["Tags"].JoinTable.Table; table != "ANALYTICS.dev_user_tags" {
+			t.Errorf("Expected the table rewritten and the schema kept, got %s", table)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package snowflake
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// TableOptions are the options of the table of a model, see TableOptioner
+type TableOptions struct {
+	// DataRetentionDays sets DATA_RETENTION_TIME_IN_DAYS (Time Travel), nil keeps the value
+	// inherited from the schema
+	DataRetentionDays *int
+	// ChangeTracking sets CHANGE_TRACKING, nil enables it when the default values of inserted rows
+	// are read back from CHANGES (no Config.DefaultValueFetcher, DefaultValueChanges strategy).
+	// Disabling it breaks that read back
+	ChangeTracking *bool
+}
+
+// TableOptioner is implemented by models setting the options of their table, written by
+// CreateTable and kept in sync by AutoMigrate:
+//
+//	func (Event) TableOptions() snowflake.TableOptions {
+//		days := 1
+//		return snowflake.TableOptions{DataRetentionDays: &days}
+//	}
+type TableOptioner interface {
+	TableOptions() TableOptions
+}
+
+// tableOptions returns the options of the model parsed in stmt, ok is false when the model
+// does not implement TableOptioner
+func tableOptions(stmt *gorm.Statement) (options TableOptions, ok bool) {
+	if stmt.Schema == nil {
+		return options, false
+	}
+	if optioner, ok := reflect.New(stmt.Schema.ModelType).Interface().(TableOptioner); ok {
+		return optioner.TableOptions(), true
+	}
+	return options, false
+}
+
+// changeTracking reports whether CHANGE_TRACKING is enabled on a table with options
+func (m Migrator) changeTracking(options TableOptions) bool {
+	if options.ChangeTracking != nil {
+		return *options.ChangeTracking
+	}
+	config := dialectorConfig(m.Dialector)
+	return config == nil || (config.DefaultValueFetcher == nil && config.DefaultValueStrategy != DefaultValueSequence &&
+		config.DefaultValueStrategy != DefaultValueSnapshot && !config.ClientSideIDs)
+}
+
+// tableOptionsSQL returns the options of the table of stmt written by CREATE TABLE
+func (m Migrator) tableOptionsSQL(stmt *gorm.Statement) string {
+	options, _ := tableOptions(stmt)
+
+	var sql string
+	if options.DataRetentionDays != nil {
+		sql += " DATA_RETENTION_TIME_IN_DAYS = " + strconv.Itoa(*options.DataRetentionDays)
+	}
+	if m.changeTracking(options) {
+		sql += " CHANGE_TRACKING = TRUE"
+	}
+	return sql
+}
+
+// migrateTableOptions alters the options of the existing table of stmt which differ from the ones
+// of the model, tables of models without TableOptioner are left untouched
+func (m Migrator) migrateTableOptions(stmt *gorm.Statement) error {
+	options, ok := tableOptions(stmt)
+	if !ok {
+		return nil
+	}
+
+	var tables []ShowTable
+	name := m.lookupName(stmt.Table)
+	if err := Show(m.DB, "TABLES LIKE ?", name, &tables); err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		// LIKE treats _ as a wildcard
+		if table.Name != name {
+			continue
+		}
+
+		var set []string
+		if days := options.DataRetentionDays; days != nil && int64(*days) != table.RetentionTime {
+			set = append(set, "DATA_RETENTION_TIME_IN_DAYS = "+strconv.Itoa(*days))
+		}
+		if changeTracking := m.changeTracking(options); changeTracking != strings.EqualFold(table.ChangeTracking, "ON") {
+			set = append(set, "CHANGE_TRACKING = "+strings.ToUpper(strconv.FormatBool(changeTracking)))
+		}
+
+		if len(set) == 0 {
+			return nil
+		}
+		return m.DB.Exec("ALTER TABLE ? SET "+strings.Join(set, " "), m.CurrentTable(stmt)).Error
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type RetainedEvent struct {
+	ID   uint
+	Name string
+}
+
+func (RetainedEvent) TableOptions() TableOptions {
+	days := 1
+	return TableOptions{DataRetentionDays: &days}
+}
+
+type UntrackedEvent struct {
+	ID   uint
+	Name string
+}
+
+func (UntrackedEvent) TableOptions() TableOptions {
+	changeTracking := false
+	return TableOptions{ChangeTracking: &changeTracking}
+}
+
+func TestCreateTableOptions(t *testing.T) {
+	for _, tt := range []struct {
+		config     Config
+		model      interface{}
+		expected   string
+		unexpected string
+	}{
+		{Config{}, &RetainedEvent{}, "DATA_RETENTION_TIME_IN_DAYS = 1 CHANGE_TRACKING = TRUE", ""},
+		{Config{}, &UntrackedEvent{}, "", "CHANGE_TRACKING"},
+		{Config{}, &TestModel{}, "CHANGE_TRACKING = TRUE", "DATA_RETENTION_TIME_IN_DAYS"},
+		{Config{DefaultValueStrategy: DefaultValueSequence}, &TestModel{}, "", "CHANGE_TRACKING"},
+		{Config{DefaultValueStrategy: DefaultValueSnapshot}, &TestModel{}, "", "CHANGE_TRACKING"},
+		{Config{DefaultValueFetcher: func(*gorm.DB, []*schema.Field) error { return nil }}, &TestModel{}, "", "CHANGE_TRACKING"},
+	} {
+		db, fake := setupFakeDB(t, tt.config, nil)
+
+		if err := db.Migrator().CreateTable(tt.model); err != nil {
+			t.Fatalf("Expected CreateTable to succeed, got %v", err)
+		}
+		if tt.expected != "" && !hasQuery(fake.queries(), tt.expected) {
+			t.Errorf("Expected %s in %v", tt.expected, fake.queries())
+		}
+		if tt.unexpected != "" && hasQuery(fake.queries(), tt.unexpected) {
+			t.Errorf("Expected no %s in %v", tt.unexpected, fake.queries())
+		}
+	}
+}
+
+func TestMigrateTableOptions(t *testing.T) {
+	for _, tt := range []struct {
+		model          interface{}
+		name           string
+		retentionTime  int64
+		changeTracking string
+		expected       string
+	}{
+		{&RetainedEvent{}, "RETAINED_EVENTS", 1, "ON", ""},
+		{&RetainedEvent{}, "RETAINED_EVENTS", 7, "ON", "ALTER TABLE retained_events SET DATA_RETENTION_TIME_IN_DAYS = 1"},
+		{&RetainedEvent{}, "RETAINED_EVENTS", 1, "OFF", "ALTER TABLE retained_events SET CHANGE_TRACKING = TRUE"},
+		{&UntrackedEvent{}, "UNTRACKED_EVENTS", 1, "ON", "ALTER TABLE untracked_events SET CHANGE_TRACKING = FALSE"},
+		{&RetainedEvent{}, "RETAINEDXEVENTS", 7, "OFF", ""},
+	} {
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			if strings.HasPrefix(query, "SHOW TABLES") {
+				return fakeResult{
+					columns: []string{"name", "retention_time", "change_tracking"},
+					rows:    [][]driver.Value{{tt.name, tt.retentionTime, tt.changeTracking}},
+				}
+			}
+			return fakeResult{}
+		})
+
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(tt.model); err != nil {
+			t.Fatalf("Failed to parse model: %v", err)
+		}
+		if err := db.Migrator().(Migrator).migrateTableOptions(stmt); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		altered := hasQuery(fake.queries(), "ALTER TABLE")
+		if tt.expected == "" && altered {
+			t.Errorf("With %s %d %s: expected no ALTER, got %v", tt.name, tt.retentionTime, tt.changeTracking, fake.queries())
+		} else if tt.expected != "" && !hasQuery(fake.queries(), tt.expected) {
+			t.Errorf("With %s %d %s: expected %s, got %v", tt.name, tt.retentionTime, tt.changeTracking, tt.expected, fake.queries())
+		}
+	}
+}
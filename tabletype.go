@@ -0,0 +1,65 @@
+package snowflake
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// tableTypeKey holds the TableType set by WithTableType
+const tableTypeKey = "snowflake:table_type"
+
+// TableType is the kind of table created by CreateTable and AutoMigrate
+type TableType string
+
+const (
+	// TablePermanent tables have Time Travel and Fail-safe (default)
+	TablePermanent TableType = ""
+	// TableTransient tables persist without Fail-safe, a cheaper storage for staging data
+	TableTransient TableType = "TRANSIENT"
+	// TableTemporary tables only exist in the session that created them
+	TableTemporary TableType = "TEMPORARY"
+	// TableHybrid tables (Unistore) are row oriented and have row locks: queries of their models
+	// keep clause.Locking (SELECT ... FOR UPDATE). They require a primary key and have neither
+	// clustering keys nor change tracking
+	TableHybrid TableType = "HYBRID"
+)
+
+// TableTyper is implemented by models created as transient, temporary or hybrid tables
+type TableTyper interface {
+	TableType() TableType
+}
+
+// WithTableType makes CreateTable and AutoMigrate of db create tables of tableType, overriding
+// the TableType of the models, and the queries of db lock rows when tableType is TableHybrid:
+//
+//	snowflake.WithTableType(db, snowflake.TableTransient).AutoMigrate(&StagedOrder{})
+func WithTableType(db *gorm.DB, tableType TableType) *gorm.DB {
+	return db.Set(tableTypeKey, tableType)
+}
+
+// tableType returns the TableType of the table created for stmt
+func (m Migrator) tableType(stmt *gorm.Statement) (TableType, error) {
+	tableType := declaredTableType(m.DB, stmt)
+	switch tableType {
+	case TablePermanent, TableTransient, TableTemporary, TableHybrid:
+		return tableType, nil
+	}
+	return tableType, fmt.Errorf("unsupported table type %q", tableType)
+}
+
+// declaredTableType returns the TableType set on db by WithTableType, else the one of the model of
+// stmt
+func declaredTableType(db *gorm.DB, stmt *gorm.Statement) TableType {
+	if value, ok := db.Get(tableTypeKey); ok {
+		tableType, _ := value.(TableType)
+		return tableType
+	}
+	if stmt.Schema != nil {
+		if typer, ok := reflect.New(stmt.Schema.ModelType).Interface().(TableTyper); ok {
+			return typer.TableType()
+		}
+	}
+	return TablePermanent
+}
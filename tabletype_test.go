@@ -0,0 +1,68 @@
+package snowflake
+
+import "testing"
+
+type transientModel struct {
+	ID   uint
+	Name string
+}
+
+func (transientModel) TableType() TableType { return TableTransient }
+
+func TestTableType(t *testing.T) {
+	t.Run("Creates the table type of the model", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		if err := db.Migrator().CreateTable(&transientModel{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !hasQuery(fake.queries(), "CREATE TRANSIENT TABLE transient_models (") {
+			t.Errorf("Expected transient table, got %v", fake.queries())
+		}
+	})
+
+	t.Run("WithTableType overrides the model", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		if err := WithTableType(db, TableTemporary).Migrator().CreateTable(&transientModel{}, &TestModel{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !hasQuery(fake.queries(), "CREATE TEMPORARY TABLE transient_models (") || !hasQuery(fake.queries(), "CREATE TEMPORARY TABLE test_models (") {
+			t.Errorf("Expected temporary tables, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Creates permanent tables by default", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		if err := db.Migrator().CreateTable(&TestModel{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !hasQuery(fake.queries(), "CREATE TABLE test_models (") {
+			t.Errorf("Expected permanent table, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Rejects unknown table types", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, nil)
+
+		if err := WithTableType(db, "EXTERNAL").Migrator().CreateTable(&TestModel{}); err == nil {
+			t.Error("Expected an error for an unknown table type")
+		}
+	})
+}
+
+func TestHybridTable(t *testing.T) {
+	db, fake := setupFakeDB(t, Config{IndexStrategy: IndexClusterByFirstIndex}, nil)
+
+	if err := db.Migrator().CreateTable(&hybridAccount{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	queries := fake.queries()
+	if !hasQuery(queries, "CREATE HYBRID TABLE hybrid_accounts (") {
+		t.Errorf("Expected hybrid table, got %v", queries)
+	}
+	if hasQuery(queries, "CHANGE_TRACKING") || hasQuery(queries, "CLUSTER BY") {
+		t.Errorf("Expected neither change tracking nor clustering key, got %v", queries)
+	}
+}
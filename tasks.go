@@ -0,0 +1,154 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInvalidTask is returned when a task cannot be built from the given arguments
+	ErrInvalidTask = errors.New("invalid task")
+
+	// taskScheduleRegex matches the SCHEDULE values accepted by CREATE TASK
+	taskScheduleRegex = regexp.MustCompile(`^(?i)(\d+ MINUTE|USING CRON [^'\\]+)$`)
+)
+
+// ShowTask is a row of SHOW TASKS
+type ShowTask struct {
+	CreatedOn    time.Time
+	Name         string
+	ID           string
+	DatabaseName string
+	SchemaName   string
+	Owner        string
+	Comment      string
+	Warehouse    string
+	Schedule     string
+	Predecessors string
+	State        string
+	Definition   string
+	Condition    string
+}
+
+// TaskOptions configures CreateTask
+type TaskOptions struct {
+	// When is the condition checked before every run, e.g. SYSTEM$STREAM_HAS_DATA('orders_stream')
+	// so runs without new data are skipped without resuming the warehouse
+	When string
+	// After are the tasks after which the task runs, it then has no schedule
+	After []string
+	// Comment is the comment of the task
+	Comment string
+}
+
+// CreateTask creates (or replaces) the task name running sql on warehouse, or serverless when
+// empty, every schedule ("<n> MINUTE" or "USING CRON <expr> <tz>"):
+//
+//	err := snowflake.CreateTask(db, "merge_orders", "5 MINUTE", "etl_wh",
+//		"INSERT INTO order_events SELECT * FROM orders_stream",
+//		snowflake.TaskOptions{When: "SYSTEM$STREAM_HAS_DATA('orders_stream')"})
+//
+// sql is stored as text, it takes no bind variables. Tasks are created suspended, see ResumeTask
+func CreateTask(db *gorm.DB, name, schedule, warehouse, sql string, opts ...TaskOptions) error {
+	var options TaskOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+	if sql == "" {
+		return fmt.Errorf("%w: no statement", ErrInvalidTask)
+	}
+
+	createTaskSQL := "CREATE OR REPLACE TASK ?"
+	values := []interface{}{clause.Table{Name: name}}
+
+	if warehouse != "" {
+		if err := validateIdentifier(warehouse); err != nil {
+			return err
+		}
+		createTaskSQL += " WAREHOUSE = ?"
+		values = append(values, clause.Table{Name: warehouse})
+	}
+
+	switch {
+	case schedule != "" && len(options.After) > 0:
+		return fmt.Errorf("%w: a task runs on a schedule or after other tasks, not both", ErrInvalidTask)
+	case schedule != "":
+		if !taskScheduleRegex.MatchString(schedule) {
+			return fmt.Errorf("%w: unsupported schedule %q", ErrInvalidTask, schedule)
+		}
+		createTaskSQL += " SCHEDULE = " + quoteLiteral(schedule)
+	}
+
+	if options.Comment != "" {
+		createTaskSQL += " COMMENT = " + quoteLiteral(options.Comment)
+	}
+
+	if len(options.After) > 0 {
+		predecessors := make([]interface{}, len(options.After))
+		for idx, predecessor := range options.After {
+			if err := validateIdentifier(predecessor); err != nil {
+				return err
+			}
+			predecessors[idx] = clause.Table{Name: predecessor}
+		}
+		createTaskSQL += " AFTER ?"
+		values = append(values, clause.Expr{SQL: "?" + strings.Repeat(", ?", len(predecessors)-1), Vars: predecessors})
+	}
+
+	if options.When != "" {
+		createTaskSQL += " WHEN ?"
+		values = append(values, clause.Expr{SQL: options.When})
+	}
+
+	createTaskSQL += " AS ?"
+	values = append(values, clause.Expr{SQL: sql})
+	return db.Exec(createTaskSQL, values...).Error
+}
+
+// ResumeTask resumes the task name, tasks do not run until resumed
+func ResumeTask(db *gorm.DB, name string) error {
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+	return db.Exec("ALTER TASK ? RESUME", clause.Table{Name: name}).Error
+}
+
+// SuspendTask suspends the task name, a running execution completes
+func SuspendTask(db *gorm.DB, name string) error {
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+	return db.Exec("ALTER TASK ? SUSPEND", clause.Table{Name: name}).Error
+}
+
+// ExecuteTask runs the task name once, now, whether it is resumed or suspended
+func ExecuteTask(db *gorm.DB, name string) error {
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+	return db.Exec("EXECUTE TASK ?", clause.Table{Name: name}).Error
+}
+
+// DropTask drops the task name
+func DropTask(db *gorm.DB, name string) error {
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+	return db.Exec("DROP TASK IF EXISTS ?", clause.Table{Name: name}).Error
+}
+
+// ShowTasks returns the tasks of the current schema
+func ShowTasks(db *gorm.DB) ([]ShowTask, error) {
+	var tasks []ShowTask
+	return tasks, Show(db, "TASKS IN SCHEMA", &tasks)
+}
@@ -0,0 +1,98 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestTaskHelpers(t *testing.T) {
+	t.Run("CreateTask", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		err := CreateTask(db, "merge_orders", "5 MINUTE", "etl_wh", "INSERT INTO order_events SELECT * FROM orders_stream WHERE note <> '?'",
+			TaskOptions{When: "SYSTEM$STREAM_HAS_DATA('orders_stream')", Comment: "orders' CDC"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := `CREATE OR REPLACE TASK "merge_orders" WAREHOUSE = "etl_wh" SCHEDULE = '5 MINUTE' COMMENT = 'orders'' CDC' ` +
+			`WHEN SYSTEM$STREAM_HAS_DATA('orders_stream') AS INSERT INTO order_events SELECT * FROM orders_stream WHERE note <> '?'`
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("Serverless task after other tasks", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		if err := CreateTask(db, "refresh_totals", "", "", "CALL refresh_totals()", TaskOptions{After: []string{"merge_orders", "merge_items"}}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := "CREATE OR REPLACE TASK refresh_totals AFTER merge_orders, merge_items AS CALL refresh_totals()"
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("Resume, suspend, execute and drop", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		for _, run := range []func(db *gorm.DB, name string) error{ResumeTask, SuspendTask, ExecuteTask, DropTask} {
+			if err := run(db, "merge_orders"); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+
+		expected := []string{
+			"ALTER TASK merge_orders RESUME",
+			"ALTER TASK merge_orders SUSPEND",
+			"EXECUTE TASK merge_orders",
+			"DROP TASK IF EXISTS merge_orders",
+		}
+		if queries := fake.queries(); strings.Join(queries, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected %v, got %v", expected, queries)
+		}
+	})
+
+	t.Run("ShowTasks", func(t *testing.T) {
+		db, _ := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			if query != "SHOW TASKS IN SCHEMA" {
+				return fakeResult{}
+			}
+			return fakeResult{
+				columns: []string{"name", "id", "warehouse", "schedule", "state", "definition", "condition"},
+				rows:    [][]driver.Value{{"MERGE_ORDERS", "01ab", "ETL_WH", "5 MINUTE", "started", "INSERT INTO order_events ...", nil}},
+			}
+		})
+
+		tasks, err := ShowTasks(db)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(tasks) != 1 || tasks[0].Name != "MERGE_ORDERS" || tasks[0].State != "started" || tasks[0].Schedule != "5 MINUTE" {
+			t.Errorf("Unexpected tasks: %+v", tasks)
+		}
+	})
+
+	t.Run("Invalid tasks", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		for _, err := range []error{
+			CreateTask(db, "t", "5 MINUTES'; DROP TABLE x", "", "SELECT 1"),
+			CreateTask(db, "t", "5 MINUTE", "", ""),
+			CreateTask(db, "t", "5 MINUTE", "", "SELECT 1", TaskOptions{After: []string{"parent"}}),
+		} {
+			if !errors.Is(err, ErrInvalidTask) {
+				t.Errorf("Expected ErrInvalidTask, got %v", err)
+			}
+		}
+		if len(fake.statements) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+}
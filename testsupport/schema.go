@@ -0,0 +1,79 @@
+// Package testsupport helps tests running against a Snowflake account
+package testsupport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// schemaPrefixRegex matches the prefixes accepted by CreateEphemeralSchema
+var schemaPrefixRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// CreateEphemeralSchema creates a transient schema named <PREFIX>_<timestamp>_<random> in the
+// current database, migrates models in it and returns a DB whose session uses it.
+// The DB runs on a dedicated connection, so tests with their own schema can run in parallel.
+// The schema is dropped and the connection released when tb and its subtests complete
+//
+//	db := testsupport.CreateEphemeralSchema(t, db, "orders_test", &Order{}, &Customer{})
+func CreateEphemeralSchema(tb testing.TB, db *gorm.DB, prefix string, models ...interface{}) *gorm.DB {
+	tb.Helper()
+
+	if !schemaPrefixRegex.MatchString(prefix) {
+		tb.Fatalf("invalid ephemeral schema prefix %q", prefix)
+	}
+	name, err := ephemeralSchemaName(prefix)
+	if err != nil {
+		tb.Fatalf("failed to name ephemeral schema: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		tb.Fatalf("failed to get the connection pool: %v", err)
+	}
+	conn, err := sqlDB.Conn(context.Background())
+	if err != nil {
+		tb.Fatalf("failed to get a connection: %v", err)
+	}
+	// cleanups run last registered first, the connection is released after the schema is dropped
+	tb.Cleanup(func() { conn.Close() })
+
+	tx := db.Session(&gorm.Session{NewDB: true, Context: context.Background()})
+	tx.Statement.ConnPool = conn
+
+	schema := clause.Table{Name: name}
+	if err := tx.Exec("CREATE TRANSIENT SCHEMA ? DATA_RETENTION_TIME_IN_DAYS = 0", schema).Error; err != nil {
+		tb.Fatalf("failed to create ephemeral schema %s: %v", name, err)
+	}
+	tb.Cleanup(func() {
+		if err := tx.Exec("DROP SCHEMA IF EXISTS ? CASCADE", schema).Error; err != nil {
+			tb.Errorf("failed to drop ephemeral schema %s: %v", name, err)
+		}
+	})
+
+	if err := tx.Exec("USE SCHEMA ?", schema).Error; err != nil {
+		tb.Fatalf("failed to use ephemeral schema %s: %v", name, err)
+	}
+	if len(models) > 0 {
+		if err := tx.AutoMigrate(models...); err != nil {
+			tb.Fatalf("failed to migrate ephemeral schema %s: %v", name, err)
+		}
+	}
+	return tx
+}
+
+// ephemeralSchemaName returns a unique uppercase schema name starting with prefix
+func ephemeralSchemaName(prefix string) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(prefix + "_" + time.Now().UTC().Format("20060102150405") + "_" + hex.EncodeToString(suffix)), nil
+}
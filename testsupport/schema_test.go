@@ -0,0 +1,102 @@
+package testsupport
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	snowflake "github.com/gorm-snowflake/gorm-snowflake"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordingDriver records the statements it receives and returns empty results
+type recordingDriver struct {
+	mu         sync.Mutex
+	statements []string
+}
+
+func (d *recordingDriver) Connect(context.Context) (driver.Conn, error) { return recordingConn{d}, nil }
+func (d *recordingDriver) Driver() driver.Driver                        { return d }
+func (d *recordingDriver) Open(string) (driver.Conn, error)             { return recordingConn{d}, nil }
+
+func (d *recordingDriver) record(query string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = append(d.statements, query)
+}
+
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+func (c recordingConn) Prepare(string) (driver.Stmt, error)      { return nil, driver.ErrSkip }
+func (c recordingConn) Close() error                             { return nil }
+func (c recordingConn) Begin() (driver.Tx, error)                { return c, nil }
+func (c recordingConn) Commit() error                            { return nil }
+func (c recordingConn) Rollback() error                          { return nil }
+func (c recordingConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.driver.record(query)
+	return driver.RowsAffected(0), nil
+}
+
+func (c recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.driver.record(query)
+	return emptyRows{}, nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return nil }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+type testModel struct {
+	ID   uint
+	Name string
+}
+
+func TestCreateEphemeralSchema(t *testing.T) {
+	recorder := &recordingDriver{}
+	sqlDB := sql.OpenDB(recorder)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(snowflake.New(snowflake.Config{Conn: sqlDB}), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+
+	t.Run("schema", func(t *testing.T) {
+		CreateEphemeralSchema(t, db, "orders_test", &testModel{})
+	})
+
+	var steps []string
+	for _, statement := range recorder.statements {
+		for _, step := range []string{"CREATE TRANSIENT SCHEMA ORDERS_TEST_", "USE SCHEMA ORDERS_TEST_", "CREATE TABLE", "DROP SCHEMA IF EXISTS ORDERS_TEST_"} {
+			if strings.HasPrefix(strings.ToUpper(statement), step) {
+				steps = append(steps, step)
+			}
+		}
+	}
+	if len(steps) != 4 || steps[0] != "CREATE TRANSIENT SCHEMA ORDERS_TEST_" || steps[3] != "DROP SCHEMA IF EXISTS ORDERS_TEST_" {
+		t.Errorf("Unexpected statements: %v", recorder.statements)
+	}
+}
+
+func TestEphemeralSchemaName(t *testing.T) {
+	first, err := ephemeralSchemaName("it")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, _ := ephemeralSchemaName("it")
+
+	if first == second || !strings.HasPrefix(first, "IT_") {
+		t.Errorf("Expected unique names starting with IT_, got %s and %s", first, second)
+	}
+}
@@ -0,0 +1,47 @@
+package snowflake
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// timeTravelClauseName is the statement clause holding the point in time the table of a query is
+// read at
+const timeTravelClauseName = "SNOWFLAKE_TIME_TRAVEL"
+
+// timeTravel is the AT or BEFORE clause following the table of a SELECT statement, it is rendered
+// by the FROM clause
+type timeTravel struct {
+	SQL   string
+	Value interface{}
+}
+
+// AsOf reads the table of a query as it was at t with Time Travel, adding AT(TIMESTAMP => ...)
+// after the table of the FROM clause:
+//
+//	db.Clauses(snowflake.AsOf(time.Now().Add(-time.Hour))).Where("status = ?", "open").Find(&orders)
+//
+// Joined tables are read as they are now. The timestamp is bound with its offset
+func AsOf(t time.Time) clause.Interface {
+	return timeTravel{SQL: "AT(TIMESTAMP => TO_TIMESTAMP_TZ(?))", Value: t.Format(time.RFC3339Nano)}
+}
+
+// Before reads the table of a query as it was just before the statement statementID (a query ID,
+// e.g. of a faulty DELETE) ran with Time Travel, adding BEFORE(STATEMENT => ...) after the table
+// of the FROM clause:
+//
+//	db.Clauses(snowflake.Before(queryID)).Find(&orders)
+func Before(statementID string) clause.Interface {
+	return timeTravel{SQL: "BEFORE(STATEMENT => ?)", Value: statementID}
+}
+
+func (timeTravel) Name() string {
+	return timeTravelClauseName
+}
+
+func (timeTravel) Build(clause.Builder) {}
+
+func (travel timeTravel) MergeClause(c *clause.Clause) {
+	c.Expression = travel
+}
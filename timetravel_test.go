@@ -0,0 +1,68 @@
+package snowflake
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestTimeTravel(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 30, 0, 0, time.FixedZone("", 2*60*60))
+
+	t.Run("AsOf", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		stmt := db.Clauses(AsOf(at)).Where("age > ?", 18).Find(&[]TestModel{}).Statement
+
+		expected := `SELECT * FROM "test_models" AT(TIMESTAMP => TO_TIMESTAMP_TZ(?)) WHERE age > ?`
+		if sql := stmt.SQL.String(); sql != expected {
+			t.Errorf("Expected %s, got %s", expected, sql)
+		}
+		if len(stmt.Vars) != 2 || stmt.Vars[0] != "2024-05-01T12:30:00+02:00" || stmt.Vars[1] != 18 {
+			t.Errorf("Expected the timestamp then the condition vars, got %v", stmt.Vars)
+		}
+	})
+
+	t.Run("Before", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		stmt := db.Clauses(Before("01b2c3d4-0000-1234-0000-000100020003")).Find(&[]TestModel{}).Statement
+
+		if sql := stmt.SQL.String(); sql != `SELECT * FROM "test_models" BEFORE(STATEMENT => ?)` {
+			t.Errorf("Expected BEFORE after the table, got %s", sql)
+		}
+		if len(stmt.Vars) != 1 || stmt.Vars[0] != "01b2c3d4-0000-1234-0000-000100020003" {
+			t.Errorf("Expected the statement ID var, got %v", stmt.Vars)
+		}
+	})
+
+	t.Run("Joined and aliased tables", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		stmt := db.Model(&TestModel{}).Clauses(
+			clause.From{Tables: []clause.Table{{Name: "test_models", Alias: "t"}}},
+			AsOf(at),
+		).Joins(`JOIN "owners" ON "owners"."id" = "t"."id"`).Find(&[]TestModel{}).Statement
+
+		expected := `FROM "test_models" AT(TIMESTAMP => TO_TIMESTAMP_TZ(?)) AS "t" JOIN "owners" ON "owners"."id" = "t"."id"`
+		if sql := stmt.SQL.String(); !strings.Contains(sql, expected) {
+			t.Errorf("Expected %s, got %s", expected, sql)
+		}
+	})
+
+	t.Run("Only SELECT statements", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		stmt := db.Clauses(AsOf(at)).Where("id = ?", 1).Delete(&TestModel{}).Statement
+
+		if sql := stmt.SQL.String(); strings.Contains(sql, "AT(") {
+			t.Errorf("Expected no time travel in DELETE, got %s", sql)
+		}
+	})
+
+	t.Run("Queries without it", func(t *testing.T) {
+		db := setupMockDB(t).Session(&gorm.Session{DryRun: true})
+		if sql := db.Find(&[]TestModel{}).Statement.SQL.String(); sql != `SELECT * FROM "test_models"` {
+			t.Errorf("Expected a plain SELECT, got %s", sql)
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package snowflake
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// objectNameRegex matches the (optionally database and schema qualified) unquoted names of
+// the functions of the tokenize and detokenize tags, and of the masking policies and object tags
+// of columns
+var objectNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*){0,2}$`)
+
+// tokenizeFunctions returns the UDFs of a column tagged for external tokenization:
+//
+//	Email string `gorm:"tokenize:pii.tokenize_email"`
+//	SSN   string `gorm:"tokenize:protect_ssn;detokenize:reveal_ssn"`
+//
+// Written values are wrapped with the tokenize function, the column is read through the
+// detokenize function, which defaults to the tokenize function with its TOKENIZE prefix
+// replaced with DETOKENIZE (pii.detokenize_email). Without one the column is read as stored
+func tokenizeFunctions(field *schema.Field) (tokenize, detokenize string, err error) {
+	if field == nil {
+		return "", "", nil
+	}
+	tokenize = strings.TrimSpace(field.TagSettings["TOKENIZE"])
+	if tokenize == "" {
+		return "", "", nil
+	}
+
+	detokenize = strings.TrimSpace(field.TagSettings["DETOKENIZE"])
+	if detokenize == "" {
+		qualifier, name := "", tokenize
+		if dot := strings.LastIndexByte(tokenize, '.'); dot >= 0 {
+			qualifier, name = tokenize[:dot+1], tokenize[dot+1:]
+		}
+		if strings.HasPrefix(strings.ToUpper(name), "TOKENIZE") {
+			// keep the case of the function name
+			prefix := "DE"
+			if name[0] == 't' {
+				prefix = "de"
+			}
+			detokenize = qualifier + prefix + name
+		}
+	}
+
+	for _, function := range []string{tokenize, detokenize} {
+		if function != "" && !objectNameRegex.MatchString(function) {
+			return "", "", fmt.Errorf("%w: %q is not a function name (field %s)", ErrInvalidIdentifier, function, field.Name)
+		}
+	}
+	return tokenize, detokenize, nil
+}
+
+// tokenizedColumns returns the tokenize function of each of columns in the statement schema,
+// nil if none is tokenized
+func tokenizedColumns(db *gorm.DB, columns []clause.Column) ([]string, error) {
+	if db.Statement.Schema == nil {
+		return nil, nil
+	}
+
+	var functions []string
+	for idx, column := range columns {
+		tokenize, _, err := tokenizeFunctions(db.Statement.Schema.LookUpField(column.Name))
+		if err != nil {
+			return nil, err
+		}
+		if tokenize != "" {
+			if functions == nil {
+				functions = make([]string, len(columns))
+			}
+			functions[idx] = tokenize
+		}
+	}
+	return functions, nil
+}
+
+// hasTokenizedColumns reports whether one of columns is a tokenized field of the statement schema
+func hasTokenizedColumns(db *gorm.DB, columns []clause.Column) bool {
+	functions, _ := tokenizedColumns(db, columns)
+	return functions != nil
+}
+
+// tokenizeExpr returns the expression writing value through the tokenize function, NULL is
+// written as is
+func tokenizeExpr(value interface{}, function string) interface{} {
+	if _, ok := value.(clause.Expression); ok || value == nil || isNilPointer(value) {
+		return value
+	}
+	return clause.Expr{SQL: function + "(?)", Vars: []interface{}{value}}
+}
+
+// bindTokenizedValues returns values with the values of tokenized columns wrapped in their
+// tokenize function. Function calls are not allowed in a VALUES clause, statements built from
+// the result use SELECT rows instead when it has tokenized columns
+func bindTokenizedValues(db *gorm.DB, values clause.Values) (clause.Values, error) {
+	functions, err := tokenizedColumns(db, values.Columns)
+	if err != nil || functions == nil {
+		return values, err
+	}
+
+	rows := make([][]interface{}, len(values.Values))
+	for rowIdx, row := range values.Values {
+		rows[rowIdx] = make([]interface{}, len(row))
+		for idx, value := range row {
+			if idx < len(functions) && functions[idx] != "" {
+				value = tokenizeExpr(value, functions[idx])
+			}
+			rows[rowIdx][idx] = value
+		}
+	}
+	return clause.Values{Columns: values.Columns, Values: rows}, nil
+}
+
+// bindTokenizedAssignments wraps the values assigned to tokenized columns of the statement
+// schema in their tokenize function, for UPDATE statements
+func bindTokenizedAssignments(stmt *gorm.Statement, set clause.Set) (clause.Set, error) {
+	if stmt.Schema == nil {
+		return set, nil
+	}
+
+	var assignments clause.Set
+	for idx, assignment := range set {
+		tokenize, _, err := tokenizeFunctions(stmt.Schema.LookUpField(assignment.Column.Name))
+		if err != nil {
+			return set, err
+		}
+		if tokenize == "" {
+			continue
+		}
+
+		if assignments == nil {
+			assignments = append(clause.Set{}, set...)
+		}
+		assignments[idx].Value = tokenizeExpr(assignment.Value, tokenize)
+	}
+
+	if assignments == nil {
+		return set, nil
+	}
+	return assignments, nil
+}
+
+// detokenizedSelect returns sel with the tokenized columns of the statement schema read through
+// their detokenize function, `SELECT *` lists the columns of the schema to do so. Columns of
+// joined tables and SQL expressions are left as is. It returns false when sel reads no
+// tokenized column
+func detokenizedSelect(stmt *gorm.Statement, sel clause.Select) (clause.Select, bool, error) {
+	if stmt.Schema == nil || sel.Expression != nil {
+		return sel, false, nil
+	}
+
+	var (
+		functions = map[string]string{}
+		err       error
+	)
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || !field.Readable {
+			continue
+		}
+		var detokenize string
+		if _, detokenize, err = tokenizeFunctions(field); err != nil {
+			return sel, false, err
+		}
+		if detokenize != "" {
+			functions[field.DBName] = detokenize
+		}
+	}
+	if len(functions) == 0 {
+		return sel, false, nil
+	}
+
+	columns := sel.Columns
+	if len(columns) == 0 {
+		columns = make([]clause.Column, 0, len(stmt.Schema.DBNames))
+		for _, dbName := range stmt.Schema.DBNames {
+			columns = append(columns, clause.Column{Table: clause.CurrentTable, Name: dbName})
+		}
+	}
+
+	detokenized := make([]clause.Column, len(columns))
+	for idx, column := range columns {
+		detokenized[idx] = column
+		if column.Raw || column.Alias != "" || (column.Table != "" && column.Table != clause.CurrentTable && column.Table != stmt.Table) {
+			continue
+		}
+		if function, ok := functions[column.Name]; ok {
+			table := column.Table
+			if table == "" {
+				table = clause.CurrentTable
+			}
+			detokenized[idx] = clause.Column{
+				Name: function + "(" + stmt.Quote(clause.Column{Table: table, Name: column.Name}) + ") AS " + stmt.Quote(column.Name),
+				Raw:  true,
+			}
+		}
+	}
+
+	sel.Columns = detokenized
+	return sel, true, nil
+}
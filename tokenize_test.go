@@ -0,0 +1,114 @@
+package snowflake
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type TokenizedModel struct {
+	ID    uint    `gorm:"primaryKey;autoIncrement:false"`
+	Email string  `gorm:"tokenize:pii.tokenize_email"`
+	SSN   string  `gorm:"tokenize:protect_ssn;detokenize:reveal_ssn"`
+	Phone *string `gorm:"tokenize:mask_phone"`
+}
+
+func TestTokenizeFunctions(t *testing.T) {
+	stmt := &gorm.Statement{DB: setupMockDB(t)}
+	if err := stmt.Parse(&TokenizedModel{}); err != nil {
+		t.Fatalf("Failed to parse model: %v", err)
+	}
+
+	tests := map[string][2]string{
+		"ID":    {"", ""},
+		"Email": {"pii.tokenize_email", "pii.detokenize_email"},
+		"SSN":   {"protect_ssn", "reveal_ssn"},
+		"Phone": {"mask_phone", ""},
+	}
+	for name, expected := range tests {
+		tokenize, detokenize, err := tokenizeFunctions(stmt.Schema.LookUpField(name))
+		if err != nil || tokenize != expected[0] || detokenize != expected[1] {
+			t.Errorf("%s: expected %v, got %s, %s, %v", name, expected, tokenize, detokenize, err)
+		}
+	}
+}
+
+func TestTokenizedCreate(t *testing.T) {
+	db := setupMockDBWithConfig(t, false, true)
+	tx := db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true}).Create(&TokenizedModel{ID: 1, Email: "a@b.c", SSN: "123"})
+	if tx.Error != nil {
+		t.Fatalf("Expected no error, got %v", tx.Error)
+	}
+
+	// function calls are not allowed in VALUES, NULL is not tokenized
+	if sql := tx.Statement.SQL.String(); !strings.Contains(sql, "SELECT ?,pii.tokenize_email(?),protect_ssn(?),?") {
+		t.Errorf("Expected tokenized values, got %s", sql)
+	}
+	if len(tx.Statement.Vars) != 4 || tx.Statement.Vars[1] != "a@b.c" || tx.Statement.Vars[2] != "123" {
+		t.Errorf("Unexpected vars: %v", tx.Statement.Vars)
+	}
+}
+
+func TestTokenizedUpdate(t *testing.T) {
+	db := setupMockDB(t)
+	tx := db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true}).Model(&TokenizedModel{ID: 1}).Updates(map[string]interface{}{"email": "a@b.c", "ssn": gorm.Expr("ssn")})
+	if tx.Error != nil {
+		t.Fatalf("Expected no error, got %v", tx.Error)
+	}
+
+	sql := tx.Statement.SQL.String()
+	if !strings.Contains(sql, `"email"=pii.tokenize_email(?)`) || !strings.Contains(sql, `"ssn"=ssn`) {
+		t.Errorf("Expected the email to be tokenized, got %s", sql)
+	}
+}
+
+func TestTokenizedSelect(t *testing.T) {
+	db := setupMockDB(t).Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true})
+
+	t.Run("All columns", func(t *testing.T) {
+		var models []TokenizedModel
+		tx := db.Find(&models)
+		if tx.Error != nil {
+			t.Fatalf("Expected no error, got %v", tx.Error)
+		}
+
+		expected := `SELECT "tokenized_models"."id",pii.detokenize_email("tokenized_models"."email") AS "email",` +
+			`reveal_ssn("tokenized_models"."ssn") AS "ssn","tokenized_models"."phone" FROM "tokenized_models"`
+		if sql := tx.Statement.SQL.String(); sql != expected {
+			t.Errorf("Expected %s, got %s", expected, sql)
+		}
+	})
+
+	t.Run("Selected columns", func(t *testing.T) {
+		var emails []string
+		tx := db.Model(&TokenizedModel{}).Pluck("email", &emails)
+		if tx.Error != nil {
+			t.Fatalf("Expected no error, got %v", tx.Error)
+		}
+
+		if sql := tx.Statement.SQL.String(); sql != `SELECT pii.detokenize_email("tokenized_models"."email") AS "email" FROM "tokenized_models"` {
+			t.Errorf("Unexpected SQL: %s", sql)
+		}
+	})
+
+	t.Run("Untokenized models", func(t *testing.T) {
+		var models []TestModel
+		if sql := db.Find(&models).Statement.SQL.String(); sql != `SELECT * FROM "test_models"` {
+			t.Errorf("Expected SELECT *, got %s", sql)
+		}
+	})
+}
+
+func TestTokenizeInvalidFunction(t *testing.T) {
+	type InvalidTokenizedModel struct {
+		ID    uint
+		Email string `gorm:"tokenize:tokenize(email); DROP TABLE users"`
+	}
+
+	db := setupMockDB(t).Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true})
+	if err := db.Create(&InvalidTokenizedModel{ID: 1}).Error; !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected ErrInvalidIdentifier, got %v", err)
+	}
+}
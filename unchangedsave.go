@@ -0,0 +1,324 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"hash/fnv"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	savingRowKey     = "snowflake:saving_row"
+	unchangedSaveKey = "snowflake:unchanged_save"
+	savedRowsKey     = "snowflake:saved_rows"
+
+	// maxTrackedRows bounds the number of loaded rows tracked by Config.SkipUnchangedSaves
+	maxTrackedRows = 100000
+)
+
+// rowTracker records a hash of the values of the rows loaded by queries, per table and primary
+// key, so Save can tell the rows it is given have not changed since they were loaded
+type rowTracker struct {
+	mu     sync.Mutex
+	size   int
+	tables map[string]map[string]uint64
+}
+
+func registerUnchangedSaveCallbacks(db *gorm.DB) {
+	tracker := &rowTracker{tables: map[string]map[string]uint64{}}
+
+	callback := db.Callback()
+	_ = callback.Query().After("gorm:query").Register("snowflake:track_loaded_rows", tracker.trackLoaded)
+	_ = callback.Update().Before("gorm:update").Register("snowflake:skip_unchanged_save", tracker.skipUnchangedSave)
+	update := callback.Update().Get("gorm:update")
+	_ = callback.Update().Replace("gorm:update", func(db *gorm.DB) {
+		if _, unchanged := db.InstanceGet(unchangedSaveKey); !unchanged {
+			update(db)
+		}
+	})
+	_ = callback.Update().After("gorm:commit_or_rollback_transaction").Register("snowflake:track_saved_rows", tracker.trackSaved)
+	_ = callback.Create().Before("gorm:create").Register("snowflake:skip_unchanged_saves", tracker.skipUnchangedSaves)
+	_ = callback.Create().After("gorm:create").Register("snowflake:restore_saved_rows", restoreSavedRows)
+	_ = callback.Create().After("gorm:commit_or_rollback_transaction").Register("snowflake:track_saved_rows", tracker.trackSaved)
+	_ = callback.Delete().After("gorm:delete").Register("snowflake:forget_deleted_rows", tracker.forgetTable)
+}
+
+// trackLoaded records the rows loaded by a query built for a model, queries selecting some
+// columns only, raw queries and queries run in a transaction are not tracked
+func (tracker *rowTracker) trackLoaded(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || db.DryRun || stmt.Schema == nil || len(stmt.Selects) > 0 || len(stmt.Omits) > 0 {
+		return
+	}
+	if _, built := stmt.Clauses["SELECT"]; !built {
+		return
+	}
+	if _, inTransaction := stmt.ConnPool.(gorm.TxCommitter); inTransaction {
+		return
+	}
+
+	for _, row := range modelRows(stmt, stmt.ReflectValue) {
+		tracker.track(stmt, row)
+	}
+}
+
+// skipUnchangedSave skips the UPDATE of Save when none of the values of the row changed since
+// it was loaded, RowsAffected is 1 so Save does not fall back to an upsert
+func (tracker *rowTracker) skipUnchangedSave(db *gorm.DB) {
+	if db.Error != nil || db.DryRun || !isSave(db.Statement) || db.Statement.ReflectValue.Kind() != reflect.Struct {
+		return
+	}
+
+	// gorm:update adds the primary key conditions, isSave no longer holds after it
+	db.InstanceSet(savingRowKey, true)
+	if tracker.unchanged(db.Statement, db.Statement.ReflectValue) {
+		db.InstanceSet(unchangedSaveKey, true)
+		db.RowsAffected = 1
+	}
+}
+
+// skipUnchangedSaves drops the unchanged rows of the upsert of Save given a slice, the others
+// are merged from a slice of pointers to them, restored by restoreSavedRows
+func (tracker *rowTracker) skipUnchangedSaves(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || db.DryRun || !isSaveUpsert(db) || stmt.ReflectValue.Kind() != reflect.Slice {
+		return
+	}
+
+	rows := modelRows(stmt, stmt.ReflectValue)
+	if len(rows) != stmt.ReflectValue.Len() {
+		return
+	}
+
+	changed := reflect.MakeSlice(reflect.SliceOf(reflect.PointerTo(stmt.Schema.ModelType)), 0, len(rows))
+	for _, row := range rows {
+		if !tracker.unchanged(stmt, row) {
+			changed = reflect.Append(changed, row.Addr())
+		}
+	}
+	if changed.Len() == len(rows) {
+		return
+	}
+
+	db.InstanceSet(savedRowsKey, stmt.ReflectValue)
+	if changed.Len() == 0 {
+		db.InstanceSet(unchangedSaveKey, true)
+		return
+	}
+	stmt.ReflectValue = changed
+}
+
+// restoreSavedRows restores the rows given to Save after skipUnchangedSaves dropped some
+func restoreSavedRows(db *gorm.DB) {
+	if value, ok := db.InstanceGet(savedRowsKey); ok {
+		db.Statement.ReflectValue = value.(reflect.Value)
+	}
+}
+
+// trackSaved records the rows written by Save once committed. The rows written in a
+// transaction of the application, by a failed statement or by other upserts are forgotten,
+// so are every row of the table after other updates
+func (tracker *rowTracker) trackSaved(db *gorm.DB) {
+	stmt := db.Statement
+	if db.DryRun || stmt.Schema == nil {
+		return
+	}
+	if _, skipped := db.InstanceGet(unchangedSaveKey); skipped {
+		return
+	}
+
+	_, isUpsert := stmt.Clauses["ON CONFLICT"]
+	_, saving := db.InstanceGet(savingRowKey)
+	if !isUpsert && !saving {
+		if _, isUpdate := stmt.Clauses["UPDATE"]; isUpdate {
+			tracker.forgetTable(db)
+		}
+		return
+	}
+
+	_, inTransaction := stmt.ConnPool.(gorm.TxCommitter)
+	record := db.Error == nil && !inTransaction && len(stmt.Omits) == 0 && (saving || isSaveUpsert(db))
+	for _, row := range modelRows(stmt, stmt.ReflectValue) {
+		if record {
+			tracker.track(stmt, row)
+		} else {
+			tracker.forget(stmt, row)
+		}
+	}
+}
+
+// forgetTable forgets the rows of the table of db, which were updated or deleted
+func (tracker *rowTracker) forgetTable(db *gorm.DB) {
+	if db.DryRun || db.Statement.Table == "" {
+		return
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	tracker.size -= len(tracker.tables[db.Statement.Table])
+	delete(tracker.tables, db.Statement.Table)
+}
+
+// track records the hash of the values of row, evicting the rows of a table when full
+func (tracker *rowTracker) track(stmt *gorm.Statement, row reflect.Value) {
+	key, ok := rowKey(stmt, row)
+	if !ok {
+		return
+	}
+	hash, ok := rowHash(stmt, row)
+	if !ok {
+		tracker.forget(stmt, row)
+		return
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if _, ok := tracker.tables[stmt.Table][key]; !ok {
+		if tracker.size >= maxTrackedRows {
+			tracker.evict()
+		}
+		tracker.size++
+	}
+
+	rows := tracker.tables[stmt.Table]
+	if rows == nil {
+		rows = map[string]uint64{}
+		tracker.tables[stmt.Table] = rows
+	}
+	rows[key] = hash
+}
+
+// evict forgets the rows of one of the tracked tables
+func (tracker *rowTracker) evict() {
+	for table, rows := range tracker.tables {
+		tracker.size -= len(rows)
+		delete(tracker.tables, table)
+		return
+	}
+}
+
+// forget forgets row
+func (tracker *rowTracker) forget(stmt *gorm.Statement, row reflect.Value) {
+	key, ok := rowKey(stmt, row)
+	if !ok {
+		return
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	if rows := tracker.tables[stmt.Table]; rows != nil {
+		if _, ok := rows[key]; ok {
+			delete(rows, key)
+			tracker.size--
+		}
+	}
+}
+
+// unchanged reports whether row was loaded and its values did not change since
+func (tracker *rowTracker) unchanged(stmt *gorm.Statement, row reflect.Value) bool {
+	key, ok := rowKey(stmt, row)
+	if !ok {
+		return false
+	}
+	hash, ok := rowHash(stmt, row)
+	if !ok {
+		return false
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	loaded, ok := tracker.tables[stmt.Table][key]
+	return ok && loaded == hash
+}
+
+// isSave reports whether stmt is the UPDATE of Save, which writes every column of the row
+// matched by its primary key
+func isSave(stmt *gorm.Statement) bool {
+	if len(stmt.Selects) != 1 || stmt.Selects[0] != "*" || len(stmt.Omits) > 0 || stmt.SQL.Len() > 0 {
+		return false
+	}
+	_, withSet := stmt.Clauses["SET"]
+	_, withWhere := stmt.Clauses["WHERE"]
+	return !withSet && !withWhere && stmt.Dest != nil && reflect.Indirect(reflect.ValueOf(stmt.Dest)).Kind() == reflect.Struct
+}
+
+// isSaveUpsert reports whether db is the upsert of Save given a slice
+func isSaveUpsert(db *gorm.DB) bool {
+	if _, ok := db.Get("gorm:update_track_time"); !ok {
+		return false
+	}
+	onConflict, ok := db.Statement.Clauses["ON CONFLICT"].Expression.(clause.OnConflict)
+	return ok && onConflict.UpdateAll
+}
+
+// modelRows returns the structs of the model of stmt held by value
+func modelRows(stmt *gorm.Statement, value reflect.Value) []reflect.Value {
+	var rows []reflect.Value
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for idx := 0; idx < value.Len(); idx++ {
+			if row := reflect.Indirect(value.Index(idx)); row.IsValid() && row.Type() == stmt.Schema.ModelType {
+				rows = append(rows, row)
+			}
+		}
+	case reflect.Struct:
+		if value.Type() == stmt.Schema.ModelType {
+			rows = append(rows, value)
+		}
+	}
+	return rows
+}
+
+// rowKey returns the primary key of row, false if the model has none or it is zero
+func rowKey(stmt *gorm.Statement, row reflect.Value) (string, bool) {
+	if len(stmt.Schema.PrimaryFields) == 0 {
+		return "", false
+	}
+
+	values := make([]interface{}, len(stmt.Schema.PrimaryFields))
+	for idx, field := range stmt.Schema.PrimaryFields {
+		value, isZero := field.ValueOf(stmt.Context, row)
+		if isZero {
+			return "", false
+		}
+		values[idx] = value
+	}
+	key, err := json.Marshal(values)
+	return string(key), err == nil
+}
+
+// rowHash returns the hash of the values of the columns of row, false if a value cannot be
+// encoded
+func rowHash(stmt *gorm.Statement, row reflect.Value) (uint64, bool) {
+	hash := fnv.New64a()
+	encoder := json.NewEncoder(hash)
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" {
+			continue
+		}
+
+		value, _ := field.ValueOf(stmt.Context, row)
+		if valuer, ok := value.(driver.Valuer); ok && !isNilPointer(value) {
+			var err error
+			if value, err = valuer.Value(); err != nil {
+				return 0, false
+			}
+		}
+		hash.Write([]byte(field.DBName))
+		if err := encoder.Encode(value); err != nil {
+			return 0, false
+		}
+	}
+	return hash.Sum64(), true
+}
+
+// isNilPointer reports whether v is a nil pointer, whose Value method may not be callable
+func isNilPointer(v interface{}) bool {
+	value := reflect.ValueOf(v)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
@@ -0,0 +1,138 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+func unchangedSaveHandler(query string, args []interface{}) fakeResult {
+	switch {
+	case strings.HasPrefix(query, "SELECT"):
+		return fakeResult{
+			columns: []string{"id", "name", "age"},
+			rows:    [][]driver.Value{{int64(1), "jinzhu", int64(18)}, {int64(2), "gorm", int64(20)}},
+		}
+	case strings.HasPrefix(query, "UPDATE"):
+		return fakeResult{rowsAffected: 1}
+	}
+	return fakeResult{}
+}
+
+func TestSkipUnchangedSaves(t *testing.T) {
+	t.Run("Skips the update of an unchanged row", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{SkipUnchangedSaves: true}, unchangedSaveHandler)
+
+		var models []TestModel
+		if err := db.Find(&models).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		result := db.Save(&models[0])
+		if result.Error != nil || result.RowsAffected != 1 {
+			t.Fatalf("Expected one row affected, got %d, %v", result.RowsAffected, result.Error)
+		}
+		if hasQuery(fake.queries(), "UPDATE") || hasQuery(fake.queries(), "MERGE") {
+			t.Errorf("Expected no write, got %v", fake.queries())
+		}
+
+		models[0].Age = 19
+		if err := db.Save(&models[0]).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if countQueries(fake.queries(), "UPDATE") != 1 {
+			t.Errorf("Expected the changed row to be updated, got %v", fake.queries())
+		}
+
+		// the saved values are tracked
+		if err := db.Save(&models[0]).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if countQueries(fake.queries(), "UPDATE") != 1 {
+			t.Errorf("Expected the saved row not to be updated again, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Merges the changed rows of a slice only", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{SkipUnchangedSaves: true, UseUnionSelect: true}, unchangedSaveHandler)
+
+		var models []TestModel
+		if err := db.Find(&models).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if err := db.Save(&models).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if hasQuery(fake.queries(), "MERGE") {
+			t.Errorf("Expected no MERGE, got %v", fake.queries())
+		}
+
+		models[1].Name = "gorm v2"
+		if err := db.Save(&models).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		var merge fakeStatement
+		for _, statement := range fake.statements {
+			if strings.HasPrefix(statement.query, "MERGE") {
+				merge = statement
+			}
+		}
+		if len(merge.args) != 3 || merge.args[0] != "gorm v2" {
+			t.Errorf("Expected the changed row only, got %s %v", merge.query, merge.args)
+		}
+		if len(models) != 2 || models[1].Name != "gorm v2" {
+			t.Errorf("Expected the rows given to Save to be kept, got %+v", models)
+		}
+	})
+
+	t.Run("Forgets the rows of a table after other updates", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{SkipUnchangedSaves: true}, unchangedSaveHandler)
+
+		var model TestModel
+		if err := db.First(&model).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := db.Model(&TestModel{}).Where("age > ?", 10).Update("age", 30).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if err := db.Save(&model).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if countQueries(fake.queries(), "UPDATE") != 2 {
+			t.Errorf("Expected the row to be updated, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Does not track rows loaded with Select", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{SkipUnchangedSaves: true}, unchangedSaveHandler)
+
+		var model TestModel
+		if err := db.Select("id", "name").First(&model).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := db.Save(&model).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if countQueries(fake.queries(), "UPDATE") != 1 {
+			t.Errorf("Expected the row to be updated, got %v", fake.queries())
+		}
+	})
+
+	t.Run("Is disabled by default", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, unchangedSaveHandler)
+
+		var model TestModel
+		if err := db.First(&model).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := db.Save(&model).Error; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if countQueries(fake.queries(), "UPDATE") != 1 {
+			t.Errorf("Expected the row to be updated, got %v", fake.queries())
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package snowflake
+
+import (
+	"fmt"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// UnloadFormat is the type of the files written by Unload
+type UnloadFormat string
+
+const (
+	// UnloadCSV writes CSV files (the default)
+	UnloadCSV UnloadFormat = "CSV"
+	// UnloadJSON writes newline delimited JSON files, the query must select a single OBJECT column
+	UnloadJSON UnloadFormat = "JSON"
+	// UnloadParquet writes Parquet files
+	UnloadParquet UnloadFormat = "PARQUET"
+)
+
+// UnloadResult is the output of COPY INTO <location>
+type UnloadResult struct {
+	RowsUnloaded int64
+	InputBytes   int64
+	OutputBytes  int64
+}
+
+// UnloadBuilder builds a COPY INTO <location> statement exporting the results of a query to a
+// stage, see Unload
+type UnloadBuilder struct {
+	db            *gorm.DB
+	query         *gorm.DB
+	location      string
+	format        UnloadFormat
+	formatOptions string
+	partitionBy   string
+	header        bool
+	overwrite     bool
+	single        bool
+	maxFileSize   int64
+	err           error
+}
+
+// Unload starts a COPY INTO <location> exporting the results of a query to files of a stage:
+//
+//	results, err := snowflake.Unload(db).Query(db.Model(&Order{}).Where("created_at >= ?", since)).
+//		To("@exports/orders/").Format(snowflake.UnloadParquet).Header(true).Execute()
+//
+// The vars of the query are inlined. Errors of the arguments are returned by Execute
+func Unload(db *gorm.DB) *UnloadBuilder {
+	return &UnloadBuilder{db: db}
+}
+
+// Query exports the results of query
+func (b *UnloadBuilder) Query(query *gorm.DB) *UnloadBuilder {
+	b.query = query
+	return b
+}
+
+// To writes the files to location, a stage with an optional path and file name prefix, e.g.
+// @exports/orders/ or the Location of a Stage
+func (b *UnloadBuilder) To(location string) *UnloadBuilder {
+	if !stageLocationRegex.MatchString(location) {
+		return b.fail(fmt.Errorf("%w: unsupported stage location %q", ErrInvalidCopy, location))
+	}
+	b.location = location
+	return b
+}
+
+// Format sets the type of the files and its options, e.g. COMPRESSION = SNAPPY
+func (b *UnloadBuilder) Format(format UnloadFormat, options ...string) *UnloadBuilder {
+	switch format {
+	case UnloadCSV, UnloadJSON, UnloadParquet:
+	default:
+		return b.fail(fmt.Errorf("%w: unsupported format %q", ErrInvalidCopy, format))
+	}
+	b.format = format
+	b.formatOptions = ""
+	for _, option := range options {
+		b.formatOptions += " " + option
+	}
+	return b
+}
+
+// PartitionBy splits the files into paths named by the string expression expr, e.g.
+// 'date=' || TO_VARCHAR(created_at::DATE)
+func (b *UnloadBuilder) PartitionBy(expr string) *UnloadBuilder {
+	b.partitionBy = expr
+	return b
+}
+
+// Header writes the column names, the header of CSV files or the column names of Parquet files
+// (_COL_1, _COL_2... otherwise)
+func (b *UnloadBuilder) Header(header bool) *UnloadBuilder {
+	b.header = header
+	return b
+}
+
+// Overwrite replaces the files of the same name in the location
+func (b *UnloadBuilder) Overwrite(overwrite bool) *UnloadBuilder {
+	b.overwrite = overwrite
+	return b
+}
+
+// Single writes a single file, named by the location, instead of one per thread
+func (b *UnloadBuilder) Single(single bool) *UnloadBuilder {
+	b.single = single
+	return b
+}
+
+// MaxFileSize sets the maximum size in bytes of every file
+// Default: 0 (16 MB)
+func (b *UnloadBuilder) MaxFileSize(bytes int64) *UnloadBuilder {
+	b.maxFileSize = bytes
+	return b
+}
+
+// Execute runs the COPY INTO and returns the number of rows and bytes unloaded
+func (b *UnloadBuilder) Execute() ([]UnloadResult, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.query == nil {
+		return nil, gorm.ErrSubQueryRequired
+	}
+	if b.location == "" {
+		return nil, fmt.Errorf("%w: no stage", ErrInvalidCopy)
+	}
+
+	query, err := inlineQuery(b.db, b.query)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := "COPY INTO " + b.location + " FROM (" + query + ")"
+	if b.partitionBy != "" {
+		sql += " PARTITION BY (" + b.partitionBy + ")"
+	}
+	if b.format != "" {
+		sql += " FILE_FORMAT = (TYPE = " + string(b.format) + b.formatOptions + ")"
+	}
+	if b.header {
+		sql += " HEADER = TRUE"
+	}
+	if b.overwrite {
+		sql += " OVERWRITE = TRUE"
+	}
+	if b.single {
+		sql += " SINGLE = TRUE"
+	}
+	if b.maxFileSize > 0 {
+		sql += " MAX_FILE_SIZE = " + strconv.FormatInt(b.maxFileSize, 10)
+	}
+
+	var results []UnloadResult
+	return results, queryInto(b.db, sql, nil, &results)
+}
+
+// fail records the first error of the arguments, returned by Execute
+func (b *UnloadBuilder) fail(err error) *UnloadBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
@@ -0,0 +1,91 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestUnload(t *testing.T) {
+	t.Run("Parquet", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			return fakeResult{
+				columns: []string{"rows_unloaded", "input_bytes", "output_bytes"},
+				rows:    [][]driver.Value{{int64(42), int64(4096), int64(1024)}},
+			}
+		})
+
+		results, err := Unload(db).Query(db.Model(&TestModel{}).Select("id", "name").Where("age >= ?", 18)).
+			To("@exports/adults/").Format(UnloadParquet, "COMPRESSION = SNAPPY").Header(true).Overwrite(true).
+			MaxFileSize(256 << 20).Execute()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := "COPY INTO @exports/adults/ FROM (SELECT id,name FROM test_models WHERE age >= 18) " +
+			"FILE_FORMAT = (TYPE = PARQUET COMPRESSION = SNAPPY) HEADER = TRUE OVERWRITE = TRUE MAX_FILE_SIZE = 268435456"
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+		if len(results) != 1 || results[0].RowsUnloaded != 42 || results[0].OutputBytes != 1024 {
+			t.Errorf("Unexpected results %+v", results)
+		}
+	})
+
+	t.Run("Escaped literal", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		_, err := Unload(db).Query(db.Table("orders").Where("note = ?", `a\' OR 1=1 --`)).To("@exports/orders/").Execute()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if queries := fake.queries(); len(queries) != 1 || !strings.Contains(queries[0], `(SELECT * FROM orders WHERE note = 'a\\'' OR 1=1 --')`) {
+			t.Errorf("Expected the note escaped, got %v", queries)
+		}
+
+		_, err = Unload(db).Query(db.Table("orders").Where("note = ?", struct{}{})).To("@exports/orders/").Execute()
+		if !errors.Is(err, ErrInlineValue) {
+			t.Errorf("Expected ErrInlineValue, got %v", err)
+		}
+	})
+
+	t.Run("Partitioned CSV", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		_, err := Unload(db).Query(db.Table("orders")).To("@%orders/export").Format(UnloadCSV).
+			PartitionBy("'day=' || TO_VARCHAR(created_at::DATE)").Single(true).Execute()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := `COPY INTO @%orders/export FROM (SELECT * FROM "orders") PARTITION BY ('day=' || TO_VARCHAR(created_at::DATE)) ` +
+			"FILE_FORMAT = (TYPE = CSV) SINGLE = TRUE"
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+		query := db.Model(&TestModel{})
+
+		if _, err := Unload(db).To("@exports").Execute(); !errors.Is(err, gorm.ErrSubQueryRequired) {
+			t.Errorf("Expected ErrSubQueryRequired, got %v", err)
+		}
+		for _, builder := range []*UnloadBuilder{
+			Unload(db).Query(query),
+			Unload(db).Query(query).To("s3://bucket/path"),
+			Unload(db).Query(query).To("@exports").Format("AVRO"),
+		} {
+			if _, err := builder.Execute(); !errors.Is(err, ErrInvalidCopy) {
+				t.Errorf("Expected ErrInvalidCopy, got %v", err)
+			}
+		}
+		if len(fake.statements) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+}
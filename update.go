@@ -0,0 +1,83 @@
+package snowflake
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+)
+
+// updateClauses are the clauses of UPDATE statements, FROM joins other tables:
+//
+//	db.Model(&Order{}).Clauses(clause.From{Tables: []clause.Table{{Name: "customers"}}}).
+//		Where("orders.customer_id = customers.id AND customers.vip").Update("priority", 1)
+var updateClauses = []string{"UPDATE", "SET", "FROM", "WHERE"}
+
+// mergeUpdateClauses are the clauses of updates with a MergeUsing clause
+var mergeUpdateClauses = []string{"MERGE", "SET"}
+
+// MergeUsing turns an update into MERGE INTO <table> USING <source> ON <conditions> WHEN MATCHED
+// THEN UPDATE SET ..., the conditions of the update joining the rows of the table to the rows
+// of the source, e.g. to apply a staging table:
+//
+//	db.Model(&Order{}).Clauses(snowflake.MergeUsing{Source: clause.Table{Name: "order_updates", Alias: "u"}}).
+//		Where("orders.id = u.id").Update("status", gorm.Expr("u.status"))
+//
+// Unlike UPDATE ... FROM, which updates a row matching several rows of the source from any of
+// them, the statement fails (ERROR_ON_NONDETERMINISTIC_MERGE)
+type MergeUsing struct {
+	Source clause.Table
+}
+
+func (MergeUsing) Name() string {
+	return "MERGE"
+}
+
+func (merge MergeUsing) Build(builder clause.Builder) {
+	builder.WriteString("INTO ")
+	builder.WriteQuoted(clause.Table{Name: clause.CurrentTable})
+	builder.WriteString(" USING ")
+	builder.WriteQuoted(merge.Source)
+	builder.WriteString(" ON ")
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		if where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			where.Build(builder)
+		} else {
+			builder.WriteString("TRUE")
+		}
+	}
+	builder.WriteString(" WHEN MATCHED THEN UPDATE")
+}
+
+func (merge MergeUsing) MergeClause(c *clause.Clause) {
+	c.Expression = merge
+}
+
+// gormUpdate is gorm:update, building the UPDATE statements with updateClauses
+var gormUpdate = callbacks.Update(&callbacks.Config{UpdateClauses: updateClauses})
+
+// Update replaces gorm:update, it runs the update like gorm, with UPDATE ... FROM, and builds
+// the updates with a MergeUsing clause as MERGE statements
+func Update(db *gorm.DB) {
+	if _, ok := db.Statement.Clauses["MERGE"]; ok && db.Error == nil {
+		db.Statement.BuildClauses = mergeUpdateClauses
+	}
+	gormUpdate(db)
+}
+
+// unqualifiedAssignments drops the table of the assigned columns, Snowflake only accepts
+// column names on the left of SET, e.g. when Updates is given "orders.status"
+func unqualifiedAssignments(set clause.Set) clause.Set {
+	unqualified := make(clause.Set, len(set))
+	for idx, assignment := range set {
+		if !assignment.Column.Raw {
+			assignment.Column.Table = ""
+			if dot := strings.LastIndexByte(assignment.Column.Name, '.'); dot >= 0 && !strings.Contains(assignment.Column.Name, `"`) {
+				assignment.Column.Name = assignment.Column.Name[dot+1:]
+			}
+		}
+		unqualified[idx] = assignment
+	}
+	return unqualified
+}
@@ -0,0 +1,103 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestUpdate(t *testing.T) {
+	db := setupMockDB(t)
+
+	tests := []struct {
+		name     string
+		update   func(tx *gorm.DB) *gorm.DB
+		expected string
+	}{
+		{
+			name: "Updates with the primary key",
+			update: func(tx *gorm.DB) *gorm.DB {
+				return tx.Model(&TestModel{ID: 1}).Update("name", "a")
+			},
+			expected: `UPDATE "test_models" SET "name"=? WHERE "id" = ?`,
+		},
+		{
+			name: "Joins other tables with FROM",
+			update: func(tx *gorm.DB) *gorm.DB {
+				return tx.Model(&TestModel{}).
+					Clauses(clause.From{Tables: []clause.Table{{Name: "people"}}}).
+					Where("test_models.name = people.name").
+					Update("age", gorm.Expr("people.age"))
+			},
+			expected: `UPDATE "test_models" SET "age"=people.age FROM "people" WHERE test_models.name = people.name`,
+		},
+		{
+			name: "Drops the table of assigned columns",
+			update: func(tx *gorm.DB) *gorm.DB {
+				return tx.Model(&TestModel{}).Where("id = ?", 1).Updates(map[string]interface{}{"test_models.age": 3})
+			},
+			expected: `UPDATE "test_models" SET "age"=? WHERE id = ?`,
+		},
+		{
+			name: "Merges from other tables with MergeUsing",
+			update: func(tx *gorm.DB) *gorm.DB {
+				return tx.Model(&TestModel{}).
+					Clauses(MergeUsing{Source: clause.Table{Name: "people", Alias: "p"}}).
+					Where("test_models.name = p.name").
+					Update("age", gorm.Expr("p.age"))
+			},
+			expected: `MERGE INTO "test_models" USING "people" "p" ON test_models.name = p.name WHEN MATCHED THEN UPDATE SET "age"=p.age`,
+		},
+		{
+			name: "Merges rows of the model",
+			update: func(tx *gorm.DB) *gorm.DB {
+				return tx.Model(&TestModel{ID: 1}).
+					Clauses(MergeUsing{Source: clause.Table{Name: "people", Alias: "p"}}).
+					Where("test_models.name = p.name").
+					Update("age", gorm.Expr("p.age"))
+			},
+			expected: `MERGE INTO "test_models" USING "people" "p" ON test_models.name = p.name AND "id" = ? WHEN MATCHED THEN UPDATE SET "age"=p.age`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := tt.update(db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true}))
+			if tx.Error != nil {
+				t.Fatalf("Expected no error, got %v", tx.Error)
+			}
+			if sql := tx.Statement.SQL.String(); sql != tt.expected {
+				t.Errorf("Expected SQL:\n%s\ngot:\n%s", tt.expected, sql)
+			}
+		})
+	}
+
+	t.Run("Refuses updates without conditions", func(t *testing.T) {
+		err := db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true}).Model(&TestModel{}).Update("age", 1).Error
+		if !errors.Is(err, gorm.ErrMissingWhereClause) {
+			t.Errorf("Expected ErrMissingWhereClause, got %v", err)
+		}
+
+		err = db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true}).Model(&TestModel{}).
+			Clauses(MergeUsing{Source: clause.Table{Name: "people"}}).Update("age", 1).Error
+		if !errors.Is(err, gorm.ErrMissingWhereClause) {
+			t.Errorf("Expected ErrMissingWhereClause, got %v", err)
+		}
+	})
+	t.Run("Reports rows affected", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+			return fakeResult{rowsAffected: 3}
+		})
+
+		result := db.Model(&TestModel{}).Clauses(MergeUsing{Source: clause.Table{Name: "people"}}).
+			Where("test_models.name = people.name").Update("age", gorm.Expr("people.age"))
+		if result.Error != nil || result.RowsAffected != 3 {
+			t.Errorf("Expected 3 rows affected, got %d (%v)", result.RowsAffected, result.Error)
+		}
+		if !hasQuery(fake.queries(), "MERGE INTO test_models USING people ON") {
+			t.Errorf("Expected a MERGE, got %v", fake.queries())
+		}
+	})
+}
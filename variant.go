@@ -0,0 +1,48 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Variant is the JSON text of a VARIANT column, parsed with PARSE_JSON when written.
+// Fields of other types (maps, structs, slices...) are stored with the SemiStructured serializer.
+// json.RawMessage fields are VARIANT columns by default, they need `gorm:"serializer:json"`
+// to be scanned as Snowflake returns VARIANT values as text
+type Variant json.RawMessage
+
+func (Variant) GormDataType() string {
+	return "variant"
+}
+
+// Value returns the JSON text of v, nil when v is empty
+func (v Variant) Value() (driver.Value, error) {
+	if len(v) == 0 {
+		return nil, nil
+	}
+	return string(v), nil
+}
+
+// Scan stores the JSON text returned by Snowflake for a VARIANT column
+func (v *Variant) Scan(value interface{}) error {
+	switch value := value.(type) {
+	case nil:
+		*v = nil
+	case string:
+		*v = Variant(value)
+	case []byte:
+		*v = append((*v)[:0], value...)
+	default:
+		return fmt.Errorf("failed to scan %T into Variant", value)
+	}
+	return nil
+}
+
+func (v Variant) MarshalJSON() ([]byte, error) {
+	return json.RawMessage(v).MarshalJSON()
+}
+
+func (v *Variant) UnmarshalJSON(data []byte) error {
+	return (*json.RawMessage)(v).UnmarshalJSON(data)
+}
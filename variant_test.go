@@ -0,0 +1,128 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VariantModel struct {
+	ID         uint `gorm:"primaryKey;autoIncrement:false"`
+	Payload    Variant
+	Attributes map[string]interface{} `gorm:"type:VARIANT;serializer:json"`
+	Raw        json.RawMessage        `gorm:"serializer:json"`
+}
+
+func TestVariantDataType(t *testing.T) {
+	db := setupMockDB(t)
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&VariantModel{}); err != nil {
+		t.Fatalf("Failed to parse model: %v", err)
+	}
+
+	for _, name := range []string{"Payload", "Raw"} {
+		if dataType := db.Dialector.DataTypeOf(stmt.Schema.LookUpField(name)); dataType != "VARIANT" {
+			t.Errorf("Expected VARIANT for %s, got %s", name, dataType)
+		}
+	}
+	if semiStructuredType(stmt.Schema.LookUpField("Attributes")) != "VARIANT" {
+		t.Error("Expected type:VARIANT field to be a VARIANT column")
+	}
+}
+
+func TestVariantCreate(t *testing.T) {
+	model := VariantModel{
+		ID:         1,
+		Payload:    Variant(`{"a":1}`),
+		Attributes: map[string]interface{}{"color": "red"},
+		Raw:        json.RawMessage(`[1,2]`),
+	}
+
+	t.Run("INSERT selects parsed rows", func(t *testing.T) {
+		db := setupMockDBWithConfig(t, false, true)
+		tx := db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true}).Create(&model)
+		if tx.Error != nil {
+			t.Fatalf("Expected no error, got %v", tx.Error)
+		}
+
+		sql := tx.Statement.SQL.String()
+		if !strings.Contains(sql, "SELECT ?,PARSE_JSON(?),PARSE_JSON(?),PARSE_JSON(?)") || strings.Contains(sql, "VALUES") {
+			t.Errorf("Expected INSERT ... SELECT with PARSE_JSON, got %s", sql)
+		}
+		expected := []interface{}{uint(1), `{"a":1}`, `{"color":"red"}`, `[1,2]`}
+		if len(tx.Statement.Vars) != len(expected) {
+			t.Fatalf("Expected vars %v, got %v", expected, tx.Statement.Vars)
+		}
+		for idx, value := range expected {
+			if tx.Statement.Vars[idx] != value {
+				t.Errorf("Expected var %d to be %v, got %v", idx, value, tx.Statement.Vars[idx])
+			}
+		}
+	})
+
+	t.Run("MERGE selects parsed rows", func(t *testing.T) {
+		db := setupMockDBWithConfig(t, false, true)
+		tx := db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true}).Clauses(clause.OnConflict{UpdateAll: true}).Create(&[]VariantModel{model, model})
+		if tx.Error != nil {
+			t.Fatalf("Expected no error, got %v", tx.Error)
+		}
+
+		sql := tx.Statement.SQL.String()
+		if !strings.Contains(sql, "USING (SELECT ?,PARSE_JSON(?)") || !strings.Contains(sql, " UNION ALL SELECT ") || strings.Contains(sql, "USING (VALUES") {
+			t.Errorf("Expected MERGE using SELECT rows, got %s", sql)
+		}
+	})
+
+	t.Run("NULL values are not parsed", func(t *testing.T) {
+		for _, value := range []interface{}{nil, Variant(nil), json.RawMessage{}, (*map[string]interface{})(nil)} {
+			if expr, err := semiStructuredExpr(value, "VARIANT"); expr != nil || err != nil {
+				t.Errorf("Expected NULL for %#v, got %v (%v)", value, expr, err)
+			}
+		}
+	})
+}
+
+func TestVariantUpdate(t *testing.T) {
+	db := setupMockDB(t)
+	tx := db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true}).Model(&VariantModel{ID: 1}).Updates(map[string]interface{}{
+		"payload": map[string]interface{}{"b": true},
+		"raw":     gorm.Expr("NULL"),
+	})
+	if tx.Error != nil {
+		t.Fatalf("Expected no error, got %v", tx.Error)
+	}
+
+	sql := tx.Statement.SQL.String()
+	if !strings.Contains(sql, `"payload"=PARSE_JSON(?)`) || !strings.Contains(sql, `"raw"=NULL`) {
+		t.Errorf("Expected payload to be parsed, got %s", sql)
+	}
+	if tx.Statement.Vars[0] != `{"b":true}` {
+		t.Errorf("Expected JSON encoded var, got %v", tx.Statement.Vars)
+	}
+}
+
+func TestVariantScan(t *testing.T) {
+	db, _ := setupFakeDB(t, Config{}, func(query string, args []interface{}) fakeResult {
+		return fakeResult{
+			columns: []string{"id", "payload", "attributes", "raw"},
+			rows:    [][]driver.Value{{int64(1), "{\n  \"a\": 1\n}", `{"color":"red"}`, "[1,2]"}},
+		}
+	})
+
+	var model VariantModel
+	if err := db.First(&model).Error; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var payload map[string]int
+	if err := json.Unmarshal(model.Payload, &payload); err != nil || payload["a"] != 1 {
+		t.Errorf("Expected payload to hold the JSON text, got %s (%v)", model.Payload, err)
+	}
+	if model.Attributes["color"] != "red" || string(model.Raw) != "[1,2]" {
+		t.Errorf("Unexpected model: %+v", model)
+	}
+}
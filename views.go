@@ -0,0 +1,193 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// viewOptionsKey holds the ViewOptions set by WithViewOptions
+const viewOptionsKey = "snowflake:view_options"
+
+// ErrInlineValue is returned when a var of the query of a view, a dynamic table or an unload
+// cannot be written as a literal, DDL statements take no bind variables
+var ErrInlineValue = errors.New("unsupported inline value")
+
+// ErrCheckOptionUnsupported is returned by CreateView for a gorm.ViewOption with a CheckOption,
+// Snowflake views are read only
+var ErrCheckOptionUnsupported = errors.New("views have no CHECK OPTION")
+
+// ViewOptions are the Snowflake options of a view, see ViewOptioner and WithViewOptions
+type ViewOptions struct {
+	// Secure creates a SECURE view, whose definition is only visible to the roles owning it
+	Secure bool
+	// Materialized creates a MATERIALIZED VIEW, whose results are stored and kept up to date by
+	// Snowflake. Materialized views select from a single table
+	Materialized bool
+	// ClusterBy is the clustering key of a materialized view
+	ClusterBy []string
+	// Comment is the comment of the view
+	Comment string
+}
+
+// ViewOptioner is implemented by ViewModel models setting the options of their view, written by
+// CreateViewModel and AutoMigrate. A model switching between a view and a materialized view must
+// be dropped first:
+//
+//	func (DailyRevenue) ViewOptions() snowflake.ViewOptions {
+//		return snowflake.ViewOptions{Secure: true, Materialized: true, ClusterBy: []string{"day"}}
+//	}
+type ViewOptioner interface {
+	ViewOptions() ViewOptions
+}
+
+// WithViewOptions makes CreateView and CreateMaterializedView of db create views with options:
+//
+//	snowflake.WithViewOptions(db, snowflake.ViewOptions{Secure: true}).Migrator().CreateView("active_users", gorm.ViewOption{
+//		Replace: true,
+//		Query:   db.Model(&User{}).Where("active"),
+//	})
+func WithViewOptions(db *gorm.DB, options ViewOptions) *gorm.DB {
+	return db.Set(viewOptionsKey, options)
+}
+
+// viewOptionsOf returns the options of the view of a ViewModel, also when the method is declared
+// on the pointer type
+func viewOptionsOf(value interface{}) ViewOptions {
+	if optioner, ok := value.(ViewOptioner); ok {
+		return optioner.ViewOptions()
+	}
+
+	modelType := reflect.TypeOf(value)
+	for modelType != nil && modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType != nil {
+		if optioner, ok := reflect.New(modelType).Interface().(ViewOptioner); ok {
+			return optioner.ViewOptions()
+		}
+	}
+	return ViewOptions{}
+}
+
+// CreateView creates the view name selecting option.Query, with CREATE OR REPLACE when
+// option.Replace and the ViewOptions set by WithViewOptions. The vars of the query are inlined
+func (m Migrator) CreateView(name string, option gorm.ViewOption) error {
+	options, _ := m.DB.Get(viewOptionsKey)
+	viewOptions, _ := options.(ViewOptions)
+	return m.createQueryView(name, option, viewOptions)
+}
+
+// CreateMaterializedView creates the materialized view name selecting option.Query, with
+// CREATE OR REPLACE when option.Replace and the other ViewOptions set by WithViewOptions
+func (m Migrator) CreateMaterializedView(name string, option gorm.ViewOption) error {
+	options, _ := m.DB.Get(viewOptionsKey)
+	viewOptions, _ := options.(ViewOptions)
+	viewOptions.Materialized = true
+	return m.createQueryView(name, option, viewOptions)
+}
+
+// DropMaterializedView drops the materialized view name, DropView only drops views
+func (m Migrator) DropMaterializedView(name string) error {
+	if m.needsMigrationSession() {
+		return m.withMigrationSession(func(m Migrator) error { return m.DropMaterializedView(name) })
+	}
+	return m.DB.Exec("DROP MATERIALIZED VIEW IF EXISTS ?", clause.Table{Name: name}).Error
+}
+
+// createQueryView creates the view name of a gorm.ViewOption
+func (m Migrator) createQueryView(name string, option gorm.ViewOption, options ViewOptions) error {
+	if m.needsMigrationSession() {
+		return m.withMigrationSession(func(m Migrator) error { return m.createQueryView(name, option, options) })
+	}
+
+	if option.Query == nil {
+		return gorm.ErrSubQueryRequired
+	}
+	if option.CheckOption != "" {
+		return fmt.Errorf("%w: %s", ErrCheckOptionUnsupported, option.CheckOption)
+	}
+
+	definition, err := m.inlineQuery(option.Query)
+	if err != nil {
+		return err
+	}
+	return m.createView(clause.Table{Name: name}, option.Replace, options, definition)
+}
+
+// inlineQuery returns the SELECT of query with its vars inlined, DDL statements take no bind
+// variables
+func (m Migrator) inlineQuery(query *gorm.DB) (string, error) {
+	return inlineQuery(m.DB, query)
+}
+
+// inlineQuery returns the SELECT of query built by db with its vars inlined as literals by
+// sqlLiteral, a var of another type fails with ErrInlineValue
+func inlineQuery(db *gorm.DB, query *gorm.DB) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	stmt.AddVar(stmt, query)
+	if stmt.Error != nil {
+		return "", stmt.Error
+	}
+
+	var style BindVarStyle
+	if config := dialectorConfig(db.Dialector); config != nil {
+		style = config.BindVarStyle
+	}
+
+	var err error
+	sql := replacePlaceholders(stmt.SQL.String(), style, stmt.Vars, func(value interface{}) string {
+		literal, literalErr := sqlLiteral(value, ErrInlineValue)
+		if err == nil {
+			err = literalErr
+		}
+		return literal
+	})
+	if err != nil {
+		return "", err
+	}
+	return sql, nil
+}
+
+// createView creates the view table selecting definition
+func (m Migrator) createView(table interface{}, replace bool, options ViewOptions, definition string) error {
+	if len(options.ClusterBy) > 0 && !options.Materialized {
+		return fmt.Errorf("CLUSTER BY requires a materialized view")
+	}
+
+	sql := "CREATE "
+	if replace {
+		sql += "OR REPLACE "
+	}
+	if options.Secure {
+		sql += "SECURE "
+	}
+	if options.Materialized {
+		sql += "MATERIALIZED "
+	}
+	sql += "VIEW ?"
+	values := []interface{}{table}
+
+	if options.Comment != "" {
+		sql += " COMMENT = ?"
+		values = append(values, clause.Expr{SQL: quoteLiteral(options.Comment)})
+	}
+	if len(options.ClusterBy) > 0 {
+		columns := make([]interface{}, len(options.ClusterBy))
+		for idx, column := range options.ClusterBy {
+			if err := validateIdentifier(column); err != nil {
+				return err
+			}
+			columns[idx] = clause.Column{Name: column}
+		}
+		sql += " CLUSTER BY ?"
+		values = append(values, columns)
+	}
+
+	sql += " AS ?"
+	values = append(values, clause.Expr{SQL: definition})
+	return m.DB.Exec(sql, values...).Error
+}
@@ -0,0 +1,137 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type dailyRevenue struct {
+	Day     string
+	Revenue float64
+}
+
+func (dailyRevenue) ViewDefinition() string {
+	return "SELECT TO_DATE(created_at) AS day, SUM(age) AS revenue FROM test_models GROUP BY 1"
+}
+
+func (dailyRevenue) ViewOptions() ViewOptions {
+	return ViewOptions{Secure: true, Materialized: true, ClusterBy: []string{"day"}, Comment: "revenue's by day"}
+}
+
+func TestViews(t *testing.T) {
+	t.Run("CreateView", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		err := db.Migrator().CreateView("adults", gorm.ViewOption{
+			Replace: true,
+			Query:   db.Model(&TestModel{}).Select("id", "name").Where("age >= ?", 18),
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := `CREATE OR REPLACE VIEW "adults" AS SELECT "id","name" FROM "test_models" WHERE age >= 18`
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("CreateView with inlined literals", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		err := db.Migrator().CreateView("matches", gorm.ViewOption{
+			Query: db.Model(&TestModel{}).Where("name = ? AND created_at > ? AND data = ?", `a\' OR 1=1 --`, since, []byte{0xca, 0xfe}),
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := `CREATE VIEW matches AS SELECT * FROM test_models WHERE name = 'a\\'' OR 1=1 --' ` +
+			`AND created_at > '2024-01-02 03:04:05 +00:00' AND data = X'cafe'`
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+
+		err = db.Migrator().CreateView("matches", gorm.ViewOption{Query: db.Model(&TestModel{}).Where("name = ?", struct{}{})})
+		if !errors.Is(err, ErrInlineValue) {
+			t.Errorf("Expected ErrInlineValue, got %v", err)
+		}
+	})
+
+	t.Run("CreateView with options", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		err := WithViewOptions(db, ViewOptions{Secure: true, Comment: "adults only"}).Migrator().CreateView("adults", gorm.ViewOption{
+			Query: db.Model(&TestModel{}).Where("age >= ?", 18),
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := "CREATE SECURE VIEW adults COMMENT = 'adults only' AS SELECT * FROM test_models WHERE age >= 18"
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("CreateMaterializedView", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		err := WithViewOptions(db, ViewOptions{ClusterBy: []string{"age"}}).Migrator().(Migrator).CreateMaterializedView("ages", gorm.ViewOption{
+			Replace: true,
+			Query:   db.Model(&TestModel{}).Select("age, COUNT(*) AS total").Group("age"),
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := db.Migrator().(Migrator).DropMaterializedView("ages"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := []string{
+			"CREATE OR REPLACE MATERIALIZED VIEW ages CLUSTER BY (age) AS SELECT age, COUNT(*) AS total FROM test_models GROUP BY age",
+			"DROP MATERIALIZED VIEW IF EXISTS ages",
+		}
+		queries := fake.queries()
+		if len(queries) != len(expected) || queries[0] != expected[0] || queries[1] != expected[1] {
+			t.Errorf("Expected %v, got %v", expected, queries)
+		}
+	})
+
+	t.Run("ViewModel options", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		if err := db.AutoMigrate(&dailyRevenue{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := "CREATE OR REPLACE SECURE MATERIALIZED VIEW daily_revenues COMMENT = 'revenue''s by day' CLUSTER BY (day) AS " +
+			dailyRevenue{}.ViewDefinition()
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("Invalid options", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+
+		if err := db.Migrator().CreateView("adults", gorm.ViewOption{}); !errors.Is(err, gorm.ErrSubQueryRequired) {
+			t.Errorf("Expected ErrSubQueryRequired, got %v", err)
+		}
+		err := db.Migrator().CreateView("adults", gorm.ViewOption{Query: db.Model(&TestModel{}), CheckOption: "WITH CHECK OPTION"})
+		if !errors.Is(err, ErrCheckOptionUnsupported) {
+			t.Errorf("Expected ErrCheckOptionUnsupported, got %v", err)
+		}
+		err = WithViewOptions(db, ViewOptions{ClusterBy: []string{"age"}}).Migrator().CreateView("adults", gorm.ViewOption{Query: db.Model(&TestModel{})})
+		if err == nil {
+			t.Error("Expected an error for CLUSTER BY on a view")
+		}
+		if len(fake.statements) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+}
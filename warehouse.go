@@ -0,0 +1,174 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WarehouseSize is the WAREHOUSE_SIZE of a virtual warehouse
+type WarehouseSize string
+
+const (
+	WarehouseXSmall   WarehouseSize = "XSMALL"
+	WarehouseSmall    WarehouseSize = "SMALL"
+	WarehouseMedium   WarehouseSize = "MEDIUM"
+	WarehouseLarge    WarehouseSize = "LARGE"
+	WarehouseXLarge   WarehouseSize = "XLARGE"
+	WarehouseXXLarge  WarehouseSize = "XXLARGE"
+	WarehouseXXXLarge WarehouseSize = "XXXLARGE"
+	WarehouseX4Large  WarehouseSize = "X4LARGE"
+	WarehouseX5Large  WarehouseSize = "X5LARGE"
+	WarehouseX6Large  WarehouseSize = "X6LARGE"
+)
+
+// ScalingPolicy is the SCALING_POLICY of a multi-cluster warehouse
+type ScalingPolicy string
+
+const (
+	ScalingPolicyStandard ScalingPolicy = "STANDARD"
+	ScalingPolicyEconomy  ScalingPolicy = "ECONOMY"
+)
+
+// maxWarehouseClusters is the upper bound of MIN/MAX_CLUSTER_COUNT
+const maxWarehouseClusters = 10
+
+// ErrInvalidWarehouseOptions is returned when WarehouseOptions cannot be turned into valid DDL
+var ErrInvalidWarehouseOptions = errors.New("invalid warehouse options")
+
+// WarehouseOptions are the properties set by CreateWarehouse and AlterWarehouse,
+// zero values leave the property unset
+type WarehouseOptions struct {
+	Size WarehouseSize
+	// AutoSuspend is the idle time in seconds before the warehouse is suspended, 0 disables auto-suspend
+	AutoSuspend *int
+	AutoResume  *bool
+	// InitiallySuspended is only accepted by CreateWarehouse
+	InitiallySuspended *bool
+	// MinClusterCount and MaxClusterCount (1 to 10) make the warehouse multi-cluster
+	MinClusterCount int
+	MaxClusterCount int
+	ScalingPolicy   ScalingPolicy
+	Comment         string
+}
+
+// build returns the properties of opts as `NAME = value` pairs
+func (opts WarehouseOptions) build() (sql string, vars []interface{}, err error) {
+	var properties []string
+
+	if opts.Size != "" {
+		size := WarehouseSize(strings.ToUpper(string(opts.Size)))
+		switch size {
+		case WarehouseXSmall, WarehouseSmall, WarehouseMedium, WarehouseLarge, WarehouseXLarge,
+			WarehouseXXLarge, WarehouseXXXLarge, WarehouseX4Large, WarehouseX5Large, WarehouseX6Large:
+			properties = append(properties, "WAREHOUSE_SIZE = "+string(size))
+		default:
+			return "", nil, fmt.Errorf("%w: unsupported size %q", ErrInvalidWarehouseOptions, opts.Size)
+		}
+	}
+
+	if opts.AutoSuspend != nil {
+		if *opts.AutoSuspend < 0 {
+			return "", nil, fmt.Errorf("%w: negative auto suspend %d", ErrInvalidWarehouseOptions, *opts.AutoSuspend)
+		}
+		properties = append(properties, "AUTO_SUSPEND = "+strconv.Itoa(*opts.AutoSuspend))
+	}
+
+	if opts.AutoResume != nil {
+		properties = append(properties, "AUTO_RESUME = "+strings.ToUpper(strconv.FormatBool(*opts.AutoResume)))
+	}
+
+	if opts.InitiallySuspended != nil {
+		properties = append(properties, "INITIALLY_SUSPENDED = "+strings.ToUpper(strconv.FormatBool(*opts.InitiallySuspended)))
+	}
+
+	for _, count := range []struct {
+		name  string
+		value int
+	}{{"MIN_CLUSTER_COUNT", opts.MinClusterCount}, {"MAX_CLUSTER_COUNT", opts.MaxClusterCount}} {
+		if count.value == 0 {
+			continue
+		}
+		if count.value < 1 || count.value > maxWarehouseClusters {
+			return "", nil, fmt.Errorf("%w: %s must be between 1 and %d, got %d", ErrInvalidWarehouseOptions, count.name, maxWarehouseClusters, count.value)
+		}
+		properties = append(properties, count.name+" = "+strconv.Itoa(count.value))
+	}
+	if opts.MinClusterCount > 0 && opts.MaxClusterCount > 0 && opts.MinClusterCount > opts.MaxClusterCount {
+		return "", nil, fmt.Errorf("%w: MIN_CLUSTER_COUNT %d is above MAX_CLUSTER_COUNT %d", ErrInvalidWarehouseOptions, opts.MinClusterCount, opts.MaxClusterCount)
+	}
+
+	if opts.ScalingPolicy != "" {
+		policy := ScalingPolicy(strings.ToUpper(string(opts.ScalingPolicy)))
+		if policy != ScalingPolicyStandard && policy != ScalingPolicyEconomy {
+			return "", nil, fmt.Errorf("%w: unsupported scaling policy %q", ErrInvalidWarehouseOptions, opts.ScalingPolicy)
+		}
+		properties = append(properties, "SCALING_POLICY = "+string(policy))
+	}
+
+	if opts.Comment != "" {
+		properties = append(properties, "COMMENT = ?")
+		vars = append(vars, clause.Expr{SQL: quoteLiteral(opts.Comment)})
+	}
+
+	return strings.Join(properties, " "), vars, nil
+}
+
+// CreateWarehouse creates the warehouse name if it does not exist yet, with the properties of opts
+func CreateWarehouse(db *gorm.DB, name string, opts WarehouseOptions) error {
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+
+	properties, vars, err := opts.build()
+	if err != nil {
+		return err
+	}
+
+	sql := "CREATE WAREHOUSE IF NOT EXISTS ?"
+	if properties != "" {
+		sql += " WITH " + properties
+	}
+	return db.Exec(sql, append([]interface{}{clause.Table{Name: name}}, vars...)...).Error
+}
+
+// AlterWarehouse sets the properties of opts on the warehouse name, e.g. to resize it
+// or change its auto-suspend delay
+func AlterWarehouse(db *gorm.DB, name string, opts WarehouseOptions) error {
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+	if opts.InitiallySuspended != nil {
+		return fmt.Errorf("%w: INITIALLY_SUSPENDED can only be set on creation", ErrInvalidWarehouseOptions)
+	}
+
+	properties, vars, err := opts.build()
+	if err != nil {
+		return err
+	}
+	if properties == "" {
+		return fmt.Errorf("%w: no property to alter", ErrInvalidWarehouseOptions)
+	}
+
+	return db.Exec("ALTER WAREHOUSE ? SET "+properties, append([]interface{}{clause.Table{Name: name}}, vars...)...).Error
+}
+
+// SuspendWarehouse suspends the warehouse name, running queries are completed first
+func SuspendWarehouse(db *gorm.DB, name string) error {
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+	return db.Exec("ALTER WAREHOUSE ? SUSPEND", clause.Table{Name: name}).Error
+}
+
+// ResumeWarehouse resumes the warehouse name, it is a no-op if the warehouse is already running
+func ResumeWarehouse(db *gorm.DB, name string) error {
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+	return db.Exec("ALTER WAREHOUSE ? RESUME IF SUSPENDED", clause.Table{Name: name}).Error
+}
@@ -0,0 +1,96 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWarehouseHelpers(t *testing.T) {
+	autoSuspend, autoResume, suspended := 60, true, true
+
+	t.Run("CreateWarehouse", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		err := CreateWarehouse(db, "etl_wh", WarehouseOptions{
+			Size:               "medium",
+			AutoSuspend:        &autoSuspend,
+			AutoResume:         &autoResume,
+			InitiallySuspended: &suspended,
+			MinClusterCount:    1,
+			MaxClusterCount:    3,
+			ScalingPolicy:      ScalingPolicyEconomy,
+			Comment:            "nightly jobs, don't resize",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := `CREATE WAREHOUSE IF NOT EXISTS "etl_wh" WITH WAREHOUSE_SIZE = MEDIUM AUTO_SUSPEND = 60 AUTO_RESUME = TRUE ` +
+			`INITIALLY_SUSPENDED = TRUE MIN_CLUSTER_COUNT = 1 MAX_CLUSTER_COUNT = 3 SCALING_POLICY = ECONOMY COMMENT = 'nightly jobs, don''t resize'`
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("AlterWarehouse", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		disabled := 0
+		if err := AlterWarehouse(db, "etl_wh", WarehouseOptions{Size: WarehouseXLarge, AutoSuspend: &disabled}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := `ALTER WAREHOUSE "etl_wh" SET WAREHOUSE_SIZE = XLARGE AUTO_SUSPEND = 0`
+		if queries := fake.queries(); len(queries) != 1 || queries[0] != expected {
+			t.Errorf("Expected %s, got %v", expected, queries)
+		}
+	})
+
+	t.Run("Suspend and resume", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{QuoteFields: true}, nil)
+
+		if err := SuspendWarehouse(db, "etl_wh"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := ResumeWarehouse(db, "etl_wh"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		queries := fake.queries()
+		if len(queries) != 2 || queries[0] != `ALTER WAREHOUSE "etl_wh" SUSPEND` || queries[1] != `ALTER WAREHOUSE "etl_wh" RESUME IF SUSPENDED` {
+			t.Errorf("Unexpected queries: %v", queries)
+		}
+	})
+
+	t.Run("Invalid options", func(t *testing.T) {
+		db, fake := setupFakeDB(t, Config{}, nil)
+		negative := -1
+
+		invalid := []WarehouseOptions{
+			{Size: "HUGE"},
+			{AutoSuspend: &negative},
+			{MaxClusterCount: 11},
+			{MinClusterCount: 4, MaxClusterCount: 2},
+			{ScalingPolicy: "FAST"},
+		}
+		for _, opts := range invalid {
+			if err := CreateWarehouse(db, "wh", opts); !errors.Is(err, ErrInvalidWarehouseOptions) {
+				t.Errorf("Expected ErrInvalidWarehouseOptions for %+v, got %v", opts, err)
+			}
+		}
+
+		if err := AlterWarehouse(db, "wh", WarehouseOptions{}); !errors.Is(err, ErrInvalidWarehouseOptions) {
+			t.Errorf("Expected ErrInvalidWarehouseOptions for empty alter, got %v", err)
+		}
+		if err := AlterWarehouse(db, "wh", WarehouseOptions{InitiallySuspended: &suspended}); !errors.Is(err, ErrInvalidWarehouseOptions) {
+			t.Errorf("Expected ErrInvalidWarehouseOptions for INITIALLY_SUSPENDED, got %v", err)
+		}
+		if err := SuspendWarehouse(db, "wh\n"); !errors.Is(err, ErrInvalidIdentifier) {
+			t.Errorf("Expected ErrInvalidIdentifier, got %v", err)
+		}
+
+		if len(fake.queries()) != 0 {
+			t.Errorf("Expected no statement to run, got %v", fake.queries())
+		}
+	})
+}